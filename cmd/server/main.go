@@ -3,9 +3,12 @@ package main
 import (
 	"context"
 	"cv_builder/config"
+	"cv_builder/internal/repository"
 	"cv_builder/internal/routes"
 	"cv_builder/pkg/auth"
 	database "cv_builder/pkg/db"
+	"cv_builder/pkg/mail"
+	"cv_builder/pkg/storage"
 	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
@@ -51,9 +54,50 @@ func main() {
 		ResetTokenExpiry:   1 * time.Hour,
 		Issuer:             "resume_generator",
 		Audience:           "resume_generator_users",
+
+		VerificationTokenExpiry: 24 * time.Hour,
+	}
+
+	if cfg.JWTSigningKeyFile != "" {
+		signingKey, err := auth.LoadKeyFile(cfg.JWTSigningKeyFile)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to load JWT signing key")
+		}
+		if signingKey.PrivateKey == nil {
+			log.Fatal().Str("path", cfg.JWTSigningKeyFile).Msg("JWT signing key file holds no private key")
+		}
+		jwtConfig.SigningKey = signingKey
+		jwtConfig.SecretValidUntil = cfg.JWTSecretValidUntil
+		log.Info().Str("kid", signingKey.ID).Str("alg", signingKey.Algorithm).Msg("signing tokens with key")
+		if cfg.JWTSecret != "" && time.Now().Before(cfg.JWTSecretValidUntil) {
+			log.Info().Time("until", cfg.JWTSecretValidUntil).Msg("accepting tokens signed with the JWT secret")
+		}
+	}
+	for _, path := range cfg.JWTVerificationKeyFiles {
+		verificationKey, err := auth.LoadKeyFile(path)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to load JWT verification key")
+		}
+		jwtConfig.VerificationKeys = append(jwtConfig.VerificationKeys, verificationKey)
 	}
 
-	router := routes.SetupRoutes(db, redisClient, jwtConfig)
+	blobStore, err := storage.NewFilesystemStore(cfg.StorageDir)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to open blob storage")
+	}
+
+	mailTransport, err := mail.New(mail.Config(cfg.Mail))
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to set up mail delivery")
+	}
+
+	// Requests only write to the outbox, the worker talks to the mail server
+	outbox := mail.NewOutbox(repository.NewPostgresMailQueue(db), mailTransport, mail.OutboxConfig{})
+	workerCtx, stopWorker := context.WithCancel(context.Background())
+	defer stopWorker()
+	go outbox.Run(workerCtx)
+
+	router := routes.SetupRoutes(cfg, db, redisClient, jwtConfig, blobStore, outbox)
 
 	server := &http.Server{
 		Addr:         ":" + cfg.Port,
@@ -74,6 +118,7 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	log.Info().Msg("shutting down server..")
+	stopWorker()
 
 	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
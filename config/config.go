@@ -1,11 +1,17 @@
 package config
 
 import (
+	"crypto/hkdf"
+	"crypto/sha256"
+	"encoding/base64"
 	"errors"
 	"github.com/joho/godotenv"
 	"github.com/rs/zerolog/log"
+	"net/url"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type Config struct {
@@ -15,6 +21,70 @@ type Config struct {
 	JWTSecret        string
 	CSRFKey          string
 	TelegramBotToken string
+	StorageDir       string
+	// RequireVerifiedEmail restricts accounts until they verify their email
+	RequireVerifiedEmail bool
+	// EncryptionKey seals secrets at rest, such as TOTP secrets
+	EncryptionKey []byte
+	// AppURL is the base URL of the frontend, used for links in emails
+	AppURL string
+	Mail   MailConfig
+	// WebAuthnRPID is the domain passkeys are registered for
+	WebAuthnRPID string
+	// WebAuthnOrigins are the origins allowed to use passkeys
+	WebAuthnOrigins []string
+	// OAuthProviders are the identity providers users can sign in with, by
+	// name
+	OAuthProviders map[string]OAuthProviderConfig
+	// JWTSigningKeyFile is a PEM Ed25519 or RSA private key tokens are
+	// signed with instead of JWTSecret
+	JWTSigningKeyFile string
+	// JWTVerificationKeyFiles are previous signing keys, kept while tokens
+	// signed with them are still valid
+	JWTVerificationKeyFiles []string
+	// JWTSecretValidUntil is when tokens signed with JWTSecret are no longer
+	// accepted after switching to JWTSigningKeyFile
+	JWTSecretValidUntil time.Time
+}
+
+// OAuthProviderConfig configures a login provider, see auth.OAuthConfig
+type OAuthProviderConfig struct {
+	ClientID     string
+	ClientSecret string
+	Issuer       string
+	AuthURL      string
+	TokenURL     string
+	UserInfoURL  string
+	EmailsURL    string
+	Scopes       []string
+	RedirectURL  string
+}
+
+// oauthPresets are the well-known providers, which only need a client ID
+// and secret
+var oauthPresets = map[string]OAuthProviderConfig{
+	"github": {
+		AuthURL:     "https://github.com/login/oauth/authorize",
+		TokenURL:    "https://github.com/login/oauth/access_token",
+		UserInfoURL: "https://api.github.com/user",
+		EmailsURL:   "https://api.github.com/user/emails",
+		Scopes:      []string{"read:user", "user:email"},
+	},
+	"google": {
+		Issuer: "https://accounts.google.com",
+		Scopes: []string{"openid", "email", "profile"},
+	},
+}
+
+// MailConfig selects how outgoing email is delivered, see mail.Config
+type MailConfig struct {
+	Driver       string
+	From         string
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	Dir          string
 }
 
 // Load loads configuration from environment variables with validation
@@ -30,6 +100,20 @@ func Load() (*Config, error) {
 		JWTSecret:        os.Getenv("JWT_SECRET"),
 		CSRFKey:          os.Getenv("CSRF_KEY"),
 		TelegramBotToken: os.Getenv("MY_BOT_TOKEN"),
+		StorageDir:       os.Getenv("STORAGE_DIR"),
+
+		RequireVerifiedEmail: os.Getenv("REQUIRE_VERIFIED_EMAIL") == "true",
+		AppURL:               strings.TrimSuffix(os.Getenv("APP_URL"), "/"),
+		WebAuthnRPID:         os.Getenv("WEBAUTHN_RP_ID"),
+		JWTSigningKeyFile:    os.Getenv("JWT_SIGNING_KEY_FILE"),
+		Mail: MailConfig{
+			Driver:       os.Getenv("MAIL_DRIVER"),
+			From:         os.Getenv("MAIL_FROM"),
+			SMTPHost:     os.Getenv("SMTP_HOST"),
+			SMTPUsername: os.Getenv("SMTP_USERNAME"),
+			SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+			Dir:          os.Getenv("MAIL_DIR"),
+		},
 	}
 
 	// Validate configuration
@@ -40,6 +124,63 @@ func Load() (*Config, error) {
 		config.Port = "8080"
 	}
 
+	if config.StorageDir == "" {
+		// Uploaded files are kept next to the binary by default
+		config.StorageDir = "./data"
+	}
+
+	if config.AppURL == "" {
+		config.AppURL = "http://localhost:3000"
+	}
+
+	if origins := os.Getenv("WEBAUTHN_RP_ORIGINS"); origins != "" {
+		for _, origin := range strings.Split(origins, ",") {
+			config.WebAuthnOrigins = append(config.WebAuthnOrigins, strings.TrimSuffix(strings.TrimSpace(origin), "/"))
+		}
+	} else {
+		// Passkeys are used from the frontend
+		config.WebAuthnOrigins = []string{config.AppURL}
+	}
+
+	if config.WebAuthnRPID == "" {
+		appURL, err := url.Parse(config.AppURL)
+		if err != nil || appURL.Hostname() == "" {
+			return nil, errors.New("APP_URL must be a valid URL")
+		}
+		config.WebAuthnRPID = appURL.Hostname()
+	}
+
+	oauthProviders, err := loadOAuthProviders(config.AppURL)
+	if err != nil {
+		return nil, err
+	}
+	config.OAuthProviders = oauthProviders
+
+	if config.Mail.Driver == "" {
+		// Mail is logged unless a real driver is configured
+		config.Mail.Driver = "log"
+	}
+
+	if config.Mail.From == "" {
+		config.Mail.From = "CV Builder <no-reply@localhost>"
+	}
+
+	if config.Mail.Dir == "" {
+		config.Mail.Dir = "./data/mail"
+	}
+
+	if port := os.Getenv("SMTP_PORT"); port != "" {
+		smtpPort, err := strconv.Atoi(port)
+		if err != nil {
+			return nil, errors.New("SMTP_PORT must be a number")
+		}
+		config.Mail.SMTPPort = smtpPort
+	}
+
+	if config.Mail.Driver == "smtp" && config.Mail.SMTPHost == "" {
+		missingVars = append(missingVars, "SMTP_HOST")
+	}
+
 	if config.DBUrl == "" {
 		missingVars = append(missingVars, "DB_URL")
 	}
@@ -48,17 +189,94 @@ func Load() (*Config, error) {
 		missingVars = append(missingVars, "REDIS_URL")
 	}
 
-	if config.JWTSecret == "" {
+	for _, path := range strings.Split(os.Getenv("JWT_VERIFICATION_KEY_FILES"), ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			config.JWTVerificationKeyFiles = append(config.JWTVerificationKeyFiles, path)
+		}
+	}
+
+	// With a signing key the secret only verifies tokens until
+	// JWT_SECRET_VALID_UNTIL
+	if config.JWTSecret == "" && config.JWTSigningKeyFile == "" {
 		missingVars = append(missingVars, "JWT_SECRET")
 	}
 
+	if validUntil := os.Getenv("JWT_SECRET_VALID_UNTIL"); validUntil != "" {
+		secretValidUntil, err := time.Parse(time.RFC3339, validUntil)
+		if err != nil {
+			return nil, errors.New("JWT_SECRET_VALID_UNTIL must be an RFC 3339 time")
+		}
+		config.JWTSecretValidUntil = secretValidUntil
+	}
+
 	if config.CSRFKey == "" {
 		missingVars = append(missingVars, "CSRF_KEY")
 	}
 
+	if key := os.Getenv("ENCRYPTION_KEY"); key != "" {
+		encryptionKey, err := base64.StdEncoding.DecodeString(key)
+		if err != nil || len(encryptionKey) != 32 {
+			return nil, errors.New("ENCRYPTION_KEY must be 32 bytes in base64")
+		}
+		config.EncryptionKey = encryptionKey
+	} else if config.JWTSecret != "" {
+		// Rotating the JWT secret would make stored secrets unreadable
+		log.Warn().Msg("ENCRYPTION_KEY is not set, deriving it from JWT_SECRET")
+		encryptionKey, err := hkdf.Key(sha256.New, []byte(config.JWTSecret), nil, "cv_builder encryption key", 32)
+		if err != nil {
+			return nil, err
+		}
+		config.EncryptionKey = encryptionKey
+	} else {
+		missingVars = append(missingVars, "ENCRYPTION_KEY")
+	}
+
 	if len(missingVars) > 0 {
 		return nil, errors.New("missing required environment variables: " + strings.Join(missingVars, ", "))
 	}
 
 	return config, nil
 }
+
+// loadOAuthProviders reads the providers named in OAUTH_PROVIDERS, e.g.
+// "github,google". Each is configured with OAUTH_<NAME>_* variables on top of
+// its preset, a custom OpenID Connect provider only needs an issuer.
+func loadOAuthProviders(appURL string) (map[string]OAuthProviderConfig, error) {
+	providers := map[string]OAuthProviderConfig{}
+	for _, name := range strings.Split(os.Getenv("OAUTH_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		provider := oauthPresets[name]
+		prefix := "OAUTH_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		for key, value := range map[string]*string{
+			"CLIENT_ID":     &provider.ClientID,
+			"CLIENT_SECRET": &provider.ClientSecret,
+			"ISSUER":        &provider.Issuer,
+			"AUTH_URL":      &provider.AuthURL,
+			"TOKEN_URL":     &provider.TokenURL,
+			"USERINFO_URL":  &provider.UserInfoURL,
+			"EMAILS_URL":    &provider.EmailsURL,
+			"REDIRECT_URL":  &provider.RedirectURL,
+		} {
+			if env := os.Getenv(prefix + key); env != "" {
+				*value = env
+			}
+		}
+		if scopes := os.Getenv(prefix + "SCOPES"); scopes != "" {
+			provider.Scopes = strings.FieldsFunc(scopes, func(r rune) bool { return r == ',' || r == ' ' })
+		}
+
+		if provider.ClientID == "" {
+			return nil, errors.New("missing required environment variables: " + prefix + "CLIENT_ID")
+		}
+		if provider.RedirectURL == "" {
+			// The frontend receives the code and passes it to the API
+			provider.RedirectURL = appURL + "/auth/oauth/" + name + "/callback"
+		}
+		providers[name] = provider
+	}
+	return providers, nil
+}
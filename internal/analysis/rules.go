@@ -0,0 +1,236 @@
+package analysis
+
+import (
+	"cv_builder/internal/domain"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultRules returns the rules used to score resumes
+func DefaultRules() []Rule {
+	return []Rule{
+		&JobTitleRule{RuleWeight: 10},
+		&AchievementsRule{RuleWeight: 20, MinAchievements: 1},
+		&QuantifiedImpactRule{RuleWeight: 15},
+		&SkillProficiencyRule{RuleWeight: 10},
+		&ExpiredCertificationsRule{RuleWeight: 10},
+		&BulletLengthRule{RuleWeight: 10, MaxLength: 200},
+	}
+}
+
+// JobTitleRule requires a headline job title in the personal information
+type JobTitleRule struct {
+	RuleWeight int
+}
+
+func (r *JobTitleRule) ID() string { return "job_title" }
+
+func (r *JobTitleRule) Description() string {
+	return "Personal information includes a job title"
+}
+
+func (r *JobTitleRule) Weight() int { return r.RuleWeight }
+
+func (r *JobTitleRule) Check(resume *domain.Resume) Evaluation {
+	evaluation := Evaluation{Checked: 1}
+	if resume.PersonalInfo == nil || strings.TrimSpace(resume.PersonalInfo.JobTitle) == "" {
+		evaluation.Findings = append(evaluation.Findings, Finding{
+			Field:      "personal_info.job_title",
+			Message:    "No job title is set",
+			Suggestion: "Add the role you are targeting, such as \"Senior Backend Engineer\", so readers see it first",
+		})
+	}
+	return evaluation
+}
+
+// AchievementsRule requires experience entries to list achievements
+type AchievementsRule struct {
+	RuleWeight      int
+	MinAchievements int
+}
+
+func (r *AchievementsRule) ID() string { return "experience_achievements" }
+
+func (r *AchievementsRule) Description() string {
+	return "Experience entries list achievements"
+}
+
+func (r *AchievementsRule) Weight() int { return r.RuleWeight }
+
+func (r *AchievementsRule) Check(resume *domain.Resume) Evaluation {
+	evaluation := Evaluation{Checked: len(resume.Experience)}
+	for i, experience := range resume.Experience {
+		if len(experience.Achievements) >= r.MinAchievements {
+			continue
+		}
+		evaluation.Findings = append(evaluation.Findings, Finding{
+			Field:      fmt.Sprintf("experience[%d].achievements", i),
+			Message:    fmt.Sprintf("%s lists %d achievements, at least %d expected", experienceName(experience), len(experience.Achievements), r.MinAchievements),
+			Suggestion: "Describe what you delivered in this role as short achievement bullets",
+		})
+	}
+	return evaluation
+}
+
+// QuantifiedImpactRule requires experience and project descriptions to
+// mention numbers such as percentages, amounts or team sizes
+type QuantifiedImpactRule struct {
+	RuleWeight int
+}
+
+func (r *QuantifiedImpactRule) ID() string { return "quantified_impact" }
+
+func (r *QuantifiedImpactRule) Description() string {
+	return "Descriptions quantify their impact with numbers"
+}
+
+func (r *QuantifiedImpactRule) Weight() int { return r.RuleWeight }
+
+func (r *QuantifiedImpactRule) Check(resume *domain.Resume) Evaluation {
+	var evaluation Evaluation
+	for i, experience := range resume.Experience {
+		text := experience.Description + " " + strings.Join(experience.Achievements, " ")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		evaluation.Checked++
+		if !containsDigit(text) {
+			evaluation.Findings = append(evaluation.Findings, Finding{
+				Field:      fmt.Sprintf("experience[%d].description", i),
+				Message:    fmt.Sprintf("%s does not mention any numbers", experienceName(experience)),
+				Suggestion: "Quantify results, for example \"cut response times by 40%\" or \"led a team of 5\"",
+			})
+		}
+	}
+	for i, project := range resume.Projects {
+		if strings.TrimSpace(project.Description) == "" {
+			continue
+		}
+		evaluation.Checked++
+		if !containsDigit(project.Description) {
+			evaluation.Findings = append(evaluation.Findings, Finding{
+				Field:      fmt.Sprintf("projects[%d].description", i),
+				Message:    fmt.Sprintf("Project %q does not mention any numbers", project.Name),
+				Suggestion: "Mention measurable outcomes such as users, stars, downloads or performance gains",
+			})
+		}
+	}
+	return evaluation
+}
+
+// SkillProficiencyRule requires skills to state a proficiency level
+type SkillProficiencyRule struct {
+	RuleWeight int
+}
+
+func (r *SkillProficiencyRule) ID() string { return "skill_proficiency" }
+
+func (r *SkillProficiencyRule) Description() string {
+	return "Skills state a proficiency level"
+}
+
+func (r *SkillProficiencyRule) Weight() int { return r.RuleWeight }
+
+func (r *SkillProficiencyRule) Check(resume *domain.Resume) Evaluation {
+	evaluation := Evaluation{Checked: len(resume.Skills)}
+	for i, skill := range resume.Skills {
+		if skill.Proficiency != 0 {
+			continue
+		}
+		evaluation.Findings = append(evaluation.Findings, Finding{
+			Field:      fmt.Sprintf("skills[%d].proficiency", i),
+			Message:    fmt.Sprintf("Skill %q has no proficiency level", skill.Name),
+			Suggestion: "Rate the skill from 1 to 5 so readers can tell core skills from passing familiarity",
+		})
+	}
+	return evaluation
+}
+
+// ExpiredCertificationsRule flags certifications past their expiry date
+type ExpiredCertificationsRule struct {
+	RuleWeight int
+	// Now returns the current time, defaulting to time.Now
+	Now func() time.Time
+}
+
+func (r *ExpiredCertificationsRule) ID() string { return "expired_certifications" }
+
+func (r *ExpiredCertificationsRule) Description() string {
+	return "Certifications are still valid"
+}
+
+func (r *ExpiredCertificationsRule) Weight() int { return r.RuleWeight }
+
+func (r *ExpiredCertificationsRule) Check(resume *domain.Resume) Evaluation {
+	now := time.Now()
+	if r.Now != nil {
+		now = r.Now()
+	}
+
+	evaluation := Evaluation{Checked: len(resume.Certifications)}
+	for i, certification := range resume.Certifications {
+		// A certification expiring in "2024-06" is valid until the end of June
+		expiry := certification.ExpiryDate
+		if expiry.Precision() == domain.PrecisionNone || !expiry.LastDay().AddDate(0, 0, 1).Before(now) {
+			continue
+		}
+		evaluation.Findings = append(evaluation.Findings, Finding{
+			Field:      fmt.Sprintf("certifications[%d].expiry_date", i),
+			Message:    fmt.Sprintf("Certification %q expired on %s", certification.Name, certification.ExpiryDate),
+			Suggestion: "Renew the certification or remove it if it is no longer relevant",
+		})
+	}
+	return evaluation
+}
+
+// BulletLengthRule flags achievement bullets too long to skim
+type BulletLengthRule struct {
+	RuleWeight int
+	MaxLength  int
+}
+
+func (r *BulletLengthRule) ID() string { return "bullet_length" }
+
+func (r *BulletLengthRule) Description() string {
+	return fmt.Sprintf("Achievement bullets are at most %d characters long", r.MaxLength)
+}
+
+func (r *BulletLengthRule) Weight() int { return r.RuleWeight }
+
+func (r *BulletLengthRule) Check(resume *domain.Resume) Evaluation {
+	var evaluation Evaluation
+	for i, experience := range resume.Experience {
+		for j, achievement := range experience.Achievements {
+			evaluation.Checked++
+			length := utf8.RuneCountInString(achievement)
+			if length <= r.MaxLength {
+				continue
+			}
+			evaluation.Findings = append(evaluation.Findings, Finding{
+				Field:      fmt.Sprintf("experience[%d].achievements[%d]", i, j),
+				Message:    fmt.Sprintf("Bullet is %d characters long", length),
+				Suggestion: "Keep each bullet to one result; split it or cut filler words",
+			})
+		}
+	}
+	return evaluation
+}
+
+func experienceName(experience *domain.Experience) string {
+	switch {
+	case experience.JobTitle != "" && experience.Employer != "":
+		return fmt.Sprintf("%s at %s", experience.JobTitle, experience.Employer)
+	case experience.JobTitle != "":
+		return experience.JobTitle
+	case experience.Employer != "":
+		return experience.Employer
+	}
+	return "Experience entry"
+}
+
+func containsDigit(text string) bool {
+	return strings.IndexFunc(text, unicode.IsDigit) >= 0
+}
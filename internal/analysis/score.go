@@ -0,0 +1,97 @@
+package analysis
+
+import (
+	"cv_builder/internal/domain"
+	"math"
+)
+
+// Rule is a single quality check run against a resume. Rules report how many
+// items they looked at and a finding for every item that failed, so partially
+// satisfied rules still contribute to the score.
+type Rule interface {
+	ID() string
+	Description() string
+	Weight() int
+	Check(resume *domain.Resume) Evaluation
+}
+
+type Evaluation struct {
+	Checked  int
+	Findings []Finding
+}
+
+type Finding struct {
+	Field      string `json:"field"`
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion"`
+}
+
+type RuleResult struct {
+	Rule        string    `json:"rule"`
+	Description string    `json:"description"`
+	Weight      int       `json:"weight"`
+	Applicable  bool      `json:"applicable"`
+	Passed      bool      `json:"passed"`
+	Score       float64   `json:"score"`
+	Findings    []Finding `json:"findings"`
+}
+
+type Report struct {
+	Score   int          `json:"score"`
+	Results []RuleResult `json:"results"`
+}
+
+// Engine scores resumes against a set of rules
+type Engine struct {
+	rules []Rule
+}
+
+func NewEngine(rules ...Rule) *Engine {
+	return &Engine{
+		rules: rules,
+	}
+}
+
+// Register adds a rule to the engine
+func (e *Engine) Register(rule Rule) {
+	e.rules = append(e.rules, rule)
+}
+
+// Evaluate runs every rule and combines the results into a score out of 100,
+// weighting each rule by the share of checked items that passed. Rules with
+// nothing to check, such as certification rules on a resume without
+// certifications, are left out of the score.
+func (e *Engine) Evaluate(resume *domain.Resume) *Report {
+	report := &Report{Results: make([]RuleResult, 0, len(e.rules))}
+
+	var total, earned float64
+	for _, rule := range e.rules {
+		evaluation := rule.Check(resume)
+		result := RuleResult{
+			Rule:        rule.ID(),
+			Description: rule.Description(),
+			Weight:      rule.Weight(),
+			Applicable:  evaluation.Checked > 0,
+			Passed:      len(evaluation.Findings) == 0,
+			Score:       1,
+			Findings:    evaluation.Findings,
+		}
+		if result.Findings == nil {
+			result.Findings = []Finding{}
+		}
+
+		if result.Applicable {
+			failed := min(len(evaluation.Findings), evaluation.Checked)
+			result.Score = float64(evaluation.Checked-failed) / float64(evaluation.Checked)
+			total += float64(result.Weight)
+			earned += float64(result.Weight) * result.Score
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	report.Score = 100
+	if total > 0 {
+		report.Score = int(math.Round(earned / total * 100))
+	}
+	return report
+}
@@ -0,0 +1,347 @@
+package analysis
+
+import (
+	"cv_builder/internal/domain"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	dateLayout  = "2006-01-02"
+	daysPerYear = 365.25
+)
+
+type TimelineOptions struct {
+	// MinGap is the shortest period without work or study reported as a gap
+	MinGap time.Duration
+	// MinOverlap is the shortest overlap between roles that is reported, so
+	// handovers of a few days are not flagged
+	MinOverlap time.Duration
+	// Now returns the date used for "Present", defaulting to time.Now
+	Now func() time.Time
+}
+
+func DefaultTimelineOptions() TimelineOptions {
+	return TimelineOptions{
+		MinGap:     90 * 24 * time.Hour,
+		MinOverlap: 31 * 24 * time.Hour,
+	}
+}
+
+type Gap struct {
+	After  string `json:"after,omitempty"`
+	Before string `json:"before,omitempty"`
+	Start  string `json:"start"`
+	End    string `json:"end"`
+	Months int    `json:"months"`
+}
+
+type Overlap struct {
+	First  string `json:"first"`
+	Second string `json:"second"`
+	Start  string `json:"start"`
+	End    string `json:"end"`
+	Months int    `json:"months"`
+}
+
+type SkillExperience struct {
+	Skill   string   `json:"skill"`
+	Years   float64  `json:"years"`
+	Sources []string `json:"sources"`
+}
+
+type Timeline struct {
+	TotalYears float64           `json:"total_years"`
+	Gaps       []Gap             `json:"gaps"`
+	Overlaps   []Overlap         `json:"overlaps"`
+	Skills     []SkillExperience `json:"skills"`
+	// Skipped lists entries left out because their dates could not be read
+	Skipped []Finding `json:"skipped"`
+}
+
+// period is a dated resume entry
+type period struct {
+	field string
+	label string
+	start time.Time
+	end   time.Time
+	// lastLabel names the entry ending last once periods are merged
+	lastLabel string
+}
+
+// AnalyzeTimeline reports employment gaps, overlapping roles, the total years
+// of experience and the years spent with each skill. Time in education is not
+// counted as a gap. Skills are linked to the roles mentioning them and to the
+// projects listing them as technologies; overlapping periods are counted once.
+func AnalyzeTimeline(resume *domain.Resume, opts TimelineOptions) *Timeline {
+	now := time.Now()
+	if opts.Now != nil {
+		now = opts.Now()
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	timeline := &Timeline{
+		Gaps:     []Gap{},
+		Overlaps: []Overlap{},
+		Skills:   []SkillExperience{},
+		Skipped:  []Finding{},
+	}
+
+	var roles, studies, projects []period
+	for i, experience := range resume.Experience {
+		field := fmt.Sprintf("experience[%d]", i)
+		if p, ok := timeline.readPeriod(field, experienceName(experience), experience.StartDate, experience.EndDate, today); ok {
+			roles = append(roles, p)
+		}
+	}
+	for i, education := range resume.Education {
+		field := fmt.Sprintf("education[%d]", i)
+		label := strings.TrimSpace(education.Degree + " " + education.Institution)
+		if p, ok := timeline.readPeriod(field, label, education.StartDate, education.EndDate, today); ok {
+			studies = append(studies, p)
+		}
+	}
+	for i, project := range resume.Projects {
+		if project.StartDate.IsZero() {
+			continue
+		}
+		field := fmt.Sprintf("projects[%d]", i)
+		if p, ok := timeline.readPeriod(field, project.Name, project.StartDate, project.EndDate, today); ok {
+			projects = append(projects, p)
+		}
+	}
+
+	timeline.TotalYears = years(mergePeriods(roles))
+	timeline.Gaps = findGaps(append(append([]period(nil), roles...), studies...), today, opts.MinGap)
+	timeline.Overlaps = findOverlaps(roles, opts.MinOverlap)
+	timeline.Skills = skillExperience(resume, roles, projects)
+
+	return timeline
+}
+
+// readPeriod turns the dates of an entry into a period. Partial dates cover
+// their whole month or year, so a role ending in "2019-05" and the next one
+// starting in "2019-06" leave no gap.
+func (t *Timeline) readPeriod(field, label string, startDate, endDate domain.PartialDate, today time.Time) (period, bool) {
+	if startDate.Precision() == domain.PrecisionNone {
+		t.Skipped = append(t.Skipped, Finding{
+			Field:      field + ".start_date",
+			Message:    fmt.Sprintf("%s has no valid start date", label),
+			Suggestion: "Set the start date in YYYY, YYYY-MM or YYYY-MM-DD format",
+		})
+		return period{}, false
+	}
+	start := startDate.FirstDay()
+
+	end := today
+	if !endDate.IsZero() && !endDate.IsPresent() {
+		if endDate.Precision() == domain.PrecisionNone {
+			t.Skipped = append(t.Skipped, Finding{
+				Field:      field + ".end_date",
+				Message:    fmt.Sprintf("%s has no valid end date", label),
+				Suggestion: "Set the end date in YYYY, YYYY-MM or YYYY-MM-DD format or to \"Present\"",
+			})
+			return period{}, false
+		}
+		end = endDate.LastDay()
+	}
+	if end.After(today) {
+		end = today
+	}
+	if end.Before(start) {
+		return period{}, false
+	}
+
+	return period{field: field, label: label, start: start, end: end, lastLabel: label}, true
+}
+
+// mergePeriods returns the union of the periods as sorted, disjoint periods
+func mergePeriods(periods []period) []period {
+	sorted := append([]period(nil), periods...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].start.Before(sorted[j].start) })
+
+	var merged []period
+	for _, p := range sorted {
+		if n := len(merged); n > 0 && !p.start.After(merged[n-1].end) {
+			last := &merged[n-1]
+			if p.end.After(last.end) {
+				last.end, last.lastLabel = p.end, p.lastLabel
+			}
+			continue
+		}
+		merged = append(merged, p)
+	}
+	return merged
+}
+
+func years(periods []period) float64 {
+	var days float64
+	for _, p := range periods {
+		days += p.end.Sub(p.start).Hours() / 24
+	}
+	return math.Round(days/daysPerYear*10) / 10
+}
+
+// findGaps reports the periods between activities, and the time since the
+// last activity ended, that are longer than minGap
+func findGaps(periods []period, today time.Time, minGap time.Duration) []Gap {
+	gaps := []Gap{}
+	merged := mergePeriods(periods)
+	for i, p := range merged {
+		next := today
+		before := ""
+		if i+1 < len(merged) {
+			next = merged[i+1].start
+			before = merged[i+1].label
+		}
+		if next.Sub(p.end) <= minGap {
+			continue
+		}
+		gap := Gap{
+			After:  p.lastLabel,
+			Before: before,
+			Start:  p.end.Format(dateLayout),
+			End:    next.Format(dateLayout),
+			Months: months(next.Sub(p.end)),
+		}
+		if i+1 == len(merged) {
+			gap.End = "Present"
+		}
+		gaps = append(gaps, gap)
+	}
+	return gaps
+}
+
+// findOverlaps reports pairs of roles held at the same time
+func findOverlaps(roles []period, minOverlap time.Duration) []Overlap {
+	overlaps := []Overlap{}
+	for i := 0; i < len(roles); i++ {
+		for j := i + 1; j < len(roles); j++ {
+			start := laterOf(roles[i].start, roles[j].start)
+			end := earlierOf(roles[i].end, roles[j].end)
+			if end.Sub(start) < minOverlap {
+				continue
+			}
+			overlaps = append(overlaps, Overlap{
+				First:  roles[i].field,
+				Second: roles[j].field,
+				Start:  start.Format(dateLayout),
+				End:    end.Format(dateLayout),
+				Months: months(end.Sub(start)),
+			})
+		}
+	}
+	return overlaps
+}
+
+// skillExperience sums the time spent in the roles and projects linked to
+// each skill of the resume
+func skillExperience(resume *domain.Resume, roles, projects []period) []SkillExperience {
+	experienceByField := make(map[string]*domain.Experience, len(resume.Experience))
+	for i, experience := range resume.Experience {
+		experienceByField[fmt.Sprintf("experience[%d]", i)] = experience
+	}
+	projectByField := make(map[string]*domain.Project, len(resume.Projects))
+	for i, project := range resume.Projects {
+		projectByField[fmt.Sprintf("projects[%d]", i)] = project
+	}
+
+	results := []SkillExperience{}
+	seen := make(map[string]bool)
+	for _, skill := range resume.Skills {
+		name := strings.TrimSpace(skill.Name)
+		if name == "" || seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+
+		var linked []period
+		sources := []string{}
+		for _, role := range roles {
+			experience := experienceByField[role.field]
+			text := strings.Join(append([]string{experience.JobTitle, experience.Description}, experience.Achievements...), " ")
+			if mentions(text, name) {
+				linked = append(linked, role)
+				sources = append(sources, role.field)
+			}
+		}
+		for _, p := range projects {
+			project := projectByField[p.field]
+			if usesTechnology(project, name) {
+				linked = append(linked, p)
+				sources = append(sources, p.field)
+			}
+		}
+
+		results = append(results, SkillExperience{
+			Skill:   name,
+			Years:   years(mergePeriods(linked)),
+			Sources: sources,
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Years > results[j].Years })
+	return results
+}
+
+func usesTechnology(project *domain.Project, skill string) bool {
+	for _, technology := range project.Technologies {
+		if strings.EqualFold(strings.TrimSpace(technology), skill) {
+			return true
+		}
+	}
+	return mentions(project.Description, skill)
+}
+
+// mentions reports whether text contains the skill name as a whole word.
+// Names of one or two letters, such as "Go" or "R", must match case exactly
+// to avoid matching ordinary words.
+func mentions(text, skill string) bool {
+	haystack, needle := text, skill
+	if len([]rune(skill)) > 2 {
+		haystack, needle = strings.ToLower(text), strings.ToLower(skill)
+	}
+
+	for offset := 0; ; {
+		i := strings.Index(haystack[offset:], needle)
+		if i < 0 {
+			return false
+		}
+		start := offset + i
+		end := start + len(needle)
+		if isBoundary(haystack, start-1) && isBoundary(haystack, end) {
+			return true
+		}
+		offset = start + 1
+	}
+}
+
+func isBoundary(text string, i int) bool {
+	if i < 0 || i >= len(text) {
+		return true
+	}
+	c := rune(text[i])
+	return c < 0x80 && !unicode.IsLetter(c) && !unicode.IsDigit(c) && c != '+' && c != '#'
+}
+
+func months(d time.Duration) int {
+	return int(math.Round(d.Hours() / 24 / (daysPerYear / 12)))
+}
+
+func laterOf(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func earlierOf(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
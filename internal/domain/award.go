@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Award is an honor, prize or scholarship
+type Award struct {
+	ID          uuid.UUID `json:"id"`
+	Title       string    `json:"title"`
+	Awarder     string    `json:"awarder,omitempty"` // e.g. the university for a dean's list
+	Date        string    `json:"date"`              // Format: YYYY-MM-DD
+	Description string    `json:"description,omitempty"`
+	URL         string    `json:"url,omitempty"`
+}
+
+func (a *Award) Validate() error {
+	if strings.TrimSpace(a.Title) == "" {
+		return NewValidationError("title", "Award title is required", ErrInvalidField)
+	}
+	if strings.TrimSpace(a.Date) == "" {
+		return NewValidationError("date", "Date is required", ErrInvalidField)
+	}
+	if _, err := time.Parse("2006-01-02", a.Date); err != nil {
+		return NewValidationError("date", "Invalid date format (must be YYYY-MM-DD)", ErrInvalidField)
+	}
+
+	if a.URL != "" {
+		if _, err := url.ParseRequestURI(a.URL); err != nil {
+			return NewValidationError("url", "Invalid URL", ErrInvalidField)
+		}
+	}
+
+	return nil
+}
+
+func (a *Award) BeforeSave() {
+	a.Title = strings.TrimSpace(a.Title)
+	a.Awarder = strings.TrimSpace(a.Awarder)
+	a.Date = strings.TrimSpace(a.Date)
+	a.Description = strings.TrimSpace(a.Description)
+	a.URL = strings.TrimSpace(a.URL)
+}
+
+func (a *Award) ToJSON() ([]byte, error) {
+	return json.Marshal(a)
+}
+
+func (a *Award) FromJSON(data []byte) error {
+	return json.Unmarshal(data, a)
+}
@@ -2,18 +2,23 @@ package domain
 
 import (
 	"encoding/json"
+	"github.com/google/uuid"
 	"net/url"
 	"strings"
-	"time"
 )
 
+// NoExpirationLabel is accepted as the expiry date of a certification that
+// does not expire, which is stored as an empty date
+const NoExpirationLabel = "No Expiration"
+
 type Certification struct {
-	Name         string `json:"name"`
-	Issuer       string `json:"issuer"`
-	IssueDate    string `json:"issue_date"`            // Format: YYYY-MM-DD
-	ExpiryDate   string `json:"expiry_date,omitempty"` // Format: YYYY-MM-DD or "No Expiration"
-	CredentialID string `json:"credential_id,omitempty"`
-	URL          string `json:"url,omitempty"`
+	ID           uuid.UUID   `json:"id"`
+	Name         string      `json:"name"`
+	Issuer       string      `json:"issuer"`
+	IssueDate    PartialDate `json:"issue_date"`           // Format: YYYY, YYYY-MM or YYYY-MM-DD
+	ExpiryDate   PartialDate `json:"expiry_date,omitzero"` // Format: YYYY, YYYY-MM or YYYY-MM-DD, empty if it does not expire
+	CredentialID string      `json:"credential_id,omitempty"`
+	URL          string      `json:"url,omitempty"`
 }
 
 func (c *Certification) Validate() error {
@@ -23,27 +28,16 @@ func (c *Certification) Validate() error {
 	if strings.TrimSpace(c.Issuer) == "" {
 		return NewValidationError("issuer", "Issuer is required", ErrInvalidField)
 	}
-	if strings.TrimSpace(c.IssueDate) == "" {
-		return NewValidationError("issue_date", "Issue date is required", ErrInvalidField)
-	}
 
 	// Validate dates
-	if c.IssueDate != "" {
-		issueDate, err := time.Parse("2006-01-02", c.IssueDate)
-		if err != nil {
-			return NewValidationError("issue_date", "Invalid issue date format (must be YYYY-MM-DD)", ErrInvalidField)
-		}
-
-		if c.ExpiryDate != "" && c.ExpiryDate != "No Expiration" {
-			expiryDate, err := time.Parse("2006-01-02", c.ExpiryDate)
-			if err != nil {
-				return NewValidationError("expiry_date", "Invalid expiry date format (must be YYYY-MM-DD or 'No Expiration')", ErrInvalidField)
-			}
-
-			if expiryDate.Before(issueDate) {
-				return NewValidationError("expiry_date", "Expiry date must be after issue date", ErrDateRange)
-			}
-		}
+	if err := validateDate("issue_date", "issue date", c.IssueDate, true, false); err != nil {
+		return err
+	}
+	if err := validateDate("expiry_date", "expiry date", c.ExpiryDate, false, false); err != nil {
+		return err
+	}
+	if !c.ExpiryDate.IsZero() && c.ExpiryDate.Before(c.IssueDate) {
+		return NewValidationError("expiry_date", "Expiry date must be after issue date", ErrDateRange)
 	}
 
 	if c.URL != "" {
@@ -58,8 +52,6 @@ func (c *Certification) Validate() error {
 func (c *Certification) BeforeSave() {
 	c.Name = strings.TrimSpace(c.Name)
 	c.Issuer = strings.TrimSpace(c.Issuer)
-	c.IssueDate = strings.TrimSpace(c.IssueDate)
-	c.ExpiryDate = strings.TrimSpace(c.ExpiryDate)
 	c.CredentialID = strings.TrimSpace(c.CredentialID)
 	c.URL = strings.TrimSpace(c.URL)
 }
@@ -71,3 +63,22 @@ func (c *Certification) ToJSON() ([]byte, error) {
 func (c *Certification) FromJSON(data []byte) error {
 	return json.Unmarshal(data, c)
 }
+
+// UnmarshalJSON accepts "No Expiration" in place of an expiry date
+func (c *Certification) UnmarshalJSON(data []byte) error {
+	type certification Certification
+	aux := struct {
+		*certification
+		ExpiryDate string `json:"expiry_date"`
+	}{certification: (*certification)(c)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	c.ExpiryDate = PartialDate{}
+	if !strings.EqualFold(strings.TrimSpace(aux.ExpiryDate), NoExpirationLabel) {
+		c.ExpiryDate = parseLenient(aux.ExpiryDate)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// CustomSection is a user-named section such as "Publications" or "Talks"
+// for content the fixed sections do not cover
+type CustomSection struct {
+	ID       uuid.UUID             `json:"id"`
+	Title    string                `json:"title"`
+	Position int                   `json:"position"`
+	Entries  []*CustomSectionEntry `json:"entries"`
+}
+
+type CustomSectionEntry struct {
+	ID        uuid.UUID `json:"id"`
+	Title     string    `json:"title"`
+	Subtitle  string    `json:"subtitle,omitempty"`
+	StartDate string    `json:"start_date,omitempty"` // Format: YYYY-MM-DD
+	EndDate   string    `json:"end_date,omitempty"`   // Format: YYYY-MM-DD or "Present"
+	URL       string    `json:"url,omitempty"`
+	Bullets   []string  `json:"bullets,omitempty"`
+	Position  int       `json:"position"`
+}
+
+func (s *CustomSection) Validate() error {
+	if strings.TrimSpace(s.Title) == "" {
+		return NewValidationError("title", "Section title is required", ErrInvalidField)
+	}
+	if len(s.Title) > 100 {
+		return NewValidationError("title", "Section title must be at most 100 characters", ErrInvalidField)
+	}
+
+	for i, entry := range s.Entries {
+		if entry == nil {
+			return NewValidationError(fmt.Sprintf("entries[%d]", i), "Entry is empty", ErrInvalidField)
+		}
+		if err := entry.Validate(); err != nil {
+			var validationErr *ValidationError
+			if errors.As(err, &validationErr) {
+				return NewValidationError(fmt.Sprintf("entries[%d].%s", i, validationErr.Field), validationErr.Message, validationErr.Err)
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *CustomSection) BeforeSave() {
+	s.Title = strings.TrimSpace(s.Title)
+	for _, entry := range s.Entries {
+		if entry != nil {
+			entry.BeforeSave()
+		}
+	}
+}
+
+func (e *CustomSectionEntry) Validate() error {
+	if strings.TrimSpace(e.Title) == "" {
+		return NewValidationError("title", "Entry title is required", ErrInvalidField)
+	}
+
+	if e.StartDate != "" {
+		if _, err := time.Parse("2006-01-02", e.StartDate); err != nil {
+			return NewValidationError("start_date", "Invalid start date format (must be YYYY-MM-DD)", ErrInvalidField)
+		}
+	}
+
+	if e.EndDate != "" && e.EndDate != "Present" {
+		endDate, err := time.Parse("2006-01-02", e.EndDate)
+		if err != nil {
+			return NewValidationError("end_date", "Invalid end date format (must be YYYY-MM-DD or 'Present')", ErrInvalidField)
+		}
+		if e.StartDate != "" {
+			startDate, _ := time.Parse("2006-01-02", e.StartDate)
+			if endDate.Before(startDate) {
+				return NewValidationError("end_date", "End date must be after start date", ErrDateRange)
+			}
+		}
+	}
+
+	if e.EndDate == "Present" && e.StartDate == "" {
+		return NewValidationError("start_date", "Start date is required when the end date is 'Present'", ErrInvalidField)
+	}
+
+	if e.URL != "" {
+		if _, err := url.ParseRequestURI(e.URL); err != nil {
+			return NewValidationError("url", "Invalid URL", ErrInvalidField)
+		}
+	}
+
+	return nil
+}
+
+func (e *CustomSectionEntry) BeforeSave() {
+	e.Title = strings.TrimSpace(e.Title)
+	e.Subtitle = strings.TrimSpace(e.Subtitle)
+	e.StartDate = strings.TrimSpace(e.StartDate)
+	e.EndDate = strings.TrimSpace(e.EndDate)
+	e.URL = strings.TrimSpace(e.URL)
+
+	filteredBullets := make([]string, 0, len(e.Bullets))
+	for _, bullet := range e.Bullets {
+		if bullet = strings.TrimSpace(bullet); bullet != "" {
+			filteredBullets = append(filteredBullets, bullet)
+		}
+	}
+	e.Bullets = filteredBullets
+}
+
+func (s *CustomSection) ToJSON() ([]byte, error) {
+	return json.Marshal(s)
+}
+
+func (s *CustomSection) FromJSON(data []byte) error {
+	return json.Unmarshal(data, s)
+}
@@ -0,0 +1,347 @@
+package domain
+
+import (
+	"cmp"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PresentLabel marks the end of a period that is still ongoing
+const PresentLabel = "Present"
+
+var ErrInvalidDate = errors.New("invalid date, must be YYYY, YYYY-MM, YYYY-MM-DD or 'Present'")
+
+// DatePrecision tells how much of a PartialDate is known
+type DatePrecision int
+
+const (
+	PrecisionNone DatePrecision = iota
+	PrecisionYear
+	PrecisionMonth
+	PrecisionDay
+)
+
+// PartialDate is a date known to the year, the month or the day, as most
+// people remember when they started a job but not the exact day. It can also
+// be "Present", the end of a period that is still ongoing. The zero value is
+// an empty date.
+//
+// Dates are written as "2019", "2019-03", "2019-03-14" or "Present" in JSON
+// and in the database. Input that does not parse is kept so Validate methods
+// can report it against the right field instead of failing the whole request.
+type PartialDate struct {
+	year    int
+	month   time.Month
+	day     int
+	present bool
+	invalid string
+}
+
+// NewYear returns a date known to the year
+func NewYear(year int) PartialDate {
+	return PartialDate{year: year}
+}
+
+// NewYearMonth returns a date known to the month
+func NewYearMonth(year int, month time.Month) PartialDate {
+	return PartialDate{year: year, month: month}
+}
+
+// NewDate returns a date known to the day
+func NewDate(year int, month time.Month, day int) PartialDate {
+	return PartialDate{year: year, month: month, day: day}
+}
+
+// Present returns the end of an ongoing period
+func Present() PartialDate {
+	return PartialDate{present: true}
+}
+
+// ParsePartialDate parses "YYYY", "YYYY-MM", "YYYY-MM-DD" or "Present". An
+// empty string is the zero date.
+func ParsePartialDate(value string) (PartialDate, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return PartialDate{}, nil
+	}
+	if strings.EqualFold(value, PresentLabel) {
+		return Present(), nil
+	}
+
+	parts := strings.Split(value, "-")
+	if len(parts) > 3 || len(parts[0]) != 4 {
+		return PartialDate{}, ErrInvalidDate
+	}
+	numbers := make([]int, len(parts))
+	for i, part := range parts {
+		if i > 0 && len(part) != 2 {
+			return PartialDate{}, ErrInvalidDate
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return PartialDate{}, ErrInvalidDate
+		}
+		numbers[i] = n
+	}
+
+	var date PartialDate
+	switch len(numbers) {
+	case 1:
+		date = NewYear(numbers[0])
+	case 2:
+		date = NewYearMonth(numbers[0], time.Month(numbers[1]))
+	default:
+		date = NewDate(numbers[0], time.Month(numbers[1]), numbers[2])
+	}
+	if !date.valid() {
+		return PartialDate{}, ErrInvalidDate
+	}
+	return date, nil
+}
+
+// valid checks the ranges of the known components
+func (d PartialDate) valid() bool {
+	if d.year < 1 || d.year > 9999 {
+		return false
+	}
+	if d.month == 0 {
+		return d.day == 0
+	}
+	if d.month < time.January || d.month > time.December {
+		return false
+	}
+	if d.day == 0 {
+		return true
+	}
+	// time.Date normalizes overflowing days, e.g. February 30 into March
+	return time.Date(d.year, d.month, d.day, 0, 0, 0, 0, time.UTC).Day() == d.day
+}
+
+func (d PartialDate) Year() int {
+	return d.year
+}
+
+func (d PartialDate) Month() time.Month {
+	return d.month
+}
+
+func (d PartialDate) Day() int {
+	return d.day
+}
+
+// IsZero reports whether the date is empty. Unparsed input is not empty.
+func (d PartialDate) IsZero() bool {
+	return d == PartialDate{}
+}
+
+func (d PartialDate) IsPresent() bool {
+	return d.present
+}
+
+// IsValid reports whether the date is empty or was parsed successfully
+func (d PartialDate) IsValid() bool {
+	return d.invalid == ""
+}
+
+// Precision returns how much of the date is known. Present and invalid
+// dates have no precision.
+func (d PartialDate) Precision() DatePrecision {
+	switch {
+	case d.year == 0:
+		return PrecisionNone
+	case d.month == 0:
+		return PrecisionYear
+	case d.day == 0:
+		return PrecisionMonth
+	default:
+		return PrecisionDay
+	}
+}
+
+// String returns the ISO form used in JSON and the database
+func (d PartialDate) String() string {
+	switch {
+	case d.invalid != "":
+		return d.invalid
+	case d.present:
+		return PresentLabel
+	}
+	switch d.Precision() {
+	case PrecisionYear:
+		return fmt.Sprintf("%04d", d.year)
+	case PrecisionMonth:
+		return fmt.Sprintf("%04d-%02d", d.year, d.month)
+	case PrecisionDay:
+		return fmt.Sprintf("%04d-%02d-%02d", d.year, d.month, d.day)
+	}
+	return ""
+}
+
+// Compare orders two dates at the precision both of them have, so "2019"
+// equals "2019-03" while "2019-02" comes before "2019-03-14". Present comes
+// after every date and empty dates come before every date.
+func (d PartialDate) Compare(other PartialDate) int {
+	switch {
+	case d.present || other.present:
+		return compareBool(d.present, other.present)
+	case d.year != other.year:
+		return cmp.Compare(d.year, other.year)
+	case d.month == 0 || other.month == 0:
+		return 0
+	case d.month != other.month:
+		return cmp.Compare(int(d.month), int(other.month))
+	case d.day == 0 || other.day == 0:
+		return 0
+	default:
+		return cmp.Compare(d.day, other.day)
+	}
+}
+
+// Before reports whether the date lies strictly before the other one
+func (d PartialDate) Before(other PartialDate) bool {
+	return d.Compare(other) < 0
+}
+
+// FirstDay returns the first day covered by the date, e.g. March 1 for "2019-03"
+func (d PartialDate) FirstDay() time.Time {
+	month, day := d.month, d.day
+	if month == 0 {
+		month = time.January
+	}
+	if day == 0 {
+		day = 1
+	}
+	return time.Date(d.year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// LastDay returns the last day covered by the date, e.g. March 31 for "2019-03"
+func (d PartialDate) LastDay() time.Time {
+	switch d.Precision() {
+	case PrecisionYear:
+		return time.Date(d.year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	case PrecisionMonth:
+		return time.Date(d.year, d.month+1, 0, 0, 0, 0, 0, time.UTC)
+	}
+	return d.FirstDay()
+}
+
+func (d PartialDate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *PartialDate) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*d = PartialDate{}
+		return nil
+	}
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*d = parseLenient(value)
+	return nil
+}
+
+// Value stores the ISO form, or NULL for an empty date
+func (d PartialDate) Value() (driver.Value, error) {
+	if d.IsZero() {
+		return nil, nil
+	}
+	if !d.IsValid() {
+		return nil, ErrInvalidDate
+	}
+	return d.String(), nil
+}
+
+func (d *PartialDate) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*d = PartialDate{}
+	case time.Time:
+		*d = NewDate(v.Year(), v.Month(), v.Day())
+	case string:
+		return d.scanString(v)
+	case []byte:
+		return d.scanString(string(v))
+	default:
+		return fmt.Errorf("cannot scan %T into PartialDate", src)
+	}
+	return nil
+}
+
+func (d *PartialDate) scanString(value string) error {
+	date, err := ParsePartialDate(value)
+	if err != nil {
+		return fmt.Errorf("scan date %q: %w", value, err)
+	}
+	*d = date
+	return nil
+}
+
+// parseLenient parses a date and keeps the input when it is invalid
+func parseLenient(value string) PartialDate {
+	date, err := ParsePartialDate(value)
+	if err != nil {
+		return PartialDate{invalid: strings.TrimSpace(value)}
+	}
+	return date
+}
+
+// validateDate checks a single date field. Present is only accepted where
+// allowPresent is set, i.e. for the end of a period.
+func validateDate(field, label string, date PartialDate, required, allowPresent bool) error {
+	if !date.IsValid() {
+		format := "YYYY, YYYY-MM or YYYY-MM-DD"
+		if allowPresent {
+			format += " or 'Present'"
+		}
+		return NewValidationError(field, fmt.Sprintf("Invalid %s format (must be %s)", label, format), ErrInvalidField)
+	}
+	if date.IsZero() {
+		if required {
+			return NewValidationError(field, fmt.Sprintf("%s is required", capitalize(label)), ErrInvalidField)
+		}
+		return nil
+	}
+	if date.IsPresent() && !allowPresent {
+		return NewValidationError(field, fmt.Sprintf("%s cannot be 'Present'", capitalize(label)), ErrInvalidField)
+	}
+	return nil
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	}
+	return -1
+}
+
+// validatePeriod checks the start_date and end_date fields of an entry. The
+// end may be "Present" and must not lie before the start.
+func validatePeriod(start, end PartialDate, startRequired bool) error {
+	if err := validateDate("start_date", "start date", start, startRequired, false); err != nil {
+		return err
+	}
+	if err := validateDate("end_date", "end date", end, false, true); err != nil {
+		return err
+	}
+	if !start.IsZero() && !end.IsZero() && end.Before(start) {
+		return NewValidationError("end_date", "End date must be after start date", ErrDateRange)
+	}
+	return nil
+}
@@ -2,18 +2,19 @@ package domain
 
 import (
 	"encoding/json"
+	"github.com/google/uuid"
 	"strings"
-	"time"
 )
 
 type Education struct {
-	Institution string `json:"institution"`
-	Location    string `json:"location"`
-	Degree      string `json:"degree"`
-	Field       string `json:"field"`
-	StartDate   string `json:"start_date"` // Format: YYYY-MM-DD
-	EndDate     string `json:"end_date"`   // Format: YYYY-MM-DD or "Present"
-	Description string `json:"description"`
+	ID          uuid.UUID   `json:"id"`
+	Institution string      `json:"institution"`
+	Location    string      `json:"location"`
+	Degree      string      `json:"degree"`
+	Field       string      `json:"field"`
+	StartDate   PartialDate `json:"start_date"` // Format: YYYY, YYYY-MM or YYYY-MM-DD
+	EndDate     PartialDate `json:"end_date"`   // Format: YYYY, YYYY-MM, YYYY-MM-DD or "Present"
+	Description string      `json:"description"`
 }
 
 func (e *Education) Validate() error {
@@ -23,28 +24,8 @@ func (e *Education) Validate() error {
 	if strings.TrimSpace(e.Degree) == "" {
 		return NewValidationError("degree", "degree is required", ErrInvalidField)
 	}
-	if strings.TrimSpace(e.StartDate) == "" {
-		return NewValidationError("start_date", "start date is required", ErrInvalidField)
-	}
-
-	if e.StartDate != "" {
-		startDate, err := time.Parse("2006-01-02", e.StartDate)
-		if err != nil {
-			return NewValidationError("start_date", "Invalid start date format (must be YYYY-MM-DD)", ErrInvalidField)
-		}
 
-		if e.EndDate != "" && e.EndDate != "Present" {
-			endDate, err := time.Parse("2006-01-02", e.EndDate)
-			if err != nil {
-				return NewValidationError("end_date", "Invalid end date format (must be YYYY-MM-DD or 'Present')", ErrInvalidField)
-			}
-
-			if endDate.Before(startDate) {
-				return NewValidationError("end_date", "End date must be after start date", ErrDateRange)
-			}
-		}
-	}
-	return nil
+	return validatePeriod(e.StartDate, e.EndDate, true)
 }
 
 func (e *Education) BeforeSave() {
@@ -52,8 +33,6 @@ func (e *Education) BeforeSave() {
 	e.Location = strings.TrimSpace(e.Location)
 	e.Degree = strings.TrimSpace(e.Degree)
 	e.Field = strings.TrimSpace(e.Field)
-	e.StartDate = strings.TrimSpace(e.StartDate)
-	e.EndDate = strings.TrimSpace(e.EndDate)
 	e.Description = strings.TrimSpace(e.Description)
 }
 
@@ -2,18 +2,19 @@ package domain
 
 import (
 	"encoding/json"
+	"github.com/google/uuid"
 	"strings"
-	"time"
 )
 
 type Experience struct {
-	Employer     string   `json:"employer"`
-	JobTitle     string   `json:"title"`
-	Location     string   `json:"location"`
-	StartDate    string   `json:"start_date"` // Format: YYYY-MM-DD
-	EndDate      string   `json:"end_date"`   // Format: YYYY-MM-DD or "Present"
-	Description  string   `json:"description"`
-	Achievements []string `json:"achievements,omitempty"`
+	ID           uuid.UUID   `json:"id"`
+	Employer     string      `json:"employer"`
+	JobTitle     string      `json:"title"`
+	Location     string      `json:"location"`
+	StartDate    PartialDate `json:"start_date"` // Format: YYYY, YYYY-MM or YYYY-MM-DD
+	EndDate      PartialDate `json:"end_date"`   // Format: YYYY, YYYY-MM, YYYY-MM-DD or "Present"
+	Description  string      `json:"description"`
+	Achievements []string    `json:"achievements,omitempty"`
 }
 
 func (e *Experience) Validate() error {
@@ -24,32 +25,9 @@ func (e *Experience) Validate() error {
 	if strings.TrimSpace(e.JobTitle) == "" {
 		return NewValidationError("title", "Job title is required", ErrInvalidField)
 	}
-	if strings.TrimSpace(e.StartDate) == "" {
-		return NewValidationError("start_date", "Start date is required", ErrInvalidField)
-	}
 
 	// Validate dates
-	if e.StartDate != "" {
-		startDate, err := time.Parse("2006-01-02", e.StartDate)
-		if err != nil {
-			return NewValidationError("start_date", "Invalid start date format (must be YYYY-MM-DD)", ErrInvalidField)
-		}
-
-		// Validate end date if provided and not "Present"
-		if e.EndDate != "" && e.EndDate != "Present" {
-			endDate, err := time.Parse("2006-01-02", e.EndDate)
-			if err != nil {
-				return NewValidationError("end_date", "Invalid end date format (must be YYYY-MM-DD or 'Present')", ErrInvalidField)
-			}
-
-			// End date must be after start date
-			if endDate.Before(startDate) {
-				return NewValidationError("end_date", "End date must be after start date", ErrDateRange)
-			}
-		}
-	}
-
-	return nil
+	return validatePeriod(e.StartDate, e.EndDate, true)
 }
 
 // BeforeSave sanitizes the data before saving
@@ -57,8 +35,6 @@ func (e *Experience) BeforeSave() {
 	e.Employer = strings.TrimSpace(e.Employer)
 	e.JobTitle = strings.TrimSpace(e.JobTitle)
 	e.Location = strings.TrimSpace(e.Location)
-	e.StartDate = strings.TrimSpace(e.StartDate)
-	e.EndDate = strings.TrimSpace(e.EndDate)
 	e.Description = strings.TrimSpace(e.Description)
 
 	// Trim achievements
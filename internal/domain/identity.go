@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"time"
+)
+
+// UserIdentity links an account at an external identity provider, such as
+// GitHub or Google, to a user
+type UserIdentity struct {
+	ID       uuid.UUID `json:"id" db:"id"`
+	UserID   uuid.UUID `json:"-" db:"user_id"`
+	Provider string    `json:"provider" db:"provider"`
+	// Subject is the provider's ID of the account
+	Subject     string     `json:"-" db:"subject"`
+	Email       string     `json:"email,omitempty" db:"email"`
+	Username    string     `json:"username,omitempty" db:"username"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty" db:"last_login_at"`
+}
+
+// OAuthState is kept between sending the user to the provider and the
+// callback
+type OAuthState struct {
+	Provider     string `json:"provider"`
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"code_verifier"`
+}
+
+type UserIdentityRepository interface {
+	GetIdentity(ctx context.Context, provider, subject string) (*UserIdentity, error)
+	GetIdentitiesByUser(ctx context.Context, userId uuid.UUID) ([]*UserIdentity, error)
+	// CreateIdentity links an identity to an existing user, ErrConflict if
+	// it is linked already
+	CreateIdentity(ctx context.Context, identity *UserIdentity) error
+	// CreateUserWithIdentity creates a user that signs in through the
+	// identity only. ErrConflict if the email or the identity is taken.
+	CreateUserWithIdentity(ctx context.Context, user *User, identity *UserIdentity) error
+	// UpdateIdentityLogin records a login and the profile the provider sent
+	UpdateIdentityLogin(ctx context.Context, identity *UserIdentity) error
+	DeleteIdentity(ctx context.Context, userId, id uuid.UUID) error
+}
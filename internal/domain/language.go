@@ -0,0 +1,283 @@
+package domain
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"strings"
+)
+
+// LanguageLevelNative marks a mother tongue; other levels follow the Common
+// European Framework of Reference (CEFR)
+const LanguageLevelNative = "native"
+
+var ValidLanguageLevels = map[string]bool{
+	"A1":                true,
+	"A2":                true,
+	"B1":                true,
+	"B2":                true,
+	"C1":                true,
+	"C2":                true,
+	LanguageLevelNative: true,
+}
+
+// Language is a spoken language with its level
+type Language struct {
+	ID          uuid.UUID `json:"id"`
+	Code        string    `json:"code"`  // ISO 639-1, e.g. "de"
+	Level       string    `json:"level"` // A1-C2 or "native"
+	Certificate string    `json:"certificate,omitempty"`
+}
+
+func (l *Language) Validate() error {
+	if l.Code == "" {
+		return NewValidationError("code", "Language code is required", ErrInvalidField)
+	}
+	if _, ok := languageNames[l.Code]; !ok {
+		return NewValidationError("code", "Invalid language code, must be an ISO 639-1 code such as \"en\"", ErrInvalidField)
+	}
+
+	if l.Level == "" {
+		return NewValidationError("level", "Language level is required", ErrInvalidField)
+	}
+	if !ValidLanguageLevels[l.Level] {
+		return NewValidationError("level", "Invalid level, must be one of: A1, A2, B1, B2, C1, C2, native", ErrInvalidField)
+	}
+
+	if len(l.Certificate) > 200 {
+		return NewValidationError("certificate", "Certificate must be at most 200 characters", ErrInvalidField)
+	}
+
+	return nil
+}
+
+// BeforeSave normalizes the code to lower case and CEFR levels to upper case
+func (l *Language) BeforeSave() {
+	l.Code = strings.ToLower(strings.TrimSpace(l.Code))
+	l.Level = strings.TrimSpace(l.Level)
+	if strings.EqualFold(l.Level, LanguageLevelNative) {
+		l.Level = LanguageLevelNative
+	} else {
+		l.Level = strings.ToUpper(l.Level)
+	}
+	l.Certificate = strings.TrimSpace(l.Certificate)
+}
+
+// Name returns the English name of the language, or the code when unknown
+func (l *Language) Name() string {
+	if name, ok := languageNames[l.Code]; ok {
+		return name
+	}
+	return l.Code
+}
+
+func (l *Language) ToJSON() ([]byte, error) {
+	return json.Marshal(l)
+}
+
+func (l *Language) FromJSON(data []byte) error {
+	return json.Unmarshal(data, l)
+}
+
+// LanguageName returns the English name for an ISO 639-1 code
+func LanguageName(code string) (string, bool) {
+	name, ok := languageNames[strings.ToLower(strings.TrimSpace(code))]
+	return name, ok
+}
+
+// LanguageCodeForName returns the ISO 639-1 code for an English language name
+func LanguageCodeForName(name string) (string, bool) {
+	name = strings.TrimSpace(name)
+	for code, known := range languageNames {
+		if strings.EqualFold(known, name) {
+			return code, true
+		}
+	}
+	return "", false
+}
+
+// languageNames lists the ISO 639-1 codes with their English names
+var languageNames = map[string]string{
+	"aa": "Afar",
+	"ab": "Abkhazian",
+	"ae": "Avestan",
+	"af": "Afrikaans",
+	"ak": "Akan",
+	"am": "Amharic",
+	"an": "Aragonese",
+	"ar": "Arabic",
+	"as": "Assamese",
+	"av": "Avaric",
+	"ay": "Aymara",
+	"az": "Azerbaijani",
+	"ba": "Bashkir",
+	"be": "Belarusian",
+	"bg": "Bulgarian",
+	"bi": "Bislama",
+	"bm": "Bambara",
+	"bn": "Bengali",
+	"bo": "Tibetan",
+	"br": "Breton",
+	"bs": "Bosnian",
+	"ca": "Catalan",
+	"ce": "Chechen",
+	"ch": "Chamorro",
+	"co": "Corsican",
+	"cr": "Cree",
+	"cs": "Czech",
+	"cu": "Church Slavic",
+	"cv": "Chuvash",
+	"cy": "Welsh",
+	"da": "Danish",
+	"de": "German",
+	"dv": "Divehi",
+	"dz": "Dzongkha",
+	"ee": "Ewe",
+	"el": "Greek",
+	"en": "English",
+	"eo": "Esperanto",
+	"es": "Spanish",
+	"et": "Estonian",
+	"eu": "Basque",
+	"fa": "Persian",
+	"ff": "Fulah",
+	"fi": "Finnish",
+	"fj": "Fijian",
+	"fo": "Faroese",
+	"fr": "French",
+	"fy": "Western Frisian",
+	"ga": "Irish",
+	"gd": "Scottish Gaelic",
+	"gl": "Galician",
+	"gn": "Guarani",
+	"gu": "Gujarati",
+	"gv": "Manx",
+	"ha": "Hausa",
+	"he": "Hebrew",
+	"hi": "Hindi",
+	"ho": "Hiri Motu",
+	"hr": "Croatian",
+	"ht": "Haitian",
+	"hu": "Hungarian",
+	"hy": "Armenian",
+	"hz": "Herero",
+	"ia": "Interlingua",
+	"id": "Indonesian",
+	"ie": "Interlingue",
+	"ig": "Igbo",
+	"ii": "Sichuan Yi",
+	"ik": "Inupiaq",
+	"io": "Ido",
+	"is": "Icelandic",
+	"it": "Italian",
+	"iu": "Inuktitut",
+	"ja": "Japanese",
+	"jv": "Javanese",
+	"ka": "Georgian",
+	"kg": "Kongo",
+	"ki": "Kikuyu",
+	"kj": "Kuanyama",
+	"kk": "Kazakh",
+	"kl": "Kalaallisut",
+	"km": "Khmer",
+	"kn": "Kannada",
+	"ko": "Korean",
+	"kr": "Kanuri",
+	"ks": "Kashmiri",
+	"ku": "Kurdish",
+	"kv": "Komi",
+	"kw": "Cornish",
+	"ky": "Kyrgyz",
+	"la": "Latin",
+	"lb": "Luxembourgish",
+	"lg": "Ganda",
+	"li": "Limburgish",
+	"ln": "Lingala",
+	"lo": "Lao",
+	"lt": "Lithuanian",
+	"lu": "Luba-Katanga",
+	"lv": "Latvian",
+	"mg": "Malagasy",
+	"mh": "Marshallese",
+	"mi": "Maori",
+	"mk": "Macedonian",
+	"ml": "Malayalam",
+	"mn": "Mongolian",
+	"mr": "Marathi",
+	"ms": "Malay",
+	"mt": "Maltese",
+	"my": "Burmese",
+	"na": "Nauru",
+	"nb": "Norwegian Bokmål",
+	"nd": "North Ndebele",
+	"ne": "Nepali",
+	"ng": "Ndonga",
+	"nl": "Dutch",
+	"nn": "Norwegian Nynorsk",
+	"no": "Norwegian",
+	"nr": "South Ndebele",
+	"nv": "Navajo",
+	"ny": "Chichewa",
+	"oc": "Occitan",
+	"oj": "Ojibwa",
+	"om": "Oromo",
+	"or": "Oriya",
+	"os": "Ossetian",
+	"pa": "Punjabi",
+	"pi": "Pali",
+	"pl": "Polish",
+	"ps": "Pashto",
+	"pt": "Portuguese",
+	"qu": "Quechua",
+	"rm": "Romansh",
+	"rn": "Rundi",
+	"ro": "Romanian",
+	"ru": "Russian",
+	"rw": "Kinyarwanda",
+	"sa": "Sanskrit",
+	"sc": "Sardinian",
+	"sd": "Sindhi",
+	"se": "Northern Sami",
+	"sg": "Sango",
+	"si": "Sinhala",
+	"sk": "Slovak",
+	"sl": "Slovenian",
+	"sm": "Samoan",
+	"sn": "Shona",
+	"so": "Somali",
+	"sq": "Albanian",
+	"sr": "Serbian",
+	"ss": "Swati",
+	"st": "Southern Sotho",
+	"su": "Sundanese",
+	"sv": "Swedish",
+	"sw": "Swahili",
+	"ta": "Tamil",
+	"te": "Telugu",
+	"tg": "Tajik",
+	"th": "Thai",
+	"ti": "Tigrinya",
+	"tk": "Turkmen",
+	"tl": "Tagalog",
+	"tn": "Tswana",
+	"to": "Tonga",
+	"tr": "Turkish",
+	"ts": "Tsonga",
+	"tt": "Tatar",
+	"tw": "Twi",
+	"ty": "Tahitian",
+	"ug": "Uyghur",
+	"uk": "Ukrainian",
+	"ur": "Urdu",
+	"uz": "Uzbek",
+	"ve": "Venda",
+	"vi": "Vietnamese",
+	"vo": "Volapük",
+	"wa": "Walloon",
+	"wo": "Wolof",
+	"xh": "Xhosa",
+	"yi": "Yiddish",
+	"yo": "Yoruba",
+	"za": "Zhuang",
+	"zh": "Chinese",
+	"zu": "Zulu",
+}
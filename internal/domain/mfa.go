@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"time"
+)
+
+// TOTPEnrollment is the authenticator app of a user. It only protects logins
+// once confirmed with a first valid code.
+type TOTPEnrollment struct {
+	UserID uuid.UUID `json:"-" db:"user_id"`
+	// Secret is encrypted, see security.Encryptor
+	Secret      string     `json:"-" db:"secret"`
+	ConfirmedAt *time.Time `json:"confirmed_at,omitempty" db:"confirmed_at"`
+	// LastUsedStep is the TOTP time step of the last accepted code, codes
+	// of this or earlier steps are rejected as replays
+	LastUsedStep int64     `json:"-" db:"last_used_step"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+func (e *TOTPEnrollment) IsConfirmed() bool {
+	return e.ConfirmedAt != nil
+}
+
+type MFARepository interface {
+	// SaveTOTP stores a new unconfirmed enrollment, replacing an earlier
+	// unconfirmed one. ErrConflict if a confirmed enrollment exists.
+	SaveTOTP(ctx context.Context, enrollment *TOTPEnrollment) error
+	GetTOTP(ctx context.Context, userId uuid.UUID) (*TOTPEnrollment, error)
+	// UseTOTPStep records an accepted code and confirms the enrollment.
+	// ErrConflict if the step is not newer than the last used one.
+	UseTOTPStep(ctx context.Context, userId uuid.UUID, step int64) error
+	// DeleteTOTP removes the enrollment together with the recovery codes
+	DeleteTOTP(ctx context.Context, userId uuid.UUID) error
+
+	// ReplaceRecoveryCodes stores the hashes of a new set of recovery codes
+	ReplaceRecoveryCodes(ctx context.Context, userId uuid.UUID, codeHashes []string) error
+	// UseRecoveryCode marks an unused code as used, ErrNotFound if there is
+	// none with the hash
+	UseRecoveryCode(ctx context.Context, userId uuid.UUID, codeHash string) error
+	CountRecoveryCodes(ctx context.Context, userId uuid.UUID) (int, error)
+}
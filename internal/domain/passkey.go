@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+)
+
+const maxPasskeyNameLength = 100
+
+// Passkey is a WebAuthn credential a user can log in with instead of a
+// password
+type Passkey struct {
+	ID              uuid.UUID `json:"id" db:"id"`
+	UserID          uuid.UUID `json:"-" db:"user_id"`
+	Name            string    `json:"name" db:"name"`
+	CredentialID    []byte    `json:"-" db:"credential_id"`
+	PublicKey       []byte    `json:"-" db:"public_key"`
+	AttestationType string    `json:"-" db:"attestation_type"`
+	AAGUID          []byte    `json:"-" db:"aaguid"`
+	Transports      []string  `json:"transports" db:"-"`
+	// SignCount is the last signature counter reported by the authenticator.
+	// Authenticators that do not count always report 0.
+	SignCount      int64      `json:"-" db:"sign_count"`
+	UserVerified   bool       `json:"-" db:"user_verified"`
+	BackupEligible bool       `json:"backup_eligible" db:"backup_eligible"`
+	BackupState    bool       `json:"backup_state" db:"backup_state"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	LastUsedAt     *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+}
+
+// BeforeSave trims the name and falls back to a default one
+func (p *Passkey) BeforeSave() {
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Name == "" {
+		p.Name = "Passkey"
+	}
+}
+
+func (p *Passkey) Validate() error {
+	if len([]rune(p.Name)) > maxPasskeyNameLength {
+		return NewValidationError("name", "Passkey name must be at most 100 characters", ErrInvalidField)
+	}
+	return nil
+}
+
+type PasskeyRepository interface {
+	// CreatePasskey stores a new passkey, ErrConflict if the credential is
+	// already registered
+	CreatePasskey(ctx context.Context, passkey *Passkey) error
+	GetPasskeysByUser(ctx context.Context, userId uuid.UUID) ([]*Passkey, error)
+	GetPasskeyByCredentialID(ctx context.Context, credentialId []byte) (*Passkey, error)
+	// UpdatePasskeyUsage records a login. ErrConflict if the sign count
+	// changed since it was read, i.e. a concurrent login used the passkey.
+	UpdatePasskeyUsage(ctx context.Context, passkey *Passkey, previousSignCount int64) error
+	RenamePasskey(ctx context.Context, userId, id uuid.UUID, name string) error
+	DeletePasskey(ctx context.Context, userId, id uuid.UUID) error
+}
@@ -2,6 +2,9 @@ package domain
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
 	"regexp"
 	"strings"
 )
@@ -22,7 +25,45 @@ type PersonalInfo struct {
 		City    string `json:"city"`
 		Country string `json:"country"`
 	} `json:"address"`
-	JobTitle string `json:"job_title"`
+	JobTitle string     `json:"job_title"`
+	Summary  string     `json:"summary,omitempty"`
+	Profiles []*Profile `json:"profiles,omitempty"`
+}
+
+const (
+	ProfileNetworkLinkedIn      = "linkedin"
+	ProfileNetworkGitHub        = "github"
+	ProfileNetworkGitLab        = "gitlab"
+	ProfileNetworkTelegram      = "telegram"
+	ProfileNetworkTwitter       = "twitter"
+	ProfileNetworkStackOverflow = "stackoverflow"
+	ProfileNetworkWebsite       = "website"
+	ProfileNetworkOther         = "other"
+)
+
+// profileHosts lists the hosts a profile URL of each network must point to.
+// Networks without an entry accept any host.
+var profileHosts = map[string][]string{
+	ProfileNetworkLinkedIn:      {"linkedin.com"},
+	ProfileNetworkGitHub:        {"github.com"},
+	ProfileNetworkGitLab:        {"gitlab.com"},
+	ProfileNetworkTelegram:      {"t.me", "telegram.me"},
+	ProfileNetworkTwitter:       {"twitter.com", "x.com"},
+	ProfileNetworkStackOverflow: {"stackoverflow.com"},
+	ProfileNetworkWebsite:       nil,
+	ProfileNetworkOther:         nil,
+}
+
+const (
+	maxSummaryLength = 2000
+	maxProfiles      = 20
+)
+
+// Profile is a link to an online profile or personal site
+type Profile struct {
+	Network  string `json:"network"`
+	Username string `json:"username,omitempty"`
+	URL      string `json:"url"`
 }
 
 func (p *PersonalInfo) Validate() error {
@@ -56,9 +97,89 @@ func (p *PersonalInfo) Validate() error {
 		}
 	}
 
+	if len([]rune(p.Summary)) > maxSummaryLength {
+		return NewValidationError("summary", fmt.Sprintf("Summary must be at most %d characters", maxSummaryLength), ErrInvalidField)
+	}
+
+	if len(p.Profiles) > maxProfiles {
+		return NewValidationError("profiles", fmt.Sprintf("At most %d profiles are allowed", maxProfiles), ErrInvalidField)
+	}
+	for i, profile := range p.Profiles {
+		if profile == nil {
+			return NewValidationError(fmt.Sprintf("profiles[%d]", i), "Profile is empty", ErrInvalidField)
+		}
+		if err := profile.Validate(); err != nil {
+			var validationErr *ValidationError
+			if errors.As(err, &validationErr) {
+				return NewValidationError(fmt.Sprintf("profiles[%d].%s", i, validationErr.Field), validationErr.Message, validationErr.Err)
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *Profile) Validate() error {
+	hosts, ok := profileHosts[p.Network]
+	if !ok {
+		return NewValidationError("network", "Invalid network, must be one of: linkedin, github, gitlab, telegram, twitter, stackoverflow, website, other", ErrInvalidField)
+	}
+
+	if p.URL == "" {
+		return NewValidationError("url", "URL is required", ErrInvalidField)
+	}
+	parsed, err := url.Parse(p.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return NewValidationError("url", "Invalid URL (must be an absolute http or https URL)", ErrInvalidField)
+	}
+
+	if len(hosts) > 0 && !matchesHost(parsed.Hostname(), hosts) {
+		return NewValidationError("url", fmt.Sprintf("URL must point to %s", strings.Join(hosts, " or ")), ErrInvalidField)
+	}
+
 	return nil
 }
 
+// BeforeSave normalizes the network and builds the URL of a Telegram
+// profile given only by its handle
+func (p *Profile) BeforeSave() {
+	p.Network = strings.ToLower(strings.TrimSpace(p.Network))
+	p.Username = strings.TrimPrefix(strings.TrimSpace(p.Username), "@")
+	p.URL = strings.TrimSpace(p.URL)
+
+	if p.URL == "" && p.Network == ProfileNetworkTelegram && p.Username != "" {
+		p.URL = "https://t.me/" + p.Username
+	}
+}
+
+// matchesHost reports whether host is one of hosts or a subdomain of one,
+// such as "www.linkedin.com"
+func matchesHost(host string, hosts []string) bool {
+	host = strings.ToLower(host)
+	for _, known := range hosts {
+		if host == known || strings.HasSuffix(host, "."+known) {
+			return true
+		}
+	}
+	return false
+}
+
+// ProfileNetworkForURL guesses the network of a profile URL, falling back to
+// "website"
+func ProfileNetworkForURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ProfileNetworkWebsite
+	}
+	for network, hosts := range profileHosts {
+		if len(hosts) > 0 && matchesHost(parsed.Hostname(), hosts) {
+			return network
+		}
+	}
+	return ProfileNetworkWebsite
+}
+
 func (p *PersonalInfo) BeforeSave() {
 	p.FirstName = strings.TrimSpace(p.FirstName)
 	p.LastName = strings.TrimSpace(p.LastName)
@@ -68,6 +189,12 @@ func (p *PersonalInfo) BeforeSave() {
 	p.Address.City = strings.TrimSpace(p.Address.City)
 	p.Address.Country = strings.TrimSpace(p.Address.Country)
 	p.JobTitle = strings.TrimSpace(p.JobTitle)
+	p.Summary = strings.TrimSpace(p.Summary)
+	for _, profile := range p.Profiles {
+		if profile != nil {
+			profile.BeforeSave()
+		}
+	}
 }
 
 func (p *PersonalInfo) ToJSON() ([]byte, error) {
@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"time"
+)
+
+// Photo describes the profile photo of a resume. The image itself lives in a
+// blob store; Data is only filled in when a renderer needs to embed it.
+type Photo struct {
+	ContentType  string    `json:"content_type" db:"content_type"`
+	Width        int       `json:"width" db:"width"`
+	Height       int       `json:"height" db:"height"`
+	OriginalKey  string    `json:"-" db:"original_key"`
+	ThumbnailKey string    `json:"-" db:"thumbnail_key"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+
+	// Data holds the square thumbnail for renderers
+	Data []byte `json:"-" db:"-"`
+}
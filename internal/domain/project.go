@@ -2,19 +2,20 @@ package domain
 
 import (
 	"encoding/json"
+	"github.com/google/uuid"
 	"net/url"
 	"strings"
-	"time"
 )
 
 type Project struct {
-	Name         string   `json:"name"`
-	Description  string   `json:"description"`
-	Technologies []string `json:"technologies,omitempty"`
-	RepoURL      string   `json:"repo_url,omitempty"`
-	DemoURL      string   `json:"demo_url,omitempty"`
-	StartDate    string   `json:"start_date,omitempty"` // Format: YYYY-MM-DD
-	EndDate      string   `json:"end_date,omitempty"`   // Format: YYYY-MM-DD or "Present"
+	ID           uuid.UUID   `json:"id"`
+	Name         string      `json:"name"`
+	Description  string      `json:"description"`
+	Technologies []string    `json:"technologies,omitempty"`
+	RepoURL      string      `json:"repo_url,omitempty"`
+	DemoURL      string      `json:"demo_url,omitempty"`
+	StartDate    PartialDate `json:"start_date,omitzero"` // Format: YYYY, YYYY-MM or YYYY-MM-DD
+	EndDate      PartialDate `json:"end_date,omitzero"`   // Format: YYYY, YYYY-MM, YYYY-MM-DD or "Present"
 }
 
 func (p *Project) Validate() error {
@@ -34,27 +35,7 @@ func (p *Project) Validate() error {
 		}
 	}
 
-	if p.StartDate != "" && p.StartDate != "Present" {
-		if _, err := time.Parse("2006-01-02", p.StartDate); err != nil {
-			return NewValidationError("start_date", "Invalid start date format (must be YYYY-MM-DD)", ErrInvalidField)
-		}
-	}
-
-	if p.EndDate != "" && p.EndDate != "Present" {
-		if _, err := time.Parse("2006-01-02", p.EndDate); err != nil {
-			return NewValidationError("end_date", "Invalid end date format (must be YYYY-MM-DD or 'Present')", ErrInvalidField)
-		}
-	}
-
-	if p.StartDate != "" && p.EndDate != "" && p.EndDate != "Present" {
-		startDate, _ := time.Parse("2006-01-02", p.StartDate)
-		endDate, _ := time.Parse("2006-01-02", p.EndDate)
-		if endDate.Before(startDate) {
-			return NewValidationError("end_date", "End date must be after start date", ErrDateRange)
-		}
-	}
-
-	return nil
+	return validatePeriod(p.StartDate, p.EndDate, false)
 }
 
 func (p *Project) BeforeSave() {
@@ -62,8 +43,6 @@ func (p *Project) BeforeSave() {
 	p.Description = strings.TrimSpace(p.Description)
 	p.RepoURL = strings.TrimSpace(p.RepoURL)
 	p.DemoURL = strings.TrimSpace(p.DemoURL)
-	p.StartDate = strings.TrimSpace(p.StartDate)
-	p.EndDate = strings.TrimSpace(p.EndDate)
 
 	for i, tech := range p.Technologies {
 		p.Technologies[i] = strings.TrimSpace(tech)
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"strings"
+)
+
+// Reference is a person who can vouch for the candidate. References are
+// personal data of third parties, see Resume.ForPublicShare.
+type Reference struct {
+	ID           uuid.UUID `json:"id"`
+	Name         string    `json:"name"`
+	Relationship string    `json:"relationship"` // e.g. "Former manager"
+	Company      string    `json:"company,omitempty"`
+	Email        string    `json:"email,omitempty"`
+	Phone        string    `json:"phone,omitempty"`
+	// AvailableOnRequest hides the contact details in rendered documents,
+	// which then say they are available on request
+	AvailableOnRequest bool `json:"available_on_request"`
+}
+
+func (r *Reference) Validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return NewValidationError("name", "Name is required", ErrInvalidField)
+	}
+	if len(r.Name) > 200 {
+		return NewValidationError("name", "Name must be at most 200 characters", ErrInvalidField)
+	}
+	if strings.TrimSpace(r.Relationship) == "" {
+		return NewValidationError("relationship", "Relationship is required", ErrInvalidField)
+	}
+
+	if r.Email != "" && !EmailRegex.MatchString(r.Email) {
+		return NewValidationError("email", "Invalid email format", ErrInvalidField)
+	}
+	if r.Phone != "" && !PhoneRegex.MatchString(r.Phone) {
+		return NewValidationError("phone", "Invalid phone format (must be E.164 format, e.g., +1234567890)", ErrInvalidField)
+	}
+	if !r.AvailableOnRequest && r.Email == "" && r.Phone == "" {
+		return NewValidationError("email", "An email or phone number is required unless the reference is available on request", ErrInvalidField)
+	}
+
+	return nil
+}
+
+func (r *Reference) BeforeSave() {
+	r.Name = strings.TrimSpace(r.Name)
+	r.Relationship = strings.TrimSpace(r.Relationship)
+	r.Company = strings.TrimSpace(r.Company)
+	r.Email = strings.TrimSpace(r.Email)
+	r.Phone = strings.TrimSpace(r.Phone)
+}
+
+func (r *Reference) ToJSON() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+func (r *Reference) FromJSON(data []byte) error {
+	return json.Unmarshal(data, r)
+}
@@ -12,12 +12,42 @@ type Resume struct {
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 
+	// DefaultLocale is the locale of the text stored in the resume itself,
+	// other locales are kept as translations
+	DefaultLocale string `json:"default_locale" db:"default_locale"`
+	// Locale is the locale the content was returned in and Locales all
+	// locales the resume is available in
+	Locale  string   `json:"locale,omitempty" db:"-"`
+	Locales []string `json:"locales,omitempty" db:"-"`
+
 	PersonalInfo   *PersonalInfo    `json:"personal_info,omitempty" db:"-"`
 	Education      []*Education     `json:"education,omitempty" db:"-"`
 	Experience     []*Experience    `json:"experience,omitempty" db:"-"`
 	Skills         []*Skill         `json:"skills,omitempty" db:"-"`
+	Languages      []*Language      `json:"languages,omitempty" db:"-"`
 	Projects       []*Project       `json:"projects,omitempty" db:"-"`
 	Certifications []*Certification `json:"certifications,omitempty" db:"-"`
+	Volunteer      []*Volunteer     `json:"volunteer,omitempty" db:"-"`
+	Awards         []*Award         `json:"awards,omitempty" db:"-"`
+	References     []*Reference     `json:"references,omitempty" db:"-"`
+	CustomSections []*CustomSection `json:"custom_sections,omitempty" db:"-"`
+	Photo          *Photo           `json:"photo,omitempty" db:"-"`
+}
+
+// ShareOptions selects the optional content a public share link reveals
+type ShareOptions struct {
+	IncludeReferences bool
+}
+
+// ForPublicShare returns a copy of the resume for public share links.
+// References are personal data of third parties and are left out unless
+// the owner opted in.
+func (r *Resume) ForPublicShare(options ShareOptions) *Resume {
+	shared := *r
+	if !options.IncludeReferences {
+		shared.References = nil
+	}
+	return &shared
 }
 
 type ResumeRepository interface {
@@ -47,6 +77,13 @@ type ResumeRepository interface {
 	GetSkill(ctx context.Context, id uuid.UUID) (*Skill, error)
 	GetSkillsByCV(ctx context.Context, resumeID uuid.UUID) ([]*Skill, error)
 
+	// Language operations, scoped to the resume
+	AddLanguage(ctx context.Context, resumeID uuid.UUID, language *Language) (uuid.UUID, error)
+	UpdateLanguage(ctx context.Context, resumeID, id uuid.UUID, language *Language) error
+	DeleteLanguage(ctx context.Context, resumeID, id uuid.UUID) error
+	GetLanguage(ctx context.Context, resumeID, id uuid.UUID) (*Language, error)
+	GetLanguagesByResume(ctx context.Context, resumeID uuid.UUID) ([]*Language, error)
+
 	// Project operations
 	AddProject(ctx context.Context, resumeID uuid.UUID, project *Project) (uuid.UUID, error)
 	UpdateProject(ctx context.Context, id uuid.UUID, project *Project) error
@@ -61,6 +98,47 @@ type ResumeRepository interface {
 	GetCertification(ctx context.Context, id uuid.UUID) (*Certification, error)
 	GetCertificationsByResume(ctx context.Context, resumeID uuid.UUID) ([]*Certification, error)
 
+	// Volunteer, award and reference operations, scoped to the resume
+	AddVolunteer(ctx context.Context, resumeID uuid.UUID, volunteer *Volunteer) (uuid.UUID, error)
+	UpdateVolunteer(ctx context.Context, resumeID, id uuid.UUID, volunteer *Volunteer) error
+	DeleteVolunteer(ctx context.Context, resumeID, id uuid.UUID) error
+	GetVolunteerByResume(ctx context.Context, resumeID uuid.UUID) ([]*Volunteer, error)
+
+	AddAward(ctx context.Context, resumeID uuid.UUID, award *Award) (uuid.UUID, error)
+	UpdateAward(ctx context.Context, resumeID, id uuid.UUID, award *Award) error
+	DeleteAward(ctx context.Context, resumeID, id uuid.UUID) error
+	GetAwardsByResume(ctx context.Context, resumeID uuid.UUID) ([]*Award, error)
+
+	AddReference(ctx context.Context, resumeID uuid.UUID, reference *Reference) (uuid.UUID, error)
+	UpdateReference(ctx context.Context, resumeID, id uuid.UUID, reference *Reference) error
+	DeleteReference(ctx context.Context, resumeID, id uuid.UUID) error
+	GetReferencesByResume(ctx context.Context, resumeID uuid.UUID) ([]*Reference, error)
+
+	// Custom section operations. Sections are scoped to their resume and
+	// entries to their section, so an ID from another resume is not found.
+	AddCustomSection(ctx context.Context, resumeID uuid.UUID, section *CustomSection) (uuid.UUID, error)
+	UpdateCustomSection(ctx context.Context, resumeID, id uuid.UUID, section *CustomSection) error
+	DeleteCustomSection(ctx context.Context, resumeID, id uuid.UUID) error
+	GetCustomSection(ctx context.Context, resumeID, id uuid.UUID) (*CustomSection, error)
+	GetCustomSectionsByResume(ctx context.Context, resumeID uuid.UUID) ([]*CustomSection, error)
+	ReorderCustomSections(ctx context.Context, resumeID uuid.UUID, ids []uuid.UUID) error
+	AddCustomSectionEntry(ctx context.Context, sectionID uuid.UUID, entry *CustomSectionEntry) (uuid.UUID, error)
+	UpdateCustomSectionEntry(ctx context.Context, sectionID, id uuid.UUID, entry *CustomSectionEntry) error
+	DeleteCustomSectionEntry(ctx context.Context, sectionID, id uuid.UUID) error
+	ReorderCustomSectionEntries(ctx context.Context, sectionID uuid.UUID, ids []uuid.UUID) error
+
+	// Photo operations; the images themselves are kept in a blob store
+	SavePhoto(ctx context.Context, resumeID uuid.UUID, photo *Photo) error
+	GetPhoto(ctx context.Context, resumeID uuid.UUID) (*Photo, error)
+	DeletePhoto(ctx context.Context, resumeID uuid.UUID) error
+
+	// Translation operations
+	SetDefaultLocale(ctx context.Context, resumeID uuid.UUID, locale string) error
+	SaveTranslation(ctx context.Context, resumeID uuid.UUID, translation *Translation) error
+	GetTranslation(ctx context.Context, resumeID uuid.UUID, locale string) (*Translation, error)
+	GetTranslationLocales(ctx context.Context, resumeID uuid.UUID) ([]string, error)
+	DeleteTranslation(ctx context.Context, resumeID uuid.UUID, locale string) error
+
 	// Complete resume operations
 	GetCompleteResume(ctx context.Context, resumeID uuid.UUID) (*Resume, error)
 }
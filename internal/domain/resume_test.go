@@ -0,0 +1,34 @@
+package domain
+
+import "testing"
+
+func TestResumeForPublicShare(t *testing.T) {
+	tests := []struct {
+		name           string
+		options        ShareOptions
+		wantReferences int
+	}{
+		{name: "references left out by default", options: ShareOptions{}, wantReferences: 0},
+		{name: "references included when opted in", options: ShareOptions{IncludeReferences: true}, wantReferences: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resume := &Resume{
+				PersonalInfo: &PersonalInfo{FirstName: "Ada"},
+				References:   []*Reference{{Name: "Grace Hopper", Relationship: "Former manager", Email: "grace@example.com"}},
+			}
+
+			shared := resume.ForPublicShare(tt.options)
+			if len(shared.References) != tt.wantReferences {
+				t.Errorf("shared references = %d, want %d", len(shared.References), tt.wantReferences)
+			}
+			if shared.PersonalInfo != resume.PersonalInfo {
+				t.Errorf("shared resume lost its personal info")
+			}
+			if len(resume.References) != 1 {
+				t.Errorf("sharing changed the owner's resume, references = %d", len(resume.References))
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// slugRegex matches lowercase slugs such as "software-engineer"
+var slugRegex = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// ResumeTemplate is starter content for a new resume, e.g. placeholder
+// sections for a software engineer. Content is copied into the resume when
+// it is created, so later changes to the template do not affect it.
+type ResumeTemplate struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	Slug        string    `json:"slug" db:"slug"`
+	Name        string    `json:"name" db:"name"`
+	Description string    `json:"description,omitempty" db:"description"`
+	Content     *Resume   `json:"content" db:"-"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+func (t *ResumeTemplate) Validate() error {
+	if t.Slug == "" {
+		return NewValidationError("slug", "Slug is required", ErrInvalidField)
+	}
+	if len(t.Slug) > 50 || !slugRegex.MatchString(t.Slug) {
+		return NewValidationError("slug", "Slug must be at most 50 lowercase letters, digits and dashes", ErrInvalidField)
+	}
+	if t.Name == "" {
+		return NewValidationError("name", "Name is required", ErrInvalidField)
+	}
+	if len(t.Name) > 100 {
+		return NewValidationError("name", "Name must be at most 100 characters", ErrInvalidField)
+	}
+	if len(t.Description) > 500 {
+		return NewValidationError("description", "Description must be at most 500 characters", ErrInvalidField)
+	}
+	if t.Content == nil {
+		return NewValidationError("content", "Content is required", ErrInvalidField)
+	}
+	return nil
+}
+
+func (t *ResumeTemplate) BeforeSave() {
+	t.Slug = strings.ToLower(strings.TrimSpace(t.Slug))
+	t.Name = strings.TrimSpace(t.Name)
+	t.Description = strings.TrimSpace(t.Description)
+}
+
+type TemplateRepository interface {
+	CreateTemplate(ctx context.Context, template *ResumeTemplate) error
+	UpdateTemplate(ctx context.Context, slug string, template *ResumeTemplate) error
+	DeleteTemplate(ctx context.Context, slug string) error
+	GetTemplate(ctx context.Context, slug string) (*ResumeTemplate, error)
+	GetTemplates(ctx context.Context) ([]*ResumeTemplate, error)
+}
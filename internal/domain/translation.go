@@ -0,0 +1,257 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"regexp"
+	"strings"
+)
+
+// DefaultLocale is the locale of resumes that did not choose one
+const DefaultLocale = "en"
+
+// localeRegex matches lowercase BCP 47 language tags such as "en" or "pt-br"
+var localeRegex = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+
+// NormalizeLocale lowercases a language tag and checks its form, so "en_US"
+// and "en-US" are both stored as "en-us"
+func NormalizeLocale(locale string) (string, error) {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+	if !localeRegex.MatchString(normalized) {
+		return "", NewValidationError("locale", "Invalid locale, must be a language tag such as 'en' or 'ru'", ErrInvalidField)
+	}
+	return normalized, nil
+}
+
+// Translation holds the free text of a resume in another locale. The default
+// locale lives in the resume itself; empty fields of a translation fall back
+// to it, so a translation may cover only part of the resume.
+type Translation struct {
+	Locale       string                         `json:"locale"`
+	PersonalInfo *TextTranslation               `json:"personal_info,omitempty"`
+	Entries      map[uuid.UUID]*TextTranslation `json:"entries,omitempty"`
+}
+
+// TextTranslation translates the free-text fields of one entry, keyed in
+// Translation.Entries by the entry ID. The fields map onto the entry as
+// follows; fields an entry does not have are ignored:
+//
+//	personal info: title = job title, description = summary
+//	experience: title = job title, description, achievements
+//	education: title = degree, subtitle = field, description
+//	project, certification: title = name, description (projects only)
+//	volunteer: title = role, description
+//	award: title, description
+//	reference: title = relationship
+//	custom section: title; its entries: title, subtitle, achievements = bullets
+type TextTranslation struct {
+	Title        string   `json:"title,omitempty"`
+	Subtitle     string   `json:"subtitle,omitempty"`
+	Description  string   `json:"description,omitempty"`
+	Achievements []string `json:"achievements,omitempty"`
+}
+
+func (t *TextTranslation) isEmpty() bool {
+	return t.Title == "" && t.Subtitle == "" && t.Description == "" && len(t.Achievements) == 0
+}
+
+func (t *TextTranslation) BeforeSave() {
+	t.Title = strings.TrimSpace(t.Title)
+	t.Subtitle = strings.TrimSpace(t.Subtitle)
+	t.Description = strings.TrimSpace(t.Description)
+
+	achievements := make([]string, 0, len(t.Achievements))
+	for _, achievement := range t.Achievements {
+		if achievement = strings.TrimSpace(achievement); achievement != "" {
+			achievements = append(achievements, achievement)
+		}
+	}
+	t.Achievements = achievements
+}
+
+// BeforeSave trims the text and drops entries without any translated field
+func (t *Translation) BeforeSave() {
+	t.Locale = strings.ToLower(strings.TrimSpace(t.Locale))
+	if t.PersonalInfo != nil {
+		t.PersonalInfo.BeforeSave()
+		if t.PersonalInfo.isEmpty() {
+			t.PersonalInfo = nil
+		}
+	}
+	for id, entry := range t.Entries {
+		if entry == nil {
+			delete(t.Entries, id)
+			continue
+		}
+		entry.BeforeSave()
+		if entry.isEmpty() {
+			delete(t.Entries, id)
+		}
+	}
+}
+
+func (t *Translation) Validate() error {
+	if _, err := NormalizeLocale(t.Locale); err != nil {
+		return err
+	}
+	for id := range t.Entries {
+		if id == uuid.Nil {
+			return NewValidationError("entries", "Entry ID is required", ErrInvalidField)
+		}
+	}
+	return nil
+}
+
+// CheckEntries reports the first translated entry that is not part of the
+// resume, so translations cannot reference entries of other resumes
+func (t *Translation) CheckEntries(resume *Resume) error {
+	known := resume.entryIDs()
+	for id := range t.Entries {
+		if !known[id] {
+			return NewValidationError(fmt.Sprintf("entries.%s", id), "Entry does not belong to this resume", ErrInvalidField)
+		}
+	}
+	return nil
+}
+
+func (t *Translation) ToJSON() ([]byte, error) {
+	return json.Marshal(t)
+}
+
+func (t *Translation) FromJSON(data []byte) error {
+	return json.Unmarshal(data, t)
+}
+
+// entryIDs collects the IDs of all entries that can be translated
+func (r *Resume) entryIDs() map[uuid.UUID]bool {
+	ids := make(map[uuid.UUID]bool)
+	for _, e := range r.Experience {
+		ids[e.ID] = true
+	}
+	for _, e := range r.Education {
+		ids[e.ID] = true
+	}
+	for _, p := range r.Projects {
+		ids[p.ID] = true
+	}
+	for _, c := range r.Certifications {
+		ids[c.ID] = true
+	}
+	for _, v := range r.Volunteer {
+		ids[v.ID] = true
+	}
+	for _, a := range r.Awards {
+		ids[a.ID] = true
+	}
+	for _, ref := range r.References {
+		ids[ref.ID] = true
+	}
+	for _, section := range r.CustomSections {
+		ids[section.ID] = true
+		for _, entry := range section.Entries {
+			ids[entry.ID] = true
+		}
+	}
+	delete(ids, uuid.Nil)
+	return ids
+}
+
+// Localize returns a copy of the resume with the translated fields of t in
+// place of the default text. Entries are copied before they are changed, so
+// the receiver is left untouched.
+func (r *Resume) Localize(t *Translation) *Resume {
+	localized := *r
+	localized.Locale = t.Locale
+
+	if r.PersonalInfo != nil && t.PersonalInfo != nil {
+		info := *r.PersonalInfo
+		translate(&info.JobTitle, t.PersonalInfo.Title)
+		translate(&info.Summary, t.PersonalInfo.Description)
+		localized.PersonalInfo = &info
+	}
+
+	localized.Experience = localizeEntries(r.Experience, t, func(e *Experience) uuid.UUID { return e.ID },
+		func(e *Experience, tr *TextTranslation) {
+			translate(&e.JobTitle, tr.Title)
+			translate(&e.Description, tr.Description)
+			translateList(&e.Achievements, tr.Achievements)
+		})
+	localized.Education = localizeEntries(r.Education, t, func(e *Education) uuid.UUID { return e.ID },
+		func(e *Education, tr *TextTranslation) {
+			translate(&e.Degree, tr.Title)
+			translate(&e.Field, tr.Subtitle)
+			translate(&e.Description, tr.Description)
+		})
+	localized.Projects = localizeEntries(r.Projects, t, func(p *Project) uuid.UUID { return p.ID },
+		func(p *Project, tr *TextTranslation) {
+			translate(&p.Name, tr.Title)
+			translate(&p.Description, tr.Description)
+		})
+	localized.Certifications = localizeEntries(r.Certifications, t, func(c *Certification) uuid.UUID { return c.ID },
+		func(c *Certification, tr *TextTranslation) {
+			translate(&c.Name, tr.Title)
+		})
+	localized.Volunteer = localizeEntries(r.Volunteer, t, func(v *Volunteer) uuid.UUID { return v.ID },
+		func(v *Volunteer, tr *TextTranslation) {
+			translate(&v.Role, tr.Title)
+			translate(&v.Description, tr.Description)
+		})
+	localized.Awards = localizeEntries(r.Awards, t, func(a *Award) uuid.UUID { return a.ID },
+		func(a *Award, tr *TextTranslation) {
+			translate(&a.Title, tr.Title)
+			translate(&a.Description, tr.Description)
+		})
+	localized.References = localizeEntries(r.References, t, func(ref *Reference) uuid.UUID { return ref.ID },
+		func(ref *Reference, tr *TextTranslation) {
+			translate(&ref.Relationship, tr.Title)
+		})
+
+	localized.CustomSections = make([]*CustomSection, len(r.CustomSections))
+	for i, section := range r.CustomSections {
+		copied := *section
+		if tr := t.Entries[section.ID]; tr != nil {
+			translate(&copied.Title, tr.Title)
+		}
+		copied.Entries = localizeEntries(section.Entries, t, func(e *CustomSectionEntry) uuid.UUID { return e.ID },
+			func(e *CustomSectionEntry, tr *TextTranslation) {
+				translate(&e.Title, tr.Title)
+				translate(&e.Subtitle, tr.Subtitle)
+				translateList(&e.Bullets, tr.Achievements)
+			})
+		localized.CustomSections[i] = &copied
+	}
+
+	return &localized
+}
+
+// localizeEntries copies the entries that have a translation and applies it
+func localizeEntries[T any](entries []*T, t *Translation, id func(*T) uuid.UUID, apply func(*T, *TextTranslation)) []*T {
+	if entries == nil {
+		return nil
+	}
+	localized := make([]*T, len(entries))
+	for i, entry := range entries {
+		localized[i] = entry
+		if tr := t.Entries[id(entry)]; tr != nil {
+			copied := *entry
+			apply(&copied, tr)
+			localized[i] = &copied
+		}
+	}
+	return localized
+}
+
+func translate(field *string, value string) {
+	if value != "" {
+		*field = value
+	}
+}
+
+// translateList replaces a whole list, since translated bullets rarely map
+// one to one onto the original ones
+func translateList(field *[]string, values []string) {
+	if len(values) > 0 {
+		*field = values
+	}
+}
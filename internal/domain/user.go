@@ -10,12 +10,19 @@ import (
 )
 
 type User struct {
-	ID           uuid.UUID `json:"id" db:"id"`
-	Email        string    `json:"email" db:"email"`
-	PasswordHash string    `json:"-" db:"password_hash"`
-	Role         string    `json:"role" db:"role"`
-	CreatedAt    time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+	ID              uuid.UUID  `json:"id" db:"id"`
+	Email           string     `json:"email" db:"email"`
+	PasswordHash    string     `json:"-" db:"password_hash"`
+	Role            string     `json:"role" db:"role"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty" db:"email_verified_at"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// IsEmailVerified reports whether the user proved they own their address.
+// Users without an address, i.e. Telegram-only users, have nothing to verify.
+func (u *User) IsEmailVerified() bool {
+	return u.Email == "" || u.EmailVerifiedAt != nil
 }
 
 type TelegramUser struct {
@@ -62,23 +69,27 @@ type TelegramAuthData struct {
 	ValidationErr error
 }
 
+// Session is a signed in device. Its ID is the family of the refresh
+// tokens issued to the device, each refresh replaces the token.
 type Session struct {
-	ID           uuid.UUID `json:"id" db:"id"`
-	UserID       uuid.UUID `json:"user_id" db:"user_id"`
-	RefreshToken string    `json:"refresh_token" db:"refresh_token"`
-	UserAgent    string    `json:"user_agent" db:"user_agent"`
-	ClientIP     string    `json:"client_ip" db:"client_ip"`
-	ExpiresAt    time.Time `json:"expires_at" db:"expires_at"`
-	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	ID     uuid.UUID `json:"id" db:"id"`
+	UserID uuid.UUID `json:"user_id" db:"user_id"`
+	// RefreshTokenHash is the SHA-256 of the current refresh token
+	RefreshTokenHash string     `json:"-" db:"refresh_token"`
+	UserAgent        string     `json:"user_agent" db:"user_agent"`
+	ClientIP         string     `json:"client_ip" db:"client_ip"`
+	ExpiresAt        time.Time  `json:"expires_at" db:"expires_at"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	LastUsedAt       *time.Time `json:"last_used_at" db:"last_used_at"`
 }
 
 type PasswordReset struct {
-	ID        uuid.UUID `json:"id" db:"id"`
-	UserID    uuid.UUID `json:"user_id" db:"user_id"`
-	Token     string    `json:"token" db:"token"`
-	ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UsedAt    time.Time `json:"used_at,omitempty" db:"used_at"`
+	ID        uuid.UUID  `json:"id" db:"id"`
+	UserID    uuid.UUID  `json:"user_id" db:"user_id"`
+	Token     string     `json:"token" db:"token"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty" db:"used_at"`
 }
 
 type UserRepository interface {
@@ -86,21 +97,40 @@ type UserRepository interface {
 	GetUserById(ctx context.Context, id uuid.UUID) (*User, error)
 	GetUserByEmail(ctx context.Context, email string) (*User, error)
 	UpdateUser(ctx context.Context, user *User) error
+	MarkEmailVerified(ctx context.Context, id uuid.UUID, email string) error
 	DeleteUser(ctx context.Context, id uuid.UUID) error
 	GetUserByTelegramID(ctx context.Context, telegramID int64) (*TelegramUser, error)
+	GetTelegramUserById(ctx context.Context, id uuid.UUID) (*TelegramUser, error)
 	CreateTelegramUser(ctx context.Context, tgUser tgInitData.User) (*TelegramUser, error)
 	UpdateTelegramUser(ctx context.Context, user *TelegramUser) error
+	// LinkTelegram adds a Telegram account to the user, ErrConflict if it
+	// belongs to another user
+	LinkTelegram(ctx context.Context, userId uuid.UUID, tgUser tgInitData.User) error
+	UnlinkTelegram(ctx context.Context, userId uuid.UUID) error
+	// MergeUsers moves everything of the source user to the target user and
+	// deletes the source user
+	MergeUsers(ctx context.Context, targetId, sourceId uuid.UUID) error
 
 	CreateSession(ctx context.Context, session *Session) error
 	GetSessionById(ctx context.Context, id uuid.UUID) (*Session, error)
-	GetSessionByToken(ctx context.Context, token string) (*Session, error)
+	GetSessionByTokenHash(ctx context.Context, tokenHash string) (*Session, error)
+	// GetSessionsByUser returns the sessions of the user that have not
+	// expired yet, most recently used first
+	GetSessionsByUser(ctx context.Context, userId uuid.UUID) ([]*Session, error)
+	// RotateSession replaces the refresh token hash of the session, if it is
+	// still previousHash, and marks the session as used. ErrNotFound if the
+	// token was rotated in the meantime.
+	RotateSession(ctx context.Context, session *Session, previousHash string) error
 	DeleteSession(ctx context.Context, id uuid.UUID) error
+	// DeleteUserSession deletes a session of the user, ErrNotFound if the
+	// user has no such session
+	DeleteUserSession(ctx context.Context, userId, id uuid.UUID) error
 	DeleteUserSessions(ctx context.Context, userId uuid.UUID) error
 
 	CreatePasswordReset(ctx context.Context, reset *PasswordReset) error
 	GetPasswordResetByToken(ctx context.Context, token string) (*PasswordReset, error)
 	MarkPasswordResetUsed(ctx context.Context, id uuid.UUID) error
-	DeleteExpiredPasswordReset(ctx context.Context) error
+	DeleteExpiredPasswordResets(ctx context.Context) error
 }
 
 func (u *TelegramUser) GetDisplayName() string {
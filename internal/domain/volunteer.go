@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Volunteer is unpaid work for an organization, kept apart from employment
+type Volunteer struct {
+	ID           uuid.UUID `json:"id"`
+	Organization string    `json:"organization"`
+	Role         string    `json:"role"`
+	Location     string    `json:"location,omitempty"`
+	StartDate    string    `json:"start_date"`         // Format: YYYY-MM-DD
+	EndDate      string    `json:"end_date,omitempty"` // Format: YYYY-MM-DD or "Present"
+	Description  string    `json:"description,omitempty"`
+	URL          string    `json:"url,omitempty"`
+}
+
+func (v *Volunteer) Validate() error {
+	if strings.TrimSpace(v.Organization) == "" {
+		return NewValidationError("organization", "Organization is required", ErrInvalidField)
+	}
+	if strings.TrimSpace(v.Role) == "" {
+		return NewValidationError("role", "Role is required", ErrInvalidField)
+	}
+	if strings.TrimSpace(v.StartDate) == "" {
+		return NewValidationError("start_date", "Start date is required", ErrInvalidField)
+	}
+
+	startDate, err := time.Parse("2006-01-02", v.StartDate)
+	if err != nil {
+		return NewValidationError("start_date", "Invalid start date format (must be YYYY-MM-DD)", ErrInvalidField)
+	}
+
+	if v.EndDate != "" && v.EndDate != "Present" {
+		endDate, err := time.Parse("2006-01-02", v.EndDate)
+		if err != nil {
+			return NewValidationError("end_date", "Invalid end date format (must be YYYY-MM-DD or 'Present')", ErrInvalidField)
+		}
+		if endDate.Before(startDate) {
+			return NewValidationError("end_date", "End date must be after start date", ErrDateRange)
+		}
+	}
+
+	if v.URL != "" {
+		if _, err := url.ParseRequestURI(v.URL); err != nil {
+			return NewValidationError("url", "Invalid URL", ErrInvalidField)
+		}
+	}
+
+	return nil
+}
+
+func (v *Volunteer) BeforeSave() {
+	v.Organization = strings.TrimSpace(v.Organization)
+	v.Role = strings.TrimSpace(v.Role)
+	v.Location = strings.TrimSpace(v.Location)
+	v.StartDate = strings.TrimSpace(v.StartDate)
+	v.EndDate = strings.TrimSpace(v.EndDate)
+	v.Description = strings.TrimSpace(v.Description)
+	v.URL = strings.TrimSpace(v.URL)
+}
+
+func (v *Volunteer) ToJSON() ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (v *Volunteer) FromJSON(data []byte) error {
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,15 @@
+package domain
+
+// ConversionWarning describes data that could not be carried over exactly
+// when a resume is converted to or from an external format
+type ConversionWarning struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func NewConversionWarning(field, message string) ConversionWarning {
+	return ConversionWarning{
+		Field:   field,
+		Message: message,
+	}
+}
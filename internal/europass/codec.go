@@ -0,0 +1,54 @@
+package europass
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var ErrInvalidDocument = errors.New("invalid Europass document")
+
+// WriteXML writes the document in the Europass XML format
+func WriteXML(w io.Writer, doc *Document) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	doc.Xmlns = Namespace
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// ReadXML parses a document in the Europass XML format
+func ReadXML(r io.Reader) (*Document, error) {
+	var doc Document
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDocument, err)
+	}
+	return &doc, nil
+}
+
+// WriteJSON writes the document in the Europass JSON format
+func WriteJSON(w io.Writer, doc *Document) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(jsonDocument{SkillsPassport: doc})
+}
+
+// ReadJSON parses a document in the Europass JSON format
+func ReadJSON(r io.Reader) (*Document, error) {
+	var envelope jsonDocument
+	if err := json.NewDecoder(r).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDocument, err)
+	}
+	if envelope.SkillsPassport == nil {
+		return nil, fmt.Errorf("%w: missing SkillsPassport object", ErrInvalidDocument)
+	}
+	return envelope.SkillsPassport, nil
+}
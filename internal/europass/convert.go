@@ -0,0 +1,1022 @@
+package europass
+
+import (
+	"cv_builder/internal/domain"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+)
+
+const (
+	headlinePreferredJob      = "preferred_job"
+	headlinePersonalStatement = "personal_statement"
+)
+
+const (
+	achievementProjects       = "projects"
+	achievementCertifications = "certifications"
+	achievementHonorsAwards   = "honors_awards"
+	achievementReferences     = "references"
+)
+
+// volunteerLabel titles the achievements holding volunteer work, for which
+// Europass has no code
+const volunteerLabel = "Volunteering"
+
+// contactOnRequest marks a reference whose contact details are withheld
+const contactOnRequest = "available on request"
+
+var computerSkillTitles = map[string]string{
+	domain.SkillCategoryLanguage:  "Programming languages",
+	domain.SkillCategoryFramework: "Frameworks",
+	domain.SkillCategoryTool:      "Tools",
+	domain.SkillCategoryDatabase:  "Databases",
+}
+
+type converter struct {
+	warnings []domain.ConversionWarning
+}
+
+func (c *converter) warn(field, format string, args ...any) {
+	c.warnings = append(c.warnings, domain.NewConversionWarning(field, fmt.Sprintf(format, args...)))
+}
+
+// FromResume maps a complete resume onto a Europass document. Data that has
+// no Europass equivalent, or that is only approximated, is reported as a
+// warning.
+func FromResume(resume *domain.Resume) (*Document, []domain.ConversionWarning) {
+	c := &converter{}
+	doc := &Document{
+		Xmlns:  Namespace,
+		Locale: "en",
+		DocumentInfo: &DocumentInfo{
+			DocumentType: "ECV",
+			CreationDate: time.Now().UTC().Format(time.RFC3339),
+			XSDVersion:   "V3.4",
+			Generator:    "cv_builder",
+		},
+	}
+
+	if info := resume.PersonalInfo; info != nil {
+		doc.LearnerInfo.Identification = c.identificationFromPersonalInfo(info)
+		if photo := resume.Photo; photo != nil && len(photo.Data) > 0 {
+			doc.LearnerInfo.Identification.Photo = &Photo{
+				MimeType: photo.ContentType,
+				Data:     base64.StdEncoding.EncodeToString(photo.Data),
+			}
+		}
+		// Europass has a single headline, used for the job title when there is one
+		switch {
+		case info.JobTitle != "":
+			doc.LearnerInfo.Headline = &Headline{
+				Type:        &CodeLabel{Code: headlinePreferredJob, Label: "Preferred job"},
+				Description: CodeLabel{Label: info.JobTitle},
+			}
+			if info.Summary != "" {
+				c.warn("personal_info.summary", "Europass has a single headline, used for the job title; the summary was dropped")
+			}
+		case info.Summary != "":
+			doc.LearnerInfo.Headline = &Headline{
+				Type:        &CodeLabel{Code: headlinePersonalStatement, Label: "Personal statement"},
+				Description: CodeLabel{Label: info.Summary},
+			}
+		}
+	} else if resume.Photo != nil {
+		c.warn("photo", "Europass keeps the photo with the personal information, which is missing; the photo was dropped")
+	}
+
+	for i, exp := range resume.Experience {
+		if exp == nil {
+			continue
+		}
+		doc.LearnerInfo.WorkExperience = append(doc.LearnerInfo.WorkExperience,
+			c.workExperienceFromExperience(fmt.Sprintf("experience[%d]", i), exp))
+	}
+
+	for i, edu := range resume.Education {
+		if edu == nil {
+			continue
+		}
+		doc.LearnerInfo.Education = append(doc.LearnerInfo.Education,
+			c.educationFromEducation(fmt.Sprintf("education[%d]", i), edu))
+	}
+
+	doc.LearnerInfo.Skills = c.skillsFromSkills(resume.Skills, resume.Languages)
+
+	for _, project := range resume.Projects {
+		if project == nil {
+			continue
+		}
+		doc.LearnerInfo.Achievement = append(doc.LearnerInfo.Achievement, achievementFromProject(project))
+	}
+	if len(resume.Projects) > 0 {
+		c.warn("projects", "Europass has no structured project section; projects were exported as achievements")
+	}
+
+	for _, cert := range resume.Certifications {
+		if cert == nil {
+			continue
+		}
+		doc.LearnerInfo.Achievement = append(doc.LearnerInfo.Achievement, achievementFromCertification(cert))
+	}
+	if len(resume.Certifications) > 0 {
+		c.warn("certifications", "Europass has no structured certification section; certifications were exported as achievements")
+	}
+
+	for _, volunteer := range resume.Volunteer {
+		if volunteer == nil {
+			continue
+		}
+		doc.LearnerInfo.Achievement = append(doc.LearnerInfo.Achievement, achievementFromVolunteer(volunteer))
+	}
+	if len(resume.Volunteer) > 0 {
+		c.warn("volunteer", "Europass has no volunteer section; volunteer work was exported as achievements")
+	}
+
+	for _, award := range resume.Awards {
+		if award == nil {
+			continue
+		}
+		doc.LearnerInfo.Achievement = append(doc.LearnerInfo.Achievement, achievementFromAward(award))
+	}
+
+	for _, reference := range resume.References {
+		if reference == nil {
+			continue
+		}
+		doc.LearnerInfo.Achievement = append(doc.LearnerInfo.Achievement, achievementFromReference(reference))
+	}
+
+	for _, section := range resume.CustomSections {
+		if section == nil {
+			continue
+		}
+		for _, entry := range section.Entries {
+			if entry == nil {
+				continue
+			}
+			doc.LearnerInfo.Achievement = append(doc.LearnerInfo.Achievement, achievementFromCustomEntry(section.Title, entry))
+		}
+	}
+
+	return doc, c.warnings
+}
+
+func (c *converter) identificationFromPersonalInfo(info *domain.PersonalInfo) *Identification {
+	identification := &Identification{
+		PersonName: PersonName{FirstName: info.FirstName, Surname: info.LastName},
+	}
+
+	contact := &ContactInfo{}
+	if info.Email != "" {
+		contact.Email = &Contact{Contact: info.Email}
+	}
+	if info.Phone != "" {
+		contact.Telephone = []Telephone{{Contact: info.Phone, Use: &CodeLabel{Code: "mobile"}}}
+	}
+	if info.Address.Street != "" || info.Address.City != "" || info.Address.Country != "" {
+		address := &Address{Contact: AddressContact{
+			AddressLine:  info.Address.Street,
+			Municipality: info.Address.City,
+		}}
+		if info.Address.Country != "" {
+			address.Contact.Country = &CodeLabel{Label: info.Address.Country}
+		}
+		contact.Address = address
+	}
+	for _, profile := range info.Profiles {
+		if profile == nil {
+			continue
+		}
+		use := &CodeLabel{Code: "other", Label: profileNetworkLabel(profile.Network)}
+		if profile.Network == domain.ProfileNetworkTelegram {
+			contact.InstantMessaging = append(contact.InstantMessaging, InstantMessage{Contact: profile.URL, Use: use})
+			continue
+		}
+		if profile.Network == domain.ProfileNetworkWebsite {
+			use = &CodeLabel{Code: "personal"}
+		}
+		contact.Website = append(contact.Website, Website{Contact: profile.URL, Use: use})
+	}
+	if contact.Email != nil || contact.Telephone != nil || contact.Address != nil || contact.Website != nil || contact.InstantMessaging != nil {
+		identification.ContactInfo = contact
+	}
+
+	return identification
+}
+
+func (c *converter) workExperienceFromExperience(field string, exp *domain.Experience) WorkExperience {
+	work := WorkExperience{
+		Period:     c.periodFromDates(field, exp.StartDate, exp.EndDate),
+		Activities: paragraph(exp.Description) + bulletList(exp.Achievements),
+	}
+	if exp.JobTitle != "" {
+		work.Position = &CodeLabel{Label: exp.JobTitle}
+	}
+	if exp.Employer != "" || exp.Location != "" {
+		work.Employer = organisation(exp.Employer, exp.Location)
+	}
+	return work
+}
+
+func (c *converter) educationFromEducation(field string, edu *domain.Education) Education {
+	education := Education{
+		Period:     c.periodFromDates(field, edu.StartDate, edu.EndDate),
+		Title:      edu.Degree,
+		Activities: paragraph(edu.Description),
+	}
+	if edu.Institution != "" || edu.Location != "" {
+		education.Organisation = organisation(edu.Institution, edu.Location)
+	}
+	if edu.Field != "" {
+		education.Field = &CodeLabel{Label: edu.Field}
+	}
+	return education
+}
+
+func organisation(name, location string) *Organisation {
+	org := &Organisation{Name: name}
+	if location != "" {
+		org.ContactInfo = &OrganisationContactInfo{
+			Address: &Address{Contact: AddressContact{Municipality: location}},
+		}
+	}
+	return org
+}
+
+func (c *converter) skillsFromSkills(skills []*domain.Skill, languages []*domain.Language) *Skills {
+	if len(skills) == 0 && len(languages) == 0 {
+		return nil
+	}
+
+	var categories []string
+	computer := make(map[string][]string)
+	var other []string
+	var linguistic Linguistic
+
+	spoken := make(map[string]bool)
+	for _, language := range languages {
+		if language == nil {
+			continue
+		}
+		spoken[language.Code] = true
+		description := CodeLabel{Code: language.Code, Label: language.Name()}
+		if language.Level == domain.LanguageLevelNative {
+			linguistic.MotherTongue = append(linguistic.MotherTongue, MotherTongue{Description: description})
+			continue
+		}
+		foreign := ForeignLanguage{Description: description, ProficiencyLevel: uniformProficiency(language.Level)}
+		if language.Certificate != "" {
+			foreign.Certificate = []LanguageCertificate{{Title: language.Certificate}}
+		}
+		linguistic.ForeignLanguage = append(linguistic.ForeignLanguage, foreign)
+	}
+
+	for i, skill := range skills {
+		if skill == nil {
+			continue
+		}
+		field := fmt.Sprintf("skills[%d]", i)
+
+		// Spoken languages entered as skills before languages had a section
+		// of their own
+		if code, ok := domain.LanguageCodeForName(skill.Name); ok && skill.Category == domain.SkillCategoryOther {
+			if spoken[code] {
+				continue
+			}
+			language := ForeignLanguage{Description: CodeLabel{Code: code, Label: skill.Name}}
+			if level := CEFRFromProficiency(skill.Proficiency); level != "" {
+				language.ProficiencyLevel = uniformProficiency(level)
+				c.warn(field+".proficiency", "CEFR level %s for %s was approximated from proficiency %d/5", level, skill.Name, skill.Proficiency)
+			}
+			linguistic.ForeignLanguage = append(linguistic.ForeignLanguage, language)
+			continue
+		}
+
+		if skill.Proficiency != 0 {
+			c.warn(field+".proficiency", "Europass has no per-skill proficiency; the level of %s was dropped", skill.Name)
+		}
+
+		if _, ok := computerSkillTitles[skill.Category]; !ok {
+			other = append(other, skill.Name)
+			continue
+		}
+		if _, ok := computer[skill.Category]; !ok {
+			categories = append(categories, skill.Category)
+		}
+		computer[skill.Category] = append(computer[skill.Category], skill.Name)
+	}
+
+	result := &Skills{}
+	if len(linguistic.MotherTongue) > 0 || len(linguistic.ForeignLanguage) > 0 {
+		result.Linguistic = &linguistic
+	}
+	if len(categories) > 0 {
+		var description strings.Builder
+		for _, category := range categories {
+			description.WriteString(paragraph(computerSkillTitles[category] + ": " + strings.Join(computer[category], ", ")))
+		}
+		result.Computer = &GenericSkill{Description: description.String()}
+	}
+	if len(other) > 0 {
+		result.Other = &GenericSkill{Description: paragraph(strings.Join(other, ", "))}
+	}
+	return result
+}
+
+func achievementFromProject(project *domain.Project) Achievement {
+	var description strings.Builder
+	description.WriteString("<p><strong>" + html.EscapeString(project.Name) + "</strong></p>")
+	description.WriteString(paragraph(project.Description))
+	description.WriteString(keyedLine(keyTechnologies, strings.Join(project.Technologies, ", ")))
+	description.WriteString(keyedLine(keyPeriod, dateRange(project.StartDate.String(), project.EndDate.String())))
+	description.WriteString(keyedLine(keyRepository, project.RepoURL))
+	description.WriteString(keyedLine(keyDemo, project.DemoURL))
+
+	return Achievement{
+		Title:       CodeLabel{Code: achievementProjects, Label: "Projects"},
+		Description: description.String(),
+	}
+}
+
+func achievementFromCertification(cert *domain.Certification) Achievement {
+	var description strings.Builder
+	description.WriteString("<p><strong>" + html.EscapeString(cert.Name) + "</strong></p>")
+	description.WriteString(keyedLine(keyIssuer, cert.Issuer))
+	description.WriteString(keyedLine(keyIssued, cert.IssueDate.String()))
+	description.WriteString(keyedLine(keyExpires, cert.ExpiryDate.String()))
+	description.WriteString(keyedLine(keyCredentialID, cert.CredentialID))
+	description.WriteString(keyedLine(keyURL, cert.URL))
+
+	return Achievement{
+		Title:       CodeLabel{Code: achievementCertifications, Label: "Certifications"},
+		Description: description.String(),
+	}
+}
+
+func achievementFromVolunteer(volunteer *domain.Volunteer) Achievement {
+	var description strings.Builder
+	description.WriteString("<p><strong>" + html.EscapeString(volunteer.Role) + "</strong></p>")
+	description.WriteString(keyedLine(keyOrganization, volunteer.Organization))
+	description.WriteString(keyedLine(keyLocation, volunteer.Location))
+	description.WriteString(keyedLine(keyPeriod, dateRange(volunteer.StartDate, volunteer.EndDate)))
+	description.WriteString(keyedLine(keyURL, volunteer.URL))
+	description.WriteString(paragraph(volunteer.Description))
+
+	return Achievement{
+		Title:       CodeLabel{Label: volunteerLabel},
+		Description: description.String(),
+	}
+}
+
+func achievementFromAward(award *domain.Award) Achievement {
+	var description strings.Builder
+	description.WriteString("<p><strong>" + html.EscapeString(award.Title) + "</strong></p>")
+	description.WriteString(keyedLine(keyAwarder, award.Awarder))
+	description.WriteString(keyedLine(keyDate, award.Date))
+	description.WriteString(keyedLine(keyURL, award.URL))
+	description.WriteString(paragraph(award.Description))
+
+	return Achievement{
+		Title:       CodeLabel{Code: achievementHonorsAwards, Label: "Honours and awards"},
+		Description: description.String(),
+	}
+}
+
+func achievementFromReference(reference *domain.Reference) Achievement {
+	var description strings.Builder
+	description.WriteString("<p><strong>" + html.EscapeString(reference.Name) + "</strong></p>")
+	description.WriteString(keyedLine(keyRelationship, reference.Relationship))
+	description.WriteString(keyedLine(keyCompany, reference.Company))
+	if reference.AvailableOnRequest {
+		description.WriteString(keyedLine(keyContact, contactOnRequest))
+	} else {
+		description.WriteString(keyedLine(keyEmail, reference.Email))
+		description.WriteString(keyedLine(keyPhone, reference.Phone))
+	}
+
+	return Achievement{
+		Title:       CodeLabel{Code: achievementReferences, Label: "References"},
+		Description: description.String(),
+	}
+}
+
+// achievementFromCustomEntry exports an entry of a custom section as an
+// achievement labelled with the section title and no code
+func achievementFromCustomEntry(title string, entry *domain.CustomSectionEntry) Achievement {
+	var description strings.Builder
+	description.WriteString("<p><strong>" + html.EscapeString(entry.Title) + "</strong></p>")
+	description.WriteString(keyedLine(keySubtitle, entry.Subtitle))
+	description.WriteString(keyedLine(keyPeriod, dateRange(entry.StartDate, entry.EndDate)))
+	description.WriteString(keyedLine(keyURL, entry.URL))
+	description.WriteString(bulletList(entry.Bullets))
+
+	return Achievement{
+		Title:       CodeLabel{Label: title},
+		Description: description.String(),
+	}
+}
+
+func (c *converter) periodFromDates(field string, start, end domain.PartialDate) Period {
+	var period Period
+	if !start.IsZero() {
+		if date, ok := europassDate(start); ok {
+			period.From = date
+		} else {
+			c.warn(field+".start_date", "Unrecognised start date %q was dropped", start)
+		}
+	}
+	switch {
+	case end.IsZero():
+	case end.IsPresent():
+		period.Current = true
+	default:
+		if date, ok := europassDate(end); ok {
+			period.To = date
+		} else {
+			c.warn(field+".end_date", "Unrecognised end date %q was dropped", end)
+		}
+	}
+	return period
+}
+
+// europassDate converts a date with its precision, Europass dates may leave
+// out the day or the month as well
+func europassDate(date domain.PartialDate) (*Date, bool) {
+	if date.Precision() == domain.PrecisionNone {
+		return nil, false
+	}
+	return &Date{Year: date.Year(), Month: int(date.Month()), Day: date.Day()}, true
+}
+
+func dateRange(start, end string) string {
+	if start == "" {
+		return end
+	}
+	if end == "" {
+		return start
+	}
+	return start + " - " + end
+}
+
+// ToResume maps a Europass document onto a resume aggregate. Entries that
+// cannot be represented, or that do not pass validation after conversion,
+// are skipped and reported as warnings.
+func (d *Document) ToResume() (*domain.Resume, []domain.ConversionWarning) {
+	c := &converter{}
+	resume := &domain.Resume{}
+	learner := d.LearnerInfo
+
+	if learner.Identification != nil {
+		info := c.personalInfoFromIdentification(learner.Identification)
+		if headline := learner.Headline; headline != nil {
+			if headline.Type != nil && headline.Type.Code == headlinePersonalStatement {
+				info.Summary = headline.Description.Label
+			} else {
+				info.JobTitle = headline.Description.Label
+			}
+		}
+		if learner.Identification.Photo != nil {
+			c.warn("Identification.Photo", "Photos are not imported; upload the photo separately")
+		}
+		info.BeforeSave()
+		info.Profiles = c.validProfiles(info.Profiles)
+		if err := info.Validate(); err != nil {
+			c.warn("Identification", "Personal information was skipped: %v", err)
+		} else {
+			resume.PersonalInfo = info
+		}
+	}
+
+	for i, work := range learner.WorkExperience {
+		field := fmt.Sprintf("WorkExperience[%d]", i)
+		exp := &domain.Experience{
+			StartDate: c.partialDate(field+".Period.From", work.Period.From),
+			EndDate:   c.endDate(field+".Period.To", work.Period),
+		}
+		exp.Description, exp.Achievements = splitActivities(work.Activities)
+		if work.Position != nil {
+			exp.JobTitle = work.Position.Label
+		}
+		if work.Employer != nil {
+			exp.Employer = work.Employer.Name
+			exp.Location = organisationLocation(work.Employer)
+		}
+		exp.BeforeSave()
+		if err := exp.Validate(); err != nil {
+			c.warn(field, "Work experience entry was skipped: %v", err)
+			continue
+		}
+		resume.Experience = append(resume.Experience, exp)
+	}
+
+	for i, education := range learner.Education {
+		field := fmt.Sprintf("Education[%d]", i)
+		edu := &domain.Education{
+			Degree:      education.Title,
+			StartDate:   c.partialDate(field+".Period.From", education.Period.From),
+			EndDate:     c.endDate(field+".Period.To", education.Period),
+			Description: plainText(education.Activities),
+		}
+		if education.Organisation != nil {
+			edu.Institution = education.Organisation.Name
+			edu.Location = organisationLocation(education.Organisation)
+		}
+		if education.Field != nil {
+			edu.Field = education.Field.Label
+		}
+		if education.Level != nil && (education.Level.Code != "" || education.Level.Label != "") {
+			c.warn(field+".Level", "Qualification level is not supported and was dropped")
+		}
+		edu.BeforeSave()
+		if err := edu.Validate(); err != nil {
+			c.warn(field, "Education entry was skipped: %v", err)
+			continue
+		}
+		resume.Education = append(resume.Education, edu)
+	}
+
+	if learner.Skills != nil {
+		resume.Skills = c.skillsFromEuropass(learner.Skills)
+		resume.Languages = c.languagesFromEuropass(learner.Skills.Linguistic)
+	}
+
+	customSections := make(map[string]*domain.CustomSection)
+	for i, achievement := range learner.Achievement {
+		field := fmt.Sprintf("Achievement[%d]", i)
+		switch achievement.Title.Code {
+		case achievementProjects:
+			if project := c.projectFromAchievement(field, achievement); project != nil {
+				resume.Projects = append(resume.Projects, project)
+			}
+		case achievementCertifications:
+			if cert := c.certificationFromAchievement(field, achievement); cert != nil {
+				resume.Certifications = append(resume.Certifications, cert)
+			}
+		case achievementHonorsAwards:
+			if award := c.awardFromAchievement(field, achievement); award != nil {
+				resume.Awards = append(resume.Awards, award)
+			}
+		case achievementReferences:
+			if reference := c.referenceFromAchievement(field, achievement); reference != nil {
+				resume.References = append(resume.References, reference)
+			}
+		case "":
+			if strings.EqualFold(strings.TrimSpace(achievement.Title.Label), volunteerLabel) {
+				if volunteer := c.volunteerFromAchievement(field, achievement); volunteer != nil {
+					resume.Volunteer = append(resume.Volunteer, volunteer)
+				}
+				continue
+			}
+			fallthrough
+		default:
+			// Any other achievement section, such as publications or honours,
+			// becomes a custom section named after its label
+			label := strings.TrimSpace(achievement.Title.Label)
+			if label == "" {
+				label = strings.TrimSpace(achievement.Title.Code)
+			}
+			if label == "" {
+				c.warn(field, "Achievement without a title was dropped")
+				continue
+			}
+			entry := c.customEntryFromAchievement(field, achievement)
+			if entry == nil {
+				continue
+			}
+			section, ok := customSections[strings.ToLower(label)]
+			if !ok {
+				section = &domain.CustomSection{Title: label}
+				customSections[strings.ToLower(label)] = section
+				resume.CustomSections = append(resume.CustomSections, section)
+			}
+			section.Entries = append(section.Entries, entry)
+		}
+	}
+
+	return resume, c.warnings
+}
+
+func (c *converter) personalInfoFromIdentification(identification *Identification) *domain.PersonalInfo {
+	info := &domain.PersonalInfo{
+		FirstName: identification.PersonName.FirstName,
+		LastName:  identification.PersonName.Surname,
+	}
+
+	if contact := identification.ContactInfo; contact != nil {
+		if contact.Email != nil {
+			info.Email = contact.Email.Contact
+		}
+		for i, phone := range contact.Telephone {
+			if i == 0 {
+				info.Phone = strings.ReplaceAll(phone.Contact, " ", "")
+				continue
+			}
+			c.warn(fmt.Sprintf("Identification.ContactInfo.Telephone[%d]", i), "Only one phone number is supported; %s was dropped", phone.Contact)
+		}
+		if contact.Address != nil {
+			info.Address.Street = contact.Address.Contact.AddressLine
+			info.Address.City = contact.Address.Contact.Municipality
+			if country := contact.Address.Contact.Country; country != nil {
+				info.Address.Country = country.Label
+				if info.Address.Country == "" {
+					info.Address.Country = country.Code
+				}
+			}
+		}
+		for _, website := range contact.Website {
+			network := domain.ProfileNetworkForURL(website.Contact)
+			if website.Use != nil {
+				if known, ok := profileNetworkForLabel(website.Use.Label); ok {
+					network = known
+				}
+			}
+			info.Profiles = append(info.Profiles, &domain.Profile{Network: network, URL: website.Contact})
+		}
+		for i, messaging := range contact.InstantMessaging {
+			if messaging.Use != nil && strings.EqualFold(messaging.Use.Label, "telegram") {
+				profile := &domain.Profile{Network: domain.ProfileNetworkTelegram, URL: messaging.Contact}
+				if !strings.Contains(messaging.Contact, "/") {
+					profile.Username, profile.URL = messaging.Contact, ""
+				}
+				info.Profiles = append(info.Profiles, profile)
+				continue
+			}
+			c.warn(fmt.Sprintf("Identification.ContactInfo.InstantMessaging[%d]", i), "Instant messaging contacts other than Telegram are not supported; %s was dropped", messaging.Contact)
+		}
+	}
+
+	if identification.Demographics != nil {
+		c.warn("Identification.Demographics", "Birth date, gender and nationality are not supported and were dropped")
+	}
+
+	return info
+}
+
+var profileNetworkLabels = map[string]string{
+	domain.ProfileNetworkLinkedIn:      "LinkedIn",
+	domain.ProfileNetworkGitHub:        "GitHub",
+	domain.ProfileNetworkGitLab:        "GitLab",
+	domain.ProfileNetworkTelegram:      "Telegram",
+	domain.ProfileNetworkTwitter:       "Twitter",
+	domain.ProfileNetworkStackOverflow: "Stack Overflow",
+	domain.ProfileNetworkWebsite:       "Website",
+	domain.ProfileNetworkOther:         "Other",
+}
+
+// validProfiles drops the profiles that do not validate, so a bad link does
+// not cost the rest of the personal information
+func (c *converter) validProfiles(profiles []*domain.Profile) []*domain.Profile {
+	var valid []*domain.Profile
+	for i, profile := range profiles {
+		if err := profile.Validate(); err != nil {
+			c.warn(fmt.Sprintf("Identification.ContactInfo.Profile[%d]", i), "Profile %s was skipped: %v", profile.URL, err)
+			continue
+		}
+		valid = append(valid, profile)
+	}
+	return valid
+}
+
+func profileNetworkLabel(network string) string {
+	if label, ok := profileNetworkLabels[network]; ok {
+		return label
+	}
+	return network
+}
+
+func profileNetworkForLabel(label string) (string, bool) {
+	for network, known := range profileNetworkLabels {
+		if network != domain.ProfileNetworkOther && strings.EqualFold(known, strings.TrimSpace(label)) {
+			return network, true
+		}
+	}
+	return "", false
+}
+
+func organisationLocation(org *Organisation) string {
+	if org.ContactInfo == nil || org.ContactInfo.Address == nil {
+		return ""
+	}
+	address := org.ContactInfo.Address.Contact
+	if address.Municipality != "" {
+		return address.Municipality
+	}
+	if address.Country != nil {
+		return address.Country.Label
+	}
+	return ""
+}
+
+func (c *converter) skillsFromEuropass(skills *Skills) []*domain.Skill {
+	var result []*domain.Skill
+
+	if skills.Computer != nil {
+		result = append(result, skillsFromDescription(skills.Computer.Description)...)
+	}
+	if skills.Other != nil {
+		result = append(result, skillsFromDescription(skills.Other.Description)...)
+	}
+
+	unsupported := []struct {
+		name  string
+		skill *GenericSkill
+	}{
+		{"Communication", skills.Communication},
+		{"Organisational", skills.Organisational},
+		{"JobRelated", skills.JobRelated},
+		{"Driving", skills.Driving},
+	}
+	for _, entry := range unsupported {
+		if entry.skill != nil && strings.TrimSpace(entry.skill.Description) != "" {
+			c.warn("Skills."+entry.name, "%s skills are free text and were dropped", entry.name)
+		}
+	}
+
+	for _, skill := range result {
+		skill.BeforeSave()
+	}
+	return result
+}
+
+// skillsFromDescription splits a skills paragraph into individual skills. A
+// "Frameworks: Gin, Echo" line assigns the matching category.
+func skillsFromDescription(description string) []*domain.Skill {
+	var skills []*domain.Skill
+	for _, line := range strings.Split(plainText(description), "\n") {
+		category := domain.SkillCategoryOther
+		if label, rest, ok := strings.Cut(line, ":"); ok {
+			if known, found := categoryForTitle(label); found {
+				category = known
+				line = rest
+			}
+		}
+		for _, name := range strings.FieldsFunc(line, func(r rune) bool { return r == ',' || r == ';' }) {
+			if name = strings.TrimSpace(name); name != "" {
+				skills = append(skills, &domain.Skill{Name: name, Category: category})
+			}
+		}
+	}
+	return skills
+}
+
+func categoryForTitle(title string) (string, bool) {
+	title = strings.TrimSpace(title)
+	for category, known := range computerSkillTitles {
+		if strings.EqualFold(known, title) {
+			return category, true
+		}
+	}
+	return "", false
+}
+
+func (c *converter) languagesFromEuropass(linguistic *Linguistic) []*domain.Language {
+	if linguistic == nil {
+		return nil
+	}
+
+	var result []*domain.Language
+	for i, tongue := range linguistic.MotherTongue {
+		field := fmt.Sprintf("Skills.Linguistic.MotherTongue[%d]", i)
+		if language := c.language(field, tongue.Description, domain.LanguageLevelNative, ""); language != nil {
+			result = append(result, language)
+		}
+	}
+	for i, foreign := range linguistic.ForeignLanguage {
+		field := fmt.Sprintf("Skills.Linguistic.ForeignLanguage[%d]", i)
+		level := highestCEFR(foreign.ProficiencyLevel)
+		if level == "" {
+			c.warn(field+".ProficiencyLevel", "%s has no CEFR level and was skipped", languageName(foreign.Description))
+			continue
+		}
+		var certificates []string
+		for _, certificate := range foreign.Certificate {
+			if title := strings.TrimSpace(certificate.Title); title != "" {
+				certificates = append(certificates, title)
+			}
+		}
+		if language := c.language(field, foreign.Description, level, strings.Join(certificates, "; ")); language != nil {
+			result = append(result, language)
+		}
+	}
+	return result
+}
+
+func (c *converter) language(field string, description CodeLabel, level, certificate string) *domain.Language {
+	code := strings.ToLower(strings.TrimSpace(description.Code))
+	if _, ok := domain.LanguageName(code); !ok {
+		code, _ = domain.LanguageCodeForName(description.Label)
+	}
+
+	language := &domain.Language{Code: code, Level: level, Certificate: certificate}
+	language.BeforeSave()
+	if err := language.Validate(); err != nil {
+		c.warn(field, "Language %s was skipped: %v", languageName(description), err)
+		return nil
+	}
+	return language
+}
+
+func languageName(description CodeLabel) string {
+	if description.Label != "" {
+		return description.Label
+	}
+	if name, ok := domain.LanguageName(description.Code); ok {
+		return name
+	}
+	return description.Code
+}
+
+func highestCEFR(levels *LanguageProficiency) string {
+	if levels == nil {
+		return ""
+	}
+	highest := ""
+	for _, level := range []string{levels.Listening, levels.Reading, levels.SpokenInteraction, levels.SpokenProduction, levels.Writing} {
+		level = strings.ToUpper(strings.TrimSpace(level))
+		if level > highest {
+			highest = level
+		}
+	}
+	return highest
+}
+
+func (c *converter) projectFromAchievement(field string, achievement Achievement) *domain.Project {
+	title, values, rest := keyedLines(achievement.Description)
+	project := &domain.Project{
+		Name:        title,
+		Description: strings.Join(rest, "\n"),
+		RepoURL:     values[keyRepository],
+		DemoURL:     values[keyDemo],
+	}
+	if technologies := values[keyTechnologies]; technologies != "" {
+		for _, tech := range strings.Split(technologies, ",") {
+			project.Technologies = append(project.Technologies, strings.TrimSpace(tech))
+		}
+	}
+	if period := values[keyPeriod]; period != "" {
+		start, end, _ := strings.Cut(period, " - ")
+		project.StartDate = c.parseDate(field+".start_date", start)
+		project.EndDate = c.parseDate(field+".end_date", end)
+	}
+
+	project.BeforeSave()
+	if err := project.Validate(); err != nil {
+		c.warn(field, "Project was skipped: %v", err)
+		return nil
+	}
+	return project
+}
+
+func (c *converter) certificationFromAchievement(field string, achievement Achievement) *domain.Certification {
+	title, values, rest := keyedLines(achievement.Description)
+	cert := &domain.Certification{
+		Name:         title,
+		Issuer:       values[keyIssuer],
+		IssueDate:    c.parseDate(field+".issue_date", values[keyIssued]),
+		ExpiryDate:   c.parseDate(field+".expiry_date", values[keyExpires]),
+		CredentialID: values[keyCredentialID],
+		URL:          values[keyURL],
+	}
+	if len(rest) > 0 {
+		c.warn(field, "Free text of certification %q was dropped", title)
+	}
+
+	cert.BeforeSave()
+	if err := cert.Validate(); err != nil {
+		c.warn(field, "Certification was skipped: %v", err)
+		return nil
+	}
+	return cert
+}
+
+func (c *converter) volunteerFromAchievement(field string, achievement Achievement) *domain.Volunteer {
+	title, values, rest := keyedLines(achievement.Description)
+	volunteer := &domain.Volunteer{
+		Role:         title,
+		Organization: values[keyOrganization],
+		Location:     values[keyLocation],
+		URL:          values[keyURL],
+		Description:  strings.Join(rest, "\n"),
+	}
+	if period := values[keyPeriod]; period != "" {
+		start, end, _ := strings.Cut(period, " - ")
+		volunteer.StartDate = strings.TrimSpace(start)
+		volunteer.EndDate = strings.TrimSpace(end)
+	}
+
+	volunteer.BeforeSave()
+	if err := volunteer.Validate(); err != nil {
+		c.warn(field, "Volunteer work was skipped: %v", err)
+		return nil
+	}
+	return volunteer
+}
+
+func (c *converter) awardFromAchievement(field string, achievement Achievement) *domain.Award {
+	title, values, rest := keyedLines(achievement.Description)
+	award := &domain.Award{
+		Title:       title,
+		Awarder:     values[keyAwarder],
+		Date:        values[keyDate],
+		URL:         values[keyURL],
+		Description: strings.Join(rest, "\n"),
+	}
+
+	award.BeforeSave()
+	if err := award.Validate(); err != nil {
+		c.warn(field, "Award was skipped: %v", err)
+		return nil
+	}
+	return award
+}
+
+func (c *converter) referenceFromAchievement(field string, achievement Achievement) *domain.Reference {
+	name, values, rest := keyedLines(achievement.Description)
+	reference := &domain.Reference{
+		Name:               name,
+		Relationship:       values[keyRelationship],
+		Company:            values[keyCompany],
+		Email:              values[keyEmail],
+		Phone:              values[keyPhone],
+		AvailableOnRequest: strings.EqualFold(values[keyContact], contactOnRequest),
+	}
+	if len(rest) > 0 {
+		c.warn(field, "Free text of reference %q was dropped", name)
+	}
+
+	reference.BeforeSave()
+	if err := reference.Validate(); err != nil {
+		c.warn(field, "Reference was skipped: %v", err)
+		return nil
+	}
+	return reference
+}
+
+func (c *converter) customEntryFromAchievement(field string, achievement Achievement) *domain.CustomSectionEntry {
+	text, bullets := splitActivities(achievement.Description)
+	title, values, rest := keyedLines(paragraph(text))
+	entry := &domain.CustomSectionEntry{
+		Title:    title,
+		Subtitle: values[keySubtitle],
+		URL:      values[keyURL],
+		Bullets:  append(rest, bullets...),
+	}
+	if period := values[keyPeriod]; period != "" {
+		start, end, _ := strings.Cut(period, " - ")
+		entry.StartDate = strings.TrimSpace(start)
+		entry.EndDate = strings.TrimSpace(end)
+	}
+
+	entry.BeforeSave()
+	if err := entry.Validate(); err != nil {
+		c.warn(field, "Achievement was skipped: %v", err)
+		return nil
+	}
+	return entry
+}
+
+// partialDate converts a Europass date keeping its precision. A day without
+// a month cannot be represented and only the year is kept.
+func (c *converter) partialDate(field string, date *Date) domain.PartialDate {
+	if date == nil || date.Year == 0 {
+		return domain.PartialDate{}
+	}
+
+	var partial domain.PartialDate
+	switch {
+	case date.Month == 0:
+		partial = domain.NewYear(date.Year)
+	case date.Day == 0:
+		partial = domain.NewYearMonth(date.Year, time.Month(date.Month))
+	default:
+		partial = domain.NewDate(date.Year, time.Month(date.Month), date.Day)
+	}
+
+	// Round trip through the parser to range check the components
+	checked, err := domain.ParsePartialDate(partial.String())
+	if err != nil {
+		c.warn(field, "Invalid date %s was dropped", partial)
+		return domain.PartialDate{}
+	}
+	return checked
+}
+
+func (c *converter) endDate(field string, period Period) domain.PartialDate {
+	if period.Current {
+		return domain.Present()
+	}
+	return c.partialDate(field, period.To)
+}
+
+// parseDate reads a date written into an achievement description
+func (c *converter) parseDate(field, value string) domain.PartialDate {
+	date, err := domain.ParsePartialDate(value)
+	if err != nil {
+		c.warn(field, "Unrecognised date %q was dropped", strings.TrimSpace(value))
+		return domain.PartialDate{}
+	}
+	return date
+}
@@ -0,0 +1,144 @@
+package europass
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// Europass stores rich text (activities, skill descriptions, achievements)
+// as small HTML fragments made of paragraphs and bullet lists.
+
+var (
+	listItemRegex  = regexp.MustCompile(`(?is)<li[^>]*>(.*?)</li>`)
+	listRegex      = regexp.MustCompile(`(?is)<(ul|ol)[^>]*>.*?</(ul|ol)>`)
+	lineBreakRegex = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</div>`)
+	tagRegex       = regexp.MustCompile(`<[^>]*>`)
+)
+
+func paragraph(text string) string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return ""
+	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = html.EscapeString(strings.TrimSpace(line))
+	}
+	return "<p>" + strings.Join(lines, "<br/>") + "</p>"
+}
+
+func bulletList(items []string) string {
+	if len(items) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("<ul>")
+	for _, item := range items {
+		b.WriteString("<li>")
+		b.WriteString(html.EscapeString(item))
+		b.WriteString("</li>")
+	}
+	b.WriteString("</ul>")
+	return b.String()
+}
+
+// plainText converts an HTML fragment into text, keeping line breaks
+func plainText(fragment string) string {
+	text := lineBreakRegex.ReplaceAllString(fragment, "\n")
+	text = tagRegex.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+
+	lines := strings.Split(text, "\n")
+	kept := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// splitActivities separates the bullet list items of an HTML fragment from
+// the surrounding text
+func splitActivities(fragment string) (string, []string) {
+	var items []string
+	for _, match := range listItemRegex.FindAllStringSubmatch(fragment, -1) {
+		if item := plainText(match[1]); item != "" {
+			items = append(items, item)
+		}
+	}
+	description := plainText(listRegex.ReplaceAllString(fragment, "\n"))
+	return description, items
+}
+
+// keyedLines returns the first line of a fragment as its title along with
+// the "Key: value" lines written by this package and any remaining text
+func keyedLines(fragment string) (string, map[string]string, []string) {
+	lines := strings.Split(plainText(fragment), "\n")
+	if len(lines) == 0 {
+		return "", nil, nil
+	}
+	title := lines[0]
+	values := make(map[string]string)
+	var rest []string
+	for _, line := range lines[1:] {
+		if key, value, ok := strings.Cut(line, ":"); ok && knownKeys[key] {
+			values[key] = strings.TrimSpace(value)
+			continue
+		}
+		rest = append(rest, line)
+	}
+	return title, values, rest
+}
+
+const (
+	keyTechnologies = "Technologies"
+	keyPeriod       = "Period"
+	keyRepository   = "Repository"
+	keyDemo         = "Demo"
+	keyIssuer       = "Issuer"
+	keyIssued       = "Issued"
+	keyExpires      = "Expires"
+	keyCredentialID = "Credential ID"
+	keyURL          = "URL"
+	keySubtitle     = "Subtitle"
+	keyOrganization = "Organization"
+	keyLocation     = "Location"
+	keyAwarder      = "Awarder"
+	keyDate         = "Date"
+	keyRelationship = "Relationship"
+	keyCompany      = "Company"
+	keyEmail        = "Email"
+	keyPhone        = "Phone"
+	keyContact      = "Contact"
+)
+
+var knownKeys = map[string]bool{
+	keyTechnologies: true,
+	keyPeriod:       true,
+	keyRepository:   true,
+	keyDemo:         true,
+	keyIssuer:       true,
+	keyIssued:       true,
+	keyExpires:      true,
+	keyCredentialID: true,
+	keyURL:          true,
+	keySubtitle:     true,
+	keyOrganization: true,
+	keyLocation:     true,
+	keyAwarder:      true,
+	keyDate:         true,
+	keyRelationship: true,
+	keyCompany:      true,
+	keyEmail:        true,
+	keyPhone:        true,
+	keyContact:      true,
+}
+
+func keyedLine(key, value string) string {
+	if strings.TrimSpace(value) == "" {
+		return ""
+	}
+	return paragraph(key + ": " + value)
+}
@@ -0,0 +1,50 @@
+package europass
+
+import "strings"
+
+// CEFRFromProficiency approximates a CEFR level from the 1-5 skill scale
+func CEFRFromProficiency(proficiency int) string {
+	switch proficiency {
+	case 1:
+		return "A1"
+	case 2:
+		return "A2"
+	case 3:
+		return "B1"
+	case 4:
+		return "B2"
+	case 5:
+		return "C1"
+	default:
+		return ""
+	}
+}
+
+// ProficiencyFromCEFR approximates the 1-5 skill scale from a CEFR level
+func ProficiencyFromCEFR(level string) int {
+	switch strings.ToUpper(strings.TrimSpace(level)) {
+	case "A1":
+		return 1
+	case "A2":
+		return 2
+	case "B1":
+		return 3
+	case "B2":
+		return 4
+	case "C1", "C2":
+		return 5
+	default:
+		return 0
+	}
+}
+
+// uniformProficiency applies one CEFR level to every language activity
+func uniformProficiency(level string) *LanguageProficiency {
+	return &LanguageProficiency{
+		Listening:         level,
+		Reading:           level,
+		SpokenInteraction: level,
+		SpokenProduction:  level,
+		Writing:           level,
+	}
+}
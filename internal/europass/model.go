@@ -0,0 +1,356 @@
+package europass
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Namespace is the XML namespace of Europass SkillsPassport documents
+const Namespace = "http://europass.cedefop.europa.eu/Europass"
+
+// Document is the subset of the Europass v3 SkillsPassport data model that
+// maps onto a resume. The same structure serialises to the XML and JSON
+// flavours of the format.
+type Document struct {
+	XMLName      xml.Name      `xml:"SkillsPassport" json:"-"`
+	Xmlns        string        `xml:"xmlns,attr,omitempty" json:"-"`
+	Locale       string        `xml:"locale,attr,omitempty" json:"Locale,omitempty"`
+	DocumentInfo *DocumentInfo `xml:"DocumentInfo,omitempty" json:"DocumentInfo,omitempty"`
+	LearnerInfo  LearnerInfo   `xml:"LearnerInfo" json:"LearnerInfo"`
+}
+
+// jsonDocument is the top-level envelope of Europass JSON documents
+type jsonDocument struct {
+	SkillsPassport *Document `json:"SkillsPassport"`
+}
+
+type DocumentInfo struct {
+	DocumentType string `xml:"DocumentType" json:"DocumentType"`
+	CreationDate string `xml:"CreationDate,omitempty" json:"CreationDate,omitempty"`
+	XSDVersion   string `xml:"XSDVersion,omitempty" json:"XSDVersion,omitempty"`
+	Generator    string `xml:"Generator,omitempty" json:"Generator,omitempty"`
+}
+
+type LearnerInfo struct {
+	Identification *Identification  `xml:"Identification,omitempty" json:"Identification,omitempty"`
+	Headline       *Headline        `xml:"Headline,omitempty" json:"Headline,omitempty"`
+	WorkExperience []WorkExperience `xml:"WorkExperienceList>WorkExperience,omitempty" json:"WorkExperience,omitempty"`
+	Education      []Education      `xml:"EducationList>Education,omitempty" json:"Education,omitempty"`
+	Skills         *Skills          `xml:"Skills,omitempty" json:"Skills,omitempty"`
+	Achievement    []Achievement    `xml:"AchievementList>Achievement,omitempty" json:"Achievement,omitempty"`
+}
+
+type Identification struct {
+	PersonName   PersonName    `xml:"PersonName" json:"PersonName"`
+	ContactInfo  *ContactInfo  `xml:"ContactInfo,omitempty" json:"ContactInfo,omitempty"`
+	Demographics *Demographics `xml:"Demographics,omitempty" json:"Demographics,omitempty"`
+	Photo        *Photo        `xml:"Photo,omitempty" json:"Photo,omitempty"`
+}
+
+// Photo embeds an image as base64 data
+type Photo struct {
+	MimeType string `xml:"MimeType" json:"MimeType"`
+	Data     string `xml:"Data" json:"Data"`
+}
+
+type PersonName struct {
+	FirstName string `xml:"FirstName" json:"FirstName"`
+	Surname   string `xml:"Surname" json:"Surname"`
+}
+
+type ContactInfo struct {
+	Address          *Address         `xml:"Address,omitempty" json:"Address,omitempty"`
+	Email            *Contact         `xml:"Email,omitempty" json:"Email,omitempty"`
+	Telephone        []Telephone      `xml:"TelephoneList>Telephone,omitempty" json:"Telephone,omitempty"`
+	Website          []Website        `xml:"WebsiteList>Website,omitempty" json:"Website,omitempty"`
+	InstantMessaging []InstantMessage `xml:"InstantMessagingList>InstantMessaging,omitempty" json:"InstantMessaging,omitempty"`
+}
+
+type Address struct {
+	Contact AddressContact `xml:"Contact" json:"Contact"`
+}
+
+type AddressContact struct {
+	AddressLine  string     `xml:"AddressLine,omitempty" json:"AddressLine,omitempty"`
+	PostalCode   string     `xml:"PostalCode,omitempty" json:"PostalCode,omitempty"`
+	Municipality string     `xml:"Municipality,omitempty" json:"Municipality,omitempty"`
+	Country      *CodeLabel `xml:"Country,omitempty" json:"Country,omitempty"`
+}
+
+type Contact struct {
+	Contact string `xml:"Contact" json:"Contact"`
+}
+
+type Telephone struct {
+	Contact string     `xml:"Contact" json:"Contact"`
+	Use     *CodeLabel `xml:"Use,omitempty" json:"Use,omitempty"`
+}
+
+type Website struct {
+	Contact string     `xml:"Contact" json:"Contact"`
+	Use     *CodeLabel `xml:"Use,omitempty" json:"Use,omitempty"`
+}
+
+type InstantMessage struct {
+	Contact string     `xml:"Contact" json:"Contact"`
+	Use     *CodeLabel `xml:"Use,omitempty" json:"Use,omitempty"`
+}
+
+type Demographics struct {
+	Birthdate   *Date       `xml:"Birthdate,omitempty" json:"Birthdate,omitempty"`
+	Gender      *CodeLabel  `xml:"Gender,omitempty" json:"Gender,omitempty"`
+	Nationality []CodeLabel `xml:"NationalityList>Nationality,omitempty" json:"Nationality,omitempty"`
+}
+
+type CodeLabel struct {
+	Code  string `xml:"Code,omitempty" json:"Code,omitempty"`
+	Label string `xml:"Label,omitempty" json:"Label,omitempty"`
+}
+
+type Headline struct {
+	Type        *CodeLabel `xml:"Type,omitempty" json:"Type,omitempty"`
+	Description CodeLabel  `xml:"Description" json:"Description"`
+}
+
+type Period struct {
+	From    *Date `xml:"From,omitempty" json:"From,omitempty"`
+	To      *Date `xml:"To,omitempty" json:"To,omitempty"`
+	Current bool  `xml:"Current,omitempty" json:"Current,omitempty"`
+}
+
+type Organisation struct {
+	Name        string                   `xml:"Name,omitempty" json:"Name,omitempty"`
+	ContactInfo *OrganisationContactInfo `xml:"ContactInfo,omitempty" json:"ContactInfo,omitempty"`
+}
+
+type OrganisationContactInfo struct {
+	Address *Address `xml:"Address,omitempty" json:"Address,omitempty"`
+	Website *Contact `xml:"Website,omitempty" json:"Website,omitempty"`
+}
+
+type WorkExperience struct {
+	Period     Period        `xml:"Period" json:"Period"`
+	Position   *CodeLabel    `xml:"Position,omitempty" json:"Position,omitempty"`
+	Activities string        `xml:"Activities,omitempty" json:"Activities,omitempty"`
+	Employer   *Organisation `xml:"Employer,omitempty" json:"Employer,omitempty"`
+}
+
+type Education struct {
+	Period       Period        `xml:"Period" json:"Period"`
+	Title        string        `xml:"Title,omitempty" json:"Title,omitempty"`
+	Activities   string        `xml:"Activities,omitempty" json:"Activities,omitempty"`
+	Organisation *Organisation `xml:"Organisation,omitempty" json:"Organisation,omitempty"`
+	Level        *CodeLabel    `xml:"Level,omitempty" json:"Level,omitempty"`
+	Field        *CodeLabel    `xml:"Field,omitempty" json:"Field,omitempty"`
+}
+
+type Skills struct {
+	Linguistic     *Linguistic   `xml:"Linguistic,omitempty" json:"Linguistic,omitempty"`
+	Communication  *GenericSkill `xml:"Communication,omitempty" json:"Communication,omitempty"`
+	Organisational *GenericSkill `xml:"Organisational,omitempty" json:"Organisational,omitempty"`
+	JobRelated     *GenericSkill `xml:"JobRelated,omitempty" json:"JobRelated,omitempty"`
+	Computer       *GenericSkill `xml:"Computer,omitempty" json:"Computer,omitempty"`
+	Driving        *GenericSkill `xml:"Driving,omitempty" json:"Driving,omitempty"`
+	Other          *GenericSkill `xml:"Other,omitempty" json:"Other,omitempty"`
+}
+
+type Linguistic struct {
+	MotherTongue    []MotherTongue    `xml:"MotherTongueList>MotherTongue,omitempty" json:"MotherTongue,omitempty"`
+	ForeignLanguage []ForeignLanguage `xml:"ForeignLanguageList>ForeignLanguage,omitempty" json:"ForeignLanguage,omitempty"`
+}
+
+type MotherTongue struct {
+	Description CodeLabel `xml:"Description" json:"Description"`
+}
+
+type ForeignLanguage struct {
+	Description      CodeLabel             `xml:"Description" json:"Description"`
+	ProficiencyLevel *LanguageProficiency  `xml:"ProficiencyLevel,omitempty" json:"ProficiencyLevel,omitempty"`
+	Certificate      []LanguageCertificate `xml:"VerificationList>Verification>Certificate,omitempty" json:"Certificate,omitempty"`
+}
+
+// LanguageProficiency holds CEFR levels (A1-C2) for each language activity
+type LanguageProficiency struct {
+	Listening         string `xml:"Listening,omitempty" json:"Listening,omitempty"`
+	Reading           string `xml:"Reading,omitempty" json:"Reading,omitempty"`
+	SpokenInteraction string `xml:"SpokenInteraction,omitempty" json:"SpokenInteraction,omitempty"`
+	SpokenProduction  string `xml:"SpokenProduction,omitempty" json:"SpokenProduction,omitempty"`
+	Writing           string `xml:"Writing,omitempty" json:"Writing,omitempty"`
+}
+
+type LanguageCertificate struct {
+	Title string `xml:"Title" json:"Title"`
+}
+
+type GenericSkill struct {
+	Description string `xml:"Description,omitempty" json:"Description,omitempty"`
+}
+
+type Achievement struct {
+	Title       CodeLabel `xml:"Title" json:"Title"`
+	Description string    `xml:"Description,omitempty" json:"Description,omitempty"`
+}
+
+// Date is a possibly partial Europass date. In XML it is written as the
+// year, month and day attributes using the XML Schema gYear, gMonth and gDay
+// lexical forms ("2019", "--03", "---01"); in JSON as plain numbers.
+type Date struct {
+	Year  int `json:"Year,omitempty"`
+	Month int `json:"Month,omitempty"`
+	Day   int `json:"Day,omitempty"`
+}
+
+func (d Date) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if d.Year != 0 {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "year"}, Value: fmt.Sprintf("%04d", d.Year)})
+	}
+	if d.Month != 0 {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "month"}, Value: fmt.Sprintf("--%02d", d.Month)})
+	}
+	if d.Day != 0 {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "day"}, Value: fmt.Sprintf("---%02d", d.Day)})
+	}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	return e.EncodeToken(start.End())
+}
+
+func (d *Date) UnmarshalXML(dec *xml.Decoder, start xml.StartElement) error {
+	for _, attr := range start.Attr {
+		value, err := strconv.Atoi(strings.TrimLeft(attr.Value, "-"))
+		if err != nil {
+			return fmt.Errorf("invalid %s attribute %q: %w", attr.Name.Local, attr.Value, err)
+		}
+		switch attr.Name.Local {
+		case "year":
+			d.Year = value
+		case "month":
+			d.Month = value
+		case "day":
+			d.Day = value
+		}
+	}
+	return dec.Skip()
+}
+
+// encoding/xml writes the parent element of an empty "List>Item" field even
+// when it is tagged omitempty, so types holding lists marshal themselves.
+
+func (l LearnerInfo) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeElement(e, start, func() error {
+		if err := encodeOptional(e, "Identification", l.Identification); err != nil {
+			return err
+		}
+		if err := encodeOptional(e, "Headline", l.Headline); err != nil {
+			return err
+		}
+		if err := encodeList(e, "WorkExperienceList", "WorkExperience", l.WorkExperience); err != nil {
+			return err
+		}
+		if err := encodeList(e, "EducationList", "Education", l.Education); err != nil {
+			return err
+		}
+		if err := encodeOptional(e, "Skills", l.Skills); err != nil {
+			return err
+		}
+		return encodeList(e, "AchievementList", "Achievement", l.Achievement)
+	})
+}
+
+func (c ContactInfo) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeElement(e, start, func() error {
+		if err := encodeOptional(e, "Address", c.Address); err != nil {
+			return err
+		}
+		if err := encodeOptional(e, "Email", c.Email); err != nil {
+			return err
+		}
+		if err := encodeList(e, "TelephoneList", "Telephone", c.Telephone); err != nil {
+			return err
+		}
+		if err := encodeList(e, "WebsiteList", "Website", c.Website); err != nil {
+			return err
+		}
+		return encodeList(e, "InstantMessagingList", "InstantMessaging", c.InstantMessaging)
+	})
+}
+
+func (d Demographics) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeElement(e, start, func() error {
+		if err := encodeOptional(e, "Birthdate", d.Birthdate); err != nil {
+			return err
+		}
+		if err := encodeOptional(e, "Gender", d.Gender); err != nil {
+			return err
+		}
+		return encodeList(e, "NationalityList", "Nationality", d.Nationality)
+	})
+}
+
+func (l Linguistic) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeElement(e, start, func() error {
+		if err := encodeList(e, "MotherTongueList", "MotherTongue", l.MotherTongue); err != nil {
+			return err
+		}
+		return encodeList(e, "ForeignLanguageList", "ForeignLanguage", l.ForeignLanguage)
+	})
+}
+
+func (f ForeignLanguage) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeElement(e, start, func() error {
+		if err := e.EncodeElement(f.Description, xml.StartElement{Name: xml.Name{Local: "Description"}}); err != nil {
+			return err
+		}
+		if err := encodeOptional(e, "ProficiencyLevel", f.ProficiencyLevel); err != nil {
+			return err
+		}
+		if len(f.Certificate) == 0 {
+			return nil
+		}
+		list := xml.StartElement{Name: xml.Name{Local: "VerificationList"}}
+		return encodeElement(e, list, func() error {
+			verification := xml.StartElement{Name: xml.Name{Local: "Verification"}}
+			return encodeElement(e, verification, func() error {
+				return encodeItems(e, "Certificate", f.Certificate)
+			})
+		})
+	})
+}
+
+func encodeElement(e *xml.Encoder, start xml.StartElement, content func() error) error {
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	if err := content(); err != nil {
+		return err
+	}
+	return e.EncodeToken(start.End())
+}
+
+func encodeOptional[T any](e *xml.Encoder, name string, value *T) error {
+	if value == nil {
+		return nil
+	}
+	return e.EncodeElement(value, xml.StartElement{Name: xml.Name{Local: name}})
+}
+
+func encodeList[T any](e *xml.Encoder, list, item string, items []T) error {
+	if len(items) == 0 {
+		return nil
+	}
+	return encodeElement(e, xml.StartElement{Name: xml.Name{Local: list}}, func() error {
+		return encodeItems(e, item, items)
+	})
+}
+
+func encodeItems[T any](e *xml.Encoder, name string, items []T) error {
+	for _, item := range items {
+		if err := e.EncodeElement(item, xml.StartElement{Name: xml.Name{Local: name}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
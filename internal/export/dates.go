@@ -0,0 +1,112 @@
+package export
+
+import (
+	"cv_builder/internal/domain"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+var ErrUnsupportedLocale = errors.New("unsupported export locale")
+
+// dateStyle writes dates the way they are usually written in a language
+type dateStyle struct {
+	present   string
+	monthYear func(month time.Month, year int) string
+	fullDate  func(day int, month time.Month, year int) string
+}
+
+func monthNames(names ...string) func(time.Month) string {
+	return func(month time.Month) string {
+		return names[month-1]
+	}
+}
+
+var (
+	englishMonths = monthNames("Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec")
+	germanMonths  = monthNames("Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember")
+	frenchMonths  = monthNames("janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre")
+	spanishMonths = monthNames("enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre")
+	// Russian uses the nominative for a month on its own and the genitive
+	// after a day
+	russianMonths    = monthNames("январь", "февраль", "март", "апрель", "май", "июнь", "июль", "август", "сентябрь", "октябрь", "ноябрь", "декабрь")
+	russianDayMonths = monthNames("января", "февраля", "марта", "апреля", "мая", "июня", "июля", "августа", "сентября", "октября", "ноября", "декабря")
+)
+
+var dateStyles = map[string]*dateStyle{
+	"en": {
+		present:   "Present",
+		monthYear: func(m time.Month, y int) string { return fmt.Sprintf("%s %d", englishMonths(m), y) },
+		fullDate:  func(d int, m time.Month, y int) string { return fmt.Sprintf("%s %d, %d", englishMonths(m), d, y) },
+	},
+	"de": {
+		present:   "heute",
+		monthYear: func(m time.Month, y int) string { return fmt.Sprintf("%s %d", germanMonths(m), y) },
+		fullDate:  func(d int, m time.Month, y int) string { return fmt.Sprintf("%d. %s %d", d, germanMonths(m), y) },
+	},
+	"fr": {
+		present:   "aujourd'hui",
+		monthYear: func(m time.Month, y int) string { return fmt.Sprintf("%s %d", frenchMonths(m), y) },
+		fullDate:  func(d int, m time.Month, y int) string { return fmt.Sprintf("%d %s %d", d, frenchMonths(m), y) },
+	},
+	"es": {
+		present:   "actualidad",
+		monthYear: func(m time.Month, y int) string { return fmt.Sprintf("%s de %d", spanishMonths(m), y) },
+		fullDate:  func(d int, m time.Month, y int) string { return fmt.Sprintf("%d de %s de %d", d, spanishMonths(m), y) },
+	},
+	"ru": {
+		present:   "настоящее время",
+		monthYear: func(m time.Month, y int) string { return fmt.Sprintf("%s %d", russianMonths(m), y) },
+		fullDate:  func(d int, m time.Month, y int) string { return fmt.Sprintf("%d %s %d", d, russianDayMonths(m), y) },
+	},
+}
+
+// Locales lists the locales dates can be written in
+func Locales() []string {
+	locales := make([]string, 0, len(dateStyles))
+	for locale := range dateStyles {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+func styleForLocale(locale string) (*dateStyle, error) {
+	if locale == "" {
+		locale = "en"
+	}
+	style, ok := dateStyles[locale]
+	if !ok {
+		return nil, ErrUnsupportedLocale
+	}
+	return style, nil
+}
+
+// format writes a date with the precision it is known to
+func (s *dateStyle) format(date domain.PartialDate) string {
+	if date.IsPresent() {
+		return s.present
+	}
+	switch date.Precision() {
+	case domain.PrecisionYear:
+		return strconv.Itoa(date.Year())
+	case domain.PrecisionMonth:
+		return s.monthYear(date.Month(), date.Year())
+	case domain.PrecisionDay:
+		return s.fullDate(date.Day(), date.Month(), date.Year())
+	}
+	// Unparsed input is printed as it was entered
+	return date.String()
+}
+
+// formatString formats a date kept as a string by sections that do not use
+// partial dates yet
+func (s *dateStyle) formatString(value string) string {
+	date, err := domain.ParsePartialDate(value)
+	if err != nil {
+		return value
+	}
+	return s.format(date)
+}
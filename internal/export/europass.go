@@ -0,0 +1,51 @@
+package export
+
+import (
+	"cv_builder/internal/domain"
+	"cv_builder/internal/europass"
+	"io"
+)
+
+// EuropassXMLExporter renders a resume as a Europass SkillsPassport XML document
+type EuropassXMLExporter struct{}
+
+func (e *EuropassXMLExporter) ContentType() string {
+	return "application/xml; charset=utf-8"
+}
+
+func (e *EuropassXMLExporter) FileExtension() string {
+	return "xml"
+}
+
+func (e *EuropassXMLExporter) Export(w io.Writer, resume *domain.Resume, options Options) ([]domain.ConversionWarning, error) {
+	doc, warnings := europass.FromResume(resume)
+	if options.Locale != "" {
+		doc.Locale = options.Locale
+	}
+	if err := europass.WriteXML(w, doc); err != nil {
+		return nil, err
+	}
+	return warnings, nil
+}
+
+// EuropassJSONExporter renders a resume as a Europass SkillsPassport JSON document
+type EuropassJSONExporter struct{}
+
+func (e *EuropassJSONExporter) ContentType() string {
+	return "application/json; charset=utf-8"
+}
+
+func (e *EuropassJSONExporter) FileExtension() string {
+	return "json"
+}
+
+func (e *EuropassJSONExporter) Export(w io.Writer, resume *domain.Resume, options Options) ([]domain.ConversionWarning, error) {
+	doc, warnings := europass.FromResume(resume)
+	if options.Locale != "" {
+		doc.Locale = options.Locale
+	}
+	if err := europass.WriteJSON(w, doc); err != nil {
+		return nil, err
+	}
+	return warnings, nil
+}
@@ -0,0 +1,56 @@
+package export
+
+import (
+	"cv_builder/internal/domain"
+	"errors"
+	"io"
+	"sort"
+)
+
+const (
+	FormatLaTeX        = "latex"
+	FormatEuropassXML  = "europass-xml"
+	FormatEuropassJSON = "europass-json"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported export format")
+
+// Options control how a resume is rendered
+type Options struct {
+	// Locale selects the language dates are written in, "en" by default.
+	// It must be one of Locales.
+	Locale string
+}
+
+// Exporter renders a complete resume into a downloadable document. Data the
+// target format cannot represent is reported as conversion warnings.
+type Exporter interface {
+	ContentType() string
+	FileExtension() string
+	Export(w io.Writer, resume *domain.Resume, options Options) ([]domain.ConversionWarning, error)
+}
+
+var exporters = map[string]Exporter{
+	FormatLaTeX:        &LaTeXExporter{},
+	FormatEuropassXML:  &EuropassXMLExporter{},
+	FormatEuropassJSON: &EuropassJSONExporter{},
+}
+
+// Get returns the exporter registered for the given format
+func Get(format string) (Exporter, error) {
+	exporter, ok := exporters[format]
+	if !ok {
+		return nil, ErrUnsupportedFormat
+	}
+	return exporter, nil
+}
+
+// Formats lists the names of all registered export formats
+func Formats() []string {
+	formats := make([]string, 0, len(exporters))
+	for format := range exporters {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
@@ -0,0 +1,526 @@
+package export
+
+import (
+	"cv_builder/internal/domain"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// LaTeXExporter produces a self-contained .tex source file laid out with the
+// moderncv document class, so users can compile and tweak it themselves.
+type LaTeXExporter struct{}
+
+func (e *LaTeXExporter) ContentType() string {
+	return "application/x-tex; charset=utf-8"
+}
+
+func (e *LaTeXExporter) FileExtension() string {
+	return "tex"
+}
+
+func (e *LaTeXExporter) Export(w io.Writer, resume *domain.Resume, options Options) ([]domain.ConversionWarning, error) {
+	dates, err := styleForLocale(options.Locale)
+	if err != nil {
+		return nil, err
+	}
+	doc := &latexDocument{dates: dates}
+
+	// Personal details and the body are rendered first so the preamble knows
+	// which font encodings the escaped text requires
+	doc.writePersonalInfo(resume.PersonalInfo)
+	doc.writePhoto(resume.Photo)
+	doc.writeBody(resume)
+
+	var out strings.Builder
+	doc.writePreamble(&out)
+	out.WriteString(doc.header.String())
+	out.WriteString(doc.body.String())
+
+	_, err = io.WriteString(w, out.String())
+	return doc.warnings, err
+}
+
+// latexTextReplacer escapes the characters that have a special meaning in
+// LaTeX. strings.Replacer performs a single pass, so the backslashes it
+// inserts are never escaped a second time.
+var latexTextReplacer = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`$`, `\$`,
+	`&`, `\&`,
+	`#`, `\#`,
+	`%`, `\%`,
+	`_`, `\_`,
+	`^`, `\textasciicircum{}`,
+	`~`, `\textasciitilde{}`,
+	`<`, `\textless{}`,
+	`>`, `\textgreater{}`,
+	`|`, `\textbar{}`,
+	"\r\n", `\newline{}`,
+	"\n", `\newline{}`,
+	"\r", `\newline{}`,
+	"\t", " ",
+)
+
+// latexURLReplacer escapes a URL used as the target of \href. The result is
+// usually nested inside moderncv macro arguments, where hyperref can no
+// longer change category codes itself.
+var latexURLReplacer = strings.NewReplacer(
+	`\`, `%5C`,
+	`{`, `%7B`,
+	`}`, `%7D`,
+	` `, `%20`,
+	`%`, `\%`,
+	`#`, `\#`,
+	`&`, `\string&`,
+	`_`, `\string_`,
+	`~`, `\string~`,
+	`^`, `\string^`,
+	`$`, `\string$`,
+)
+
+// EscapeLaTeX escapes free text so it can be placed verbatim in a LaTeX document
+func EscapeLaTeX(s string) string {
+	return latexTextReplacer.Replace(stripControl(s))
+}
+
+// EscapeLaTeXURL escapes a URL so it can be used as a hyperlink target
+func EscapeLaTeXURL(s string) string {
+	return latexURLReplacer.Replace(stripControl(strings.TrimSpace(s)))
+}
+
+// stripControl drops control characters other than line breaks and tabs,
+// which would otherwise end up as invalid input for the TeX engine
+func stripControl(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\r' || r == '\t' {
+			return r
+		}
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+type latexDocument struct {
+	header   strings.Builder
+	body     strings.Builder
+	cyrillic bool
+	dates    *dateStyle
+	warnings []domain.ConversionWarning
+}
+
+// text escapes a free-text field and records which scripts it uses
+func (d *latexDocument) text(s string) string {
+	if !d.cyrillic {
+		for _, r := range s {
+			if unicode.Is(unicode.Cyrillic, r) {
+				d.cyrillic = true
+				break
+			}
+		}
+	}
+	return EscapeLaTeX(s)
+}
+
+func (d *latexDocument) link(url, label string) string {
+	return fmt.Sprintf(`\href{%s}{%s}`, EscapeLaTeXURL(url), d.text(label))
+}
+
+func (d *latexDocument) writePreamble(out *strings.Builder) {
+	out.WriteString("% Generated by cv_builder. Compile with pdflatex; requires the moderncv package.\n")
+	out.WriteString("\\documentclass[11pt,a4paper,sans]{moderncv}\n")
+	out.WriteString("\\moderncvstyle{classic}\n")
+	out.WriteString("\\moderncvcolor{blue}\n")
+	out.WriteString("\\usepackage[utf8]{inputenc}\n")
+	if d.cyrillic {
+		out.WriteString("\\usepackage[T2A,T1]{fontenc}\n")
+		out.WriteString("\\usepackage[russian,english]{babel}\n")
+	} else {
+		out.WriteString("\\usepackage[T1]{fontenc}\n")
+	}
+	out.WriteString("\\usepackage[scale=0.8]{geometry}\n\n")
+}
+
+func (d *latexDocument) writePersonalInfo(info *domain.PersonalInfo) {
+	out := &d.header
+	if info == nil {
+		out.WriteString("\\name{}{}\n\n")
+		return
+	}
+
+	fmt.Fprintf(out, "\\name{%s}{%s}\n", d.text(info.FirstName), d.text(info.LastName))
+	if info.JobTitle != "" {
+		fmt.Fprintf(out, "\\title{%s}\n", d.text(info.JobTitle))
+	}
+	if info.Address.Street != "" || info.Address.City != "" || info.Address.Country != "" {
+		fmt.Fprintf(out, "\\address{%s}{%s}{%s}\n",
+			d.text(info.Address.Street), d.text(info.Address.City), d.text(info.Address.Country))
+	}
+	if info.Phone != "" {
+		fmt.Fprintf(out, "\\phone[mobile]{%s}\n", d.text(info.Phone))
+	}
+	if info.Email != "" {
+		fmt.Fprintf(out, "\\email{%s}\n", EscapeLaTeXURL(info.Email))
+	}
+	d.writeProfiles(info.Profiles)
+	out.WriteString("\n")
+}
+
+// writePhoto references the photo as a file next to the .tex source, since a
+// single text document cannot carry the image itself. The document still
+// compiles without the file, only the photo is missing then.
+func (d *latexDocument) writePhoto(photo *domain.Photo) {
+	if photo == nil {
+		return
+	}
+
+	name := "photo.jpg"
+	if photo.ContentType == "image/png" {
+		name = "photo.png"
+	}
+	fmt.Fprintf(&d.header, "\\IfFileExists{%s}{\\photo[64pt][0.4pt]{%s}}{}\n\n", name, name)
+	d.warnings = append(d.warnings, domain.NewConversionWarning("photo",
+		fmt.Sprintf("The photo is referenced as %s; download it and place it next to the .tex file to include it", name)))
+}
+
+// latexSocialNetworks are the networks every moderncv version supports in
+// \social; other profiles are listed as links
+var latexSocialNetworks = map[string]bool{
+	domain.ProfileNetworkLinkedIn: true,
+	domain.ProfileNetworkGitHub:   true,
+	domain.ProfileNetworkTwitter:  true,
+}
+
+func (d *latexDocument) writeProfiles(profiles []*domain.Profile) {
+	out := &d.header
+	homepage := false
+	var links []string
+	for _, profile := range profiles {
+		if profile == nil {
+			continue
+		}
+		switch {
+		case latexSocialNetworks[profile.Network] && profile.Username != "":
+			fmt.Fprintf(out, "\\social[%s]{%s}\n", profile.Network, d.text(profile.Username))
+		case profile.Network == domain.ProfileNetworkWebsite && !homepage:
+			fmt.Fprintf(out, "\\homepage{%s}\n", EscapeLaTeXURL(strings.TrimPrefix(strings.TrimPrefix(profile.URL, "https://"), "http://")))
+			homepage = true
+		default:
+			links = append(links, d.link(profile.URL, profile.URL))
+		}
+	}
+	if len(links) > 0 {
+		fmt.Fprintf(out, "\\extrainfo{%s}\n", strings.Join(links, " | "))
+	}
+}
+
+func (d *latexDocument) writeBody(resume *domain.Resume) {
+	d.body.WriteString("\\begin{document}\n")
+	d.body.WriteString("\\makecvtitle\n")
+
+	if resume.PersonalInfo != nil && resume.PersonalInfo.Summary != "" {
+		d.section("Summary")
+		fmt.Fprintf(&d.body, "\\cvitem{}{%s}\n", d.text(resume.PersonalInfo.Summary))
+	}
+	d.writeExperience(resume.Experience)
+	d.writeEducation(resume.Education)
+	d.writeSkills(resume.Skills)
+	d.writeLanguages(resume.Languages)
+	d.writeProjects(resume.Projects)
+	d.writeCertifications(resume.Certifications)
+	d.writeVolunteer(resume.Volunteer)
+	d.writeAwards(resume.Awards)
+	d.writeCustomSections(resume.CustomSections)
+	d.writeReferences(resume.References)
+
+	d.body.WriteString("\n\\end{document}\n")
+}
+
+func (d *latexDocument) section(title string) {
+	fmt.Fprintf(&d.body, "\n\\section{%s}\n", d.text(title))
+}
+
+// cventry writes a moderncv \cventry; detail is already escaped LaTeX
+func (d *latexDocument) cventry(dates, title, organization, location, grade, detail string) {
+	fmt.Fprintf(&d.body, "\\cventry{%s}{%s}{%s}{%s}{%s}{%s}\n",
+		d.text(dates), d.text(title), d.text(organization), d.text(location), d.text(grade), detail)
+}
+
+func (d *latexDocument) itemize(items []string) string {
+	if len(items) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\\begin{itemize}")
+	for _, item := range items {
+		// The empty group ends \item, so an item starting with "[" is not
+		// read as its optional label
+		b.WriteString("\\item{} ")
+		b.WriteString(d.text(item))
+	}
+	b.WriteString("\\end{itemize}")
+	return b.String()
+}
+
+func (d *latexDocument) writeExperience(experience []*domain.Experience) {
+	if len(experience) == 0 {
+		return
+	}
+	d.section("Experience")
+	for _, exp := range experience {
+		if exp == nil {
+			continue
+		}
+		detail := d.text(exp.Description) + d.itemize(exp.Achievements)
+		d.cventry(d.period(exp.StartDate, exp.EndDate), exp.JobTitle, exp.Employer, exp.Location, "", detail)
+	}
+}
+
+func (d *latexDocument) writeEducation(education []*domain.Education) {
+	if len(education) == 0 {
+		return
+	}
+	d.section("Education")
+	for _, edu := range education {
+		if edu == nil {
+			continue
+		}
+		d.cventry(d.period(edu.StartDate, edu.EndDate), edu.Degree, edu.Institution, edu.Location, edu.Field, d.text(edu.Description))
+	}
+}
+
+var skillCategoryTitles = map[string]string{
+	domain.SkillCategoryLanguage:  "Programming languages",
+	domain.SkillCategoryFramework: "Frameworks",
+	domain.SkillCategoryTool:      "Tools",
+	domain.SkillCategoryDatabase:  "Databases",
+	domain.SkillCategoryOther:     "Other",
+}
+
+func (d *latexDocument) writeSkills(skills []*domain.Skill) {
+	if len(skills) == 0 {
+		return
+	}
+
+	// Group skills by category, keeping the order in which categories appear
+	var categories []string
+	grouped := make(map[string][]string)
+	for _, skill := range skills {
+		if skill == nil {
+			continue
+		}
+		category := skill.Category
+		if category == "" {
+			category = domain.SkillCategoryOther
+		}
+		if _, ok := grouped[category]; !ok {
+			categories = append(categories, category)
+		}
+		name := d.text(skill.Name)
+		if skill.Proficiency != 0 {
+			name = fmt.Sprintf("%s (%d/5)", name, skill.Proficiency)
+		}
+		grouped[category] = append(grouped[category], name)
+	}
+
+	d.section("Skills")
+	for _, category := range categories {
+		title, ok := skillCategoryTitles[category]
+		if !ok {
+			title = category
+		}
+		fmt.Fprintf(&d.body, "\\cvitem{%s}{%s}\n", d.text(title), strings.Join(grouped[category], ", "))
+	}
+}
+
+func (d *latexDocument) writeLanguages(languages []*domain.Language) {
+	if len(languages) == 0 {
+		return
+	}
+	d.section("Languages")
+	for _, language := range languages {
+		if language == nil {
+			continue
+		}
+		level := language.Level
+		if level == domain.LanguageLevelNative {
+			level = "Native"
+		}
+		comment := ""
+		if language.Certificate != "" {
+			comment = d.text(language.Certificate)
+		}
+		fmt.Fprintf(&d.body, "\\cvitemwithcomment{%s}{%s}{%s}\n", d.text(language.Name()), d.text(level), comment)
+	}
+}
+
+func (d *latexDocument) writeProjects(projects []*domain.Project) {
+	if len(projects) == 0 {
+		return
+	}
+	d.section("Projects")
+	for _, project := range projects {
+		if project == nil {
+			continue
+		}
+		var links []string
+		if project.RepoURL != "" {
+			links = append(links, d.link(project.RepoURL, "Repository"))
+		}
+		if project.DemoURL != "" {
+			links = append(links, d.link(project.DemoURL, "Demo"))
+		}
+		detail := d.text(project.Description)
+		if len(links) > 0 {
+			if detail != "" {
+				detail += `\newline{}`
+			}
+			detail += strings.Join(links, " | ")
+		}
+		d.cventry(d.period(project.StartDate, project.EndDate), project.Name, strings.Join(project.Technologies, ", "), "", "", detail)
+	}
+}
+
+func (d *latexDocument) writeCertifications(certifications []*domain.Certification) {
+	if len(certifications) == 0 {
+		return
+	}
+	d.section("Certifications")
+	for _, cert := range certifications {
+		if cert == nil {
+			continue
+		}
+		var credential string
+		if cert.CredentialID != "" {
+			credential = "Credential ID: " + cert.CredentialID
+		}
+		var detail string
+		if cert.URL != "" {
+			detail = d.link(cert.URL, cert.URL)
+		}
+		d.cventry(d.period(cert.IssueDate, cert.ExpiryDate), cert.Name, cert.Issuer, credential, "", detail)
+	}
+}
+
+func (d *latexDocument) writeVolunteer(volunteer []*domain.Volunteer) {
+	if len(volunteer) == 0 {
+		return
+	}
+	d.section("Volunteer Experience")
+	for _, v := range volunteer {
+		if v == nil {
+			continue
+		}
+		detail := d.text(v.Description)
+		if v.URL != "" {
+			if detail != "" {
+				detail += `\newline{}`
+			}
+			detail += d.link(v.URL, v.URL)
+		}
+		d.cventry(dateRange(d.dates.formatString(v.StartDate), d.dates.formatString(v.EndDate)), v.Role, v.Organization, v.Location, "", detail)
+	}
+}
+
+func (d *latexDocument) writeAwards(awards []*domain.Award) {
+	if len(awards) == 0 {
+		return
+	}
+	d.section("Honors & Awards")
+	for _, award := range awards {
+		if award == nil {
+			continue
+		}
+		detail := d.text(award.Description)
+		if award.URL != "" {
+			if detail != "" {
+				detail += `\newline{}`
+			}
+			detail += d.link(award.URL, award.URL)
+		}
+		d.cventry(d.dates.formatString(award.Date), award.Title, award.Awarder, "", "", detail)
+	}
+}
+
+// writeReferences lists the references last; contact details of references
+// marked as available on request are left out
+func (d *latexDocument) writeReferences(references []*domain.Reference) {
+	if len(references) == 0 {
+		return
+	}
+	d.section("References")
+	for _, ref := range references {
+		if ref == nil {
+			continue
+		}
+		title := ref.Relationship
+		if ref.Company != "" {
+			title += ", " + ref.Company
+		}
+		var contact string
+		switch {
+		case ref.AvailableOnRequest:
+			contact = d.text("Contact details available on request")
+		default:
+			var parts []string
+			if ref.Email != "" {
+				parts = append(parts, d.link("mailto:"+ref.Email, ref.Email))
+			}
+			if ref.Phone != "" {
+				parts = append(parts, d.text(ref.Phone))
+			}
+			contact = strings.Join(parts, " | ")
+		}
+		fmt.Fprintf(&d.body, "\\cvitemwithcomment{%s}{%s}{%s}\n", d.text(ref.Name), d.text(title), contact)
+	}
+}
+
+func (d *latexDocument) writeCustomSections(sections []*domain.CustomSection) {
+	for _, section := range sections {
+		if section == nil || len(section.Entries) == 0 {
+			continue
+		}
+		d.section(section.Title)
+		for _, entry := range section.Entries {
+			if entry == nil {
+				continue
+			}
+			var detail string
+			if entry.URL != "" {
+				detail = d.link(entry.URL, entry.URL)
+			}
+			detail += d.itemize(entry.Bullets)
+			d.cventry(dateRange(d.dates.formatString(entry.StartDate), d.dates.formatString(entry.EndDate)), entry.Title, entry.Subtitle, "", "", detail)
+		}
+	}
+}
+
+// period writes the dates of an entry in the language of the document
+func (d *latexDocument) period(start, end domain.PartialDate) string {
+	var from, to string
+	if !start.IsZero() {
+		from = d.dates.format(start)
+	}
+	if !end.IsZero() {
+		to = d.dates.format(end)
+	}
+	return dateRange(from, to)
+}
+
+func dateRange(start, end string) string {
+	switch {
+	case start == "" && end == "":
+		return ""
+	case start == "":
+		return end
+	case end == "":
+		return start
+	default:
+		return start + " -- " + end
+	}
+}
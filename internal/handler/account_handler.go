@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"cv_builder/internal/service"
+	"encoding/json"
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+	"net/http"
+)
+
+// AccountHandler links Telegram and email logins to the signed in user and
+// merges accounts of the same person
+type AccountHandler struct {
+	accountService *service.AccountService
+	validator      *validator.Validate
+}
+
+func NewAccountHandler(accountService *service.AccountService) *AccountHandler {
+	return &AccountHandler{
+		accountService: accountService,
+		validator:      validator.New(),
+	}
+}
+
+type LinkTelegramRequest struct {
+	InitData string `json:"init_data" validate:"required"`
+}
+
+type LinkEmailRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,min=8,max=100"`
+}
+
+// MergeAccountRequest proves ownership of the account to merge, either with
+// its email login or with Telegram, plus its second factor if it has one
+type MergeAccountRequest struct {
+	Email            string `json:"email" validate:"required_without=TelegramInitData,omitempty,email"`
+	Password         string `json:"password" validate:"required_with=Email"`
+	Code             string `json:"code" validate:"max=32"`
+	TelegramInitData string `json:"telegram_init_data"`
+}
+
+func (h *AccountHandler) LinkTelegramHandler(w http.ResponseWriter, r *http.Request) {
+	userId, err := GetUserIdFromContext(r.Context())
+	if err != nil {
+		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
+		return
+	}
+
+	var req LinkTelegramRequest
+	if !h.decodeRequest(w, r, &req) {
+		return
+	}
+
+	if err := h.accountService.LinkTelegram(r.Context(), userId, req.InitData); err != nil {
+		respondAccountError(w, err, "Failed to link Telegram")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Telegram linked",
+	})
+}
+
+func (h *AccountHandler) UnlinkTelegramHandler(w http.ResponseWriter, r *http.Request) {
+	userId, err := GetUserIdFromContext(r.Context())
+	if err != nil {
+		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
+		return
+	}
+
+	if err := h.accountService.UnlinkTelegram(r.Context(), userId); err != nil {
+		respondAccountError(w, err, "Failed to unlink Telegram")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Telegram unlinked",
+	})
+}
+
+func (h *AccountHandler) LinkEmailHandler(w http.ResponseWriter, r *http.Request) {
+	userId, err := GetUserIdFromContext(r.Context())
+	if err != nil {
+		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
+		return
+	}
+
+	var req LinkEmailRequest
+	if !h.decodeRequest(w, r, &req) {
+		return
+	}
+
+	if err := h.accountService.LinkEmail(r.Context(), userId, req.Email, req.Password); err != nil {
+		respondAccountError(w, err, "Failed to add email login")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Email login added",
+	})
+}
+
+func (h *AccountHandler) UnlinkEmailHandler(w http.ResponseWriter, r *http.Request) {
+	userId, err := GetUserIdFromContext(r.Context())
+	if err != nil {
+		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
+		return
+	}
+
+	if err := h.accountService.UnlinkEmail(r.Context(), userId); err != nil {
+		respondAccountError(w, err, "Failed to remove email login")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Email login removed",
+	})
+}
+
+// MergeAccountHandler moves the resumes, sessions and logins of another
+// account of the user into the signed in account and deletes the other one
+func (h *AccountHandler) MergeAccountHandler(w http.ResponseWriter, r *http.Request) {
+	userId, err := GetUserIdFromContext(r.Context())
+	if err != nil {
+		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
+		return
+	}
+
+	var req MergeAccountRequest
+	if !h.decodeRequest(w, r, &req) {
+		return
+	}
+
+	if req.TelegramInitData != "" {
+		err = h.accountService.MergeWithTelegram(r.Context(), userId, req.TelegramInitData, req.Code)
+	} else {
+		err = h.accountService.MergeWithPassword(r.Context(), userId, req.Email, req.Password, req.Code)
+	}
+	if err != nil {
+		respondAccountError(w, err, "Failed to merge accounts")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Accounts merged",
+	})
+}
+
+func (h *AccountHandler) decodeRequest(w http.ResponseWriter, r *http.Request, req any) bool {
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST")
+		return false
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		errors.As(err, &validationErrors)
+		RespondWithValidationError(w, validationErrors)
+		return false
+	}
+	return true
+}
+
+func respondAccountError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidCredentials):
+		RespondWithError(w, http.StatusUnauthorized, "Invalid credentials for the other account", "INVALID_CREDENTIALS")
+	case errors.Is(err, service.ErrInvalidMFACode):
+		RespondWithError(w, http.StatusUnauthorized, "Invalid two-factor code for the other account", "INVALID_MFA_CODE")
+	case errors.Is(err, service.ErrTooManyMFAAttempts):
+		RespondWithError(w, http.StatusTooManyRequests, "Too many invalid codes, try again later", "TOO_MANY_ATTEMPTS")
+	case errors.Is(err, service.ErrTelegramInUse):
+		RespondWithError(w, http.StatusConflict, "Telegram account belongs to another account, merge it instead", "TELEGRAM_IN_USE")
+	case errors.Is(err, service.ErrTelegramAlreadyLinked):
+		RespondWithError(w, http.StatusConflict, "Another Telegram account is linked", "TELEGRAM_ALREADY_LINKED")
+	case errors.Is(err, service.ErrTelegramNotLinked):
+		RespondWithError(w, http.StatusConflict, "No Telegram account is linked", "TELEGRAM_NOT_LINKED")
+	case errors.Is(err, service.ErrUserAlreadyExists):
+		RespondWithError(w, http.StatusConflict, "Email belongs to another account, merge it instead", "USER_EXISTS")
+	case errors.Is(err, service.ErrEmailAlreadyLinked):
+		RespondWithError(w, http.StatusConflict, "Account already has an email login", "EMAIL_ALREADY_LINKED")
+	case errors.Is(err, service.ErrEmailNotLinked):
+		RespondWithError(w, http.StatusConflict, "Account has no email login", "EMAIL_NOT_LINKED")
+	case errors.Is(err, service.ErrLastLoginMethod):
+		RespondWithError(w, http.StatusConflict, "Add another way to sign in first", "LAST_LOGIN_METHOD")
+	case errors.Is(err, service.ErrMergeSameUser):
+		RespondWithError(w, http.StatusBadRequest, "Cannot merge an account into itself", "INVALID_REQUEST")
+	case errors.Is(err, service.ErrUserNotFound):
+		RespondWithError(w, http.StatusNotFound, "Account not found", "NOT_FOUND")
+	case errors.Is(err, service.ErrTelegramNotConfigured):
+		log.Error().Msg("telegram bot token not configured")
+		RespondWithError(w, http.StatusInternalServerError, "Telegram authentication not configured", "INTERNAL_SERVER_ERROR")
+	default:
+		log.Error().Err(err).Msg(message)
+		RespondWithError(w, http.StatusInternalServerError, message, "INTERNAL_SERVER_ERROR")
+	}
+}
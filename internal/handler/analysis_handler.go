@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"cv_builder/internal/analysis"
+	"cv_builder/internal/domain"
+	"cv_builder/internal/lint"
+	"cv_builder/internal/repository"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type AnalysisHandler struct {
+	resumeRepo domain.ResumeRepository
+	engine     *analysis.Engine
+}
+
+func NewAnalysisHandler(resumeRepo domain.ResumeRepository, engine *analysis.Engine) *AnalysisHandler {
+	return &AnalysisHandler{
+		resumeRepo: resumeRepo,
+		engine:     engine,
+	}
+}
+
+// ScoreResumeHandler rates the completeness and quality of a resume
+func (h *AnalysisHandler) ScoreResumeHandler(w http.ResponseWriter, r *http.Request) {
+	resume, ok := h.loadResume(w, r)
+	if !ok {
+		return
+	}
+
+	report := h.engine.Evaluate(resume)
+	RespondWithJSON(w, http.StatusOK, map[string]any{
+		"resume_id": resume.ID,
+		"score":     report.Score,
+		"results":   report.Results,
+	})
+}
+
+// TimelineHandler reports employment gaps, overlapping roles and the years
+// of experience in total and per skill. Gaps shorter than the min_gap_months
+// query parameter, 3 by default, are ignored.
+func (h *AnalysisHandler) TimelineHandler(w http.ResponseWriter, r *http.Request) {
+	opts := analysis.DefaultTimelineOptions()
+	if value := r.URL.Query().Get("min_gap_months"); value != "" {
+		months, err := strconv.Atoi(value)
+		if err != nil || months < 0 || months > 120 {
+			RespondWithError(w, http.StatusBadRequest, "min_gap_months must be a number between 0 and 120", "INVALID_REQUEST")
+			return
+		}
+		opts.MinGap = time.Duration(months) * 30 * 24 * time.Hour
+	}
+
+	resume, ok := h.loadResume(w, r)
+	if !ok {
+		return
+	}
+
+	timeline := analysis.AnalyzeTimeline(resume, opts)
+	RespondWithJSON(w, http.StatusOK, map[string]any{
+		"resume_id":   resume.ID,
+		"total_years": timeline.TotalYears,
+		"gaps":        timeline.Gaps,
+		"overlaps":    timeline.Overlaps,
+		"skills":      timeline.Skills,
+		"skipped":     timeline.Skipped,
+	})
+}
+
+// LintResumeHandler checks the writing style of the descriptions and
+// achievements of a resume. The locale query parameter selects the rule set,
+// "en" by default.
+func (h *AnalysisHandler) LintResumeHandler(w http.ResponseWriter, r *http.Request) {
+	locale := r.URL.Query().Get("locale")
+	if locale == "" {
+		locale = "en"
+	}
+	linter, err := lint.ForLocale(locale)
+	if err != nil {
+		if errors.Is(err, lint.ErrUnsupportedLocale) {
+			RespondWithError(w, http.StatusBadRequest,
+				fmt.Sprintf("Unsupported locale, must be one of: %s", strings.Join(lint.Locales(), ", ")),
+				"UNSUPPORTED_LOCALE")
+			return
+		}
+		log.Error().Err(err).Msg("failed to load lint rules")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to lint resume", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	resume, ok := h.loadResume(w, r)
+	if !ok {
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]any{
+		"resume_id": resume.ID,
+		"locale":    locale,
+		"findings":  linter.Lint(resume),
+	})
+}
+
+// loadResume loads the complete resume named in the path, writing an error
+// response when it is missing or belongs to another user
+func (h *AnalysisHandler) loadResume(w http.ResponseWriter, r *http.Request) (*domain.Resume, bool) {
+	claims, err := GetClaimsFromContext(r.Context())
+	if err != nil {
+		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
+		return nil, false
+	}
+
+	userId, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Invalid user ID", "INTERNAL_SERVER_ERROR")
+		return nil, false
+	}
+
+	resumeId := r.PathValue("id")
+	if resumeId == "" {
+		RespondWithError(w, http.StatusBadRequest, "Resume ID is required", "INVALID_REQUEST")
+		return nil, false
+	}
+
+	resumeUUID, err := uuid.Parse(resumeId)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid resume ID", "INVALID_REQUEST")
+		return nil, false
+	}
+
+	resume, err := h.resumeRepo.GetCompleteResume(r.Context(), resumeUUID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			RespondWithError(w, http.StatusNotFound, "Resume not found", "NOT_FOUND")
+			return nil, false
+		}
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get resume", "INTERNAL_SERVER_ERROR")
+		return nil, false
+	}
+
+	if resume.UserID != userId && claims.Role != "admin" {
+		RespondWithError(w, http.StatusForbidden, "You don't have permission to access this resume", "FORBIDDEN")
+		return nil, false
+	}
+
+	return resume, true
+}
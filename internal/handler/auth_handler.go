@@ -1,7 +1,6 @@
 package handler
 
 import (
-	"cv_builder/config"
 	"cv_builder/internal/service"
 	"cv_builder/pkg/security"
 	"encoding/json"
@@ -10,7 +9,6 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog/log"
-	tgInitData "github.com/telegram-mini-apps/init-data-golang"
 	"net/http"
 	"time"
 )
@@ -45,6 +43,21 @@ type LoginRequest struct {
 	Password string `json:"password" validate:"required"`
 }
 
+type LoginMFARequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required,max=32"`
+}
+
+type LoginPasskeyRequest struct {
+	CeremonyID string          `json:"ceremony_id" validate:"required"`
+	Credential json.RawMessage `json:"credential" validate:"required"`
+}
+
+type OAuthCallbackRequest struct {
+	Code  string `json:"code" validate:"required"`
+	State string `json:"state" validate:"required"`
+}
+
 type TelegramRequest struct {
 	InitData string `json:"initData"`
 }
@@ -57,6 +70,10 @@ type PasswordResetRequestRequest struct {
 	Email string `json:"email" validate:"required,email"`
 }
 
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
 type PasswordResetRequest struct {
 	Token       string `json:"token" validate:"required"`
 	NewPassword string `json:"new_password" validate:"required,min=8,max=100"`
@@ -136,9 +153,9 @@ func (h *AuthHandler) LoginHandler(w http.ResponseWriter, r *http.Request) {
 	clientIP := getClientIP(r)
 
 	// Login user
-	tokens, err := h.authService.Login(ctx, req.Email, req.Password, userAgent, clientIP)
+	tokens, challenge, err := h.authService.Login(ctx, req.Email, req.Password, userAgent, clientIP)
 	if err != nil {
-		if errors.Is(err, errors.New("invalid credentials!!")) {
+		if errors.Is(err, service.ErrInvalidCredentials) {
 			// Return same error for invalid email or password to prevent user enumeration
 			RespondWithError(w, http.StatusUnauthorized, "Invalid email or password", "INVALID_CREDENTIALS")
 			return
@@ -148,10 +165,198 @@ func (h *AuthHandler) LoginHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The second factor is sent to LoginMFAHandler with the challenge token
+	if challenge != nil {
+		RespondWithJSON(w, http.StatusOK, challenge)
+		return
+	}
+
 	// Return tokens
 	RespondWithJSON(w, http.StatusOK, tokens)
 }
 
+// LoginMFAHandler completes a login that returned an MFA challenge
+func (h *AuthHandler) LoginMFAHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if ok := h.applyRateLimit(w, r); !ok {
+		return
+	}
+
+	var req LoginMFARequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST")
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		errors.As(err, &validationErrors)
+		RespondWithValidationError(w, validationErrors)
+		return
+	}
+
+	tokens, err := h.authService.CompleteMFALogin(ctx, req.MFAToken, req.Code, r.UserAgent(), getClientIP(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrExpiredToken):
+			RespondWithError(w, http.StatusUnauthorized, "Login expired, sign in again", "TOKEN_EXPIRED")
+		case errors.Is(err, service.ErrInvalidToken), errors.Is(err, service.ErrMFANotEnabled):
+			RespondWithError(w, http.StatusUnauthorized, "Invalid MFA token", "INVALID_TOKEN")
+		case errors.Is(err, service.ErrInvalidMFACode):
+			RespondWithError(w, http.StatusUnauthorized, "Invalid two-factor code", "INVALID_MFA_CODE")
+		case errors.Is(err, service.ErrTooManyMFAAttempts):
+			RespondWithError(w, http.StatusTooManyRequests, "Too many invalid codes, try again later", "TOO_MANY_ATTEMPTS")
+		default:
+			log.Error().Err(err).Msg("Failed to complete mfa login")
+			RespondWithError(w, http.StatusInternalServerError, "Failed to login user", "LOGIN_FAILED")
+		}
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, tokens)
+}
+
+// BeginPasskeyLoginHandler returns the options for
+// navigator.credentials.get() and the ceremony to finish the login with
+func (h *AuthHandler) BeginPasskeyLoginHandler(w http.ResponseWriter, r *http.Request) {
+	if ok := h.applyRateLimit(w, r); !ok {
+		return
+	}
+
+	ceremonyId, options, err := h.authService.BeginPasskeyLogin(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to begin passkey login")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to start passkey login", "LOGIN_FAILED")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]any{
+		"ceremony_id": ceremonyId,
+		"options":     options,
+	})
+}
+
+// LoginPasskeyHandler exchanges the signed assertion for a token pair
+func (h *AuthHandler) LoginPasskeyHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if ok := h.applyRateLimit(w, r); !ok {
+		return
+	}
+
+	var req LoginPasskeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST")
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		errors.As(err, &validationErrors)
+		RespondWithValidationError(w, validationErrors)
+		return
+	}
+
+	tokens, err := h.authService.LoginWithPasskey(ctx, req.CeremonyID, req.Credential, r.UserAgent(), getClientIP(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrCeremonyNotFound):
+			RespondWithError(w, http.StatusUnauthorized, "Login expired, try again", "CEREMONY_EXPIRED")
+		case errors.Is(err, service.ErrPasskeyNotFound), errors.Is(err, service.ErrPasskeyInvalid):
+			RespondWithError(w, http.StatusUnauthorized, "Invalid passkey", "INVALID_CREDENTIALS")
+		case errors.Is(err, service.ErrPasskeyCloned):
+			RespondWithError(w, http.StatusUnauthorized, "Passkey rejected, it may have been copied", "PASSKEY_CLONED")
+		default:
+			log.Error().Err(err).Msg("Failed to login with passkey")
+			RespondWithError(w, http.StatusInternalServerError, "Failed to login user", "LOGIN_FAILED")
+		}
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, tokens)
+}
+
+// JWKSHandler publishes the public keys access tokens are signed with. Keys
+// rarely change, verifiers may cache them for a while.
+func (h *AuthHandler) JWKSHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	RespondWithJSON(w, http.StatusOK, h.authService.JWKS())
+}
+
+func (h *AuthHandler) OAuthProvidersHandler(w http.ResponseWriter, r *http.Request) {
+	RespondWithJSON(w, http.StatusOK, map[string]any{
+		"providers": h.authService.OAuthProviders(),
+	})
+}
+
+// BeginOAuthLoginHandler returns the provider URL to send the user to. The
+// provider redirects back to the frontend, which passes the code and state
+// on to OAuthCallbackHandler.
+func (h *AuthHandler) BeginOAuthLoginHandler(w http.ResponseWriter, r *http.Request) {
+	if ok := h.applyRateLimit(w, r); !ok {
+		return
+	}
+
+	authURL, err := h.authService.BeginOAuthLogin(r.Context(), r.PathValue("provider"))
+	if err != nil {
+		if errors.Is(err, service.ErrOAuthProviderNotFound) {
+			RespondWithError(w, http.StatusNotFound, "Unknown login provider", "NOT_FOUND")
+			return
+		}
+		log.Error().Err(err).Msg("Failed to begin oauth login")
+		RespondWithError(w, http.StatusBadGateway, "Login provider is unavailable", "PROVIDER_UNAVAILABLE")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]string{
+		"authorization_url": authURL,
+	})
+}
+
+func (h *AuthHandler) OAuthCallbackHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if ok := h.applyRateLimit(w, r); !ok {
+		return
+	}
+
+	var req OAuthCallbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST")
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		errors.As(err, &validationErrors)
+		RespondWithValidationError(w, validationErrors)
+		return
+	}
+
+	tokens, challenge, err := h.authService.LoginWithOAuth(ctx, r.PathValue("provider"), req.State, req.Code, r.UserAgent(), getClientIP(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrOAuthProviderNotFound):
+			RespondWithError(w, http.StatusNotFound, "Unknown login provider", "NOT_FOUND")
+		case errors.Is(err, service.ErrOAuthStateNotFound):
+			RespondWithError(w, http.StatusUnauthorized, "Login expired, try again", "STATE_EXPIRED")
+		case errors.Is(err, service.ErrOAuthFailed):
+			RespondWithError(w, http.StatusUnauthorized, "Sign in with the provider failed", "INVALID_CREDENTIALS")
+		case errors.Is(err, service.ErrIdentityEmailInUse):
+			RespondWithError(w, http.StatusConflict, "An account with this email exists, sign in to it first", "EMAIL_IN_USE")
+		default:
+			log.Error().Err(err).Msg("Failed to login with oauth")
+			RespondWithError(w, http.StatusInternalServerError, "Failed to login user", "LOGIN_FAILED")
+		}
+		return
+	}
+
+	if challenge != nil {
+		RespondWithJSON(w, http.StatusOK, challenge)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, tokens)
+}
+
 func (h *AuthHandler) LogoutHandler(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
@@ -216,6 +421,9 @@ func (h *AuthHandler) RefreshTokenHandler(w http.ResponseWriter, r *http.Request
 		} else if errors.Is(err, errors.New("invalid session")) {
 			code = "INVALID_SESSION"
 			message = "Invalid session"
+		} else if errors.Is(err, service.ErrRefreshTokenReused) {
+			code = "REFRESH_TOKEN_REUSED"
+			message = "Refresh token already used, sign in again"
 		}
 
 		RespondWithError(w, status, message, code)
@@ -243,21 +451,15 @@ func (h *AuthHandler) RequestPasswordResetHandler(w http.ResponseWriter, r *http
 		return
 	}
 
-	resetToken, err := h.authService.RequestPasswordReset(ctx, req.Email)
-	if err != nil {
-		if errors.Is(err, errors.New("user not found")) {
-			RespondWithJSON(w, http.StatusOK, map[string]any{
-				"message": "pwd reset instructons sent to email if exists",
-			})
-			return
-		}
+	// Unknown addresses get the same answer, so the endpoint does not reveal
+	// which emails have an account
+	if err := h.authService.RequestPasswordReset(ctx, req.Email); err != nil && !errors.Is(err, service.ErrUserNotFound) {
 		log.Error().Err(err).Msg("failed to req pwd reset")
 		RespondWithError(w, http.StatusInternalServerError, "failed to req pwd reset", "PASSWORD_RESET_FAILED")
 		return
 	}
 	RespondWithJSON(w, http.StatusOK, map[string]any{
-		"message": "pwd rest instructions sent",
-		"token":   resetToken,
+		"message": "pwd reset instructions sent to email if exists",
 	})
 }
 
@@ -305,6 +507,74 @@ func (h *AuthHandler) ResetPasswordHandler(w http.ResponseWriter, r *http.Reques
 	})
 }
 
+func (h *AuthHandler) VerifyEmailHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if ok := h.applyRateLimit(w, r); !ok {
+		return
+	}
+
+	var req VerifyEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST")
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		errors.As(err, &validationErrors)
+		RespondWithValidationError(w, validationErrors)
+		return
+	}
+
+	if err := h.authService.VerifyEmail(ctx, req.Token); err != nil {
+		switch {
+		case errors.Is(err, service.ErrExpiredToken):
+			RespondWithError(w, http.StatusBadRequest, "Verification token expired", "TOKEN_EXPIRED")
+		case errors.Is(err, service.ErrInvalidToken):
+			RespondWithError(w, http.StatusBadRequest, "Invalid verification token", "INVALID_TOKEN")
+		default:
+			log.Error().Err(err).Msg("failed to verify email")
+			RespondWithError(w, http.StatusInternalServerError, "Failed to verify email", "EMAIL_VERIFICATION_FAILED")
+		}
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]any{
+		"message": "Email verified successfully",
+	})
+}
+
+// ResendVerificationHandler sends a new verification link to the signed in user
+func (h *AuthHandler) ResendVerificationHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if ok := h.applyRateLimit(w, r); !ok {
+		return
+	}
+
+	userId, err := GetUserIdFromContext(ctx)
+	if err != nil {
+		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
+		return
+	}
+
+	if err := h.authService.ResendEmailVerification(ctx, userId); err != nil {
+		switch {
+		case errors.Is(err, service.ErrEmailAlreadyVerified):
+			RespondWithError(w, http.StatusConflict, "Email is already verified", "EMAIL_ALREADY_VERIFIED")
+		case errors.Is(err, service.ErrUserNotFound):
+			RespondWithError(w, http.StatusNotFound, "User not found", "NOT_FOUND")
+		default:
+			log.Error().Err(err).Msg("failed to resend email verification")
+			RespondWithError(w, http.StatusInternalServerError, "Failed to send verification email", "EMAIL_VERIFICATION_FAILED")
+		}
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]any{
+		"message": "Verification email sent",
+	})
+}
+
 func (h *AuthHandler) applyRateLimit(w http.ResponseWriter, r *http.Request) bool {
 	count, err := h.rateLimiter.CheckRateLimit(r.Context(), r)
 	if err != nil {
@@ -342,46 +612,33 @@ func (h *AuthHandler) LoginTelegram(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Load config for bot token
-	cfg, err := config.Load()
-	if err != nil {
-		log.Error().Err(err).Msg("failed to load config")
-		RespondWithError(w, http.StatusInternalServerError, "Configuration error", "INTERNAL_SERVER_ERROR")
-		return
-	}
-
-	if cfg.TelegramBotToken == "" {
-		log.Error().Msg("telegram bot token not configured")
-		RespondWithError(w, http.StatusInternalServerError, "Telegram authentication not configured", "INTERNAL_SERVER_ERROR")
-		return
-	}
-
-	// Validate Telegram init data
-	const INIT_DATA_EXPIRATION = 3 * time.Hour
-
-	err = tgInitData.Validate(req.InitData, cfg.TelegramBotToken, INIT_DATA_EXPIRATION)
-	if err != nil {
-		log.Error().Err(err).Msg("telegram init data validation failed")
-		RespondWithError(w, http.StatusUnauthorized, "Invalid Telegram authentication data", "TG_VALIDATION_FAILED")
-		return
-	}
-
 	// Get client info
 	userAgent := r.UserAgent()
 	clientIP := getClientIP(r)
 
 	// Login with Telegram
-	tokens, err := h.authService.LoginWithTelegram(ctx, req.InitData, userAgent, clientIP)
+	tokens, challenge, err := h.authService.LoginWithTelegram(ctx, req.InitData, userAgent, clientIP)
 	if err != nil {
 		if errors.Is(err, service.ErrInvalidCredentials) {
 			RespondWithError(w, http.StatusUnauthorized, "Invalid Telegram authentication", "INVALID_CREDENTIALS")
 			return
 		}
+		if errors.Is(err, service.ErrTelegramNotConfigured) {
+			log.Error().Msg("telegram bot token not configured")
+			RespondWithError(w, http.StatusInternalServerError, "Telegram authentication not configured", "INTERNAL_SERVER_ERROR")
+			return
+		}
 		log.Error().Err(err).Msg("failed to login with telegram")
 		RespondWithError(w, http.StatusInternalServerError, "Login failed", "LOGIN_FAILED")
 		return
 	}
 
+	// The second factor is sent to LoginMFAHandler with the challenge token
+	if challenge != nil {
+		RespondWithJSON(w, http.StatusOK, challenge)
+		return
+	}
+
 	// Return tokens
 	RespondWithJSON(w, http.StatusOK, map[string]any{
 		"message": "Telegram login successful",
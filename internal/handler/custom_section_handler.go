@@ -0,0 +1,391 @@
+package handler
+
+import (
+	"cv_builder/internal/domain"
+	"cv_builder/internal/repository"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"net/http"
+)
+
+type CustomSectionHandler struct {
+	resumeRepo domain.ResumeRepository
+}
+
+func NewCustomSectionHandler(resumeRepo domain.ResumeRepository) *CustomSectionHandler {
+	return &CustomSectionHandler{
+		resumeRepo: resumeRepo,
+	}
+}
+
+type reorderRequest struct {
+	IDs []uuid.UUID `json:"ids"`
+}
+
+func (h *CustomSectionHandler) GetCustomSectionsHandler(w http.ResponseWriter, r *http.Request) {
+	resumeId, ok := h.authorizeResume(w, r)
+	if !ok {
+		return
+	}
+
+	sections, err := h.resumeRepo.GetCustomSectionsByResume(r.Context(), resumeId)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get custom sections", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, sections)
+}
+
+func (h *CustomSectionHandler) AddCustomSectionHandler(w http.ResponseWriter, r *http.Request) {
+	resumeId, ok := h.authorizeResume(w, r)
+	if !ok {
+		return
+	}
+
+	var section domain.CustomSection
+	if err := json.NewDecoder(r.Body).Decode(&section); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST")
+		return
+	}
+
+	section.BeforeSave()
+
+	if err := section.Validate(); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error(), "VALIDATION_ERROR")
+		return
+	}
+
+	sectionId, err := h.resumeRepo.AddCustomSection(r.Context(), resumeId, &section)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Failed to add custom section", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusCreated, map[string]any{
+		"id":      sectionId,
+		"message": "Custom section added successfully",
+	})
+}
+
+func (h *CustomSectionHandler) GetCustomSectionHandler(w http.ResponseWriter, r *http.Request) {
+	resumeId, ok := h.authorizeResume(w, r)
+	if !ok {
+		return
+	}
+
+	section, ok := h.loadSection(w, r, resumeId)
+	if !ok {
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, section)
+}
+
+// UpdateCustomSectionHandler renames a custom section
+func (h *CustomSectionHandler) UpdateCustomSectionHandler(w http.ResponseWriter, r *http.Request) {
+	resumeId, ok := h.authorizeResume(w, r)
+	if !ok {
+		return
+	}
+
+	sectionId, ok := pathUUID(w, r, "sectionId", "Invalid section ID")
+	if !ok {
+		return
+	}
+
+	var section domain.CustomSection
+	if err := json.NewDecoder(r.Body).Decode(&section); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST")
+		return
+	}
+	// Entries have their own endpoints and are not replaced here
+	section.Entries = nil
+
+	section.BeforeSave()
+
+	if err := section.Validate(); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error(), "VALIDATION_ERROR")
+		return
+	}
+
+	if err := h.resumeRepo.UpdateCustomSection(r.Context(), resumeId, sectionId, &section); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			RespondWithError(w, http.StatusNotFound, "Custom section not found", "NOT_FOUND")
+			return
+		}
+		RespondWithError(w, http.StatusInternalServerError, "Failed to update custom section", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Custom section updated successfully",
+	})
+}
+
+func (h *CustomSectionHandler) DeleteCustomSectionHandler(w http.ResponseWriter, r *http.Request) {
+	resumeId, ok := h.authorizeResume(w, r)
+	if !ok {
+		return
+	}
+
+	sectionId, ok := pathUUID(w, r, "sectionId", "Invalid section ID")
+	if !ok {
+		return
+	}
+
+	if err := h.resumeRepo.DeleteCustomSection(r.Context(), resumeId, sectionId); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			RespondWithError(w, http.StatusNotFound, "Custom section not found", "NOT_FOUND")
+			return
+		}
+		RespondWithError(w, http.StatusInternalServerError, "Failed to delete custom section", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Custom section deleted successfully",
+	})
+}
+
+// ReorderCustomSectionsHandler sets the order of the custom sections of a
+// resume from a list of every section ID
+func (h *CustomSectionHandler) ReorderCustomSectionsHandler(w http.ResponseWriter, r *http.Request) {
+	resumeId, ok := h.authorizeResume(w, r)
+	if !ok {
+		return
+	}
+
+	var req reorderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST")
+		return
+	}
+
+	if err := h.resumeRepo.ReorderCustomSections(r.Context(), resumeId, req.IDs); err != nil {
+		respondReorderError(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Custom sections reordered successfully",
+	})
+}
+
+func (h *CustomSectionHandler) AddCustomSectionEntryHandler(w http.ResponseWriter, r *http.Request) {
+	resumeId, ok := h.authorizeResume(w, r)
+	if !ok {
+		return
+	}
+
+	section, ok := h.loadSection(w, r, resumeId)
+	if !ok {
+		return
+	}
+
+	var entry domain.CustomSectionEntry
+	if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST")
+		return
+	}
+
+	entry.BeforeSave()
+
+	if err := entry.Validate(); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error(), "VALIDATION_ERROR")
+		return
+	}
+
+	entryId, err := h.resumeRepo.AddCustomSectionEntry(r.Context(), section.ID, &entry)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Failed to add custom section entry", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusCreated, map[string]any{
+		"id":      entryId,
+		"message": "Custom section entry added successfully",
+	})
+}
+
+func (h *CustomSectionHandler) UpdateCustomSectionEntryHandler(w http.ResponseWriter, r *http.Request) {
+	resumeId, ok := h.authorizeResume(w, r)
+	if !ok {
+		return
+	}
+
+	section, ok := h.loadSection(w, r, resumeId)
+	if !ok {
+		return
+	}
+
+	entryId, ok := pathUUID(w, r, "entryId", "Invalid entry ID")
+	if !ok {
+		return
+	}
+
+	var entry domain.CustomSectionEntry
+	if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST")
+		return
+	}
+
+	entry.BeforeSave()
+
+	if err := entry.Validate(); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error(), "VALIDATION_ERROR")
+		return
+	}
+
+	if err := h.resumeRepo.UpdateCustomSectionEntry(r.Context(), section.ID, entryId, &entry); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			RespondWithError(w, http.StatusNotFound, "Custom section entry not found", "NOT_FOUND")
+			return
+		}
+		RespondWithError(w, http.StatusInternalServerError, "Failed to update custom section entry", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Custom section entry updated successfully",
+	})
+}
+
+func (h *CustomSectionHandler) DeleteCustomSectionEntryHandler(w http.ResponseWriter, r *http.Request) {
+	resumeId, ok := h.authorizeResume(w, r)
+	if !ok {
+		return
+	}
+
+	section, ok := h.loadSection(w, r, resumeId)
+	if !ok {
+		return
+	}
+
+	entryId, ok := pathUUID(w, r, "entryId", "Invalid entry ID")
+	if !ok {
+		return
+	}
+
+	if err := h.resumeRepo.DeleteCustomSectionEntry(r.Context(), section.ID, entryId); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			RespondWithError(w, http.StatusNotFound, "Custom section entry not found", "NOT_FOUND")
+			return
+		}
+		RespondWithError(w, http.StatusInternalServerError, "Failed to delete custom section entry", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Custom section entry deleted successfully",
+	})
+}
+
+// ReorderCustomSectionEntriesHandler sets the order of the entries of a
+// custom section from a list of every entry ID
+func (h *CustomSectionHandler) ReorderCustomSectionEntriesHandler(w http.ResponseWriter, r *http.Request) {
+	resumeId, ok := h.authorizeResume(w, r)
+	if !ok {
+		return
+	}
+
+	section, ok := h.loadSection(w, r, resumeId)
+	if !ok {
+		return
+	}
+
+	var req reorderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST")
+		return
+	}
+
+	if err := h.resumeRepo.ReorderCustomSectionEntries(r.Context(), section.ID, req.IDs); err != nil {
+		respondReorderError(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Custom section entries reordered successfully",
+	})
+}
+
+// authorizeResume checks that the resume named in the path exists and belongs
+// to the caller, writing an error response otherwise
+func (h *CustomSectionHandler) authorizeResume(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	claims, err := GetClaimsFromContext(r.Context())
+	if err != nil {
+		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
+		return uuid.Nil, false
+	}
+
+	userId, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Invalid user ID", "INTERNAL_SERVER_ERROR")
+		return uuid.Nil, false
+	}
+
+	resumeUUID, ok := pathUUID(w, r, "id", "Invalid resume ID")
+	if !ok {
+		return uuid.Nil, false
+	}
+
+	resume, err := h.resumeRepo.GetCVById(r.Context(), resumeUUID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			RespondWithError(w, http.StatusNotFound, "Resume not found", "NOT_FOUND")
+			return uuid.Nil, false
+		}
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get resume", "INTERNAL_SERVER_ERROR")
+		return uuid.Nil, false
+	}
+
+	if resume.UserID != userId && claims.Role != "admin" {
+		RespondWithError(w, http.StatusForbidden, "You don't have permission to update this resume", "FORBIDDEN")
+		return uuid.Nil, false
+	}
+
+	return resume.ID, true
+}
+
+// loadSection loads the section named in the path, which must belong to the
+// resume
+func (h *CustomSectionHandler) loadSection(w http.ResponseWriter, r *http.Request, resumeId uuid.UUID) (*domain.CustomSection, bool) {
+	sectionId, ok := pathUUID(w, r, "sectionId", "Invalid section ID")
+	if !ok {
+		return nil, false
+	}
+
+	section, err := h.resumeRepo.GetCustomSection(r.Context(), resumeId, sectionId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			RespondWithError(w, http.StatusNotFound, "Custom section not found", "NOT_FOUND")
+			return nil, false
+		}
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get custom section", "INTERNAL_SERVER_ERROR")
+		return nil, false
+	}
+
+	return section, true
+}
+
+func pathUUID(w http.ResponseWriter, r *http.Request, name, message string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(r.PathValue(name))
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, message, "INVALID_REQUEST")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func respondReorderError(w http.ResponseWriter, err error) {
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		RespondWithError(w, http.StatusBadRequest, err.Error(), "VALIDATION_ERROR")
+		return
+	}
+	log.Error().Err(err).Msg("failed to reorder custom sections")
+	RespondWithError(w, http.StatusInternalServerError, "Failed to update order", "INTERNAL_SERVER_ERROR")
+}
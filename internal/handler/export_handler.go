@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"bytes"
+	"cv_builder/internal/domain"
+	"cv_builder/internal/export"
+	"cv_builder/internal/repository"
+	"cv_builder/pkg/storage"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+type ExportHandler struct {
+	resumeRepo domain.ResumeRepository
+	store      storage.BlobStore
+}
+
+func NewExportHandler(resumeRepo domain.ResumeRepository, store storage.BlobStore) *ExportHandler {
+	return &ExportHandler{
+		resumeRepo: resumeRepo,
+		store:      store,
+	}
+}
+
+func (h *ExportHandler) ExportResumeHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, err := GetClaimsFromContext(r.Context())
+	if err != nil {
+		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
+		return
+	}
+
+	userId, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Invalid user ID", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	resumeId := r.PathValue("id")
+	if resumeId == "" {
+		RespondWithError(w, http.StatusBadRequest, "Resume ID is required", "INVALID_REQUEST")
+		return
+	}
+
+	resumeUUID, err := uuid.Parse(resumeId)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid resume ID", "INVALID_REQUEST")
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		RespondWithError(w, http.StatusBadRequest, "Export format is required", "INVALID_REQUEST")
+		return
+	}
+
+	exporter, err := export.Get(format)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest,
+			fmt.Sprintf("Unsupported export format, must be one of: %s", strings.Join(export.Formats(), ", ")),
+			"UNSUPPORTED_FORMAT")
+		return
+	}
+
+	resume, err := h.resumeRepo.GetCompleteResume(ctx, resumeUUID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			RespondWithError(w, http.StatusNotFound, "Resume not found", "NOT_FOUND")
+			return
+		}
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get resume", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	if resume.UserID != userId && claims.Role != "admin" {
+		RespondWithError(w, http.StatusForbidden, "You don't have permission to access this resume", "FORBIDDEN")
+		return
+	}
+
+	// The content is exported in the requested translation. Dates are written
+	// in its language where supported, so ?locale=de also works for resumes
+	// without a German translation.
+	resume, locale, ok := localizeResume(w, r, h.resumeRepo, resume, export.Locales())
+	if !ok {
+		return
+	}
+	options := export.Options{}
+	if language := primaryLanguage(locale); slices.Contains(export.Locales(), language) {
+		options.Locale = language
+	}
+
+	if resume.Photo != nil {
+		data, err := loadPhotoData(ctx, h.store, resume.Photo)
+		if err != nil {
+			// Export without the photo rather than failing the whole document
+			log.Error().Err(err).Str("resume_id", resumeId).Msg("failed to load photo for export")
+			resume.Photo = nil
+		} else {
+			resume.Photo.Data = data
+		}
+	}
+
+	// Render into a buffer first so a failed export can still be reported as JSON
+	var buf bytes.Buffer
+	warnings, err := exporter.Export(&buf, resume, options)
+	if err != nil {
+		log.Error().Err(err).Str("resume_id", resumeId).Str("format", format).Msg("failed to export resume")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to export resume", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	w.Header().Set("Content-Type", exporter.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="resume-%s.%s"`, resumeUUID, exporter.FileExtension()))
+	if len(warnings) > 0 {
+		// The body is the document itself, so lossy conversions are reported in a header
+		if encoded, err := json.Marshal(warnings); err == nil {
+			w.Header().Set("X-Export-Warnings", string(encoded))
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		log.Error().Err(err).Msg("failed to write export response")
+	}
+}
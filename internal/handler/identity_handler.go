@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"cv_builder/internal/service"
+	"errors"
+	"github.com/rs/zerolog/log"
+	"net/http"
+)
+
+// IdentityHandler lists and unlinks the identity provider accounts of the
+// signed in user
+type IdentityHandler struct {
+	oauthService *service.OAuthService
+}
+
+func NewIdentityHandler(oauthService *service.OAuthService) *IdentityHandler {
+	return &IdentityHandler{
+		oauthService: oauthService,
+	}
+}
+
+func (h *IdentityHandler) GetIdentitiesHandler(w http.ResponseWriter, r *http.Request) {
+	userId, err := GetUserIdFromContext(r.Context())
+	if err != nil {
+		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
+		return
+	}
+
+	identities, err := h.oauthService.GetIdentities(r.Context(), userId)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get identities")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get linked accounts", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, identities)
+}
+
+func (h *IdentityHandler) UnlinkIdentityHandler(w http.ResponseWriter, r *http.Request) {
+	userId, err := GetUserIdFromContext(r.Context())
+	if err != nil {
+		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
+		return
+	}
+
+	identityId, ok := pathUUID(w, r, "id", "Invalid identity ID")
+	if !ok {
+		return
+	}
+
+	if err := h.oauthService.UnlinkIdentity(r.Context(), userId, identityId); err != nil {
+		switch {
+		case errors.Is(err, service.ErrIdentityNotFound):
+			RespondWithError(w, http.StatusNotFound, "Linked account not found", "NOT_FOUND")
+		case errors.Is(err, service.ErrLastLoginMethod):
+			RespondWithError(w, http.StatusConflict, "Set a password or add a passkey before unlinking the last account", "LAST_LOGIN_METHOD")
+		default:
+			log.Error().Err(err).Msg("failed to unlink identity")
+			RespondWithError(w, http.StatusInternalServerError, "Failed to unlink account", "INTERNAL_SERVER_ERROR")
+		}
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Account unlinked",
+	})
+}
@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"bytes"
+	"cv_builder/internal/domain"
+	"cv_builder/internal/importer"
+	"cv_builder/internal/service"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// maxImportSize limits the size of uploaded resume documents
+const maxImportSize = 5 << 20
+
+type ImportHandler struct {
+	resumeService *service.ResumeService
+}
+
+func NewImportHandler(resumeService *service.ResumeService) *ImportHandler {
+	return &ImportHandler{
+		resumeService: resumeService,
+	}
+}
+
+func (h *ImportHandler) ImportResumeHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, err := GetClaimsFromContext(r.Context())
+	if err != nil {
+		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
+		return
+	}
+
+	userId, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Invalid user ID", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	content, warnings, ok := parseImport(w, r)
+	if !ok {
+		return
+	}
+
+	resume, err := h.resumeService.CreateResume(ctx, userId, content)
+	if err != nil {
+		var validationErr *domain.ValidationError
+		if errors.As(err, &validationErr) {
+			RespondWithError(w, http.StatusBadRequest, err.Error(), "VALIDATION_ERROR")
+			return
+		}
+		log.Error().Err(err).Msg("failed to import resume")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to import resume", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusCreated, map[string]any{
+		"id":       resume.ID,
+		"warnings": warnings,
+		"message":  "Resume imported successfully",
+	})
+}
+
+// PreviewImportHandler parses an uploaded document without saving it, so
+// users can review and fix the parsed resume before committing it
+func (h *ImportHandler) PreviewImportHandler(w http.ResponseWriter, r *http.Request) {
+	if _, err := GetClaimsFromContext(r.Context()); err != nil {
+		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
+		return
+	}
+
+	content, warnings, ok := parseImport(w, r)
+	if !ok {
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]any{
+		"resume":   content,
+		"warnings": warnings,
+	})
+}
+
+// CommitImportHandler saves a previewed, possibly edited, resume
+func (h *ImportHandler) CommitImportHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, err := GetClaimsFromContext(r.Context())
+	if err != nil {
+		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
+		return
+	}
+
+	userId, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Invalid user ID", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	var content domain.Resume
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+	if err := json.NewDecoder(r.Body).Decode(&content); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST")
+		return
+	}
+
+	resume, err := h.resumeService.CreateResume(ctx, userId, &content)
+	if err != nil {
+		var validationErr *domain.ValidationError
+		if errors.As(err, &validationErr) {
+			RespondWithError(w, http.StatusBadRequest, err.Error(), "VALIDATION_ERROR")
+			return
+		}
+		log.Error().Err(err).Msg("failed to save imported resume")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to save resume", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusCreated, map[string]any{
+		"id":      resume.ID,
+		"message": "Resume saved successfully",
+	})
+}
+
+var errMissingUploadFile = errors.New("missing upload file")
+
+// readUploadBody returns an uploaded file of at most maxSize bytes, sent
+// either as the raw request body or as the "file" field of a multipart form
+func readUploadBody(w http.ResponseWriter, r *http.Request, maxSize int64) ([]byte, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize)
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		return io.ReadAll(r.Body)
+	}
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, errMissingUploadFile
+		}
+		if err != nil {
+			return nil, err
+		}
+		if part.FormName() == "file" {
+			return io.ReadAll(part)
+		}
+	}
+}
+
+// parseImport runs the importer selected by the format query parameter on
+// the request body, writing an error response when that fails
+func parseImport(w http.ResponseWriter, r *http.Request) (*domain.Resume, []domain.ConversionWarning, bool) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		RespondWithError(w, http.StatusBadRequest, "Import format is required", "INVALID_REQUEST")
+		return nil, nil, false
+	}
+
+	imp, err := importer.Get(format)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest,
+			fmt.Sprintf("Unsupported import format, must be one of: %s", strings.Join(importer.Formats(), ", ")),
+			"UNSUPPORTED_FORMAT")
+		return nil, nil, false
+	}
+
+	data, err := readUploadBody(w, r, maxImportSize)
+	if errors.Is(err, errMissingUploadFile) {
+		RespondWithError(w, http.StatusBadRequest, "Form field \"file\" is required", "INVALID_REQUEST")
+		return nil, nil, false
+	}
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			RespondWithError(w, http.StatusRequestEntityTooLarge, "Document is too large", "PAYLOAD_TOO_LARGE")
+			return nil, nil, false
+		}
+		RespondWithError(w, http.StatusBadRequest, "Failed to read document", "INVALID_REQUEST")
+		return nil, nil, false
+	}
+
+	content, warnings, err := imp.Import(bytes.NewReader(data))
+	if err != nil {
+		if errors.Is(err, importer.ErrInvalidDocument) {
+			RespondWithError(w, http.StatusBadRequest, err.Error(), "INVALID_DOCUMENT")
+			return nil, nil, false
+		}
+		log.Error().Err(err).Str("format", format).Msg("failed to parse imported document")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to parse document", "INTERNAL_SERVER_ERROR")
+		return nil, nil, false
+	}
+
+	if warnings == nil {
+		warnings = []domain.ConversionWarning{}
+	}
+	return content, warnings, true
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"cv_builder/internal/domain"
+	"cv_builder/internal/repository"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// resolveLocale picks the locale to answer in. An explicit ?locale= must match
+// one of the available locales, otherwise the Accept-Language header is
+// matched against them, falling back to the given locale.
+func resolveLocale(r *http.Request, available []string, fallback string) (string, error) {
+	if requested := r.URL.Query().Get("locale"); requested != "" {
+		locale, err := domain.NormalizeLocale(requested)
+		if err != nil {
+			return "", err
+		}
+		if match := matchLocale(locale, available); match != "" {
+			return match, nil
+		}
+		return "", fmt.Errorf("unsupported locale %q", locale)
+	}
+
+	for _, tag := range acceptedLanguages(r.Header.Get("Accept-Language")) {
+		if tag == "*" {
+			return fallback, nil
+		}
+		if match := matchLocale(tag, available); match != "" {
+			return match, nil
+		}
+	}
+	return fallback, nil
+}
+
+// matchLocale finds the locale in available that fits a requested one,
+// preferring an exact match over one of the same language, so "en-gb"
+// matches "en" and "en" matches "en-us"
+func matchLocale(locale string, available []string) string {
+	if slices.Contains(available, locale) {
+		return locale
+	}
+	language := primaryLanguage(locale)
+	for _, candidate := range available {
+		if candidate == language {
+			return candidate
+		}
+	}
+	for _, candidate := range available {
+		if primaryLanguage(candidate) == language {
+			return candidate
+		}
+	}
+	return ""
+}
+
+func primaryLanguage(locale string) string {
+	language, _, _ := strings.Cut(locale, "-")
+	return language
+}
+
+// acceptedLanguages returns the language tags of an Accept-Language header,
+// most preferred first. Tags with q=0 and malformed tags are left out.
+func acceptedLanguages(header string) []string {
+	type weighted struct {
+		tag     string
+		quality float64
+	}
+
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if quality <= 0 {
+			continue
+		}
+		if tag = strings.TrimSpace(tag); tag != "*" {
+			normalized, err := domain.NormalizeLocale(tag)
+			if err != nil {
+				continue
+			}
+			tag = normalized
+		}
+		tags = append(tags, weighted{tag: tag, quality: quality})
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].quality > tags[j].quality
+	})
+
+	languages := make([]string, len(tags))
+	for i, tag := range tags {
+		languages[i] = tag.tag
+	}
+	return languages
+}
+
+// localizeResume returns the resume in the locale asked for by the request,
+// along with that locale. Locales without a translation fall back to the
+// default locale of the resume; extraLocales are accepted in ?locale= on top
+// of the translated ones, e.g. by exports that only format dates for them.
+// Errors are written to w.
+func localizeResume(w http.ResponseWriter, r *http.Request, resumeRepo domain.ResumeRepository, resume *domain.Resume, extraLocales []string) (*domain.Resume, string, bool) {
+	ctx := r.Context()
+
+	translated, err := resumeRepo.GetTranslationLocales(ctx, resume.ID)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get translations", "INTERNAL_SERVER_ERROR")
+		return nil, "", false
+	}
+
+	// Extra locales only apply when asked for explicitly, Accept-Language
+	// picks among the translated ones
+	available := append([]string{resume.DefaultLocale}, translated...)
+	accepted := available
+	if r.URL.Query().Get("locale") != "" {
+		for _, extra := range extraLocales {
+			if !slices.Contains(accepted, extra) {
+				accepted = append(slices.Clip(accepted), extra)
+			}
+		}
+	}
+	locale, err := resolveLocale(r, accepted, resume.DefaultLocale)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest,
+			fmt.Sprintf("Unsupported locale, must be one of: %s", strings.Join(accepted, ", ")),
+			"UNSUPPORTED_LOCALE")
+		return nil, "", false
+	}
+
+	localized := resume
+	if locale != resume.DefaultLocale && slices.Contains(translated, locale) {
+		translation, err := resumeRepo.GetTranslation(ctx, resume.ID, locale)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			RespondWithError(w, http.StatusInternalServerError, "Failed to get translation", "INTERNAL_SERVER_ERROR")
+			return nil, "", false
+		}
+		if translation != nil {
+			localized = resume.Localize(translation)
+		}
+	}
+	if localized.Locale == "" {
+		localized.Locale = resume.DefaultLocale
+	}
+	localized.Locales = available
+
+	w.Header().Set("Content-Language", localized.Locale)
+	w.Header().Add("Vary", "Accept-Language")
+	return localized, locale, true
+}
@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"cv_builder/internal/service"
+	"encoding/json"
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"net/http"
+)
+
+// MFAHandler lets signed in users manage two-factor authentication. Logins
+// with a second factor go through AuthHandler.LoginMFAHandler.
+type MFAHandler struct {
+	mfaService *service.MFAService
+	validator  *validator.Validate
+}
+
+func NewMFAHandler(mfaService *service.MFAService) *MFAHandler {
+	return &MFAHandler{
+		mfaService: mfaService,
+		validator:  validator.New(),
+	}
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code" validate:"required,max=32"`
+}
+
+func (h *MFAHandler) GetStatusHandler(w http.ResponseWriter, r *http.Request) {
+	userId, err := GetUserIdFromContext(r.Context())
+	if err != nil {
+		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
+		return
+	}
+
+	status, err := h.mfaService.Status(r.Context(), userId)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get mfa status")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get two-factor status", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, status)
+}
+
+// SetupTOTPHandler returns a new secret and its otpauth URI. Two-factor
+// authentication is only on once the setup is confirmed with a code.
+func (h *MFAHandler) SetupTOTPHandler(w http.ResponseWriter, r *http.Request) {
+	userId, err := GetUserIdFromContext(r.Context())
+	if err != nil {
+		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
+		return
+	}
+
+	setup, err := h.mfaService.SetupTOTP(r.Context(), userId)
+	if err != nil {
+		respondMFAError(w, err, "Failed to set up two-factor authentication")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, setup)
+}
+
+// ConfirmTOTPHandler enables two-factor authentication and returns the
+// recovery codes, which cannot be retrieved again
+func (h *MFAHandler) ConfirmTOTPHandler(w http.ResponseWriter, r *http.Request) {
+	userId, req, ok := h.decodeCodeRequest(w, r)
+	if !ok {
+		return
+	}
+
+	codes, err := h.mfaService.ConfirmTOTP(r.Context(), userId, req.Code)
+	if err != nil {
+		respondMFAError(w, err, "Failed to confirm two-factor authentication")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]any{
+		"message":        "Two-factor authentication enabled",
+		"recovery_codes": codes,
+	})
+}
+
+func (h *MFAHandler) DisableTOTPHandler(w http.ResponseWriter, r *http.Request) {
+	userId, req, ok := h.decodeCodeRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.mfaService.DisableTOTP(r.Context(), userId, req.Code); err != nil {
+		respondMFAError(w, err, "Failed to disable two-factor authentication")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Two-factor authentication disabled",
+	})
+}
+
+func (h *MFAHandler) RegenerateRecoveryCodesHandler(w http.ResponseWriter, r *http.Request) {
+	userId, req, ok := h.decodeCodeRequest(w, r)
+	if !ok {
+		return
+	}
+
+	codes, err := h.mfaService.RegenerateRecoveryCodes(r.Context(), userId, req.Code)
+	if err != nil {
+		respondMFAError(w, err, "Failed to create recovery codes")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]any{
+		"message":        "Recovery codes replaced",
+		"recovery_codes": codes,
+	})
+}
+
+func (h *MFAHandler) decodeCodeRequest(w http.ResponseWriter, r *http.Request) (uuid.UUID, *MFACodeRequest, bool) {
+	userId, err := GetUserIdFromContext(r.Context())
+	if err != nil {
+		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
+		return uuid.Nil, nil, false
+	}
+
+	var req MFACodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST")
+		return uuid.Nil, nil, false
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		errors.As(err, &validationErrors)
+		RespondWithValidationError(w, validationErrors)
+		return uuid.Nil, nil, false
+	}
+
+	return userId, &req, true
+}
+
+func respondMFAError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidMFACode):
+		RespondWithError(w, http.StatusBadRequest, "Invalid two-factor code", "INVALID_MFA_CODE")
+	case errors.Is(err, service.ErrTooManyMFAAttempts):
+		RespondWithError(w, http.StatusTooManyRequests, "Too many invalid codes, try again later", "TOO_MANY_ATTEMPTS")
+	case errors.Is(err, service.ErrMFAAlreadyEnabled):
+		RespondWithError(w, http.StatusConflict, "Two-factor authentication is already enabled", "MFA_ALREADY_ENABLED")
+	case errors.Is(err, service.ErrMFANotEnabled):
+		RespondWithError(w, http.StatusConflict, "Two-factor authentication is not enabled", "MFA_NOT_ENABLED")
+	case errors.Is(err, service.ErrMFASetupRequired):
+		RespondWithError(w, http.StatusConflict, "Start the two-factor setup first", "MFA_SETUP_REQUIRED")
+	case errors.Is(err, service.ErrMFARequiresPassword):
+		RespondWithError(w, http.StatusBadRequest, "Two-factor authentication needs an account with a password", "MFA_UNAVAILABLE")
+	case errors.Is(err, service.ErrUserNotFound):
+		RespondWithError(w, http.StatusNotFound, "User not found", "NOT_FOUND")
+	default:
+		log.Error().Err(err).Msg(message)
+		RespondWithError(w, http.StatusInternalServerError, message, "INTERNAL_SERVER_ERROR")
+	}
+}
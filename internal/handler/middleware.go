@@ -152,6 +152,17 @@ func (m *AuthMiddleware) AuthRequired(next http.Handler) http.Handler {
 			return
 		}
 
+		// Reject tokens revoked before they expired
+		if err := m.authService.CheckAccessToken(r.Context(), claims); err != nil {
+			if errors.Is(err, service.ErrTokenRevoked) {
+				RespondWithError(w, http.StatusUnauthorized, "Token revoked", "TOKEN_REVOKED")
+				return
+			}
+			log.Error().Err(err).Msg("failed to check token revocation")
+			RespondWithError(w, http.StatusInternalServerError, "Failed to check token", "INTERNAL_SERVER_ERROR")
+			return
+		}
+
 		// Add claims to context
 		ctx := context.WithValue(r.Context(), claimsContextKey, claims)
 
@@ -177,3 +188,26 @@ func (m *AuthMiddleware) RequireRole(role string) func(http.Handler) http.Handle
 		})
 	}
 }
+
+// VerifiedEmailRequired rejects users who have not verified their email
+// address yet, when the server restricts unverified accounts
+func (m *AuthMiddleware) VerifiedEmailRequired(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userId, err := GetUserIdFromContext(r.Context())
+		if err != nil {
+			RespondWithError(w, http.StatusUnauthorized, "Not Authorized", "UNAUTHORIZED")
+			return
+		}
+
+		if err := m.authService.RequireVerifiedEmail(r.Context(), userId); err != nil {
+			if errors.Is(err, service.ErrEmailNotVerified) {
+				RespondWithError(w, http.StatusForbidden, "Verify your email address to use this feature", "EMAIL_NOT_VERIFIED")
+				return
+			}
+			log.Error().Err(err).Str("user_id", userId.String()).Msg("failed to check email verification")
+			RespondWithError(w, http.StatusInternalServerError, "Failed to check email verification", "INTERNAL_SERVER_ERROR")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
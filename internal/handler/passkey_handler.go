@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"cv_builder/internal/domain"
+	"cv_builder/internal/service"
+	"encoding/json"
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+	"net/http"
+)
+
+// PasskeyHandler lets signed in users register and manage passkeys. Logins
+// with a passkey go through AuthHandler.LoginPasskeyHandler.
+type PasskeyHandler struct {
+	passkeyService *service.PasskeyService
+	validator      *validator.Validate
+}
+
+func NewPasskeyHandler(passkeyService *service.PasskeyService) *PasskeyHandler {
+	return &PasskeyHandler{
+		passkeyService: passkeyService,
+		validator:      validator.New(),
+	}
+}
+
+type RegisterPasskeyRequest struct {
+	CeremonyID string          `json:"ceremony_id" validate:"required"`
+	Name       string          `json:"name" validate:"max=100"`
+	Credential json.RawMessage `json:"credential" validate:"required"`
+}
+
+type RenamePasskeyRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+func (h *PasskeyHandler) GetPasskeysHandler(w http.ResponseWriter, r *http.Request) {
+	userId, err := GetUserIdFromContext(r.Context())
+	if err != nil {
+		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
+		return
+	}
+
+	passkeys, err := h.passkeyService.GetPasskeys(r.Context(), userId)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get passkeys")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get passkeys", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, passkeys)
+}
+
+// BeginRegistrationHandler returns the options for
+// navigator.credentials.create() and the ceremony to finish it with
+func (h *PasskeyHandler) BeginRegistrationHandler(w http.ResponseWriter, r *http.Request) {
+	userId, err := GetUserIdFromContext(r.Context())
+	if err != nil {
+		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
+		return
+	}
+
+	ceremonyId, options, err := h.passkeyService.BeginRegistration(r.Context(), userId)
+	if err != nil {
+		respondPasskeyError(w, err, "Failed to start passkey registration")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]any{
+		"ceremony_id": ceremonyId,
+		"options":     options,
+	})
+}
+
+func (h *PasskeyHandler) FinishRegistrationHandler(w http.ResponseWriter, r *http.Request) {
+	userId, err := GetUserIdFromContext(r.Context())
+	if err != nil {
+		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
+		return
+	}
+
+	var req RegisterPasskeyRequest
+	if !h.decodeRequest(w, r, &req) {
+		return
+	}
+
+	passkey, err := h.passkeyService.FinishRegistration(r.Context(), userId, req.CeremonyID, req.Name, req.Credential)
+	if err != nil {
+		respondPasskeyError(w, err, "Failed to register passkey")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusCreated, passkey)
+}
+
+func (h *PasskeyHandler) RenamePasskeyHandler(w http.ResponseWriter, r *http.Request) {
+	userId, err := GetUserIdFromContext(r.Context())
+	if err != nil {
+		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
+		return
+	}
+
+	passkeyId, ok := pathUUID(w, r, "id", "Invalid passkey ID")
+	if !ok {
+		return
+	}
+
+	var req RenamePasskeyRequest
+	if !h.decodeRequest(w, r, &req) {
+		return
+	}
+
+	if err := h.passkeyService.RenamePasskey(r.Context(), userId, passkeyId, req.Name); err != nil {
+		respondPasskeyError(w, err, "Failed to rename passkey")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Passkey renamed",
+	})
+}
+
+func (h *PasskeyHandler) DeletePasskeyHandler(w http.ResponseWriter, r *http.Request) {
+	userId, err := GetUserIdFromContext(r.Context())
+	if err != nil {
+		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
+		return
+	}
+
+	passkeyId, ok := pathUUID(w, r, "id", "Invalid passkey ID")
+	if !ok {
+		return
+	}
+
+	if err := h.passkeyService.DeletePasskey(r.Context(), userId, passkeyId); err != nil {
+		respondPasskeyError(w, err, "Failed to delete passkey")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Passkey deleted",
+	})
+}
+
+func (h *PasskeyHandler) decodeRequest(w http.ResponseWriter, r *http.Request, req any) bool {
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST")
+		return false
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		errors.As(err, &validationErrors)
+		RespondWithValidationError(w, validationErrors)
+		return false
+	}
+	return true
+}
+
+func respondPasskeyError(w http.ResponseWriter, err error, message string) {
+	var validationErr *domain.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		RespondWithError(w, http.StatusBadRequest, err.Error(), "VALIDATION_ERROR")
+	case errors.Is(err, service.ErrCeremonyNotFound):
+		RespondWithError(w, http.StatusBadRequest, "Registration expired, try again", "CEREMONY_EXPIRED")
+	case errors.Is(err, service.ErrPasskeyInvalid):
+		RespondWithError(w, http.StatusBadRequest, "Passkey could not be verified", "INVALID_PASSKEY")
+	case errors.Is(err, service.ErrPasskeyExists):
+		RespondWithError(w, http.StatusConflict, "Passkey is already registered", "PASSKEY_EXISTS")
+	case errors.Is(err, service.ErrPasskeyNotFound):
+		RespondWithError(w, http.StatusNotFound, "Passkey not found", "NOT_FOUND")
+	case errors.Is(err, service.ErrUserNotFound):
+		RespondWithError(w, http.StatusNotFound, "User not found", "NOT_FOUND")
+	default:
+		log.Error().Err(err).Msg(message)
+		RespondWithError(w, http.StatusInternalServerError, message, "INTERNAL_SERVER_ERROR")
+	}
+}
@@ -0,0 +1,243 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"cv_builder/internal/domain"
+	"cv_builder/internal/photo"
+	"cv_builder/internal/repository"
+	"cv_builder/pkg/storage"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxPhotoSize limits the size of uploaded photos
+const maxPhotoSize = 10 << 20
+
+type PhotoHandler struct {
+	resumeRepo domain.ResumeRepository
+	userRepo   domain.UserRepository
+	store      storage.BlobStore
+}
+
+func NewPhotoHandler(resumeRepo domain.ResumeRepository, userRepo domain.UserRepository, store storage.BlobStore) *PhotoHandler {
+	return &PhotoHandler{
+		resumeRepo: resumeRepo,
+		userRepo:   userRepo,
+		store:      store,
+	}
+}
+
+// UploadPhotoHandler stores a JPEG or PNG photo for the resume, replacing the
+// previous one. The image is re-encoded, which strips EXIF and other metadata.
+func (h *PhotoHandler) UploadPhotoHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resume, ok := loadAuthorizedResume(w, r, h.resumeRepo)
+	if !ok {
+		return
+	}
+
+	data, err := readUploadBody(w, r, maxPhotoSize)
+	if errors.Is(err, errMissingUploadFile) {
+		RespondWithError(w, http.StatusBadRequest, "Form field \"file\" is required", "INVALID_REQUEST")
+		return
+	}
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			RespondWithError(w, http.StatusRequestEntityTooLarge, "Photo is too large", "PAYLOAD_TOO_LARGE")
+			return
+		}
+		RespondWithError(w, http.StatusBadRequest, "Failed to read photo", "INVALID_REQUEST")
+		return
+	}
+
+	processed, err := photo.Process(data)
+	if err != nil {
+		switch {
+		case errors.Is(err, photo.ErrUnsupportedType):
+			RespondWithError(w, http.StatusUnsupportedMediaType, err.Error(), "UNSUPPORTED_MEDIA_TYPE")
+		case errors.Is(err, photo.ErrInvalidImage), errors.Is(err, photo.ErrDimensions):
+			RespondWithError(w, http.StatusBadRequest, err.Error(), "INVALID_IMAGE")
+		default:
+			log.Error().Err(err).Str("resume_id", resume.ID.String()).Msg("failed to process photo")
+			RespondWithError(w, http.StatusInternalServerError, "Failed to process photo", "INTERNAL_SERVER_ERROR")
+		}
+		return
+	}
+
+	previous, err := h.resumeRepo.GetPhoto(ctx, resume.ID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get photo", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	stored := &domain.Photo{
+		ContentType:  processed.ContentType,
+		Width:        processed.Width,
+		Height:       processed.Height,
+		OriginalKey:  photoKey(resume.ID, "photo", processed.Extension()),
+		ThumbnailKey: photoKey(resume.ID, "photo-thumbnail", processed.Extension()),
+	}
+
+	if err := h.store.Put(ctx, stored.OriginalKey, bytes.NewReader(processed.Original), stored.ContentType); err != nil {
+		log.Error().Err(err).Str("key", stored.OriginalKey).Msg("failed to store photo")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to store photo", "INTERNAL_SERVER_ERROR")
+		return
+	}
+	if err := h.store.Put(ctx, stored.ThumbnailKey, bytes.NewReader(processed.Thumbnail), stored.ContentType); err != nil {
+		log.Error().Err(err).Str("key", stored.ThumbnailKey).Msg("failed to store photo thumbnail")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to store photo", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	if err := h.resumeRepo.SavePhoto(ctx, resume.ID, stored); err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Failed to save photo", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	// A photo in another format was stored under different keys
+	if previous != nil {
+		if previous.OriginalKey != stored.OriginalKey {
+			deleteBlob(ctx, h.store, previous.OriginalKey)
+		}
+		if previous.ThumbnailKey != stored.ThumbnailKey {
+			deleteBlob(ctx, h.store, previous.ThumbnailKey)
+		}
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]any{
+		"message": "Photo uploaded successfully",
+		"photo":   stored,
+	})
+}
+
+// GetPhotoHandler streams the resume photo, or its square thumbnail with
+// ?size=thumbnail. Without an uploaded photo, users who signed in with
+// Telegram are redirected to their Telegram profile photo.
+func (h *PhotoHandler) GetPhotoHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resume, ok := loadAuthorizedResume(w, r, h.resumeRepo)
+	if !ok {
+		return
+	}
+
+	size := r.URL.Query().Get("size")
+	if size != "" && size != "original" && size != "thumbnail" {
+		RespondWithError(w, http.StatusBadRequest, "Invalid size, must be one of: original, thumbnail", "INVALID_REQUEST")
+		return
+	}
+
+	stored, err := h.resumeRepo.GetPhoto(ctx, resume.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			if photoURL := h.telegramPhotoURL(ctx, resume.UserID); photoURL != "" {
+				http.Redirect(w, r, photoURL, http.StatusFound)
+				return
+			}
+			RespondWithError(w, http.StatusNotFound, "Photo not found", "NOT_FOUND")
+			return
+		}
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get photo", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	key := stored.OriginalKey
+	if size == "thumbnail" {
+		key = stored.ThumbnailKey
+	}
+
+	blob, err := h.store.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, storage.ErrBlobNotFound) {
+			log.Error().Str("key", key).Msg("photo is missing from blob storage")
+			RespondWithError(w, http.StatusNotFound, "Photo not found", "NOT_FOUND")
+			return
+		}
+		log.Error().Err(err).Str("key", key).Msg("failed to read photo")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to read photo", "INTERNAL_SERVER_ERROR")
+		return
+	}
+	defer blob.Close()
+
+	w.Header().Set("Content-Type", stored.ContentType)
+	w.Header().Set("Cache-Control", "private, no-cache")
+	w.Header().Set("Last-Modified", stored.UpdatedAt.UTC().Format(http.TimeFormat))
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, blob); err != nil {
+		log.Error().Err(err).Msg("failed to write photo response")
+	}
+}
+
+func (h *PhotoHandler) DeletePhotoHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resume, ok := loadAuthorizedResume(w, r, h.resumeRepo)
+	if !ok {
+		return
+	}
+
+	stored, err := h.resumeRepo.GetPhoto(ctx, resume.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			RespondWithError(w, http.StatusNotFound, "Photo not found", "NOT_FOUND")
+			return
+		}
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get photo", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	if err := h.resumeRepo.DeletePhoto(ctx, resume.ID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			RespondWithError(w, http.StatusNotFound, "Photo not found", "NOT_FOUND")
+			return
+		}
+		RespondWithError(w, http.StatusInternalServerError, "Failed to delete photo", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	deleteBlob(ctx, h.store, stored.OriginalKey)
+	deleteBlob(ctx, h.store, stored.ThumbnailKey)
+
+	RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Photo deleted successfully",
+	})
+}
+
+// telegramPhotoURL returns the Telegram profile photo of the user, if any
+func (h *PhotoHandler) telegramPhotoURL(ctx context.Context, userId uuid.UUID) string {
+	user, err := h.userRepo.GetTelegramUserById(ctx, userId)
+	if err != nil || user.TelegramID == nil || user.PhotoURL == nil {
+		return ""
+	}
+	if !strings.HasPrefix(*user.PhotoURL, "https://") {
+		return ""
+	}
+	return *user.PhotoURL
+}
+
+// deleteBlob removes a blob that is no longer referenced. Failures only leave
+// an orphaned file behind, so they are logged and otherwise ignored.
+func deleteBlob(ctx context.Context, store storage.BlobStore, key string) {
+	if err := store.Delete(ctx, key); err != nil {
+		log.Error().Err(err).Str("key", key).Msg("failed to delete photo blob")
+	}
+}
+
+func photoKey(resumeId uuid.UUID, name, extension string) string {
+	return fmt.Sprintf("resumes/%s/%s.%s", resumeId, name, extension)
+}
+
+// loadPhotoData reads the thumbnail of a stored photo so renderers can embed it
+func loadPhotoData(ctx context.Context, store storage.BlobStore, stored *domain.Photo) ([]byte, error) {
+	blob, err := store.Get(ctx, stored.ThumbnailKey)
+	if err != nil {
+		return nil, err
+	}
+	defer blob.Close()
+	return io.ReadAll(io.LimitReader(blob, maxPhotoSize))
+}
@@ -3,19 +3,26 @@ package handler
 import (
 	"cv_builder/internal/domain"
 	"cv_builder/internal/repository"
+	"cv_builder/internal/service"
+	"cv_builder/pkg/storage"
 	"encoding/json"
 	"errors"
 	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
 	"net/http"
 )
 
 type ResumeHandler struct {
-	resumeRepo domain.ResumeRepository
+	resumeRepo      domain.ResumeRepository
+	templateService *service.TemplateService
+	store           storage.BlobStore
 }
 
-func NewResumeHandler(resumeRepo domain.ResumeRepository) *ResumeHandler {
+func NewResumeHandler(resumeRepo domain.ResumeRepository, templateService *service.TemplateService, store storage.BlobStore) *ResumeHandler {
 	return &ResumeHandler{
-		resumeRepo: resumeRepo,
+		resumeRepo:      resumeRepo,
+		templateService: templateService,
+		store:           store,
 	}
 }
 
@@ -59,7 +66,14 @@ func (h *ResumeHandler) GetResumeHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	RespondWithJSON(w, http.StatusOK, resume)
+	// The content is returned in the translation picked by ?locale= or
+	// Accept-Language, falling back to the default locale
+	localized, _, ok := localizeResume(w, r, h.resumeRepo, resume, nil)
+	if !ok {
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, localized)
 }
 
 func (h *ResumeHandler) CreateResumeHandler(w http.ResponseWriter, r *http.Request) {
@@ -76,6 +90,26 @@ func (h *ResumeHandler) CreateResumeHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	// ?template= starts the resume with the placeholder content of a template
+	if slug := r.URL.Query().Get("template"); slug != "" {
+		resume, err := h.templateService.CreateResumeFromTemplate(ctx, userId, slug)
+		if err != nil {
+			var validationErr *domain.ValidationError
+			switch {
+			case errors.Is(err, repository.ErrNotFound):
+				RespondWithError(w, http.StatusBadRequest, "Unknown template", "UNKNOWN_TEMPLATE")
+			case errors.As(err, &validationErr):
+				log.Error().Err(err).Str("template", slug).Msg("template content is invalid")
+				RespondWithError(w, http.StatusInternalServerError, "Failed to create resume", "INTERNAL_SERVER_ERROR")
+			default:
+				RespondWithError(w, http.StatusInternalServerError, "Failed to create resume", "INTERNAL_SERVER_ERROR")
+			}
+			return
+		}
+		RespondWithJSON(w, http.StatusCreated, resume)
+		return
+	}
+
 	resume, err := h.resumeRepo.CreateCV(ctx, userId)
 	if err != nil {
 		RespondWithError(w, http.StatusInternalServerError, "Failed to create resume", "INTERNAL_SERVER_ERROR")
@@ -126,11 +160,23 @@ func (h *ResumeHandler) DeleteResumeHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	// The photo row goes with the resume, its files have to be removed here
+	photo, err := h.resumeRepo.GetPhoto(ctx, resumeUUID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get photo", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
 	if err := h.resumeRepo.DeleteCV(ctx, resumeUUID); err != nil {
 		RespondWithError(w, http.StatusInternalServerError, "Failed to delete resume", "INTERNAL_SERVER_ERROR")
 		return
 	}
 
+	if photo != nil {
+		deleteBlob(ctx, h.store, photo.OriginalKey)
+		deleteBlob(ctx, h.store, photo.ThumbnailKey)
+	}
+
 	RespondWithJSON(w, http.StatusOK, map[string]any{
 		"message": "Resume deleted successfully",
 	})
@@ -209,20 +255,22 @@ func (h *ResumeHandler) SavePersonalInfoHandler(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	// Sanitize first so a Telegram handle gets its profile URL before validation
+	personalInfo.BeforeSave()
+
 	if err := personalInfo.Validate(); err != nil {
 		RespondWithError(w, http.StatusBadRequest, err.Error(), "VALIDATION_ERROR")
 		return
 	}
 
-	personalInfo.BeforeSave()
-
 	if err := h.resumeRepo.SavePersonalInfo(ctx, resumeUUID, &personalInfo); err != nil {
 		RespondWithError(w, http.StatusInternalServerError, "Failed to save personal info", "INTERNAL_SERVER_ERROR")
 		return
 	}
 
 	RespondWithJSON(w, http.StatusOK, map[string]any{
-		"message": "Personal info saved successfully",
+		"message":       "Personal info saved successfully",
+		"personal_info": personalInfo,
 	})
 
 }
@@ -781,6 +829,190 @@ func (h *ResumeHandler) DeleteSkillHandler(w http.ResponseWriter, r *http.Reques
 	})
 }
 
+func (h *ResumeHandler) AddLanguageHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, err := GetClaimsFromContext(r.Context())
+	if err != nil {
+		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
+		return
+	}
+
+	userId, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Invalid user ID", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	resumeId := r.PathValue("id")
+	if resumeId == "" {
+		RespondWithError(w, http.StatusBadRequest, "Resume ID is required", "INVALID_REQUEST")
+		return
+	}
+
+	resumeUUID, err := uuid.Parse(resumeId)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid resume ID", "INVALID_REQUEST")
+		return
+	}
+
+	resume, err := h.resumeRepo.GetCVById(ctx, resumeUUID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			RespondWithError(w, http.StatusNotFound, "Resume not found", "NOT_FOUND")
+			return
+		}
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get resume", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	if resume.UserID != userId && claims.Role != "admin" {
+		RespondWithError(w, http.StatusForbidden, "You don't have permission to update this resume", "FORBIDDEN")
+		return
+	}
+
+	var language domain.Language
+	if err := json.NewDecoder(r.Body).Decode(&language); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST")
+		return
+	}
+
+	language.BeforeSave()
+
+	if err := language.Validate(); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error(), "VALIDATION_ERROR")
+		return
+	}
+
+	languageID, err := h.resumeRepo.AddLanguage(ctx, resumeUUID, &language)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Failed to add language", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusCreated, map[string]any{
+		"id":      languageID,
+		"message": "Language added successfully",
+	})
+}
+
+func (h *ResumeHandler) GetLanguagesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, err := GetClaimsFromContext(r.Context())
+	if err != nil {
+		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
+		return
+	}
+
+	userId, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Invalid user ID", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	resumeId := r.PathValue("id")
+	if resumeId == "" {
+		RespondWithError(w, http.StatusBadRequest, "Resume ID is required", "INVALID_REQUEST")
+		return
+	}
+
+	resumeUUID, err := uuid.Parse(resumeId)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid resume ID", "INVALID_REQUEST")
+		return
+	}
+
+	resume, err := h.resumeRepo.GetCVById(ctx, resumeUUID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			RespondWithError(w, http.StatusNotFound, "Resume not found", "NOT_FOUND")
+			return
+		}
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get resume", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	if resume.UserID != userId && claims.Role != "admin" {
+		RespondWithError(w, http.StatusForbidden, "You don't have permission to access this resume", "FORBIDDEN")
+		return
+	}
+
+	languages, err := h.resumeRepo.GetLanguagesByResume(ctx, resumeUUID)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get languages", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, languages)
+}
+
+func (h *ResumeHandler) DeleteLanguageHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	claims, err := GetClaimsFromContext(r.Context())
+	if err != nil {
+		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
+		return
+	}
+
+	userId, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Invalid user ID", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	resumeId := r.PathValue("id")
+	languageID := r.PathValue("languageId")
+
+	if resumeId == "" {
+		RespondWithError(w, http.StatusBadRequest, "Resume ID is required", "INVALID_REQUEST")
+		return
+	}
+
+	if languageID == "" {
+		RespondWithError(w, http.StatusBadRequest, "Language ID is required", "INVALID_REQUEST")
+		return
+	}
+
+	resumeUUID, err := uuid.Parse(resumeId)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid resume ID", "INVALID_REQUEST")
+		return
+	}
+
+	languageUUID, err := uuid.Parse(languageID)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid language ID", "INVALID_REQUEST")
+		return
+	}
+
+	resume, err := h.resumeRepo.GetCVById(ctx, resumeUUID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			RespondWithError(w, http.StatusNotFound, "Resume not found", "NOT_FOUND")
+			return
+		}
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get resume", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	if resume.UserID != userId && claims.Role != "admin" {
+		RespondWithError(w, http.StatusForbidden, "You don't have permission to update this resume", "FORBIDDEN")
+		return
+	}
+
+	if err := h.resumeRepo.DeleteLanguage(ctx, resumeUUID, languageUUID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			RespondWithError(w, http.StatusNotFound, "Language not found", "NOT_FOUND")
+			return
+		}
+		RespondWithError(w, http.StatusInternalServerError, "Failed to delete language", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]any{
+		"message": "Language deleted successfully",
+	})
+}
+
 func (h *ResumeHandler) AddProjectHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	claims, err := GetClaimsFromContext(r.Context())
@@ -0,0 +1,386 @@
+package handler
+
+import (
+	"cv_builder/internal/domain"
+	"cv_builder/internal/repository"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+// SectionHandler serves the volunteer, awards and references sections
+type SectionHandler struct {
+	resumeRepo domain.ResumeRepository
+}
+
+func NewSectionHandler(resumeRepo domain.ResumeRepository) *SectionHandler {
+	return &SectionHandler{
+		resumeRepo: resumeRepo,
+	}
+}
+
+func (h *SectionHandler) GetVolunteerHandler(w http.ResponseWriter, r *http.Request) {
+	resume, ok := loadAuthorizedResume(w, r, h.resumeRepo)
+	if !ok {
+		return
+	}
+
+	entries, err := h.resumeRepo.GetVolunteerByResume(r.Context(), resume.ID)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get volunteer work", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, entries)
+}
+
+func (h *SectionHandler) AddVolunteerHandler(w http.ResponseWriter, r *http.Request) {
+	resume, ok := loadAuthorizedResume(w, r, h.resumeRepo)
+	if !ok {
+		return
+	}
+
+	var volunteer domain.Volunteer
+	if err := json.NewDecoder(r.Body).Decode(&volunteer); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST")
+		return
+	}
+
+	volunteer.BeforeSave()
+
+	if err := volunteer.Validate(); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error(), "VALIDATION_ERROR")
+		return
+	}
+
+	id, err := h.resumeRepo.AddVolunteer(r.Context(), resume.ID, &volunteer)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Failed to add volunteer work", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusCreated, map[string]any{
+		"id":      id,
+		"message": "Volunteer work added successfully",
+	})
+}
+
+func (h *SectionHandler) UpdateVolunteerHandler(w http.ResponseWriter, r *http.Request) {
+	resume, ok := loadAuthorizedResume(w, r, h.resumeRepo)
+	if !ok {
+		return
+	}
+
+	id, ok := pathUUID(w, r, "volunteerId", "Invalid volunteer ID")
+	if !ok {
+		return
+	}
+
+	var volunteer domain.Volunteer
+	if err := json.NewDecoder(r.Body).Decode(&volunteer); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST")
+		return
+	}
+
+	volunteer.BeforeSave()
+
+	if err := volunteer.Validate(); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error(), "VALIDATION_ERROR")
+		return
+	}
+
+	if err := h.resumeRepo.UpdateVolunteer(r.Context(), resume.ID, id, &volunteer); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			RespondWithError(w, http.StatusNotFound, "Volunteer work not found", "NOT_FOUND")
+			return
+		}
+		RespondWithError(w, http.StatusInternalServerError, "Failed to update volunteer work", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Volunteer work updated successfully",
+	})
+}
+
+func (h *SectionHandler) DeleteVolunteerHandler(w http.ResponseWriter, r *http.Request) {
+	resume, ok := loadAuthorizedResume(w, r, h.resumeRepo)
+	if !ok {
+		return
+	}
+
+	id, ok := pathUUID(w, r, "volunteerId", "Invalid volunteer ID")
+	if !ok {
+		return
+	}
+
+	if err := h.resumeRepo.DeleteVolunteer(r.Context(), resume.ID, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			RespondWithError(w, http.StatusNotFound, "Volunteer work not found", "NOT_FOUND")
+			return
+		}
+		RespondWithError(w, http.StatusInternalServerError, "Failed to delete volunteer work", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Volunteer work deleted successfully",
+	})
+}
+
+func (h *SectionHandler) GetAwardsHandler(w http.ResponseWriter, r *http.Request) {
+	resume, ok := loadAuthorizedResume(w, r, h.resumeRepo)
+	if !ok {
+		return
+	}
+
+	entries, err := h.resumeRepo.GetAwardsByResume(r.Context(), resume.ID)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get award", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, entries)
+}
+
+func (h *SectionHandler) AddAwardHandler(w http.ResponseWriter, r *http.Request) {
+	resume, ok := loadAuthorizedResume(w, r, h.resumeRepo)
+	if !ok {
+		return
+	}
+
+	var award domain.Award
+	if err := json.NewDecoder(r.Body).Decode(&award); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST")
+		return
+	}
+
+	award.BeforeSave()
+
+	if err := award.Validate(); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error(), "VALIDATION_ERROR")
+		return
+	}
+
+	id, err := h.resumeRepo.AddAward(r.Context(), resume.ID, &award)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Failed to add award", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusCreated, map[string]any{
+		"id":      id,
+		"message": "Award added successfully",
+	})
+}
+
+func (h *SectionHandler) UpdateAwardHandler(w http.ResponseWriter, r *http.Request) {
+	resume, ok := loadAuthorizedResume(w, r, h.resumeRepo)
+	if !ok {
+		return
+	}
+
+	id, ok := pathUUID(w, r, "awardId", "Invalid award ID")
+	if !ok {
+		return
+	}
+
+	var award domain.Award
+	if err := json.NewDecoder(r.Body).Decode(&award); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST")
+		return
+	}
+
+	award.BeforeSave()
+
+	if err := award.Validate(); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error(), "VALIDATION_ERROR")
+		return
+	}
+
+	if err := h.resumeRepo.UpdateAward(r.Context(), resume.ID, id, &award); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			RespondWithError(w, http.StatusNotFound, "Award not found", "NOT_FOUND")
+			return
+		}
+		RespondWithError(w, http.StatusInternalServerError, "Failed to update award", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Award updated successfully",
+	})
+}
+
+func (h *SectionHandler) DeleteAwardHandler(w http.ResponseWriter, r *http.Request) {
+	resume, ok := loadAuthorizedResume(w, r, h.resumeRepo)
+	if !ok {
+		return
+	}
+
+	id, ok := pathUUID(w, r, "awardId", "Invalid award ID")
+	if !ok {
+		return
+	}
+
+	if err := h.resumeRepo.DeleteAward(r.Context(), resume.ID, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			RespondWithError(w, http.StatusNotFound, "Award not found", "NOT_FOUND")
+			return
+		}
+		RespondWithError(w, http.StatusInternalServerError, "Failed to delete award", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Award deleted successfully",
+	})
+}
+
+func (h *SectionHandler) GetReferencesHandler(w http.ResponseWriter, r *http.Request) {
+	resume, ok := loadAuthorizedResume(w, r, h.resumeRepo)
+	if !ok {
+		return
+	}
+
+	entries, err := h.resumeRepo.GetReferencesByResume(r.Context(), resume.ID)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get reference", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, entries)
+}
+
+func (h *SectionHandler) AddReferenceHandler(w http.ResponseWriter, r *http.Request) {
+	resume, ok := loadAuthorizedResume(w, r, h.resumeRepo)
+	if !ok {
+		return
+	}
+
+	var reference domain.Reference
+	if err := json.NewDecoder(r.Body).Decode(&reference); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST")
+		return
+	}
+
+	reference.BeforeSave()
+
+	if err := reference.Validate(); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error(), "VALIDATION_ERROR")
+		return
+	}
+
+	id, err := h.resumeRepo.AddReference(r.Context(), resume.ID, &reference)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Failed to add reference", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusCreated, map[string]any{
+		"id":      id,
+		"message": "Reference added successfully",
+	})
+}
+
+func (h *SectionHandler) UpdateReferenceHandler(w http.ResponseWriter, r *http.Request) {
+	resume, ok := loadAuthorizedResume(w, r, h.resumeRepo)
+	if !ok {
+		return
+	}
+
+	id, ok := pathUUID(w, r, "referenceId", "Invalid reference ID")
+	if !ok {
+		return
+	}
+
+	var reference domain.Reference
+	if err := json.NewDecoder(r.Body).Decode(&reference); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST")
+		return
+	}
+
+	reference.BeforeSave()
+
+	if err := reference.Validate(); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error(), "VALIDATION_ERROR")
+		return
+	}
+
+	if err := h.resumeRepo.UpdateReference(r.Context(), resume.ID, id, &reference); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			RespondWithError(w, http.StatusNotFound, "Reference not found", "NOT_FOUND")
+			return
+		}
+		RespondWithError(w, http.StatusInternalServerError, "Failed to update reference", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Reference updated successfully",
+	})
+}
+
+func (h *SectionHandler) DeleteReferenceHandler(w http.ResponseWriter, r *http.Request) {
+	resume, ok := loadAuthorizedResume(w, r, h.resumeRepo)
+	if !ok {
+		return
+	}
+
+	id, ok := pathUUID(w, r, "referenceId", "Invalid reference ID")
+	if !ok {
+		return
+	}
+
+	if err := h.resumeRepo.DeleteReference(r.Context(), resume.ID, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			RespondWithError(w, http.StatusNotFound, "Reference not found", "NOT_FOUND")
+			return
+		}
+		RespondWithError(w, http.StatusInternalServerError, "Failed to delete reference", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Reference deleted successfully",
+	})
+}
+
+// loadAuthorizedResume loads the resume named in the path, which the caller
+// must own unless they are an admin
+func loadAuthorizedResume(w http.ResponseWriter, r *http.Request, resumeRepo domain.ResumeRepository) (*domain.Resume, bool) {
+	claims, err := GetClaimsFromContext(r.Context())
+	if err != nil {
+		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
+		return nil, false
+	}
+
+	userId, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Invalid user ID", "INTERNAL_SERVER_ERROR")
+		return nil, false
+	}
+
+	resumeUUID, ok := pathUUID(w, r, "id", "Invalid resume ID")
+	if !ok {
+		return nil, false
+	}
+
+	resume, err := resumeRepo.GetCVById(r.Context(), resumeUUID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			RespondWithError(w, http.StatusNotFound, "Resume not found", "NOT_FOUND")
+			return nil, false
+		}
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get resume", "INTERNAL_SERVER_ERROR")
+		return nil, false
+	}
+
+	if resume.UserID != userId && claims.Role != "admin" {
+		RespondWithError(w, http.StatusForbidden, "You don't have permission to access this resume", "FORBIDDEN")
+		return nil, false
+	}
+
+	return resume, true
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"cv_builder/internal/domain"
+	"cv_builder/internal/service"
+	"errors"
+	"github.com/rs/zerolog/log"
+	"net/http"
+)
+
+// SessionHandler shows the signed in user where they are signed in and
+// signs them out of single devices or everywhere
+type SessionHandler struct {
+	authService *service.AuthService
+}
+
+func NewSessionHandler(authService *service.AuthService) *SessionHandler {
+	return &SessionHandler{
+		authService: authService,
+	}
+}
+
+// SessionResponse is a session with a flag for the one making the request
+type SessionResponse struct {
+	*domain.Session
+	Current bool `json:"current"`
+}
+
+func (h *SessionHandler) GetSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	claims, err := GetClaimsFromContext(r.Context())
+	if err != nil {
+		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
+		return
+	}
+	userId, err := GetUserIdFromContext(r.Context())
+	if err != nil {
+		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
+		return
+	}
+
+	sessions, err := h.authService.GetSessions(r.Context(), userId)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get sessions")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get sessions", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	response := make([]SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		response = append(response, SessionResponse{
+			Session: session,
+			Current: claims.SessionID != "" && claims.SessionID == session.ID.String(),
+		})
+	}
+
+	RespondWithJSON(w, http.StatusOK, response)
+}
+
+func (h *SessionHandler) RevokeSessionHandler(w http.ResponseWriter, r *http.Request) {
+	userId, err := GetUserIdFromContext(r.Context())
+	if err != nil {
+		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
+		return
+	}
+
+	sessionId, ok := pathUUID(w, r, "id", "Invalid session ID")
+	if !ok {
+		return
+	}
+
+	if err := h.authService.RevokeSession(r.Context(), userId, sessionId); err != nil {
+		if errors.Is(err, service.ErrSessionNotFound) {
+			RespondWithError(w, http.StatusNotFound, "Session not found", "NOT_FOUND")
+			return
+		}
+		log.Error().Err(err).Msg("failed to revoke session")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to revoke session", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Session revoked",
+	})
+}
+
+// RevokeAllSessionsHandler signs the user out on every device, including
+// the one making the request
+func (h *SessionHandler) RevokeAllSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	userId, err := GetUserIdFromContext(r.Context())
+	if err != nil {
+		RespondWithError(w, http.StatusUnauthorized, "Unauthorized", "UNAUTHORIZED")
+		return
+	}
+
+	if err := h.authService.LogoutAll(r.Context(), userId); err != nil {
+		log.Error().Err(err).Msg("failed to revoke sessions")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to revoke sessions", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Signed out everywhere",
+	})
+}
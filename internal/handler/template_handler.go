@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"cv_builder/internal/domain"
+	"cv_builder/internal/repository"
+	"cv_builder/internal/service"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// TemplateHandler serves the catalog of starter resumes. Users can browse it,
+// admins manage it.
+type TemplateHandler struct {
+	templateService *service.TemplateService
+}
+
+func NewTemplateHandler(templateService *service.TemplateService) *TemplateHandler {
+	return &TemplateHandler{
+		templateService: templateService,
+	}
+}
+
+func (h *TemplateHandler) GetTemplatesHandler(w http.ResponseWriter, r *http.Request) {
+	templates, err := h.templateService.GetTemplates(r.Context())
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get templates", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, templates)
+}
+
+func (h *TemplateHandler) GetTemplateHandler(w http.ResponseWriter, r *http.Request) {
+	template, err := h.templateService.GetTemplate(r.Context(), r.PathValue("slug"))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			RespondWithError(w, http.StatusNotFound, "Template not found", "NOT_FOUND")
+			return
+		}
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get template", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, template)
+}
+
+func (h *TemplateHandler) CreateTemplateHandler(w http.ResponseWriter, r *http.Request) {
+	var template domain.ResumeTemplate
+	if err := json.NewDecoder(r.Body).Decode(&template); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST")
+		return
+	}
+
+	if err := h.templateService.CreateTemplate(r.Context(), &template); err != nil {
+		respondTemplateError(w, err, "Failed to create template")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusCreated, map[string]any{
+		"message":  "Template created successfully",
+		"template": template,
+	})
+}
+
+// UpdateTemplateHandler replaces a template. Resumes created from it keep
+// their content.
+func (h *TemplateHandler) UpdateTemplateHandler(w http.ResponseWriter, r *http.Request) {
+	var template domain.ResumeTemplate
+	if err := json.NewDecoder(r.Body).Decode(&template); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST")
+		return
+	}
+
+	if err := h.templateService.UpdateTemplate(r.Context(), r.PathValue("slug"), &template); err != nil {
+		respondTemplateError(w, err, "Failed to update template")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]any{
+		"message":  "Template updated successfully",
+		"template": template,
+	})
+}
+
+func (h *TemplateHandler) DeleteTemplateHandler(w http.ResponseWriter, r *http.Request) {
+	if err := h.templateService.DeleteTemplate(r.Context(), r.PathValue("slug")); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			RespondWithError(w, http.StatusNotFound, "Template not found", "NOT_FOUND")
+			return
+		}
+		RespondWithError(w, http.StatusInternalServerError, "Failed to delete template", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Template deleted successfully",
+	})
+}
+
+func respondTemplateError(w http.ResponseWriter, err error, message string) {
+	var validationErr *domain.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		RespondWithError(w, http.StatusBadRequest, err.Error(), "VALIDATION_ERROR")
+	case errors.Is(err, repository.ErrNotFound):
+		RespondWithError(w, http.StatusNotFound, "Template not found", "NOT_FOUND")
+	case errors.Is(err, repository.ErrConflict):
+		RespondWithError(w, http.StatusConflict, "A template with this slug already exists", "TEMPLATE_EXISTS")
+	default:
+		RespondWithError(w, http.StatusInternalServerError, message, "INTERNAL_SERVER_ERROR")
+	}
+}
@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"cv_builder/internal/domain"
+	"cv_builder/internal/repository"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+)
+
+// TranslationHandler manages the translations of a resume into other locales
+type TranslationHandler struct {
+	resumeRepo domain.ResumeRepository
+}
+
+func NewTranslationHandler(resumeRepo domain.ResumeRepository) *TranslationHandler {
+	return &TranslationHandler{
+		resumeRepo: resumeRepo,
+	}
+}
+
+// GetTranslationsHandler lists the default locale and the translated locales
+func (h *TranslationHandler) GetTranslationsHandler(w http.ResponseWriter, r *http.Request) {
+	resume, ok := loadAuthorizedResume(w, r, h.resumeRepo)
+	if !ok {
+		return
+	}
+
+	locales, err := h.resumeRepo.GetTranslationLocales(r.Context(), resume.ID)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get translations", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]any{
+		"default_locale": resume.DefaultLocale,
+		"locales":        locales,
+	})
+}
+
+func (h *TranslationHandler) GetTranslationHandler(w http.ResponseWriter, r *http.Request) {
+	resume, ok := loadAuthorizedResume(w, r, h.resumeRepo)
+	if !ok {
+		return
+	}
+
+	locale, ok := pathLocale(w, r)
+	if !ok {
+		return
+	}
+
+	translation, err := h.resumeRepo.GetTranslation(r.Context(), resume.ID, locale)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			RespondWithError(w, http.StatusNotFound, "Translation not found", "NOT_FOUND")
+			return
+		}
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get translation", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, translation)
+}
+
+// SaveTranslationHandler creates or replaces the translation into the locale
+// in the path. Entries are referenced by their IDs and must belong to the resume.
+func (h *TranslationHandler) SaveTranslationHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resume, ok := loadAuthorizedResume(w, r, h.resumeRepo)
+	if !ok {
+		return
+	}
+
+	locale, ok := pathLocale(w, r)
+	if !ok {
+		return
+	}
+
+	var translation domain.Translation
+	if err := json.NewDecoder(r.Body).Decode(&translation); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST")
+		return
+	}
+	translation.Locale = locale
+
+	translation.BeforeSave()
+
+	if err := translation.Validate(); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error(), "VALIDATION_ERROR")
+		return
+	}
+
+	if locale == resume.DefaultLocale {
+		RespondWithError(w, http.StatusBadRequest, "The default locale is edited in the resume itself", "VALIDATION_ERROR")
+		return
+	}
+
+	complete, err := h.resumeRepo.GetCompleteResume(ctx, resume.ID)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get resume", "INTERNAL_SERVER_ERROR")
+		return
+	}
+	if err := translation.CheckEntries(complete); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error(), "VALIDATION_ERROR")
+		return
+	}
+
+	if err := h.resumeRepo.SaveTranslation(ctx, resume.ID, &translation); err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Failed to save translation", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]any{
+		"message":     "Translation saved successfully",
+		"translation": translation,
+	})
+}
+
+func (h *TranslationHandler) DeleteTranslationHandler(w http.ResponseWriter, r *http.Request) {
+	resume, ok := loadAuthorizedResume(w, r, h.resumeRepo)
+	if !ok {
+		return
+	}
+
+	locale, ok := pathLocale(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.resumeRepo.DeleteTranslation(r.Context(), resume.ID, locale); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			RespondWithError(w, http.StatusNotFound, "Translation not found", "NOT_FOUND")
+			return
+		}
+		RespondWithError(w, http.StatusInternalServerError, "Failed to delete translation", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message": "Translation deleted successfully",
+	})
+}
+
+// SetDefaultLocaleHandler declares the locale the resume itself is written in.
+// It does not translate anything, so a locale that already has a translation
+// must have it deleted first.
+func (h *TranslationHandler) SetDefaultLocaleHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resume, ok := loadAuthorizedResume(w, r, h.resumeRepo)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Locale string `json:"locale"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_REQUEST")
+		return
+	}
+
+	locale, err := domain.NormalizeLocale(request.Locale)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error(), "VALIDATION_ERROR")
+		return
+	}
+
+	locales, err := h.resumeRepo.GetTranslationLocales(ctx, resume.ID)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get translations", "INTERNAL_SERVER_ERROR")
+		return
+	}
+	if slices.Contains(locales, locale) {
+		RespondWithError(w, http.StatusConflict, "The resume has a translation for this locale, delete it first", "TRANSLATION_EXISTS")
+		return
+	}
+
+	if err := h.resumeRepo.SetDefaultLocale(ctx, resume.ID, locale); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			RespondWithError(w, http.StatusNotFound, "Resume not found", "NOT_FOUND")
+			return
+		}
+		RespondWithError(w, http.StatusInternalServerError, "Failed to set default locale", "INTERNAL_SERVER_ERROR")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]string{
+		"message":        "Default locale updated successfully",
+		"default_locale": locale,
+	})
+}
+
+func pathLocale(w http.ResponseWriter, r *http.Request) (string, bool) {
+	locale, err := domain.NormalizeLocale(r.PathValue("locale"))
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid locale", "INVALID_REQUEST")
+		return "", false
+	}
+	return locale, true
+}
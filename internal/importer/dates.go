@@ -0,0 +1,171 @@
+package importer
+
+import (
+	"cv_builder/internal/domain"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Resumes write dates in many ways ("Mar 2019", "03/2019", "2019-03", "2019").
+// They are converted to partial dates that keep the precision of the source.
+
+const (
+	monthPattern = `(?:jan(?:uary)?|feb(?:ruary)?|mar(?:ch)?|apr(?:il)?|may|june?|july?|aug(?:ust)?|sep(?:t(?:ember)?)?|oct(?:ober)?|nov(?:ember)?|dec(?:ember)?)\.?`
+	datePattern  = `(?:` + monthPattern + `\s+\d{4}|\d{4}-\d{1,2}-\d{1,2}|\d{4}[-/.]\d{1,2}|\d{1,2}[/.]\d{4}|\d{4})`
+	endPattern   = `(?:` + datePattern + `|present|current|now|today|ongoing)`
+)
+
+var (
+	dateRangeRegex  = regexp.MustCompile(`(?i)\b(` + datePattern + `)\s*(?:–|—|-|\bto\b|\buntil\b|\btill\b)\s*(` + endPattern + `)\b`)
+	singleDateRegex = regexp.MustCompile(`(?i)\b(` + datePattern + `)\b`)
+
+	monthNameRegex = regexp.MustCompile(`(?i)^(` + monthPattern + `)\s+(\d{4})$`)
+	isoDateRegex   = regexp.MustCompile(`^(\d{4})-(\d{1,2})-(\d{1,2})$`)
+	yearMonthRegex = regexp.MustCompile(`^(\d{4})[-/.](\d{1,2})$`)
+	monthYearRegex = regexp.MustCompile(`^(\d{1,2})[/.](\d{4})$`)
+	yearRegex      = regexp.MustCompile(`^(\d{4})$`)
+)
+
+var presentWords = map[string]bool{
+	"present": true,
+	"current": true,
+	"now":     true,
+	"today":   true,
+	"ongoing": true,
+}
+
+// findDateRange locates a date range such as "Mar 2019 – Present" in a line
+// and returns the normalised start and end dates along with the rest of the
+// line
+func findDateRange(line string) (domain.PartialDate, domain.PartialDate, string, bool) {
+	match := dateRangeRegex.FindStringSubmatchIndex(line)
+	if match == nil {
+		return domain.PartialDate{}, domain.PartialDate{}, line, false
+	}
+
+	start, ok := normalizeDate(line[match[2]:match[3]])
+	if !ok {
+		return domain.PartialDate{}, domain.PartialDate{}, line, false
+	}
+	end, ok := normalizeEndDate(line[match[4]:match[5]])
+	if !ok {
+		return domain.PartialDate{}, domain.PartialDate{}, line, false
+	}
+
+	return start, end, removeSpan(line, match[0], match[1]), true
+}
+
+// findDate locates a single date in a line and returns it normalised along
+// with the rest of the line
+func findDate(line string) (domain.PartialDate, string, bool) {
+	for _, match := range singleDateRegex.FindAllStringSubmatchIndex(line, -1) {
+		if date, ok := normalizeDate(line[match[2]:match[3]]); ok {
+			return date, removeSpan(line, match[0], match[1]), true
+		}
+	}
+	return domain.PartialDate{}, line, false
+}
+
+func hasDateRange(line string) bool {
+	_, _, _, ok := findDateRange(line)
+	return ok
+}
+
+// removeSpan cuts a match out of a line along with brackets and separators
+// left dangling around it
+func removeSpan(line string, start, end int) string {
+	rest := line[:start] + " " + line[end:]
+	rest = strings.ReplaceAll(rest, "()", "")
+	rest = strings.ReplaceAll(rest, "[]", "")
+	rest = strings.ReplaceAll(rest, "( )", "")
+	return strings.Trim(strings.Join(strings.Fields(rest), " "), " ,|·•–—-")
+}
+
+func normalizeEndDate(value string) (domain.PartialDate, bool) {
+	if presentWords[strings.ToLower(strings.TrimSpace(value))] {
+		return domain.Present(), true
+	}
+	return normalizeDate(value)
+}
+
+// normalizeDate converts a date written in one of the supported layouts to a
+// partial date with the precision it was written with
+func normalizeDate(value string) (domain.PartialDate, bool) {
+	value = strings.TrimSpace(value)
+
+	if m := isoDateRegex.FindStringSubmatch(value); m != nil {
+		return partialDate(m[1], m[2], m[3])
+	}
+	if m := monthNameRegex.FindStringSubmatch(value); m != nil {
+		month, ok := monthNumber(m[1])
+		if !ok {
+			return domain.PartialDate{}, false
+		}
+		return partialDate(m[2], strconv.Itoa(month), "")
+	}
+	if m := yearMonthRegex.FindStringSubmatch(value); m != nil {
+		return partialDate(m[1], m[2], "")
+	}
+	if m := monthYearRegex.FindStringSubmatch(value); m != nil {
+		return partialDate(m[2], m[1], "")
+	}
+	if m := yearRegex.FindStringSubmatch(value); m != nil {
+		return partialDate(m[1], "", "")
+	}
+	return domain.PartialDate{}, false
+}
+
+// partialDate builds a date from its components, leaving out empty ones
+func partialDate(year, month, day string) (domain.PartialDate, bool) {
+	y, _ := strconv.Atoi(year)
+	if y < 1900 || y > 2100 {
+		return domain.PartialDate{}, false
+	}
+	if month == "" {
+		return domain.NewYear(y), true
+	}
+
+	m, _ := strconv.Atoi(month)
+	if m < 1 || m > 12 {
+		return domain.PartialDate{}, false
+	}
+	if day == "" {
+		return domain.NewYearMonth(y, time.Month(m)), true
+	}
+
+	d, _ := strconv.Atoi(day)
+	date := domain.NewDate(y, time.Month(m), d)
+	// Reject days the month does not have
+	if d < 1 || date.FirstDay().Day() != d {
+		return domain.PartialDate{}, false
+	}
+	return date, true
+}
+
+// fullDate writes a date for the sections that still take a full YYYY-MM-DD
+// date, using the first day of a month or year
+func fullDate(date domain.PartialDate) string {
+	switch {
+	case date.IsZero():
+		return ""
+	case date.IsPresent():
+		return domain.PresentLabel
+	}
+	return date.FirstDay().Format("2006-01-02")
+}
+
+func monthNumber(name string) (int, bool) {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	if len(name) < 3 {
+		return 0, false
+	}
+	months := []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+	for i, month := range months {
+		if name[:3] == month {
+			return i + 1, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,989 @@
+package importer
+
+import (
+	"cv_builder/internal/domain"
+	"cv_builder/internal/europass"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// The text based importers (Markdown, PDF) first split a document into a
+// draft of headed sections and entries, which is then mapped onto the
+// domain. Entries that do not validate are kept so users can fix them in the
+// preview; the validation problems are reported as warnings.
+
+const (
+	sectionExperience     = "experience"
+	sectionEducation      = "education"
+	sectionSkills         = "skills"
+	sectionLanguages      = "languages"
+	sectionProjects       = "projects"
+	sectionCertifications = "certifications"
+	sectionSummary        = "summary"
+	sectionVolunteer      = "volunteer"
+	sectionAwards         = "awards"
+	sectionReferences     = "references"
+)
+
+var sectionTitles = map[string]string{
+	"experience":                  sectionExperience,
+	"work experience":             sectionExperience,
+	"professional experience":     sectionExperience,
+	"relevant experience":         sectionExperience,
+	"employment":                  sectionExperience,
+	"employment history":          sectionExperience,
+	"work history":                sectionExperience,
+	"career history":              sectionExperience,
+	"education":                   sectionEducation,
+	"education and training":      sectionEducation,
+	"academic background":         sectionEducation,
+	"skills":                      sectionSkills,
+	"technical skills":            sectionSkills,
+	"core skills":                 sectionSkills,
+	"key skills":                  sectionSkills,
+	"skills and tools":            sectionSkills,
+	"technologies":                sectionSkills,
+	"tech stack":                  sectionSkills,
+	"languages":                   sectionLanguages,
+	"spoken languages":            sectionLanguages,
+	"projects":                    sectionProjects,
+	"personal projects":           sectionProjects,
+	"side projects":               sectionProjects,
+	"selected projects":           sectionProjects,
+	"open source":                 sectionProjects,
+	"certifications":              sectionCertifications,
+	"certificates":                sectionCertifications,
+	"licenses and certifications": sectionCertifications,
+	"courses and certifications":  sectionCertifications,
+	"summary":                     sectionSummary,
+	"professional summary":        sectionSummary,
+	"profile":                     sectionSummary,
+	"professional profile":        sectionSummary,
+	"about":                       sectionSummary,
+	"about me":                    sectionSummary,
+	"objective":                   sectionSummary,
+	"career objective":            sectionSummary,
+	"personal statement":          sectionSummary,
+	"volunteer":                   sectionVolunteer,
+	"volunteering":                sectionVolunteer,
+	"volunteer work":              sectionVolunteer,
+	"volunteer experience":        sectionVolunteer,
+	"awards":                      sectionAwards,
+	"honors":                      sectionAwards,
+	"honours":                     sectionAwards,
+	"honors and awards":           sectionAwards,
+	"honours and awards":          sectionAwards,
+	"awards and honors":           sectionAwards,
+	"achievements and awards":     sectionAwards,
+	"references":                  sectionReferences,
+	"referees":                    sectionReferences,
+}
+
+// sectionKind recognises a section heading such as "Work Experience" or
+// "TECHNICAL SKILLS"
+func sectionKind(title string) (string, bool) {
+	normalized := strings.ToLower(strings.Trim(title, " :#*_"))
+	normalized = strings.ReplaceAll(normalized, "&", " and ")
+	normalized = strings.Join(strings.Fields(normalized), " ")
+	kind, ok := sectionTitles[normalized]
+	return kind, ok
+}
+
+type draftLine struct {
+	text   string
+	bullet bool
+	links  []string
+}
+
+type draftEntry struct {
+	title   string
+	lines   []string
+	bullets []string
+	links   []string
+}
+
+type draftSection struct {
+	kind    string
+	title   string
+	lines   []draftLine
+	entries []draftEntry
+}
+
+type draftDocument struct {
+	name     string
+	header   []string
+	sections []draftSection
+}
+
+var (
+	emailRegex     = regexp.MustCompile(`[a-zA-Z0-9.!#$%&'*+/=?^_{|}~-]+@[a-zA-Z0-9-]+(?:\.[a-zA-Z0-9-]+)+`)
+	phoneRegex     = regexp.MustCompile(`(?:\+|00)?\d[\d\s().-]{6,}\d`)
+	urlRegex       = regexp.MustCompile(`(?i)\bhttps?://[^\s)<>|]+|\b(?:www\.)?(?:linkedin\.com|github\.com|gitlab\.com)/[^\s)<>|]+`)
+	degreeField    = regexp.MustCompile(`^(.+?)\s+in\s+(.+)$`)
+	fractionRegex  = regexp.MustCompile(`\b(\d{1,2})\s*/\s*(5|10)\b`)
+	cefrRegex      = regexp.MustCompile(`(?i)\b([ABC][12])\b`)
+	credentialLine = regexp.MustCompile(`(?i)^(?:credential(?:\s+id)?|certificate\s+(?:id|no\.?|number)|license\s+(?:id|no\.?|number))\s*[:#]?\s*(.+)$`)
+)
+
+var institutionWords = []string{"university", "college", "institute", "school", "academy", "polytechnic", "universität", "université", "universidad"}
+
+var skillCategoryLabels = map[string]string{
+	"languages":                domain.SkillCategoryLanguage,
+	"programming languages":    domain.SkillCategoryLanguage,
+	"programming":              domain.SkillCategoryLanguage,
+	"frameworks":               domain.SkillCategoryFramework,
+	"libraries":                domain.SkillCategoryFramework,
+	"frameworks and libraries": domain.SkillCategoryFramework,
+	"tools":                    domain.SkillCategoryTool,
+	"tooling":                  domain.SkillCategoryTool,
+	"devops":                   domain.SkillCategoryTool,
+	"infrastructure":           domain.SkillCategoryTool,
+	"databases":                domain.SkillCategoryDatabase,
+	"data stores":              domain.SkillCategoryDatabase,
+	"storage":                  domain.SkillCategoryDatabase,
+	"other":                    domain.SkillCategoryOther,
+}
+
+// proficiencyWords maps level descriptions to the 1-5 scale, checked in order
+var proficiencyWords = []struct {
+	word  string
+	level int
+}{
+	{"mother tongue", 5},
+	{"native", 5},
+	{"bilingual", 5},
+	{"expert", 5},
+	{"advanced", 4},
+	{"proficient", 4},
+	{"fluent", 4},
+	{"intermediate", 3},
+	{"conversational", 3},
+	{"working", 3},
+	{"elementary", 2},
+	{"basic", 1},
+	{"beginner", 1},
+}
+
+// languageLevelWords maps level descriptions of spoken languages to CEFR
+// levels, checked in order
+var languageLevelWords = []struct {
+	word  string
+	level string
+}{
+	{"mother tongue", domain.LanguageLevelNative},
+	{"native", domain.LanguageLevelNative},
+	{"bilingual", domain.LanguageLevelNative},
+	{"fluent", "C1"},
+	{"proficient", "C1"},
+	{"advanced", "C1"},
+	{"upper intermediate", "B2"},
+	{"upper-intermediate", "B2"},
+	{"working", "B2"},
+	{"intermediate", "B1"},
+	{"conversational", "B1"},
+	{"elementary", "A2"},
+	{"basic", "A1"},
+	{"beginner", "A1"},
+}
+
+type draftBuilder struct {
+	warnings []domain.ConversionWarning
+}
+
+func (b *draftBuilder) warn(field, format string, args ...any) {
+	b.warnings = append(b.warnings, domain.NewConversionWarning(field, fmt.Sprintf(format, args...)))
+}
+
+// validate reports validation problems of an entry without dropping it
+func (b *draftBuilder) validate(field string, entry interface{ Validate() error }) {
+	err := entry.Validate()
+	if err == nil {
+		return
+	}
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		b.warn(field+"."+validationErr.Field, "%s", validationErr.Message)
+		return
+	}
+	b.warn(field, "%s", err.Error())
+}
+
+// toResume maps a draft onto a resume aggregate
+func (d *draftDocument) toResume() (*domain.Resume, []domain.ConversionWarning) {
+	b := &draftBuilder{}
+	resume := &domain.Resume{}
+
+	resume.PersonalInfo = b.personalInfo(d, d.summary())
+
+	for _, section := range d.sections {
+		switch section.kind {
+		case sectionExperience:
+			for _, entry := range section.draftEntries() {
+				resume.Experience = append(resume.Experience, b.experience(fmt.Sprintf("experience[%d]", len(resume.Experience)), entry))
+			}
+		case sectionEducation:
+			for _, entry := range section.draftEntries() {
+				resume.Education = append(resume.Education, b.education(fmt.Sprintf("education[%d]", len(resume.Education)), entry))
+			}
+		case sectionProjects:
+			for _, entry := range section.draftEntries() {
+				resume.Projects = append(resume.Projects, b.project(fmt.Sprintf("projects[%d]", len(resume.Projects)), entry))
+			}
+		case sectionCertifications:
+			for _, entry := range section.listEntries() {
+				resume.Certifications = append(resume.Certifications, b.certification(fmt.Sprintf("certifications[%d]", len(resume.Certifications)), entry))
+			}
+		case sectionVolunteer:
+			for _, entry := range section.draftEntries() {
+				resume.Volunteer = append(resume.Volunteer, b.volunteer(fmt.Sprintf("volunteer[%d]", len(resume.Volunteer)), entry))
+			}
+		case sectionAwards:
+			for _, entry := range section.listEntries() {
+				resume.Awards = append(resume.Awards, b.award(fmt.Sprintf("awards[%d]", len(resume.Awards)), entry))
+			}
+		case sectionReferences:
+			for _, entry := range referenceEntries(section) {
+				if reference := b.reference(fmt.Sprintf("references[%d]", len(resume.References)), entry); reference != nil {
+					resume.References = append(resume.References, reference)
+				}
+			}
+		case sectionSummary:
+			// Part of the personal info
+		case sectionSkills, sectionLanguages:
+			skills, languages := b.skills(section)
+			resume.Skills = append(resume.Skills, skills...)
+			resume.Languages = append(resume.Languages, languages...)
+		default:
+			resume.CustomSections = append(resume.CustomSections, b.customSection(fmt.Sprintf("custom_sections[%d]", len(resume.CustomSections)), section))
+		}
+	}
+
+	for i, skill := range resume.Skills {
+		b.validate(fmt.Sprintf("skills[%d]", i), skill)
+	}
+	for i, language := range resume.Languages {
+		b.validate(fmt.Sprintf("languages[%d]", i), language)
+	}
+
+	return resume, b.warnings
+}
+
+// draftEntries returns the entries of a section. Sections written without
+// entry headings are split at the lines holding date ranges.
+func (s draftSection) draftEntries() []draftEntry {
+	if len(s.entries) > 0 || len(s.lines) == 0 {
+		return s.entries
+	}
+	return segmentEntries(s.lines)
+}
+
+// listEntries returns the entries of a section whose items are usually
+// written one per line, such as certifications
+func (s draftSection) listEntries() []draftEntry {
+	entries := append([]draftEntry(nil), s.entries...)
+	for _, line := range s.lines {
+		entries = append(entries, draftEntry{title: line.text, links: line.links})
+	}
+	return entries
+}
+
+// segmentEntries splits the lines of a section into entries. An entry starts
+// with the line preceding a date range, or with the date line itself when
+// that line already follows another entry's content.
+func segmentEntries(lines []draftLine) []draftEntry {
+	var starts []int
+	for i, line := range lines {
+		if line.bullet || !hasDateRange(line.text) {
+			continue
+		}
+		start := i
+		if i > 0 && !lines[i-1].bullet && !hasDateRange(lines[i-1].text) && (len(starts) == 0 || i-1 > starts[len(starts)-1]) {
+			start = i - 1
+		}
+		starts = append(starts, start)
+	}
+	if len(starts) == 0 {
+		return []draftEntry{entryFromLines(lines)}
+	}
+
+	// Lines before the first entry belong to it
+	starts[0] = 0
+
+	entries := make([]draftEntry, 0, len(starts))
+	for i, start := range starts {
+		end := len(lines)
+		if i+1 < len(starts) {
+			end = starts[i+1]
+		}
+		entries = append(entries, entryFromLines(lines[start:end]))
+	}
+	return entries
+}
+
+func entryFromLines(lines []draftLine) draftEntry {
+	var entry draftEntry
+	for i, line := range lines {
+		entry.links = append(entry.links, line.links...)
+		switch {
+		case i == 0 && !line.bullet:
+			entry.title = line.text
+		case line.bullet:
+			entry.bullets = append(entry.bullets, line.text)
+		default:
+			entry.lines = append(entry.lines, line.text)
+		}
+	}
+	return entry
+}
+
+// summary joins the text of the summary sections into one paragraph
+func (d *draftDocument) summary() string {
+	var parts []string
+	for _, section := range d.sections {
+		if section.kind != sectionSummary {
+			continue
+		}
+		for _, line := range section.lines {
+			parts = append(parts, line.text)
+		}
+		for _, entry := range section.entries {
+			parts = append(parts, entry.title)
+			parts = append(parts, entry.lines...)
+			parts = append(parts, entry.bullets...)
+		}
+	}
+	return strings.Join(strings.Fields(strings.Join(parts, " ")), " ")
+}
+
+func (b *draftBuilder) personalInfo(d *draftDocument, summary string) *domain.PersonalInfo {
+	if d.name == "" && len(d.header) == 0 && summary == "" {
+		return nil
+	}
+
+	info := &domain.PersonalInfo{Summary: summary}
+	info.FirstName, info.LastName = splitName(d.name)
+
+	for _, line := range d.header {
+		parts := splitParts(line)
+		recognised := false
+		var unknown []string
+		for _, part := range parts {
+			switch {
+			case emailRegex.MatchString(part):
+				if info.Email == "" {
+					info.Email = emailRegex.FindString(part)
+				}
+				recognised = true
+			case urlRegex.MatchString(part):
+				info.Profiles = append(info.Profiles, profileFromURL(absoluteURL(urlRegex.FindString(part))))
+				recognised = true
+			case phoneRegex.MatchString(part) && info.Phone == "":
+				info.Phone = normalizePhone(phoneRegex.FindString(part))
+				recognised = true
+			default:
+				unknown = append(unknown, part)
+			}
+		}
+		if !recognised && info.JobTitle == "" && len(parts) == 1 {
+			info.JobTitle = parts[0]
+			continue
+		}
+		for _, part := range unknown {
+			b.warn("personal_info", "Header text %q was not recognised and was dropped", part)
+		}
+	}
+
+	info.BeforeSave()
+	b.validate("personal_info", info)
+	return info
+}
+
+func splitName(name string) (string, string) {
+	fields := strings.Fields(name)
+	switch len(fields) {
+	case 0:
+		return "", ""
+	case 1:
+		return fields[0], ""
+	default:
+		return strings.Join(fields[:len(fields)-1], " "), fields[len(fields)-1]
+	}
+}
+
+// normalizePhone strips the formatting from a phone number so that
+// international numbers end up in E.164 form
+func normalizePhone(phone string) string {
+	var digits strings.Builder
+	for i, r := range phone {
+		if r == '+' && i == 0 {
+			digits.WriteRune(r)
+		}
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	normalized := digits.String()
+	if strings.HasPrefix(normalized, "00") {
+		normalized = "+" + normalized[2:]
+	}
+	return normalized
+}
+
+// splitParts splits a line on the separators commonly used between header
+// and metadata fields
+func splitParts(line string) []string {
+	fields := strings.FieldsFunc(line, func(r rune) bool {
+		return r == '|' || r == '·' || r == '•' || r == '⋅'
+	})
+	parts := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field = strings.TrimSpace(field); field != "" {
+			parts = append(parts, field)
+		}
+	}
+	return parts
+}
+
+// splitTitle splits an entry heading like "Engineer — Acme" or
+// "Engineer at Acme" into its two halves
+func splitTitle(title string) (string, string) {
+	for _, sep := range []string{" — ", " – ", " - ", " | ", " at ", " @ ", ", "} {
+		if first, second, ok := strings.Cut(title, sep); ok {
+			return strings.TrimSpace(first), strings.TrimSpace(second)
+		}
+	}
+	return strings.TrimSpace(title), ""
+}
+
+// entryDates takes the date range of an entry from its title or its first
+// line that holds one, returning the remaining text of that line
+func entryDates(entry *draftEntry) (domain.PartialDate, domain.PartialDate, string, bool) {
+	if start, end, rest, ok := findDateRange(entry.title); ok {
+		entry.title = rest
+		return start, end, "", true
+	}
+	for i, line := range entry.lines {
+		if start, end, rest, ok := findDateRange(line); ok {
+			entry.lines = append(entry.lines[:i:i], entry.lines[i+1:]...)
+			return start, end, rest, true
+		}
+	}
+	return domain.PartialDate{}, domain.PartialDate{}, "", false
+}
+
+func (b *draftBuilder) experience(field string, entry draftEntry) *domain.Experience {
+	exp := &domain.Experience{}
+
+	start, end, meta, _ := entryDates(&entry)
+	exp.StartDate, exp.EndDate = start, end
+	exp.JobTitle, exp.Employer = splitTitle(entry.title)
+
+	parts := splitParts(meta)
+	if exp.Employer == "" && len(parts) > 0 {
+		exp.Employer, parts = parts[0], parts[1:]
+	}
+	exp.Location = strings.Join(parts, ", ")
+
+	exp.Description = strings.Join(entry.lines, "\n")
+	exp.Achievements = entry.bullets
+
+	exp.BeforeSave()
+	b.validate(field, exp)
+	return exp
+}
+
+func (b *draftBuilder) education(field string, entry draftEntry) *domain.Education {
+	edu := &domain.Education{}
+
+	start, end, meta, _ := entryDates(&entry)
+	edu.StartDate, edu.EndDate = start, end
+	edu.Degree, edu.Institution = splitTitle(entry.title)
+	if isInstitution(edu.Degree) && !isInstitution(edu.Institution) {
+		edu.Degree, edu.Institution = edu.Institution, edu.Degree
+	}
+
+	parts := splitParts(meta)
+	if edu.Institution == "" && len(parts) > 0 {
+		edu.Institution, parts = parts[0], parts[1:]
+	}
+	edu.Location = strings.Join(parts, ", ")
+
+	if m := degreeField.FindStringSubmatch(edu.Degree); m != nil {
+		edu.Degree, edu.Field = m[1], m[2]
+	}
+
+	description := append(entry.lines, entry.bullets...)
+	edu.Description = strings.Join(description, "\n")
+
+	edu.BeforeSave()
+	b.validate(field, edu)
+	return edu
+}
+
+func isInstitution(name string) bool {
+	name = strings.ToLower(name)
+	for _, word := range institutionWords {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *draftBuilder) project(field string, entry draftEntry) *domain.Project {
+	project := &domain.Project{}
+
+	start, end, _, _ := entryDates(&entry)
+	project.StartDate, project.EndDate = start, end
+	project.Name = strings.TrimSpace(entry.title)
+
+	var description []string
+	for _, line := range append(entry.lines, entry.bullets...) {
+		key, value, ok := strings.Cut(line, ":")
+		if ok {
+			switch strings.ToLower(strings.TrimSpace(key)) {
+			case "technologies", "tech stack", "stack", "built with", "tech":
+				for _, tech := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' }) {
+					project.Technologies = append(project.Technologies, strings.TrimSpace(tech))
+				}
+				continue
+			case "repository", "repo", "github", "gitlab", "source", "code":
+				if link := urlRegex.FindString(value); link != "" {
+					entry.links = append([]string{link}, entry.links...)
+				}
+				continue
+			case "demo", "live", "website", "url":
+				if link := urlRegex.FindString(value); link != "" && project.DemoURL == "" {
+					project.DemoURL = absoluteURL(link)
+				}
+				continue
+			}
+		}
+		description = append(description, line)
+	}
+	project.Description = strings.Join(description, "\n")
+
+	for _, link := range entry.links {
+		link = absoluteURL(link)
+		switch {
+		case isRepositoryURL(link) && project.RepoURL == "":
+			project.RepoURL = link
+		case !isRepositoryURL(link) && project.DemoURL == "":
+			project.DemoURL = link
+		}
+	}
+
+	project.BeforeSave()
+	b.validate(field, project)
+	return project
+}
+
+// profileFromURL recognises the network of a profile link, taking the
+// username from the last part of the path of social network links
+func profileFromURL(link string) *domain.Profile {
+	profile := &domain.Profile{Network: domain.ProfileNetworkForURL(link), URL: link}
+	if profile.Network != domain.ProfileNetworkWebsite {
+		if parsed, err := url.Parse(link); err == nil {
+			segments := strings.FieldsFunc(parsed.Path, func(r rune) bool { return r == '/' })
+			if len(segments) > 0 {
+				profile.Username = segments[len(segments)-1]
+			}
+		}
+	}
+	return profile
+}
+
+func isRepositoryURL(link string) bool {
+	link = strings.ToLower(link)
+	return strings.Contains(link, "github.com") || strings.Contains(link, "gitlab.com") || strings.Contains(link, "bitbucket.org")
+}
+
+func absoluteURL(link string) string {
+	if strings.HasPrefix(strings.ToLower(link), "http://") || strings.HasPrefix(strings.ToLower(link), "https://") {
+		return link
+	}
+	return "https://" + link
+}
+
+func (b *draftBuilder) certification(field string, entry draftEntry) *domain.Certification {
+	cert := &domain.Certification{}
+
+	text := entry.title
+	if start, end, rest, ok := findDateRange(text); ok {
+		cert.IssueDate = start
+		if !end.IsPresent() {
+			cert.ExpiryDate = end
+		}
+		text = rest
+	} else if date, rest, ok := findDate(text); ok {
+		cert.IssueDate = date
+		text = rest
+	}
+
+	for _, line := range append(entry.lines, entry.bullets...) {
+		if m := credentialLine.FindStringSubmatch(line); m != nil {
+			cert.CredentialID = strings.TrimSpace(m[1])
+			continue
+		}
+		if cert.IssueDate.IsZero() {
+			if date, _, ok := findDate(line); ok {
+				cert.IssueDate = date
+				continue
+			}
+		}
+		if cert.Issuer == "" {
+			cert.Issuer = line
+		}
+	}
+
+	name, issuer := splitTitle(text)
+	cert.Name = name
+	if issuer != "" {
+		cert.Issuer = issuer
+	}
+	if len(entry.links) > 0 {
+		cert.URL = absoluteURL(entry.links[0])
+	}
+
+	cert.BeforeSave()
+	b.validate(field, cert)
+	return cert
+}
+
+func (b *draftBuilder) volunteer(field string, entry draftEntry) *domain.Volunteer {
+	volunteer := &domain.Volunteer{}
+
+	start, end, meta, _ := entryDates(&entry)
+	volunteer.StartDate, volunteer.EndDate = fullDate(start), fullDate(end)
+	volunteer.Role, volunteer.Organization = splitTitle(entry.title)
+
+	parts := splitParts(meta)
+	if volunteer.Organization == "" && len(parts) > 0 {
+		volunteer.Organization, parts = parts[0], parts[1:]
+	}
+	volunteer.Location = strings.Join(parts, ", ")
+
+	volunteer.Description = strings.Join(append(entry.lines, entry.bullets...), "\n")
+	if len(entry.links) > 0 {
+		volunteer.URL = absoluteURL(entry.links[0])
+	}
+
+	volunteer.BeforeSave()
+	b.validate(field, volunteer)
+	return volunteer
+}
+
+func (b *draftBuilder) award(field string, entry draftEntry) *domain.Award {
+	award := &domain.Award{}
+
+	text := entry.title
+	if date, rest, ok := findDate(text); ok {
+		award.Date = fullDate(date)
+		text = rest
+	}
+	award.Title, award.Awarder = splitTitle(strings.TrimSpace(urlRegex.ReplaceAllString(text, "")))
+
+	var description []string
+	for _, line := range append(entry.lines, entry.bullets...) {
+		if award.Date == "" {
+			if date, rest, ok := findDate(line); ok {
+				award.Date = fullDate(date)
+				if line = strings.TrimSpace(rest); line == "" {
+					continue
+				}
+			}
+		}
+		if award.Awarder == "" {
+			award.Awarder = line
+			continue
+		}
+		description = append(description, line)
+	}
+	award.Description = strings.Join(description, "\n")
+	if len(entry.links) > 0 {
+		award.URL = absoluteURL(entry.links[0])
+	}
+
+	award.BeforeSave()
+	b.validate(field, award)
+	return award
+}
+
+// referenceEntries joins lines holding only contact details to the
+// reference above them, as in "Jane Doe — Manager" followed by "jane@acme.com"
+func referenceEntries(section draftSection) []draftEntry {
+	var entries []draftEntry
+	for _, entry := range section.listEntries() {
+		if len(entries) > 0 && isContactLine(entry.title) {
+			last := &entries[len(entries)-1]
+			last.lines = append(last.lines, entry.title)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func isContactLine(line string) bool {
+	parts := splitParts(line)
+	if len(parts) == 0 {
+		return false
+	}
+	for _, part := range parts {
+		if !emailRegex.MatchString(part) && !phoneRegex.MatchString(part) {
+			return false
+		}
+	}
+	return true
+}
+
+// onRequestRegex matches the "References available upon request" note
+var onRequestRegex = regexp.MustCompile(`(?i)\bavailable\s+(?:up)?on\s+request\b`)
+
+// reference reads an entry like "Jane Doe — Former manager, Acme" followed
+// by contact lines. A bare "available upon request" note is not a reference.
+func (b *draftBuilder) reference(field string, entry draftEntry) *domain.Reference {
+	if onRequestRegex.MatchString(entry.title) && len(entry.lines) == 0 && len(entry.bullets) == 0 {
+		return nil
+	}
+
+	reference := &domain.Reference{}
+	reference.Name, reference.Relationship = splitTitle(entry.title)
+	if relationship, company, ok := strings.Cut(reference.Relationship, ", "); ok {
+		reference.Relationship, reference.Company = relationship, company
+	}
+
+	for _, line := range append(entry.lines, entry.bullets...) {
+		if onRequestRegex.MatchString(line) {
+			reference.AvailableOnRequest = true
+			continue
+		}
+		recognised := false
+		for _, part := range splitParts(line) {
+			switch {
+			case emailRegex.MatchString(part) && reference.Email == "":
+				reference.Email = emailRegex.FindString(part)
+				recognised = true
+			case phoneRegex.MatchString(part) && reference.Phone == "":
+				reference.Phone = normalizePhone(phoneRegex.FindString(part))
+				recognised = true
+			}
+		}
+		if !recognised && reference.Relationship == "" {
+			reference.Relationship = line
+		}
+	}
+	if reference.Email == "" && reference.Phone == "" {
+		reference.AvailableOnRequest = true
+	}
+
+	reference.BeforeSave()
+	b.validate(field, reference)
+	return reference
+}
+
+// customSection keeps a section with no dedicated model, such as
+// "Publications", as a custom section with one entry per item
+func (b *draftBuilder) customSection(field string, section draftSection) *domain.CustomSection {
+	custom := &domain.CustomSection{Title: strings.Trim(section.title, " :#*_")}
+
+	for _, item := range section.listEntries() {
+		entry := &domain.CustomSectionEntry{}
+		start, end, meta, _ := entryDates(&item)
+		entry.StartDate, entry.EndDate = fullDate(start), fullDate(end)
+		title := item.title
+		if stripped := strings.TrimSpace(urlRegex.ReplaceAllString(title, "")); stripped != "" {
+			title = stripped
+		}
+		entry.Title, entry.Subtitle = splitTitle(title)
+		if entry.Subtitle == "" {
+			entry.Subtitle = meta
+		}
+		entry.Bullets = append(item.lines, item.bullets...)
+		if len(item.links) > 0 {
+			entry.URL = absoluteURL(item.links[0])
+		}
+		custom.Entries = append(custom.Entries, entry)
+	}
+
+	custom.BeforeSave()
+	b.validate(field, custom)
+	return custom
+}
+
+// skills parses skill lists written as comma separated items, optionally
+// grouped under a "Label:" prefix or an entry heading naming the category.
+// Spoken languages are returned separately.
+func (b *draftBuilder) skills(section draftSection) ([]*domain.Skill, []*domain.Language) {
+	var skills []*domain.Skill
+	var spokenLanguages []*domain.Language
+	languages := section.kind == sectionLanguages
+
+	add := func(category, line string) {
+		if label, rest, ok := strings.Cut(line, ":"); ok {
+			if known, found := skillCategory(label); found {
+				category, line = known, rest
+			} else if len(strings.Fields(label)) <= 3 && !strings.Contains(rest, ",") {
+				// "English: Native" names a single item and its level
+				line = label + " (" + strings.TrimSpace(rest) + ")"
+			}
+		}
+		for _, item := range splitItems(line) {
+			name, _ := splitLevel(item)
+			if _, spoken := domain.LanguageCodeForName(name); spoken || languages {
+				if language, ok := languageFromItem(item); ok {
+					spokenLanguages = append(spokenLanguages, language)
+				} else {
+					b.warn(section.title, "%q is not a known language and was skipped", name)
+				}
+				continue
+			}
+			skill := skillFromItem(item, category)
+			if skill.Name == "" {
+				continue
+			}
+			skills = append(skills, skill)
+		}
+	}
+
+	for _, line := range section.lines {
+		add("", line.text)
+	}
+	for _, entry := range section.entries {
+		category, _ := skillCategory(entry.title)
+		for _, line := range append(entry.lines, entry.bullets...) {
+			add(category, line)
+		}
+	}
+
+	return skills, spokenLanguages
+}
+
+func skillCategory(label string) (string, bool) {
+	normalized := strings.ToLower(strings.Trim(label, " *_#"))
+	normalized = strings.ReplaceAll(normalized, "&", "and")
+	normalized = strings.Join(strings.Fields(normalized), " ")
+	category, ok := skillCategoryLabels[normalized]
+	return category, ok
+}
+
+// splitItems splits a skill list on commas and semicolons that are not
+// inside parentheses
+func splitItems(line string) []string {
+	var items []string
+	depth := 0
+	start := 0
+	for i, r := range line {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			if depth > 0 {
+				depth--
+			}
+		case ',', ';', '•':
+			if depth == 0 {
+				items = append(items, line[start:i])
+				start = i + len(string(r))
+			}
+		}
+	}
+	items = append(items, line[start:])
+
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// splitLevel splits an item like "Go (5/5)", "German (C1)" or
+// "Docker - Advanced" into its name and level
+func splitLevel(item string) (string, string) {
+	name, level := item, ""
+	if open := strings.Index(item, "("); open > 0 && strings.HasSuffix(item, ")") {
+		name, level = item[:open], item[open+1:len(item)-1]
+	} else if before, after, ok := strings.Cut(item, " - "); ok {
+		name, level = before, after
+	} else if before, after, ok := strings.Cut(item, " – "); ok {
+		name, level = before, after
+	}
+	return strings.TrimSpace(name), level
+}
+
+func skillFromItem(item, category string) *domain.Skill {
+	name, level := splitLevel(item)
+
+	skill := &domain.Skill{Name: name, Category: category}
+	if proficiency, ok := parseProficiency(level); ok {
+		skill.Proficiency = proficiency
+	} else if level != "" {
+		// Not a level, so the bracketed text is part of the name
+		skill.Name = strings.TrimSpace(item)
+	}
+	skill.BeforeSave()
+	return skill
+}
+
+// languageFromItem parses a spoken language such as "German (C1)" or
+// "Spanish - Native". A missing or unknown level is left empty for the user
+// to fill in.
+func languageFromItem(item string) (*domain.Language, bool) {
+	name, level := splitLevel(item)
+	code, ok := domain.LanguageCodeForName(name)
+	if !ok {
+		return nil, false
+	}
+
+	language := &domain.Language{Code: code}
+	level = strings.ToLower(strings.TrimSpace(level))
+	if m := cefrRegex.FindStringSubmatch(level); m != nil {
+		language.Level = m[1]
+	} else {
+		for _, entry := range languageLevelWords {
+			if strings.Contains(level, entry.word) {
+				language.Level = entry.level
+				break
+			}
+		}
+		if language.Level == "" {
+			if proficiency, ok := parseProficiency(level); ok {
+				language.Level = europass.CEFRFromProficiency(proficiency)
+			}
+		}
+	}
+	language.BeforeSave()
+	return language, true
+}
+
+func parseProficiency(level string) (int, bool) {
+	level = strings.ToLower(strings.TrimSpace(level))
+	if level == "" {
+		return 0, false
+	}
+	if m := fractionRegex.FindStringSubmatch(level); m != nil {
+		value, _ := strconv.Atoi(m[1])
+		scale, _ := strconv.Atoi(m[2])
+		if scale == 10 {
+			value = (value + 1) / 2
+		}
+		if value >= 1 && value <= 5 {
+			return value, true
+		}
+	}
+	if m := cefrRegex.FindStringSubmatch(level); m != nil {
+		return europass.ProficiencyFromCEFR(m[1]), true
+	}
+	for _, entry := range proficiencyWords {
+		if strings.Contains(level, entry.word) {
+			return entry.level, true
+		}
+	}
+	return 0, false
+}
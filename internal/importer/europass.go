@@ -0,0 +1,32 @@
+package importer
+
+import (
+	"cv_builder/internal/domain"
+	"cv_builder/internal/europass"
+	"fmt"
+	"io"
+)
+
+// EuropassXMLImporter reads Europass SkillsPassport XML documents
+type EuropassXMLImporter struct{}
+
+func (i *EuropassXMLImporter) Import(r io.Reader) (*domain.Resume, []domain.ConversionWarning, error) {
+	doc, err := europass.ReadXML(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidDocument, err)
+	}
+	resume, warnings := doc.ToResume()
+	return resume, warnings, nil
+}
+
+// EuropassJSONImporter reads Europass SkillsPassport JSON documents
+type EuropassJSONImporter struct{}
+
+func (i *EuropassJSONImporter) Import(r io.Reader) (*domain.Resume, []domain.ConversionWarning, error) {
+	doc, err := europass.ReadJSON(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidDocument, err)
+	}
+	resume, warnings := doc.ToResume()
+	return resume, warnings, nil
+}
@@ -0,0 +1,52 @@
+package importer
+
+import (
+	"cv_builder/internal/domain"
+	"errors"
+	"io"
+	"sort"
+)
+
+const (
+	FormatEuropassXML  = "europass-xml"
+	FormatEuropassJSON = "europass-json"
+	FormatMarkdown     = "markdown"
+	FormatPDF          = "pdf"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported import format")
+	ErrInvalidDocument   = errors.New("invalid import document")
+)
+
+// Importer parses an external document into a resume aggregate. Data that
+// cannot be carried over is reported as conversion warnings.
+type Importer interface {
+	Import(r io.Reader) (*domain.Resume, []domain.ConversionWarning, error)
+}
+
+var importers = map[string]Importer{
+	FormatEuropassXML:  &EuropassXMLImporter{},
+	FormatEuropassJSON: &EuropassJSONImporter{},
+	FormatMarkdown:     &MarkdownImporter{},
+	FormatPDF:          &PDFImporter{},
+}
+
+// Get returns the importer registered for the given format
+func Get(format string) (Importer, error) {
+	importer, ok := importers[format]
+	if !ok {
+		return nil, ErrUnsupportedFormat
+	}
+	return importer, nil
+}
+
+// Formats lists the names of all registered import formats
+func Formats() []string {
+	formats := make([]string, 0, len(importers))
+	for format := range importers {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
@@ -0,0 +1,255 @@
+package importer
+
+import (
+	"cv_builder/internal/domain"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	headingRegex      = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*$`)
+	setextRegex       = regexp.MustCompile(`^(=+|-+)$`)
+	ruleRegex         = regexp.MustCompile(`^(?:(?:-\s*){3,}|(?:\*\s*){3,}|(?:_\s*){3,})$`)
+	bulletRegex       = regexp.MustCompile(`^(?:[-*+•]|\d+[.)])\s+(.*)$`)
+	boldLineRegex     = regexp.MustCompile(`^(?:\*\*|__)([^*_].*?)(?:\*\*|__)$`)
+	markdownLinkRegex = regexp.MustCompile(`!?\[([^\]]*)\]\(<?([^)\s>]+)>?(?:\s+"[^"]*")?\)`)
+	autoLinkRegex     = regexp.MustCompile(`<((?:https?://|mailto:)[^>\s]+)>`)
+	emphasisRegex     = regexp.MustCompile("\\*\\*|__|~~|`")
+	looseEmphasis     = regexp.MustCompile(`(^|\s)[*_]+|[*_]+(\s|$)`)
+	linkSeparators    = regexp.MustCompile(`[\s|,·•–—-]+`)
+)
+
+// genericLinkLabels are link texts that carry no information beyond the link
+var genericLinkLabels = map[string]bool{
+	"link":        true,
+	"here":        true,
+	"verify":      true,
+	"view":        true,
+	"credential":  true,
+	"certificate": true,
+	"demo":        true,
+	"live":        true,
+	"source":      true,
+	"code":        true,
+	"repo":        true,
+	"repository":  true,
+	"github":      true,
+	"gitlab":      true,
+	"website":     true,
+}
+
+// MarkdownImporter reads resumes written in Markdown with conventional
+// headings: the name as the top-level heading, contact details below it and
+// sections such as "## Experience" holding "### Title — Company" entries
+type MarkdownImporter struct{}
+
+func (i *MarkdownImporter) Import(r io.Reader) (*domain.Resume, []domain.ConversionWarning, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !utf8.Valid(data) {
+		return nil, nil, fmt.Errorf("%w: document is not valid UTF-8", ErrInvalidDocument)
+	}
+
+	doc := parseMarkdown(string(data))
+	if doc.name == "" && len(doc.sections) == 0 {
+		return nil, nil, fmt.Errorf("%w: no Markdown headings found", ErrInvalidDocument)
+	}
+
+	resume, warnings := doc.toResume()
+	return resume, warnings, nil
+}
+
+// parseMarkdown splits a Markdown document into a draft. Headings at or
+// above the level of the first section heading start sections, deeper
+// headings and lines written entirely in bold start entries.
+func parseMarkdown(src string) *draftDocument {
+	doc := &draftDocument{}
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+
+	var section *draftSection
+	var entry *draftEntry
+	sectionLevel := 2
+	inFence := false
+
+	closeEntry := func() {
+		if entry != nil && section != nil {
+			section.entries = append(section.entries, *entry)
+		}
+		entry = nil
+	}
+	closeSection := func() {
+		closeEntry()
+		if section != nil {
+			doc.sections = append(doc.sections, *section)
+		}
+		section = nil
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(line, "```") || strings.HasPrefix(line, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence || line == "" || strings.HasPrefix(line, "<!--") {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimLeft(line, ">"))
+
+		level, title := 0, ""
+		if m := headingRegex.FindStringSubmatch(line); m != nil {
+			level, title = len(m[1]), m[2]
+		} else if i+1 < len(lines) && setextRegex.MatchString(strings.TrimSpace(lines[i+1])) && !bulletRegex.MatchString(line) {
+			level, title = 2, line
+			if strings.HasPrefix(strings.TrimSpace(lines[i+1]), "=") {
+				level = 1
+			}
+			i++
+		} else if ruleRegex.MatchString(line) {
+			continue
+		}
+
+		if level > 0 {
+			title, links, _ := cleanInline(title, false)
+			kind, known := sectionKind(title)
+			switch {
+			case level == 1 && doc.name == "" && section == nil:
+				doc.name = title
+			case known && (section == nil || level <= sectionLevel):
+				closeSection()
+				section = &draftSection{kind: kind, title: title}
+				sectionLevel = level
+			case section == nil && level > 2:
+				doc.header = append(doc.header, title)
+			case section == nil || level <= sectionLevel:
+				closeSection()
+				section = &draftSection{title: title}
+				sectionLevel = level
+			default:
+				closeEntry()
+				entry = &draftEntry{title: title, links: links}
+			}
+			continue
+		}
+
+		if section == nil {
+			if m := bulletRegex.FindStringSubmatch(line); m != nil {
+				line = m[1]
+			}
+			if text, _, _ := cleanInline(line, true); text != "" {
+				doc.header = append(doc.header, text)
+			}
+			continue
+		}
+
+		if m := boldLineRegex.FindStringSubmatch(line); m != nil {
+			title, links, _ := cleanInline(m[1], false)
+			closeEntry()
+			entry = &draftEntry{title: title, links: links}
+			continue
+		}
+
+		bullet := false
+		if m := bulletRegex.FindStringSubmatch(line); m != nil {
+			line, bullet = m[1], true
+		}
+		text, links, onlyLinks := cleanInline(line, false)
+
+		switch {
+		case entry != nil:
+			entry.links = append(entry.links, links...)
+			if onlyLinks {
+				continue
+			}
+			if bullet {
+				entry.bullets = append(entry.bullets, text)
+			} else {
+				entry.lines = append(entry.lines, text)
+			}
+		case onlyLinks && len(section.lines) > 0:
+			last := &section.lines[len(section.lines)-1]
+			last.links = append(last.links, links...)
+		case !onlyLinks:
+			section.lines = append(section.lines, draftLine{text: text, bullet: bullet, links: links})
+		}
+	}
+	closeSection()
+
+	return doc
+}
+
+// cleanInline removes Markdown inline formatting from a line and collects the
+// URLs it links to. Links are replaced by their label, or by their URL when
+// keepURLs is set so that header lines keep the profile addresses. The last
+// result reports whether the line held nothing but links.
+func cleanInline(text string, keepURLs bool) (string, []string, bool) {
+	var links []string
+	addLink := func(link string) {
+		lower := strings.ToLower(link)
+		if strings.HasPrefix(lower, "mailto:") || strings.HasPrefix(lower, "tel:") || strings.HasPrefix(lower, "#") {
+			return
+		}
+		links = append(links, link)
+	}
+
+	var labels strings.Builder
+	text = markdownLinkRegex.ReplaceAllStringFunc(text, func(match string) string {
+		m := markdownLinkRegex.FindStringSubmatch(match)
+		label, link := strings.TrimSpace(m[1]), m[2]
+		addLink(link)
+		labels.WriteString(label + " ")
+		lower := strings.ToLower(link)
+		switch {
+		case !keepURLs && genericLinkLabels[strings.ToLower(label)]:
+			return ""
+		case strings.HasPrefix(lower, "mailto:"):
+			return link[len("mailto:"):]
+		case strings.HasPrefix(lower, "tel:"):
+			return link[len("tel:"):]
+		case keepURLs || label == "":
+			return link
+		default:
+			return label
+		}
+	})
+	text = autoLinkRegex.ReplaceAllStringFunc(text, func(match string) string {
+		link := match[1 : len(match)-1]
+		addLink(link)
+		return strings.TrimPrefix(link, "mailto:")
+	})
+
+	if !keepURLs {
+		for _, link := range urlRegex.FindAllString(text, -1) {
+			if !containsString(links, link) {
+				addLink(link)
+			}
+		}
+	}
+
+	text = emphasisRegex.ReplaceAllString(text, "")
+	text = looseEmphasis.ReplaceAllString(text, "$1$2")
+	text = strings.Join(strings.Fields(text), " ")
+
+	rest := text
+	for _, label := range strings.Fields(labels.String()) {
+		rest = strings.Replace(rest, label, "", 1)
+	}
+	rest = urlRegex.ReplaceAllString(rest, "")
+	onlyLinks := len(links) > 0 && linkSeparators.ReplaceAllString(rest, "") == ""
+
+	return text, links, onlyLinks
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
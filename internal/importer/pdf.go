@@ -0,0 +1,279 @@
+package importer
+
+import (
+	"cv_builder/internal/domain"
+	"cv_builder/pkg/pdf"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var pageNumberRegex = regexp.MustCompile(`(?i)^(?:page\s+)?\d{1,3}(?:\s*(?:/|of)\s*\d{1,3})?$`)
+
+// bulletMarkers are the characters PDF producers draw in front of list items.
+// U+F0B7 and U+F0A7 are the private use code points of the Symbol and
+// Wingdings bullets exported by word processors.
+var bulletMarkers = []string{"•", "●", "▪", "■", "◦", "‣", "∙", "·", "➢", "►", "", "", "-", "–", "*"}
+
+// minExtractedText is the number of letters below which a PDF is assumed to
+// be a scanned image
+const minExtractedText = 40
+
+// PDFImporter reads resumes exported to PDF. The text is extracted and split
+// into sections at headings recognised by their wording, capitalisation or
+// font size; entries are found by their date ranges. The result is a draft
+// meant to be reviewed before it is saved.
+type PDFImporter struct{}
+
+func (i *PDFImporter) Import(r io.Reader) (*domain.Resume, []domain.ConversionWarning, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	doc, err := pdf.Open(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidDocument, err)
+	}
+	pages, err := doc.Text()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidDocument, err)
+	}
+
+	lines := pdfLines(pages)
+	if countLetters(lines) < minExtractedText {
+		return nil, nil, fmt.Errorf("%w: no extractable text found, scanned documents are not supported", ErrInvalidDocument)
+	}
+
+	draft := parsePDFLines(lines)
+	resume, warnings := draft.toResume()
+	return resume, warnings, nil
+}
+
+// pdfLine is an extracted line along with the page it was found on
+type pdfLine struct {
+	pdf.Line
+	page int
+}
+
+// pdfLines flattens the pages into a single list of lines, dropping page
+// numbers and the headers or footers repeated on every page
+func pdfLines(pages []pdf.Page) []pdfLine {
+	repeated := make(map[string]int)
+	for _, page := range pages {
+		seen := make(map[string]bool)
+		for _, line := range page.Lines {
+			if !seen[line.Text] {
+				seen[line.Text] = true
+				repeated[line.Text]++
+			}
+		}
+	}
+
+	var lines []pdfLine
+	for i, page := range pages {
+		for _, line := range page.Lines {
+			text := strings.TrimSpace(line.Text)
+			if text == "" || pageNumberRegex.MatchString(text) {
+				continue
+			}
+			if len(pages) > 2 && repeated[line.Text] == len(pages) {
+				continue
+			}
+			line.Text = text
+			lines = append(lines, pdfLine{Line: line, page: i})
+		}
+	}
+	return lines
+}
+
+func countLetters(lines []pdfLine) int {
+	count := 0
+	for _, line := range lines {
+		for _, r := range line.Text {
+			if unicode.IsLetter(r) {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// bodySize returns the font size used by most of the text
+func bodySize(lines []pdfLine) float64 {
+	counts := make(map[float64]int)
+	for _, line := range lines {
+		counts[roundSize(line.Size)] += utf8.RuneCountInString(line.Text)
+	}
+	sizes := make([]float64, 0, len(counts))
+	for size := range counts {
+		sizes = append(sizes, size)
+	}
+	sort.Float64s(sizes)
+
+	best := 0.0
+	for _, size := range sizes {
+		if counts[size] > counts[best] {
+			best = size
+		}
+	}
+	return best
+}
+
+func roundSize(size float64) float64 {
+	return float64(int(size*2+0.5)) / 2
+}
+
+// parsePDFLines builds a draft from the extracted lines. The largest line
+// above the first section heading is taken as the name and the remaining
+// lines there as contact details.
+func parsePDFLines(lines []pdfLine) *draftDocument {
+	doc := &draftDocument{}
+	body := bodySize(lines)
+
+	first := len(lines)
+	for i, line := range lines {
+		if _, ok := pdfHeading(line, body, i > 0); ok {
+			first = i
+			break
+		}
+	}
+
+	nameIndex := -1
+	for i, line := range lines[:first] {
+		if line.page > 0 || !isNameLine(line.Text) {
+			continue
+		}
+		if nameIndex < 0 || roundSize(line.Size) > roundSize(lines[nameIndex].Size) {
+			nameIndex = i
+		}
+	}
+	for i, line := range lines[:first] {
+		if i == nameIndex {
+			doc.name = line.Text
+			continue
+		}
+		text, _ := stripBullet(line.Text)
+		doc.header = append(doc.header, text)
+	}
+
+	var section *draftSection
+	bulletX := -1.0
+	for _, line := range lines[first:] {
+		if kind, ok := pdfHeading(line, body, true); ok {
+			if section != nil {
+				doc.sections = append(doc.sections, *section)
+			}
+			section = &draftSection{kind: kind, title: headingTitle(line.Text)}
+			bulletX = -1
+			continue
+		}
+
+		text, bullet := stripBullet(line.Text)
+		links := urlRegex.FindAllString(text, -1)
+
+		// Wrapped list items continue on lines indented past the marker
+		if !bullet && bulletX >= 0 && line.X > bulletX+1 && !hasDateRange(text) && len(section.lines) > 0 {
+			last := &section.lines[len(section.lines)-1]
+			last.text = joinWrapped(last.text, text)
+			last.links = append(last.links, links...)
+			continue
+		}
+		bulletX = -1
+		if bullet {
+			bulletX = line.X
+		}
+
+		section.lines = append(section.lines, draftLine{text: text, bullet: bullet, links: links})
+	}
+	if section != nil {
+		doc.sections = append(doc.sections, *section)
+	}
+
+	return doc
+}
+
+// pdfHeading reports whether a line is a section heading: a known section
+// title, or a short line set in capitals or in a larger font than the body.
+// The latter are only considered when generic is set, as the name at the top
+// of a resume looks the same.
+func pdfHeading(line pdfLine, body float64, generic bool) (string, bool) {
+	title := headingTitle(line.Text)
+	if kind, ok := sectionKind(title); ok {
+		return kind, true
+	}
+
+	words := strings.Fields(title)
+	if !generic || len(words) == 0 || len(words) > 4 || hasDateRange(title) || strings.ContainsAny(title, "@,.|") {
+		return "", false
+	}
+	if _, bullet := stripBullet(line.Text); bullet {
+		return "", false
+	}
+	if isUpperCase(title) && utf8.RuneCountInString(title) > 3 {
+		return "", true
+	}
+	return "", body > 0 && roundSize(line.Size) >= body*1.25
+}
+
+// headingTitle removes the decoration some templates put around headings
+func headingTitle(text string) string {
+	return strings.TrimSpace(strings.Trim(text, " :—–-_|•"))
+}
+
+func isUpperCase(text string) bool {
+	letters := 0
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			if !unicode.IsUpper(r) {
+				return false
+			}
+			letters++
+		}
+	}
+	return letters > 0
+}
+
+func isNameLine(text string) bool {
+	words := strings.Fields(text)
+	if len(words) < 1 || len(words) > 5 {
+		return false
+	}
+	return !emailRegex.MatchString(text) && !urlRegex.MatchString(text) && !phoneRegex.MatchString(text) &&
+		!strings.ContainsAny(text, "|@:")
+}
+
+// stripBullet removes a list marker from the start of a line
+func stripBullet(text string) (string, bool) {
+	for _, marker := range bulletMarkers {
+		rest, ok := strings.CutPrefix(text, marker)
+		if !ok {
+			continue
+		}
+		// ASCII markers must be followed by a space to tell them from
+		// hyphenated words or negative numbers
+		if len(marker) == 1 || marker == "–" {
+			if !strings.HasPrefix(rest, " ") {
+				continue
+			}
+		}
+		rest = strings.TrimSpace(rest)
+		if rest == "" {
+			return text, false
+		}
+		return rest, true
+	}
+	return text, false
+}
+
+// joinWrapped appends a wrapped line, rejoining words hyphenated at the break
+func joinWrapped(text, next string) string {
+	if strings.HasSuffix(text, "-") && len(next) > 0 && unicode.IsLower(rune(next[0])) {
+		return strings.TrimSuffix(text, "-") + next
+	}
+	return text + " " + next
+}
@@ -0,0 +1,257 @@
+// Package lint checks the writing style of resume descriptions and
+// achievement bullets. It works offline from word lists kept per locale.
+package lint
+
+import (
+	"cv_builder/internal/domain"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	RuleWeakPhrase            = "weak_phrase"
+	RuleFirstPerson           = "first_person"
+	RulePassiveVoice          = "passive_voice"
+	RuleMissingQuantification = "missing_quantification"
+	RuleTense                 = "tense"
+	RuleLongBullet            = "long_bullet"
+)
+
+const (
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// Finding is a style problem in one text field. Start and End are character
+// (code point) offsets into the field, End exclusive, so editors can
+// underline the span.
+type Finding struct {
+	Field      string `json:"field"`
+	Rule       string `json:"rule"`
+	Severity   string `json:"severity"`
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion"`
+	Start      int    `json:"start"`
+	End        int    `json:"end"`
+	Text       string `json:"text"`
+}
+
+type Linter struct {
+	rules                *ruleSet
+	weakPhrases          []weakPhrase
+	pronouns             map[string]bool
+	passiveAuxiliaries   map[string]bool
+	irregularParticiples map[string]bool
+	pastTenseVerbs       map[string]bool
+	presentTenseVerbs    map[string]bool
+	numberWords          map[string]bool
+}
+
+var wordRegex = regexp.MustCompile(`[\p{L}\p{N}][\p{L}\p{N}'’-]*`)
+
+// word is a word of a text with its byte offsets
+type word struct {
+	text       string
+	start, end int
+}
+
+// item is a text field to lint
+type item struct {
+	field string
+	text  string
+	// bullet is set for achievement bullets, which are expected to be short
+	// and to open with a verb
+	bullet bool
+	// current is set for the text of a current role
+	current bool
+	// inRole is set for experience text, where tense is checked
+	inRole bool
+	// project is set for project descriptions, which should quantify results
+	project bool
+}
+
+// Lint checks the experience descriptions and achievements and the project
+// descriptions of a resume
+func (l *Linter) Lint(resume *domain.Resume) []Finding {
+	var items []item
+	for i, experience := range resume.Experience {
+		current := experience.EndDate.IsZero() || experience.EndDate.IsPresent()
+		items = append(items, item{
+			field:   fmt.Sprintf("experience[%d].description", i),
+			text:    experience.Description,
+			current: current,
+			inRole:  true,
+		})
+		for j, achievement := range experience.Achievements {
+			items = append(items, item{
+				field:   fmt.Sprintf("experience[%d].achievements[%d]", i, j),
+				text:    achievement,
+				bullet:  true,
+				current: current,
+				inRole:  true,
+			})
+		}
+	}
+	for i, project := range resume.Projects {
+		items = append(items, item{
+			field:   fmt.Sprintf("projects[%d].description", i),
+			text:    project.Description,
+			project: true,
+		})
+	}
+
+	findings := []Finding{}
+	for _, it := range items {
+		if strings.TrimSpace(it.text) == "" {
+			continue
+		}
+		findings = append(findings, l.lintItem(it)...)
+	}
+	return findings
+}
+
+func (l *Linter) lintItem(it item) []Finding {
+	var findings []Finding
+	add := func(rule, severity, message, suggestion string, start, end int) {
+		findings = append(findings, Finding{
+			Field:      it.field,
+			Rule:       rule,
+			Severity:   severity,
+			Message:    message,
+			Suggestion: suggestion,
+			Start:      utf8.RuneCountInString(it.text[:start]),
+			End:        utf8.RuneCountInString(it.text[:end]),
+			Text:       it.text[start:end],
+		})
+	}
+
+	var words []word
+	for _, m := range wordRegex.FindAllStringIndex(it.text, -1) {
+		words = append(words, word{text: it.text[m[0]:m[1]], start: m[0], end: m[1]})
+	}
+
+	for _, weak := range l.weakPhrases {
+		for _, m := range weak.pattern.FindAllStringSubmatchIndex(it.text, -1) {
+			phrase := it.text[m[2]:m[3]]
+			add(RuleWeakPhrase, SeverityWarning, l.message("weak_phrase", phrase), weak.suggestion, m[2], m[3])
+		}
+	}
+
+	for _, w := range words {
+		lower := strings.ToLower(w.text)
+		// Upper case words such as "US" are abbreviations, and a lower case
+		// "i" is usually an enumeration or a typo
+		if l.pronouns[lower] && (w.text == "I" || (lower != "i" && !isUpper(w.text))) {
+			add(RuleFirstPerson, SeverityWarning, l.message("first_person", w.text), l.message("first_person_suggestion"), w.start, w.end)
+		}
+	}
+
+	for i := 0; i+1 < len(words); i++ {
+		if !l.passiveAuxiliaries[strings.ToLower(words[i].text)] {
+			continue
+		}
+		// Allow a single adverb such as "was quickly adopted"
+		next := i + 1
+		if strings.HasSuffix(strings.ToLower(words[next].text), "ly") && next+1 < len(words) {
+			next++
+		}
+		if l.isParticiple(words[next].text) {
+			phrase := it.text[words[i].start:words[next].end]
+			add(RulePassiveVoice, SeverityInfo, l.message("passive_voice", phrase), l.message("passive_voice_suggestion"), words[i].start, words[next].end)
+		}
+	}
+
+	if (it.bullet || it.project) && !l.isQuantified(words) {
+		add(RuleMissingQuantification, SeverityInfo, l.message("missing_quantification"), l.message("missing_quantification_suggestion"), 0, len(it.text))
+	}
+
+	if it.inRole && it.bullet && len(words) > 0 {
+		first := words[0]
+		switch {
+		case it.current && l.isPastTense(first.text):
+			add(RuleTense, SeverityWarning, l.message("past_tense_in_current_role", first.text), l.message("past_tense_in_current_role_suggestion"), first.start, first.end)
+		case !it.current && l.isPresentTense(first.text):
+			add(RuleTense, SeverityWarning, l.message("present_tense_in_past_role", first.text), l.message("present_tense_in_past_role_suggestion"), first.start, first.end)
+		}
+	}
+
+	if it.bullet && l.rules.MaxWords > 0 && len(words) > l.rules.MaxWords {
+		add(RuleLongBullet, SeverityWarning, l.message("long_bullet", len(words)), l.message("long_bullet_suggestion", l.rules.MaxWords), 0, len(it.text))
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].Start < findings[j].Start })
+	return findings
+}
+
+func (l *Linter) isParticiple(w string) bool {
+	lower := strings.ToLower(w)
+	if l.irregularParticiples[lower] {
+		return true
+	}
+	return hasSuffix(lower, l.rules.ParticipleSuffixes)
+}
+
+func (l *Linter) isPastTense(w string) bool {
+	lower := strings.ToLower(w)
+	if l.pastTenseVerbs[lower] {
+		return true
+	}
+	return hasSuffix(lower, l.rules.PastTenseSuffixes)
+}
+
+// isPresentTense recognises known verbs in their base, third person or
+// gerund forms, such as "Build", "Builds" and "Building"
+func (l *Linter) isPresentTense(w string) bool {
+	lower := strings.ToLower(w)
+	candidates := []string{lower}
+	if stem, ok := strings.CutSuffix(lower, "ing"); ok {
+		candidates = append(candidates, stem, stem+"e")
+		if n := len(stem); n > 2 && stem[n-1] == stem[n-2] {
+			// Doubled consonants as in "running"
+			candidates = append(candidates, stem[:n-1])
+		}
+	}
+	if stem, ok := strings.CutSuffix(lower, "es"); ok {
+		candidates = append(candidates, stem)
+	}
+	if stem, ok := strings.CutSuffix(lower, "s"); ok {
+		candidates = append(candidates, stem)
+	}
+	for _, candidate := range candidates {
+		if l.presentTenseVerbs[candidate] {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *Linter) isQuantified(words []word) bool {
+	for _, w := range words {
+		if strings.IndexFunc(w.text, unicode.IsDigit) >= 0 || l.numberWords[strings.ToLower(w.text)] {
+			return true
+		}
+	}
+	return false
+}
+
+// hasSuffix reports whether a word of at least four letters ends with one of
+// the suffixes, so short words such as "bed" do not count as inflected
+func hasSuffix(w string, suffixes []string) bool {
+	if utf8.RuneCountInString(w) < 4 {
+		return false
+	}
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(w, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func isUpper(w string) bool {
+	return utf8.RuneCountInString(w) > 1 && strings.ToUpper(w) == w
+}
@@ -0,0 +1,141 @@
+package lint
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Rules for each locale live in locales/<locale>.json; adding a file adds the
+// locale.
+//
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+var ErrUnsupportedLocale = errors.New("unsupported lint locale")
+
+type ruleSet struct {
+	Locale      string `json:"locale"`
+	MaxWords    int    `json:"max_words"`
+	WeakPhrases []struct {
+		Phrase     string `json:"phrase"`
+		Suggestion string `json:"suggestion"`
+	} `json:"weak_phrases"`
+	Pronouns             []string          `json:"pronouns"`
+	PassiveAuxiliaries   []string          `json:"passive_auxiliaries"`
+	ParticipleSuffixes   []string          `json:"participle_suffixes"`
+	IrregularParticiples []string          `json:"irregular_participles"`
+	PastTenseSuffixes    []string          `json:"past_tense_suffixes"`
+	PastTenseVerbs       []string          `json:"past_tense_verbs"`
+	PresentTenseVerbs    []string          `json:"present_tense_verbs"`
+	NumberWords          []string          `json:"number_words"`
+	Messages             map[string]string `json:"messages"`
+}
+
+// weakPhrase is a weak phrase compiled into a case-insensitive, whole-word
+// pattern
+type weakPhrase struct {
+	pattern    *regexp.Regexp
+	suggestion string
+}
+
+var (
+	loadOnce sync.Once
+	linters  map[string]*Linter
+	loadErr  error
+)
+
+// ForLocale returns the linter for a locale such as "en"
+func ForLocale(locale string) (*Linter, error) {
+	loadOnce.Do(loadLocales)
+	if loadErr != nil {
+		return nil, loadErr
+	}
+	linter, ok := linters[strings.ToLower(locale)]
+	if !ok {
+		return nil, ErrUnsupportedLocale
+	}
+	return linter, nil
+}
+
+// Locales lists the locales with a rule file
+func Locales() []string {
+	loadOnce.Do(loadLocales)
+	locales := make([]string, 0, len(linters))
+	for locale := range linters {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+func loadLocales() {
+	linters = make(map[string]*Linter)
+	files, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		loadErr = err
+		return
+	}
+	for _, file := range files {
+		data, err := localeFiles.ReadFile(path.Join("locales", file.Name()))
+		if err != nil {
+			loadErr = err
+			return
+		}
+		var rules ruleSet
+		if err := json.Unmarshal(data, &rules); err != nil {
+			loadErr = fmt.Errorf("failed to parse lint rules %s: %w", file.Name(), err)
+			return
+		}
+		linters[strings.ToLower(rules.Locale)] = newLinter(&rules)
+	}
+}
+
+func newLinter(rules *ruleSet) *Linter {
+	l := &Linter{
+		rules:                rules,
+		pronouns:             wordSet(rules.Pronouns),
+		passiveAuxiliaries:   wordSet(rules.PassiveAuxiliaries),
+		irregularParticiples: wordSet(rules.IrregularParticiples),
+		pastTenseVerbs:       wordSet(rules.PastTenseVerbs),
+		presentTenseVerbs:    wordSet(rules.PresentTenseVerbs),
+		numberWords:          wordSet(rules.NumberWords),
+	}
+	for _, weak := range rules.WeakPhrases {
+		words := strings.Fields(weak.Phrase)
+		for i, word := range words {
+			words[i] = regexp.QuoteMeta(word)
+		}
+		l.weakPhrases = append(l.weakPhrases, weakPhrase{
+			pattern:    regexp.MustCompile(`(?i)(?:^|[^\p{L}\p{N}])(` + strings.Join(words, `\s+`) + `)(?:[^\p{L}\p{N}]|$)`),
+			suggestion: weak.Suggestion,
+		})
+	}
+	return l
+}
+
+func wordSet(words []string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[strings.ToLower(word)] = true
+	}
+	return set
+}
+
+// message formats a localized message, falling back to the message key
+func (l *Linter) message(key string, args ...any) string {
+	format, ok := l.rules.Messages[key]
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
@@ -0,0 +1,99 @@
+package photo
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+const (
+	jpegMarkerSOS  = 0xDA
+	jpegMarkerEOI  = 0xD9
+	jpegMarkerAPP1 = 0xE1
+
+	exifTagOrientation = 0x0112
+	exifTypeShort      = 3
+)
+
+// jpegOrientation returns the EXIF orientation (1-8) of a JPEG file, or 1
+// when the file has no usable orientation tag
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return 1
+		}
+		marker := data[pos+1]
+		switch {
+		case marker == 0xFF:
+			// Fill byte before the marker
+			pos++
+			continue
+		case marker == jpegMarkerSOS || marker == jpegMarkerEOI:
+			// Metadata segments all come before the image data
+			return 1
+		case marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7):
+			// Markers without a payload
+			pos += 2
+			continue
+		}
+
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		if length < 2 || pos+2+length > len(data) {
+			return 1
+		}
+		segment := data[pos+4 : pos+2+length]
+		if marker == jpegMarkerAPP1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[6:])
+		}
+		pos += 2 + length
+	}
+	return 1
+}
+
+// exifOrientation reads the orientation tag from the first IFD of a TIFF
+// structure as embedded in an EXIF segment
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	if order.Uint16(tiff[2:]) != 42 {
+		return 1
+	}
+
+	offset := int64(order.Uint32(tiff[4:]))
+	if offset < 8 || offset+2 > int64(len(tiff)) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[offset:]))
+	entries := tiff[offset+2:]
+	for i := 0; i < count && (i+1)*12 <= len(entries); i++ {
+		entry := entries[i*12 : (i+1)*12]
+		if order.Uint16(entry) != exifTagOrientation {
+			continue
+		}
+		// A single SHORT is stored inline in the value field
+		if order.Uint16(entry[2:]) != exifTypeShort {
+			return 1
+		}
+		orientation := int(order.Uint16(entry[8:]))
+		if orientation < 1 || orientation > 8 {
+			return 1
+		}
+		return orientation
+	}
+	return 1
+}
@@ -0,0 +1,125 @@
+// Package photo validates uploaded profile photos and prepares them for
+// storage: the image is decoded, turned upright according to its EXIF
+// orientation and encoded again, which drops all metadata, and a square
+// thumbnail is generated for the renderers.
+package photo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+const (
+	ContentTypeJPEG = "image/jpeg"
+	ContentTypePNG  = "image/png"
+
+	// ThumbnailSize is the edge length of the square thumbnail. Smaller
+	// uploads are rejected so thumbnails are never upscaled.
+	ThumbnailSize = 256
+	MinDimension  = ThumbnailSize
+	// MaxDimension bounds the decoded size, about 144 MB as RGBA
+	MaxDimension = 6000
+
+	jpegQuality = 90
+)
+
+var (
+	ErrUnsupportedType = errors.New("photo must be a JPEG or PNG image")
+	ErrInvalidImage    = errors.New("photo could not be decoded")
+	ErrDimensions      = errors.New("photo dimensions are out of range")
+)
+
+// Processed is an upload ready to be stored. Both images use ContentType.
+type Processed struct {
+	ContentType string
+	Width       int
+	Height      int
+	Original    []byte
+	Thumbnail   []byte
+}
+
+// Extension returns the file extension for the processed content type
+func (p *Processed) Extension() string {
+	if p.ContentType == ContentTypePNG {
+		return "png"
+	}
+	return "jpg"
+}
+
+// Process validates an uploaded photo and re-encodes it without metadata.
+// The type is detected from the content, the declared type is ignored.
+func Process(data []byte) (*Processed, error) {
+	contentType := http.DetectContentType(data)
+	if contentType != ContentTypeJPEG && contentType != ContentTypePNG {
+		return nil, ErrUnsupportedType
+	}
+
+	// Check the dimensions from the header before decoding the pixels
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	if "image/"+format != contentType {
+		return nil, ErrInvalidImage
+	}
+	if config.Width < MinDimension || config.Height < MinDimension ||
+		config.Width > MaxDimension || config.Height > MaxDimension {
+		return nil, fmt.Errorf("%w: %dx%d, must be between %dx%d and %dx%d pixels", ErrDimensions,
+			config.Width, config.Height, MinDimension, MinDimension, MaxDimension, MaxDimension)
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	img := toRGBA(decoded)
+	if contentType == ContentTypeJPEG {
+		img = orient(img, jpegOrientation(data))
+	}
+
+	original, err := encode(img, contentType)
+	if err != nil {
+		return nil, err
+	}
+	thumbnail, err := encode(resize(cropSquare(img), ThumbnailSize), contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := img.Bounds()
+	return &Processed{
+		ContentType: contentType,
+		Width:       bounds.Dx(),
+		Height:      bounds.Dy(),
+		Original:    original,
+		Thumbnail:   thumbnail,
+	}, nil
+}
+
+func toRGBA(src image.Image) *image.RGBA {
+	bounds := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Src)
+	return dst
+}
+
+func encode(img image.Image, contentType string) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	if contentType == ContentTypePNG {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,98 @@
+package photo
+
+import (
+	"image"
+)
+
+// orient turns the image upright according to its EXIF orientation
+func orient(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation <= 1 || orientation > 8 {
+		return src
+	}
+
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		// Orientations 5-8 swap width and height
+		dw, dh = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2: // mirrored horizontally
+				sx, sy = w-1-x, y
+			case 3: // rotated 180°
+				sx, sy = w-1-x, h-1-y
+			case 4: // mirrored vertically
+				sx, sy = x, h-1-y
+			case 5: // transposed
+				sx, sy = y, x
+			case 6: // needs a 90° clockwise rotation
+				sx, sy = y, h-1-x
+			case 7: // transversed
+				sx, sy = w-1-y, h-1-x
+			case 8: // needs a 90° counter-clockwise rotation
+				sx, sy = w-1-y, x
+			}
+			s := sy*src.Stride + sx*4
+			d := y*dst.Stride + x*4
+			copy(dst.Pix[d:d+4], src.Pix[s:s+4])
+		}
+	}
+	return dst
+}
+
+// cropSquare cuts the largest centered square out of the image
+func cropSquare(src *image.RGBA) *image.RGBA {
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	side := min(w, h)
+	x0, y0 := (w-side)/2, (h-side)/2
+	return toRGBA(src.SubImage(image.Rect(x0, y0, x0+side, y0+side)))
+}
+
+// resize scales a square image down to size×size by averaging the source
+// pixels that fall into each target pixel
+func resize(src *image.RGBA, size int) *image.RGBA {
+	sw, sh := src.Bounds().Dx(), src.Bounds().Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+
+	for y := 0; y < size; y++ {
+		y0, y1 := span(y, sh, size)
+		for x := 0; x < size; x++ {
+			x0, x1 := span(x, sw, size)
+
+			var r, g, b, a, n int
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					i := sx * 4
+					r += int(row[i])
+					g += int(row[i+1])
+					b += int(row[i+2])
+					a += int(row[i+3])
+					n++
+				}
+			}
+
+			d := y*dst.Stride + x*4
+			dst.Pix[d] = uint8(r / n)
+			dst.Pix[d+1] = uint8(g / n)
+			dst.Pix[d+2] = uint8(b / n)
+			dst.Pix[d+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// span returns the source pixel range covered by target pixel i
+func span(i, source, target int) (int, int) {
+	from := i * source / target
+	to := (i + 1) * source / target
+	if to <= from {
+		to = from + 1
+	}
+	return from, to
+}
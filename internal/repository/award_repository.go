@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"cv_builder/internal/domain"
+	"database/sql"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+type awardRow struct {
+	ID          uuid.UUID      `db:"id"`
+	Title       string         `db:"title"`
+	Awarder     sql.NullString `db:"awarder"`
+	AwardDate   time.Time      `db:"award_date"`
+	Description sql.NullString `db:"description"`
+	URL         sql.NullString `db:"url"`
+}
+
+func (row awardRow) toDomain() *domain.Award {
+	return &domain.Award{
+		ID:          row.ID,
+		Title:       row.Title,
+		Awarder:     row.Awarder.String,
+		Date:        row.AwardDate.Format("2006-01-02"),
+		Description: row.Description.String,
+		URL:         row.URL.String,
+	}
+}
+
+func (r *PostgresCVRepository) AddAward(ctx context.Context, resumeId uuid.UUID, award *domain.Award) (uuid.UUID, error) {
+	query := `
+		INSERT INTO awards (
+			id, resume_id, title, awarder, award_date, description, url, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`
+
+	award.BeforeSave()
+
+	if err := award.Validate(); err != nil {
+		return uuid.Nil, err
+	}
+
+	awardDate, err := time.Parse("2006-01-02", award.Date)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	id := uuid.New()
+	now := time.Now()
+
+	var returnedID uuid.UUID
+	err = r.db.QueryRowContext(
+		ctx,
+		query,
+		id,
+		resumeId,
+		award.Title,
+		sql.NullString{String: award.Awarder, Valid: award.Awarder != ""},
+		awardDate,
+		sql.NullString{String: award.Description, Valid: award.Description != ""},
+		sql.NullString{String: award.URL, Valid: award.URL != ""},
+		now,
+		now,
+	).Scan(&returnedID)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to add award")
+		return uuid.Nil, err
+	}
+
+	award.ID = returnedID
+	return returnedID, nil
+}
+
+func (r *PostgresCVRepository) UpdateAward(ctx context.Context, resumeId, id uuid.UUID, award *domain.Award) error {
+	query := `
+		UPDATE awards
+		SET title = $1,
+			awarder = $2,
+			award_date = $3,
+			description = $4,
+			url = $5,
+			updated_at = $6
+		WHERE id = $7 AND resume_id = $8
+	`
+
+	award.BeforeSave()
+
+	if err := award.Validate(); err != nil {
+		return err
+	}
+
+	awardDate, err := time.Parse("2006-01-02", award.Date)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(
+		ctx,
+		query,
+		award.Title,
+		sql.NullString{String: award.Awarder, Valid: award.Awarder != ""},
+		awardDate,
+		sql.NullString{String: award.Description, Valid: award.Description != ""},
+		sql.NullString{String: award.URL, Valid: award.URL != ""},
+		time.Now(),
+		id,
+		resumeId,
+	)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to update award")
+		return err
+	}
+
+	return requireRowsAffected(result)
+}
+
+func (r *PostgresCVRepository) DeleteAward(ctx context.Context, resumeId, id uuid.UUID) error {
+	query := `
+		DELETE FROM awards
+		WHERE id = $1 AND resume_id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, resumeId)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to delete award")
+		return err
+	}
+
+	return requireRowsAffected(result)
+}
+
+// GetAwardsByResume lists the awards of a resume, most recent first
+func (r *PostgresCVRepository) GetAwardsByResume(ctx context.Context, resumeId uuid.UUID) ([]*domain.Award, error) {
+	query := `
+		SELECT id, title, awarder, award_date, description, url
+		FROM awards
+		WHERE resume_id = $1
+		ORDER BY award_date DESC
+	`
+
+	var rows []awardRow
+	err := r.db.SelectContext(ctx, &rows, query, resumeId)
+	if err != nil {
+		log.Error().Err(err).Str("resume_id", resumeId.String()).Msg("failed to get awards by resume")
+		return nil, err
+	}
+
+	awards := make([]*domain.Award, len(rows))
+	for i, row := range rows {
+		awards[i] = row.toDomain()
+	}
+	return awards, nil
+}
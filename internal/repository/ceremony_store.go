@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+// RedisCeremonyStore keeps the state of WebAuthn ceremonies between their
+// begin and finish requests
+type RedisCeremonyStore struct {
+	redis *redis.Client
+}
+
+func NewRedisCeremonyStore(redisClient *redis.Client) *RedisCeremonyStore {
+	return &RedisCeremonyStore{
+		redis: redisClient,
+	}
+}
+
+func (s *RedisCeremonyStore) SaveCeremony(ctx context.Context, id string, session *webauthn.SessionData, ttl time.Duration) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	if err := s.redis.Set(ctx, ceremonyKey(id), data, ttl).Err(); err != nil {
+		log.Error().Err(err).Msg("failed to save webauthn ceremony")
+		return err
+	}
+	return nil
+}
+
+// TakeCeremony returns and removes the ceremony, so each challenge can only
+// be answered once
+func (s *RedisCeremonyStore) TakeCeremony(ctx context.Context, id string) (*webauthn.SessionData, error) {
+	data, err := s.redis.GetDel(ctx, ceremonyKey(id)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrNotFound
+		}
+		log.Error().Err(err).Msg("failed to get webauthn ceremony")
+		return nil, err
+	}
+
+	var session webauthn.SessionData
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func ceremonyKey(id string) string {
+	return "webauthn:ceremony:" + id
+}
@@ -0,0 +1,465 @@
+package repository
+
+import (
+	"context"
+	"cv_builder/internal/domain"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+type customEntryRow struct {
+	ID        uuid.UUID      `db:"id"`
+	SectionID uuid.UUID      `db:"section_id"`
+	Title     string         `db:"title"`
+	Subtitle  sql.NullString `db:"subtitle"`
+	StartDate *time.Time     `db:"start_date"`
+	EndDate   *time.Time     `db:"end_date"`
+	IsCurrent bool           `db:"is_current"`
+	URL       sql.NullString `db:"url"`
+	Bullets   pq.StringArray `db:"bullets"`
+	Position  int            `db:"position"`
+}
+
+func (row customEntryRow) toDomain() *domain.CustomSectionEntry {
+	entry := &domain.CustomSectionEntry{
+		ID:       row.ID,
+		Title:    row.Title,
+		Subtitle: row.Subtitle.String,
+		URL:      row.URL.String,
+		Bullets:  []string(row.Bullets),
+		Position: row.Position,
+	}
+	if row.StartDate != nil {
+		entry.StartDate = row.StartDate.Format("2006-01-02")
+	}
+	if row.IsCurrent {
+		entry.EndDate = "Present"
+	} else if row.EndDate != nil {
+		entry.EndDate = row.EndDate.Format("2006-01-02")
+	}
+	return entry
+}
+
+// customEntryDates converts the entry dates into column values; "Present" is
+// stored as a missing end date with is_current set
+func customEntryDates(entry *domain.CustomSectionEntry) (*time.Time, *time.Time, bool, error) {
+	var startDate, endDate *time.Time
+	if entry.StartDate != "" {
+		parsed, err := time.Parse("2006-01-02", entry.StartDate)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		startDate = &parsed
+	}
+	if entry.EndDate != "" && entry.EndDate != "Present" {
+		parsed, err := time.Parse("2006-01-02", entry.EndDate)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		endDate = &parsed
+	}
+	return startDate, endDate, entry.EndDate == "Present", nil
+}
+
+// AddCustomSection adds a section after the existing ones, together with its
+// entries
+func (r *PostgresCVRepository) AddCustomSection(ctx context.Context, resumeId uuid.UUID, section *domain.CustomSection) (uuid.UUID, error) {
+	query := `
+		INSERT INTO custom_sections (id, resume_id, title, position, created_at, updated_at)
+		VALUES ($1, $2, $3, (SELECT COALESCE(MAX(position) + 1, 0) FROM custom_sections WHERE resume_id = $2), $4, $5)
+		RETURNING id, position
+	`
+
+	section.BeforeSave()
+
+	if err := section.Validate(); err != nil {
+		return uuid.Nil, err
+	}
+
+	id := uuid.New()
+	now := time.Now()
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to begin transaction")
+		return uuid.Nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	err = tx.QueryRowContext(ctx, query, id, resumeId, section.Title, now, now).Scan(&section.ID, &section.Position)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to add custom section")
+		return uuid.Nil, err
+	}
+
+	for i, entry := range section.Entries {
+		entry.Position = i
+		if entry.ID, err = r.insertCustomSectionEntry(ctx, tx, section.ID, entry); err != nil {
+			log.Error().Err(err).Msg("failed to add custom section entry")
+			return uuid.Nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Msg("failed to commit transaction")
+		return uuid.Nil, err
+	}
+
+	return section.ID, nil
+}
+
+// UpdateCustomSection renames a section; its entries are managed separately
+func (r *PostgresCVRepository) UpdateCustomSection(ctx context.Context, resumeId, id uuid.UUID, section *domain.CustomSection) error {
+	query := `
+		UPDATE custom_sections
+		SET title = $1,
+			updated_at = $2
+		WHERE id = $3 AND resume_id = $4
+	`
+
+	section.BeforeSave()
+
+	if err := section.Validate(); err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(ctx, query, section.Title, time.Now(), id, resumeId)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to update custom section")
+		return err
+	}
+
+	return requireRowsAffected(result)
+}
+
+func (r *PostgresCVRepository) DeleteCustomSection(ctx context.Context, resumeId, id uuid.UUID) error {
+	query := `
+		DELETE FROM custom_sections
+		WHERE id = $1 AND resume_id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, resumeId)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to delete custom section")
+		return err
+	}
+
+	return requireRowsAffected(result)
+}
+
+func (r *PostgresCVRepository) GetCustomSection(ctx context.Context, resumeId, id uuid.UUID) (*domain.CustomSection, error) {
+	query := `
+		SELECT id, title, position
+		FROM custom_sections
+		WHERE id = $1 AND resume_id = $2
+	`
+
+	var section domain.CustomSection
+	err := r.db.QueryRowContext(ctx, query, id, resumeId).Scan(&section.ID, &section.Title, &section.Position)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		log.Error().Err(err).Str("section_id", id.String()).Msg("failed to get custom section")
+		return nil, err
+	}
+
+	entriesQuery := `
+		SELECT id, section_id, title, subtitle, start_date, end_date, is_current, url, bullets, position
+		FROM custom_section_entries
+		WHERE section_id = $1
+		ORDER BY position, created_at
+	`
+
+	var rows []customEntryRow
+	if err := r.db.SelectContext(ctx, &rows, entriesQuery, id); err != nil {
+		log.Error().Err(err).Str("section_id", id.String()).Msg("failed to get custom section entries")
+		return nil, err
+	}
+
+	section.Entries = make([]*domain.CustomSectionEntry, len(rows))
+	for i, row := range rows {
+		section.Entries[i] = row.toDomain()
+	}
+
+	return &section, nil
+}
+
+func (r *PostgresCVRepository) GetCustomSectionsByResume(ctx context.Context, resumeId uuid.UUID) ([]*domain.CustomSection, error) {
+	query := `
+		SELECT id, title, position
+		FROM custom_sections
+		WHERE resume_id = $1
+		ORDER BY position, created_at
+	`
+
+	type sectionRow struct {
+		ID       uuid.UUID `db:"id"`
+		Title    string    `db:"title"`
+		Position int       `db:"position"`
+	}
+
+	var sectionRows []sectionRow
+	if err := r.db.SelectContext(ctx, &sectionRows, query, resumeId); err != nil {
+		log.Error().Err(err).Str("resume_id", resumeId.String()).Msg("failed to get custom sections by resume")
+		return nil, err
+	}
+
+	sections := make([]*domain.CustomSection, len(sectionRows))
+	byId := make(map[uuid.UUID]*domain.CustomSection, len(sectionRows))
+	for i, row := range sectionRows {
+		sections[i] = &domain.CustomSection{
+			ID:       row.ID,
+			Title:    row.Title,
+			Position: row.Position,
+			Entries:  []*domain.CustomSectionEntry{},
+		}
+		byId[row.ID] = sections[i]
+	}
+	if len(sections) == 0 {
+		return sections, nil
+	}
+
+	entriesQuery := `
+		SELECT e.id, e.section_id, e.title, e.subtitle, e.start_date, e.end_date, e.is_current, e.url, e.bullets, e.position
+		FROM custom_section_entries e
+		JOIN custom_sections s ON s.id = e.section_id
+		WHERE s.resume_id = $1
+		ORDER BY e.position, e.created_at
+	`
+
+	var entryRows []customEntryRow
+	if err := r.db.SelectContext(ctx, &entryRows, entriesQuery, resumeId); err != nil {
+		log.Error().Err(err).Str("resume_id", resumeId.String()).Msg("failed to get custom section entries by resume")
+		return nil, err
+	}
+
+	for _, row := range entryRows {
+		if section, ok := byId[row.SectionID]; ok {
+			section.Entries = append(section.Entries, row.toDomain())
+		}
+	}
+
+	return sections, nil
+}
+
+// ReorderCustomSections sets the order of the sections of a resume; ids must
+// list every section exactly once
+func (r *PostgresCVRepository) ReorderCustomSections(ctx context.Context, resumeId uuid.UUID, ids []uuid.UUID) error {
+	return r.reorder(ctx, "custom_sections", "resume_id", resumeId, ids)
+}
+
+// AddCustomSectionEntry adds an entry after the existing entries of a section
+func (r *PostgresCVRepository) AddCustomSectionEntry(ctx context.Context, sectionId uuid.UUID, entry *domain.CustomSectionEntry) (uuid.UUID, error) {
+	entry.BeforeSave()
+
+	if err := entry.Validate(); err != nil {
+		return uuid.Nil, err
+	}
+
+	var position int
+	err := r.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(position) + 1, 0) FROM custom_section_entries WHERE section_id = $1`, sectionId).Scan(&position)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get next custom section entry position")
+		return uuid.Nil, err
+	}
+	entry.Position = position
+
+	id, err := r.insertCustomSectionEntry(ctx, r.db, sectionId, entry)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to add custom section entry")
+		return uuid.Nil, err
+	}
+	entry.ID = id
+
+	return id, nil
+}
+
+func (r *PostgresCVRepository) insertCustomSectionEntry(ctx context.Context, db sqlx.QueryerContext, sectionId uuid.UUID, entry *domain.CustomSectionEntry) (uuid.UUID, error) {
+	query := `
+		INSERT INTO custom_section_entries (
+			id, section_id, title, subtitle, start_date, end_date,
+			is_current, url, bullets, position, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id
+	`
+
+	startDate, endDate, isCurrent, err := customEntryDates(entry)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	now := time.Now()
+	var returnedId uuid.UUID
+	err = db.QueryRowxContext(
+		ctx,
+		query,
+		uuid.New(),
+		sectionId,
+		entry.Title,
+		entry.Subtitle,
+		startDate,
+		endDate,
+		isCurrent,
+		entry.URL,
+		pq.StringArray(entry.Bullets),
+		entry.Position,
+		now,
+		now,
+	).Scan(&returnedId)
+	return returnedId, err
+}
+
+func (r *PostgresCVRepository) UpdateCustomSectionEntry(ctx context.Context, sectionId, id uuid.UUID, entry *domain.CustomSectionEntry) error {
+	query := `
+		UPDATE custom_section_entries
+		SET title = $1,
+			subtitle = $2,
+			start_date = $3,
+			end_date = $4,
+			is_current = $5,
+			url = $6,
+			bullets = $7,
+			updated_at = $8
+		WHERE id = $9 AND section_id = $10
+	`
+
+	entry.BeforeSave()
+
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+
+	startDate, endDate, isCurrent, err := customEntryDates(entry)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(
+		ctx,
+		query,
+		entry.Title,
+		entry.Subtitle,
+		startDate,
+		endDate,
+		isCurrent,
+		entry.URL,
+		pq.StringArray(entry.Bullets),
+		time.Now(),
+		id,
+		sectionId,
+	)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to update custom section entry")
+		return err
+	}
+
+	return requireRowsAffected(result)
+}
+
+func (r *PostgresCVRepository) DeleteCustomSectionEntry(ctx context.Context, sectionId, id uuid.UUID) error {
+	query := `
+		DELETE FROM custom_section_entries
+		WHERE id = $1 AND section_id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, sectionId)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to delete custom section entry")
+		return err
+	}
+
+	return requireRowsAffected(result)
+}
+
+// ReorderCustomSectionEntries sets the order of the entries of a section;
+// ids must list every entry exactly once
+func (r *PostgresCVRepository) ReorderCustomSectionEntries(ctx context.Context, sectionId uuid.UUID, ids []uuid.UUID) error {
+	return r.reorder(ctx, "custom_section_entries", "section_id", sectionId, ids)
+}
+
+// reorder rewrites the position column of the rows belonging to a parent.
+// The table and column names are constants supplied by the callers above.
+func (r *PostgresCVRepository) reorder(ctx context.Context, table, parentColumn string, parentId uuid.UUID, ids []uuid.UUID) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to begin transaction")
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var existing []uuid.UUID
+	err = tx.SelectContext(ctx, &existing, `SELECT id FROM `+table+` WHERE `+parentColumn+` = $1 FOR UPDATE`, parentId)
+	if err != nil {
+		log.Error().Err(err).Str("table", table).Msg("failed to lock rows for reordering")
+		return err
+	}
+
+	if !sameIds(existing, ids) {
+		err = domain.NewValidationError("ids", "The order must list every item exactly once", domain.ErrInvalidField)
+		return err
+	}
+
+	now := time.Now()
+	for position, id := range ids {
+		_, err = tx.ExecContext(ctx, `UPDATE `+table+` SET position = $1, updated_at = $2 WHERE id = $3`, position, now, id)
+		if err != nil {
+			log.Error().Err(err).Str("table", table).Msg("failed to update position")
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Msg("failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
+
+func sameIds(existing, ids []uuid.UUID) bool {
+	if len(existing) != len(ids) {
+		return false
+	}
+	seen := make(map[uuid.UUID]bool, len(existing))
+	for _, id := range existing {
+		seen[id] = true
+	}
+	for _, id := range ids {
+		if !seen[id] {
+			return false
+		}
+		delete(seen, id)
+	}
+	return true
+}
+
+func requireRowsAffected(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get rows affected")
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -28,8 +28,8 @@ func NewPostgresCVRepository(db *sqlx.DB) *PostgresCVRepository {
 
 func (r *PostgresCVRepository) CreateCV(ctx context.Context, userId uuid.UUID) (*domain.Resume, error) {
 	query := `
-		INSERT INTO resumes (id, user_id, created_at)
-		VALUES ($1, $2, $3)
+		INSERT INTO resumes (id, user_id, created_at, default_locale)
+		VALUES ($1, $2, $3, $4)
 		RETURNING id
 	`
 
@@ -38,7 +38,7 @@ func (r *PostgresCVRepository) CreateCV(ctx context.Context, userId uuid.UUID) (
 
 	var id uuid.UUID
 
-	err := r.db.QueryRowContext(ctx, query, resumeID, userId, now).Scan(&id)
+	err := r.db.QueryRowContext(ctx, query, resumeID, userId, now, domain.DefaultLocale).Scan(&id)
 
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to create resume")
@@ -46,9 +46,10 @@ func (r *PostgresCVRepository) CreateCV(ctx context.Context, userId uuid.UUID) (
 	}
 
 	resume := &domain.Resume{
-		ID:        resumeID,
-		UserID:    userId,
-		CreatedAt: now,
+		ID:            resumeID,
+		UserID:        userId,
+		CreatedAt:     now,
+		DefaultLocale: domain.DefaultLocale,
 	}
 
 	return resume, nil
@@ -56,7 +57,7 @@ func (r *PostgresCVRepository) CreateCV(ctx context.Context, userId uuid.UUID) (
 
 func (r *PostgresCVRepository) GetCVById(ctx context.Context, id uuid.UUID) (*domain.Resume, error) {
 	query := `
-		SELECT id, user_id, created_at
+		SELECT id, user_id, created_at, default_locale
 		FROM resumes
 		WHERE id = $1
 	`
@@ -76,7 +77,7 @@ func (r *PostgresCVRepository) GetCVById(ctx context.Context, id uuid.UUID) (*do
 
 func (r *PostgresCVRepository) GetCVByUserId(ctx context.Context, userId uuid.UUID) ([]*domain.Resume, error) {
 	query := `
-		SELECT id, user_id, created_at
+		SELECT id, user_id, created_at, default_locale
 		FROM resumes
 		WHERE user_id = $1
 		ORDER BY created_at DESC
@@ -117,13 +118,15 @@ func (r *PostgresCVRepository) DeleteCV(ctx context.Context, id uuid.UUID) error
 	return nil
 }
 
+// SavePersonalInfo creates or replaces the personal info of a resume,
+// including its profile links
 func (r *PostgresCVRepository) SavePersonalInfo(ctx context.Context, resumeID uuid.UUID, info *domain.PersonalInfo) error {
 	query := `
 		INSERT INTO personal_info (
 			id, resume_id, first_name, last_name, email, phone, 
-			street, city, country, job_title, created_at, updated_at
+			street, city, country, job_title, summary, created_at, updated_at
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 		ON CONFLICT (resume_id) DO UPDATE SET
 			first_name = $3,
 			last_name = $4,
@@ -133,7 +136,8 @@ func (r *PostgresCVRepository) SavePersonalInfo(ctx context.Context, resumeID uu
 			city = $8,
 			country = $9,
 			job_title = $10,
-			updated_at = $12
+			summary = $11,
+			updated_at = $13
 		RETURNING id
 	`
 
@@ -143,8 +147,20 @@ func (r *PostgresCVRepository) SavePersonalInfo(ctx context.Context, resumeID uu
 	id := uuid.New()
 	now := time.Now()
 
-	var returnedID uuid.UUID
-	err := r.db.QueryRowContext(
+	tx, err := r.db.Beginx()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to begin transaction")
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var personalInfoID uuid.UUID
+	err = tx.QueryRowContext(
 		ctx,
 		query,
 		id,
@@ -157,33 +173,70 @@ func (r *PostgresCVRepository) SavePersonalInfo(ctx context.Context, resumeID uu
 		info.Address.City,
 		info.Address.Country,
 		info.JobTitle,
+		sql.NullString{String: info.Summary, Valid: info.Summary != ""},
 		now,
 		now,
-	).Scan(&returnedID)
+	).Scan(&personalInfoID)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to save personal info")
 		return err
 	}
 
+	_, err = tx.ExecContext(ctx, `DELETE FROM personal_info_profiles WHERE personal_info_id = $1`, personalInfoID)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to delete profiles")
+		return err
+	}
+
+	profileQuery := `
+		INSERT INTO personal_info_profiles (id, personal_info_id, network, username, url, position, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	for position, profile := range info.Profiles {
+		_, err = tx.ExecContext(
+			ctx,
+			profileQuery,
+			uuid.New(),
+			personalInfoID,
+			profile.Network,
+			sql.NullString{String: profile.Username, Valid: profile.Username != ""},
+			profile.URL,
+			position,
+			now,
+		)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to add profile")
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Msg("failed to commit transaction")
+		return err
+	}
+
 	return nil
 }
 
 func (r *PostgresCVRepository) GetPersonalInfo(ctx context.Context, resumeId uuid.UUID) (*domain.PersonalInfo, error) {
 	query := `
-		SELECT first_name, last_name, email, phone, street, city, country, job_title
+		SELECT id, first_name, last_name, email, phone, street, city, country, job_title, summary
 		FROM personal_info
 		WHERE resume_id = $1
 	`
 
 	var info struct {
-		FirstName string `db:"first_name"`
-		LastName  string `db:"last_name"`
-		Email     string `db:"email"`
-		Phone     string `db:"phone"`
-		Street    string `db:"street"`
-		City      string `db:"city"`
-		Country   string `db:"country"`
-		JobTitle  string `db:"job_title"`
+		ID        uuid.UUID      `db:"id"`
+		FirstName string         `db:"first_name"`
+		LastName  string         `db:"last_name"`
+		Email     string         `db:"email"`
+		Phone     string         `db:"phone"`
+		Street    string         `db:"street"`
+		City      string         `db:"city"`
+		Country   string         `db:"country"`
+		JobTitle  string         `db:"job_title"`
+		Summary   sql.NullString `db:"summary"`
 	}
 
 	err := r.db.GetContext(ctx, &info, query, resumeId)
@@ -201,12 +254,40 @@ func (r *PostgresCVRepository) GetPersonalInfo(ctx context.Context, resumeId uui
 		Email:     info.Email,
 		Phone:     info.Phone,
 		JobTitle:  info.JobTitle,
+		Summary:   info.Summary.String,
 	}
 
 	result.Address.Street = info.Street
 	result.Address.City = info.City
 	result.Address.Country = info.Country
 
+	profileQuery := `
+		SELECT network, username, url
+		FROM personal_info_profiles
+		WHERE personal_info_id = $1
+		ORDER BY position
+	`
+
+	var profiles []struct {
+		Network  string         `db:"network"`
+		Username sql.NullString `db:"username"`
+		URL      string         `db:"url"`
+	}
+
+	err = r.db.SelectContext(ctx, &profiles, profileQuery, info.ID)
+	if err != nil {
+		log.Error().Err(err).Str("resume_id", resumeId.String()).Msg("failed to get profiles")
+		return nil, err
+	}
+
+	for _, profile := range profiles {
+		result.Profiles = append(result.Profiles, &domain.Profile{
+			Network:  profile.Network,
+			Username: profile.Username.String,
+			URL:      profile.URL,
+		})
+	}
+
 	return result, nil
 }
 
@@ -228,25 +309,6 @@ func (r *PostgresCVRepository) AddEducation(ctx context.Context, resumeId uuid.U
 	id := uuid.New()
 	now := time.Now()
 
-	var startDate *time.Time
-	var endDate *time.Time
-
-	if education.StartDate != "" {
-		parsedStartDate, err := time.Parse("2006-01-02", education.StartDate)
-		if err != nil {
-			return uuid.Nil, err
-		}
-		startDate = &parsedStartDate
-	}
-
-	if education.EndDate != "" && education.EndDate != "Present" {
-		parsedEndDate, err := time.Parse("2006-01-02", education.EndDate)
-		if err != nil {
-			return uuid.Nil, err
-		}
-		endDate = &parsedEndDate
-	}
-
 	var returnedId uuid.UUID
 	err := r.db.QueryRowContext(
 		ctx,
@@ -257,8 +319,8 @@ func (r *PostgresCVRepository) AddEducation(ctx context.Context, resumeId uuid.U
 		education.Location,
 		education.Degree,
 		education.Field,
-		startDate,
-		endDate,
+		education.StartDate,
+		education.EndDate,
 		education.Description,
 		now,
 		now,
@@ -293,25 +355,6 @@ func (r *PostgresCVRepository) UpdateEducation(ctx context.Context, id uuid.UUID
 
 	now := time.Now()
 
-	var startDate *time.Time
-	var endDate *time.Time
-
-	if education.StartDate != "" {
-		parsedStartDate, err := time.Parse("2006-01-02", education.StartDate)
-		if err != nil {
-			return err
-		}
-		startDate = &parsedStartDate
-	}
-
-	if education.EndDate != "" && education.EndDate != "Present" {
-		parsedEndDate, err := time.Parse("2006-01-02", education.EndDate)
-		if err != nil {
-			return err
-		}
-		endDate = &parsedEndDate
-	}
-
 	result, err := r.db.ExecContext(
 		ctx,
 		query,
@@ -319,8 +362,8 @@ func (r *PostgresCVRepository) UpdateEducation(ctx context.Context, id uuid.UUID
 		education.Location,
 		education.Degree,
 		education.Field,
-		startDate,
-		endDate,
+		education.StartDate,
+		education.EndDate,
 		education.Description,
 		now,
 		id)
@@ -377,13 +420,13 @@ func (r *PostgresCVRepository) GetEducation(ctx context.Context, id uuid.UUID) (
 	`
 
 	var edu struct {
-		Institution string     `db:"institution"`
-		Location    string     `db:"location"`
-		Degree      string     `db:"degree"`
-		Field       string     `db:"field"`
-		StartDate   time.Time  `db:"start_date"`
-		EndDate     *time.Time `db:"end_date"`
-		Description string     `db:"description"`
+		Institution string             `db:"institution"`
+		Location    string             `db:"location"`
+		Degree      string             `db:"degree"`
+		Field       string             `db:"field"`
+		StartDate   domain.PartialDate `db:"start_date"`
+		EndDate     domain.PartialDate `db:"end_date"`
+		Description string             `db:"description"`
 	}
 
 	err := r.db.GetContext(ctx, &edu, query, id)
@@ -395,22 +438,14 @@ func (r *PostgresCVRepository) GetEducation(ctx context.Context, id uuid.UUID) (
 		return nil, err
 	}
 
-	// Format dates
-	startDate := edu.StartDate.Format("2006-01-02")
-	var endDate string
-	if edu.EndDate != nil {
-		endDate = edu.EndDate.Format("2006-01-02")
-	} else {
-		endDate = "Present"
-	}
-
 	education := &domain.Education{
+		ID:          id,
 		Institution: edu.Institution,
 		Location:    edu.Location,
 		Degree:      edu.Degree,
 		Field:       edu.Field,
-		StartDate:   startDate,
-		EndDate:     endDate,
+		StartDate:   edu.StartDate,
+		EndDate:     edu.EndDate,
 		Description: edu.Description,
 	}
 
@@ -427,14 +462,14 @@ func (r *PostgresCVRepository) GetEducationByResume(ctx context.Context, resumeI
 	`
 
 	type educationRow struct {
-		ID          uuid.UUID  `db:"id"`
-		Institution string     `db:"institution"`
-		Location    string     `db:"location"`
-		Degree      string     `db:"degree"`
-		Field       string     `db:"field"`
-		StartDate   time.Time  `db:"start_date"`
-		EndDate     *time.Time `db:"end_date"`
-		Description string     `db:"description"`
+		ID          uuid.UUID          `db:"id"`
+		Institution string             `db:"institution"`
+		Location    string             `db:"location"`
+		Degree      string             `db:"degree"`
+		Field       string             `db:"field"`
+		StartDate   domain.PartialDate `db:"start_date"`
+		EndDate     domain.PartialDate `db:"end_date"`
+		Description string             `db:"description"`
 	}
 
 	var rows []educationRow
@@ -446,22 +481,14 @@ func (r *PostgresCVRepository) GetEducationByResume(ctx context.Context, resumeI
 
 	education := make([]*domain.Education, len(rows))
 	for i, row := range rows {
-		// Format dates
-		startDate := row.StartDate.Format("2006-01-02")
-		var endDate string
-		if row.EndDate != nil {
-			endDate = row.EndDate.Format("2006-01-02")
-		} else {
-			endDate = "Present"
-		}
-
 		education[i] = &domain.Education{
+			ID:          row.ID,
 			Institution: row.Institution,
 			Location:    row.Location,
 			Degree:      row.Degree,
 			Field:       row.Field,
-			StartDate:   startDate,
-			EndDate:     endDate,
+			StartDate:   row.StartDate,
+			EndDate:     row.EndDate,
 			Description: row.Description,
 		}
 	}
@@ -682,25 +709,6 @@ func (r *PostgresCVRepository) AddProject(ctx context.Context, resumeId uuid.UUI
 	id := uuid.New()
 	now := time.Now()
 
-	var startDate *time.Time
-	var endDate *time.Time
-
-	if project.StartDate != "" && project.StartDate != "Present" {
-		parsedStartDate, err := time.Parse("2006-01-02", project.StartDate)
-		if err != nil {
-			return uuid.Nil, err
-		}
-		startDate = &parsedStartDate
-	}
-
-	if project.EndDate != "" && project.EndDate != "Present" {
-		parsedEndDate, err := time.Parse("2006-01-02", project.EndDate)
-		if err != nil {
-			return uuid.Nil, err
-		}
-		endDate = &parsedEndDate
-	}
-
 	tx, err := r.db.Beginx()
 	if err != nil {
 		log.Error().Err(err).Msg("failed to begin transaction")
@@ -723,8 +731,8 @@ func (r *PostgresCVRepository) AddProject(ctx context.Context, resumeId uuid.UUI
 		project.Description,
 		project.RepoURL,
 		project.DemoURL,
-		startDate,
-		endDate,
+		project.StartDate,
+		project.EndDate,
 		now,
 		now,
 	).Scan(&returnedId)
@@ -784,26 +792,6 @@ func (r *PostgresCVRepository) UpdateProject(ctx context.Context, id uuid.UUID,
 
 	now := time.Now()
 
-	// Parse dates
-	var startDate *time.Time
-	var endDate *time.Time
-
-	if project.StartDate != "" && project.StartDate != "Present" {
-		parsedStartDate, err := time.Parse("2006-01-02", project.StartDate)
-		if err != nil {
-			return err
-		}
-		startDate = &parsedStartDate
-	}
-
-	if project.EndDate != "" && project.EndDate != "Present" {
-		parsedEndDate, err := time.Parse("2006-01-02", project.EndDate)
-		if err != nil {
-			return err
-		}
-		endDate = &parsedEndDate
-	}
-
 	tx, err := r.db.Beginx()
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to begin transaction")
@@ -822,8 +810,8 @@ func (r *PostgresCVRepository) UpdateProject(ctx context.Context, id uuid.UUID,
 		project.Description,
 		project.RepoURL,
 		project.DemoURL,
-		startDate,
-		endDate,
+		project.StartDate,
+		project.EndDate,
 		now,
 		id,
 	)
@@ -899,12 +887,12 @@ func (r *PostgresCVRepository) GetProject(ctx context.Context, id uuid.UUID) (*d
 	`
 
 	var projectRow struct {
-		Name        string     `db:"name"`
-		Description string     `db:"description"`
-		RepoURL     string     `db:"repo_url"`
-		DemoURL     string     `db:"demo_url"`
-		StartDate   *time.Time `db:"start_date"`
-		EndDate     *time.Time `db:"end_date"`
+		Name        string             `db:"name"`
+		Description string             `db:"description"`
+		RepoURL     string             `db:"repo_url"`
+		DemoURL     string             `db:"demo_url"`
+		StartDate   domain.PartialDate `db:"start_date"`
+		EndDate     domain.PartialDate `db:"end_date"`
 	}
 
 	err := r.db.GetContext(ctx, &projectRow, query, id)
@@ -916,17 +904,6 @@ func (r *PostgresCVRepository) GetProject(ctx context.Context, id uuid.UUID) (*d
 		return nil, err
 	}
 
-	// Format dates
-	var startDate, endDate string
-	if projectRow.StartDate != nil {
-		startDate = projectRow.StartDate.Format("2006-01-02")
-	}
-	if projectRow.EndDate != nil {
-		endDate = projectRow.EndDate.Format("2006-01-02")
-	} else {
-		endDate = "Present"
-	}
-
 	// Get technologies
 	technologies, err := r.GetProjectTechnologies(ctx, id)
 	if err != nil {
@@ -935,12 +912,13 @@ func (r *PostgresCVRepository) GetProject(ctx context.Context, id uuid.UUID) (*d
 	}
 
 	project := &domain.Project{
+		ID:           id,
 		Name:         projectRow.Name,
 		Description:  projectRow.Description,
 		RepoURL:      projectRow.RepoURL,
 		DemoURL:      projectRow.DemoURL,
-		StartDate:    startDate,
-		EndDate:      endDate,
+		StartDate:    projectRow.StartDate,
+		EndDate:      projectRow.EndDate,
 		Technologies: technologies,
 	}
 
@@ -955,13 +933,13 @@ func (r *PostgresCVRepository) GetProjectByCV(ctx context.Context, resumeId uuid
 		ORDER BY COALESCE(start_date, '9999-12-31') DESC
 	`
 	type projectRow struct {
-		ID          uuid.UUID  `db:"id"`
-		Name        string     `db:"name"`
-		Description string     `db:"description"`
-		RepoURL     string     `db:"repo_url"`
-		DemoURL     string     `db:"demo_url"`
-		StartDate   *time.Time `db:"start_date"`
-		EndDate     *time.Time `db:"end_date"`
+		ID          uuid.UUID          `db:"id"`
+		Name        string             `db:"name"`
+		Description string             `db:"description"`
+		RepoURL     string             `db:"repo_url"`
+		DemoURL     string             `db:"demo_url"`
+		StartDate   domain.PartialDate `db:"start_date"`
+		EndDate     domain.PartialDate `db:"end_date"`
 	}
 
 	var rows []projectRow
@@ -974,16 +952,6 @@ func (r *PostgresCVRepository) GetProjectByCV(ctx context.Context, resumeId uuid
 	projects := make([]*domain.Project, len(rows))
 
 	for i, row := range rows {
-		var startDate, endDate string
-		if row.StartDate != nil {
-			startDate = row.StartDate.Format("2006-01-02")
-		}
-		if row.EndDate != nil {
-			endDate = row.EndDate.Format("2006-01-02")
-		} else {
-			endDate = "Present"
-		}
-
 		technologies, err := r.GetProjectTechnologies(ctx, row.ID)
 
 		if err != nil {
@@ -991,12 +959,14 @@ func (r *PostgresCVRepository) GetProjectByCV(ctx context.Context, resumeId uuid
 			continue
 		}
 
-		projects[i] = &domain.Project{Name: row.Name,
+		projects[i] = &domain.Project{
+			ID:           row.ID,
+			Name:         row.Name,
 			Description:  row.Description,
 			RepoURL:      row.RepoURL,
 			DemoURL:      row.DemoURL,
-			StartDate:    startDate,
-			EndDate:      endDate,
+			StartDate:    row.StartDate,
+			EndDate:      row.EndDate,
 			Technologies: technologies,
 		}
 	}
@@ -1081,26 +1051,6 @@ func (r *PostgresCVRepository) AddCertification(ctx context.Context, resumeId uu
 	id := uuid.New()
 	now := time.Now()
 
-	// Parse dates
-	var issueDate *time.Time
-	var expiryDate *time.Time
-
-	if certification.IssueDate != "" {
-		parsedIssueDate, err := time.Parse("2006-01-02", certification.IssueDate)
-		if err != nil {
-			return uuid.Nil, err
-		}
-		issueDate = &parsedIssueDate
-	}
-
-	if certification.ExpiryDate != "" && certification.ExpiryDate != "No Expiration" {
-		parsedExpiryDate, err := time.Parse("2006-01-02", certification.ExpiryDate)
-		if err != nil {
-			return uuid.Nil, err
-		}
-		expiryDate = &parsedExpiryDate
-	}
-
 	var returnedID uuid.UUID
 	err := r.db.QueryRowContext(
 		ctx,
@@ -1109,8 +1059,8 @@ func (r *PostgresCVRepository) AddCertification(ctx context.Context, resumeId uu
 		resumeId,
 		certification.Name,
 		certification.Issuer,
-		issueDate,
-		expiryDate,
+		certification.IssueDate,
+		certification.ExpiryDate,
 		certification.CredentialID,
 		certification.URL,
 		now,
@@ -1147,33 +1097,13 @@ func (r *PostgresCVRepository) UpdateCertification(ctx context.Context, id uuid.
 
 	now := time.Now()
 
-	// Parse dates
-	var issueDate *time.Time
-	var expiryDate *time.Time
-
-	if certification.IssueDate != "" {
-		parsedIssueDate, err := time.Parse("2006-01-02", certification.IssueDate)
-		if err != nil {
-			return err
-		}
-		issueDate = &parsedIssueDate
-	}
-
-	if certification.ExpiryDate != "" && certification.ExpiryDate != "No Expiration" {
-		parsedExpiryDate, err := time.Parse("2006-01-02", certification.ExpiryDate)
-		if err != nil {
-			return err
-		}
-		expiryDate = &parsedExpiryDate
-	}
-
 	result, err := r.db.ExecContext(
 		ctx,
 		query,
 		certification.Name,
 		certification.Issuer,
-		issueDate,
-		expiryDate,
+		certification.IssueDate,
+		certification.ExpiryDate,
 		certification.CredentialID,
 		certification.URL,
 		now,
@@ -1230,12 +1160,12 @@ func (r *PostgresCVRepository) GetCertification(ctx context.Context, id uuid.UUI
 	`
 
 	var certRow struct {
-		Name         string     `db:"name"`
-		Issuer       string     `db:"issuer"`
-		IssueDate    time.Time  `db:"issue_date"`
-		ExpiryDate   *time.Time `db:"expiry_date"`
-		CredentialID string     `db:"credential_id"`
-		URL          string     `db:"url"`
+		Name         string             `db:"name"`
+		Issuer       string             `db:"issuer"`
+		IssueDate    domain.PartialDate `db:"issue_date"`
+		ExpiryDate   domain.PartialDate `db:"expiry_date"`
+		CredentialID string             `db:"credential_id"`
+		URL          string             `db:"url"`
 	}
 
 	err := r.db.GetContext(ctx, &certRow, query, id)
@@ -1247,20 +1177,12 @@ func (r *PostgresCVRepository) GetCertification(ctx context.Context, id uuid.UUI
 		return nil, err
 	}
 
-	// Format dates
-	issueDate := certRow.IssueDate.Format("2006-01-02")
-	var expiryDate string
-	if certRow.ExpiryDate != nil {
-		expiryDate = certRow.ExpiryDate.Format("2006-01-02")
-	} else {
-		expiryDate = "No Expiration"
-	}
-
 	certification := &domain.Certification{
+		ID:           id,
 		Name:         certRow.Name,
 		Issuer:       certRow.Issuer,
-		IssueDate:    issueDate,
-		ExpiryDate:   expiryDate,
+		IssueDate:    certRow.IssueDate,
+		ExpiryDate:   certRow.ExpiryDate,
 		CredentialID: certRow.CredentialID,
 		URL:          certRow.URL,
 	}
@@ -1277,13 +1199,13 @@ func (r *PostgresCVRepository) GetCertificationsByResume(ctx context.Context, re
 	`
 
 	type certRow struct {
-		ID           uuid.UUID  `db:"id"`
-		Name         string     `db:"name"`
-		Issuer       string     `db:"issuer"`
-		IssueDate    time.Time  `db:"issue_date"`
-		ExpiryDate   *time.Time `db:"expiry_date"`
-		CredentialID string     `db:"credential_id"`
-		URL          string     `db:"url"`
+		ID           uuid.UUID          `db:"id"`
+		Name         string             `db:"name"`
+		Issuer       string             `db:"issuer"`
+		IssueDate    domain.PartialDate `db:"issue_date"`
+		ExpiryDate   domain.PartialDate `db:"expiry_date"`
+		CredentialID string             `db:"credential_id"`
+		URL          string             `db:"url"`
 	}
 
 	var rows []certRow
@@ -1295,20 +1217,12 @@ func (r *PostgresCVRepository) GetCertificationsByResume(ctx context.Context, re
 
 	certifications := make([]*domain.Certification, len(rows))
 	for i, row := range rows {
-		// Format dates
-		issueDate := row.IssueDate.Format("2006-01-02")
-		var expiryDate string
-		if row.ExpiryDate != nil {
-			expiryDate = row.ExpiryDate.Format("2006-01-02")
-		} else {
-			expiryDate = "No Expiration"
-		}
-
 		certifications[i] = &domain.Certification{
+			ID:           row.ID,
 			Name:         row.Name,
 			Issuer:       row.Issuer,
-			IssueDate:    issueDate,
-			ExpiryDate:   expiryDate,
+			IssueDate:    row.IssueDate,
+			ExpiryDate:   row.ExpiryDate,
 			CredentialID: row.CredentialID,
 			URL:          row.URL,
 		}
@@ -1337,25 +1251,6 @@ func (r *PostgresCVRepository) AddExperience(ctx context.Context, resumeId uuid.
 	id := uuid.New()
 	now := time.Now()
 
-	var startDate *time.Time
-	var endDate *time.Time
-
-	if experience.StartDate != "" {
-		parsedStartDate, err := time.Parse("2006-01-02", experience.StartDate)
-		if err != nil {
-			return uuid.Nil, err
-		}
-		startDate = &parsedStartDate
-	}
-
-	if experience.EndDate != "" && experience.EndDate != "Present" {
-		parsedEndDate, err := time.Parse("2006-01-02", experience.EndDate)
-		if err != nil {
-			return uuid.Nil, err
-		}
-		endDate = &parsedEndDate
-	}
-
 	var returnedId uuid.UUID
 	err := r.db.QueryRowContext(
 		ctx,
@@ -1365,13 +1260,14 @@ func (r *PostgresCVRepository) AddExperience(ctx context.Context, resumeId uuid.
 		experience.Employer,
 		experience.JobTitle,
 		experience.Location,
-		startDate,
-		endDate,
+		experience.StartDate,
+		experience.EndDate,
 		experience.Description,
 		now,
 		now).Scan(&returnedId)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to add experience")
+		return uuid.Nil, err
 	}
 
 	if len(experience.Achievements) > 0 {
@@ -1402,34 +1298,14 @@ func (r *PostgresCVRepository) UpdateExperience(ctx context.Context, id uuid.UUI
 
 	now := time.Now()
 
-	// Parse dates
-	var startDate *time.Time
-	var endDate *time.Time
-
-	if experience.StartDate != "" {
-		parsedStartDate, err := time.Parse("2006-01-02", experience.StartDate)
-		if err != nil {
-			return err
-		}
-		startDate = &parsedStartDate
-	}
-
-	if experience.EndDate != "" && experience.EndDate != "Present" {
-		parsedEndDate, err := time.Parse("2006-01-02", experience.EndDate)
-		if err != nil {
-			return err
-		}
-		endDate = &parsedEndDate
-	}
-
 	result, err := r.db.ExecContext(
 		ctx,
 		query,
 		experience.Employer,
 		experience.JobTitle,
 		experience.Location,
-		startDate,
-		endDate,
+		experience.StartDate,
+		experience.EndDate,
 		experience.Description,
 		now,
 		id)
@@ -1486,12 +1362,12 @@ func (r *PostgresCVRepository) GetExperience(ctx context.Context, id uuid.UUID)
 	`
 
 	var exp struct {
-		Employer    string     `db:"employer"`
-		JobTitle    string     `db:"job_title"`
-		Location    string     `db:"location"`
-		StartDate   time.Time  `db:"start_date"`
-		EndDate     *time.Time `db:"end_date"`
-		Description string     `db:"description"`
+		Employer    string             `db:"employer"`
+		JobTitle    string             `db:"job_title"`
+		Location    string             `db:"location"`
+		StartDate   domain.PartialDate `db:"start_date"`
+		EndDate     domain.PartialDate `db:"end_date"`
+		Description string             `db:"description"`
 	}
 
 	err := r.db.GetContext(ctx, &exp, query, id)
@@ -1503,20 +1379,13 @@ func (r *PostgresCVRepository) GetExperience(ctx context.Context, id uuid.UUID)
 		return nil, err
 	}
 
-	startDate := exp.StartDate.Format("2006-01-02")
-	var endDate string
-	if exp.EndDate != nil {
-		endDate = exp.EndDate.Format("2006-01-02")
-	} else {
-		endDate = "Present"
-	}
-
 	experience := &domain.Experience{
+		ID:           id,
 		Employer:     exp.Employer,
 		JobTitle:     exp.JobTitle,
 		Location:     exp.Location,
-		StartDate:    startDate,
-		EndDate:      endDate,
+		StartDate:    exp.StartDate,
+		EndDate:      exp.EndDate,
 		Description:  exp.Description,
 		Achievements: []string{},
 	}
@@ -1534,13 +1403,13 @@ func (r *PostgresCVRepository) GetExperienceByResume(ctx context.Context, resume
 	`
 
 	type experienceRow struct {
-		ID          uuid.UUID  `db:"id"`
-		Employer    string     `db:"employer"`
-		JobTitle    string     `db:"job_title"`
-		Location    string     `db:"location"`
-		StartDate   time.Time  `db:"start_date"`
-		EndDate     *time.Time `db:"end_date"`
-		Description string     `db:"description"`
+		ID          uuid.UUID          `db:"id"`
+		Employer    string             `db:"employer"`
+		JobTitle    string             `db:"job_title"`
+		Location    string             `db:"location"`
+		StartDate   domain.PartialDate `db:"start_date"`
+		EndDate     domain.PartialDate `db:"end_date"`
+		Description string             `db:"description"`
 	}
 
 	var rows []experienceRow
@@ -1553,20 +1422,13 @@ func (r *PostgresCVRepository) GetExperienceByResume(ctx context.Context, resume
 	experience := make([]*domain.Experience, len(rows))
 
 	for i, row := range rows {
-		startDate := row.StartDate.Format("2006-01-02")
-		var endDate string
-		if row.EndDate != nil {
-			endDate = row.EndDate.Format("2006-01-02")
-		} else {
-			endDate = "Present"
-		}
-
 		experience[i] = &domain.Experience{
+			ID:          row.ID,
 			Employer:    row.Employer,
 			JobTitle:    row.JobTitle,
 			Location:    row.Location,
-			StartDate:   startDate,
-			EndDate:     endDate,
+			StartDate:   row.StartDate,
+			EndDate:     row.EndDate,
 			Description: row.Description,
 			// Fetch achievements if needed
 			Achievements: []string{},
@@ -1588,15 +1450,27 @@ func (r *PostgresCVRepository) GetCompleteResume(ctx context.Context, resumeId u
 	education, _ := r.GetEducationByResume(ctx, resumeId)
 	experience, _ := r.GetExperienceByResume(ctx, resumeId)
 	skills, _ := r.GetSkillsByCV(ctx, resumeId)
+	languages, _ := r.GetLanguagesByResume(ctx, resumeId)
 	projects, _ := r.GetProjectByCV(ctx, resumeId)
 	certifications, _ := r.GetCertificationsByResume(ctx, resumeId)
+	volunteer, _ := r.GetVolunteerByResume(ctx, resumeId)
+	awards, _ := r.GetAwardsByResume(ctx, resumeId)
+	references, _ := r.GetReferencesByResume(ctx, resumeId)
+	customSections, _ := r.GetCustomSectionsByResume(ctx, resumeId)
+	photo, _ := r.GetPhoto(ctx, resumeId)
 
 	resume.PersonalInfo = personalInfo
 	resume.Education = education
 	resume.Experience = experience
 	resume.Skills = skills
+	resume.Languages = languages
 	resume.Projects = projects
 	resume.Certifications = certifications
+	resume.Volunteer = volunteer
+	resume.Awards = awards
+	resume.References = references
+	resume.CustomSections = customSections
+	resume.Photo = photo
 
 	return resume, nil
 }
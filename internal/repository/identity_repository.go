@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"cv_builder/internal/domain"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+type PostgresIdentityRepository struct {
+	db *sqlx.DB
+}
+
+func NewPostgresIdentityRepository(db *sqlx.DB) *PostgresIdentityRepository {
+	return &PostgresIdentityRepository{
+		db: db,
+	}
+}
+
+const identityColumns = `id, user_id, provider, subject, email, username, created_at, last_login_at`
+
+func (r *PostgresIdentityRepository) GetIdentity(ctx context.Context, provider, subject string) (*domain.UserIdentity, error) {
+	query := `SELECT ` + identityColumns + ` FROM user_identities WHERE provider = $1 AND subject = $2`
+
+	var identity domain.UserIdentity
+	if err := r.db.GetContext(ctx, &identity, query, provider, subject); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		log.Error().Err(err).Str("provider", provider).Msg("failed to get identity")
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *PostgresIdentityRepository) GetIdentitiesByUser(ctx context.Context, userId uuid.UUID) ([]*domain.UserIdentity, error) {
+	query := `SELECT ` + identityColumns + ` FROM user_identities WHERE user_id = $1 ORDER BY created_at`
+
+	identities := []*domain.UserIdentity{}
+	if err := r.db.SelectContext(ctx, &identities, query, userId); err != nil {
+		log.Error().Err(err).Str("user_id", userId.String()).Msg("failed to get identities")
+		return nil, err
+	}
+	return identities, nil
+}
+
+func (r *PostgresIdentityRepository) CreateIdentity(ctx context.Context, identity *domain.UserIdentity) error {
+	return createIdentity(ctx, r.db, identity)
+}
+
+func (r *PostgresIdentityRepository) CreateUserWithIdentity(ctx context.Context, user *domain.User, identity *domain.UserIdentity) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Email and password are NULL rather than empty, the columns are unique
+	query := `
+		INSERT INTO users (id, email, password_hash, role, email_verified_at, created_at, updated_at)
+		VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), $4, $5, $6, $7)
+	`
+
+	if user.ID == uuid.Nil {
+		user.ID = uuid.New()
+	}
+	if user.Role == "" {
+		user.Role = "user"
+	}
+	now := time.Now()
+	user.CreatedAt, user.UpdatedAt = now, now
+
+	_, err = tx.ExecContext(ctx, query, user.ID, user.Email, user.PasswordHash, user.Role,
+		user.EmailVerifiedAt, user.CreatedAt, user.UpdatedAt)
+	if err != nil {
+		if isDubpicateKeyError(err) {
+			return ErrConflict
+		}
+		log.Error().Err(err).Msg("failed to create user for identity")
+		return err
+	}
+
+	identity.UserID = user.ID
+	if err := createIdentity(ctx, tx, identity); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func createIdentity(ctx context.Context, db sqlx.ExecerContext, identity *domain.UserIdentity) error {
+	query := `
+		INSERT INTO user_identities (id, user_id, provider, subject, email, username, created_at, last_login_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	identity.ID = uuid.New()
+	identity.CreatedAt = time.Now()
+	identity.LastLoginAt = &identity.CreatedAt
+	_, err := db.ExecContext(ctx, query, identity.ID, identity.UserID, identity.Provider, identity.Subject,
+		identity.Email, identity.Username, identity.CreatedAt, identity.LastLoginAt)
+	if err != nil {
+		if isDubpicateKeyError(err) {
+			return ErrConflict
+		}
+		log.Error().Err(err).Str("user_id", identity.UserID.String()).Msg("failed to create identity")
+		return err
+	}
+	return nil
+}
+
+func (r *PostgresIdentityRepository) UpdateIdentityLogin(ctx context.Context, identity *domain.UserIdentity) error {
+	query := `UPDATE user_identities SET email = $2, username = $3, last_login_at = $4 WHERE id = $1`
+
+	now := time.Now()
+	result, err := r.db.ExecContext(ctx, query, identity.ID, identity.Email, identity.Username, now)
+	if err != nil {
+		log.Error().Err(err).Str("identity_id", identity.ID.String()).Msg("failed to update identity")
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	identity.LastLoginAt = &now
+	return nil
+}
+
+func (r *PostgresIdentityRepository) DeleteIdentity(ctx context.Context, userId, id uuid.UUID) error {
+	query := `DELETE FROM user_identities WHERE id = $1 AND user_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, id, userId)
+	if err != nil {
+		log.Error().Err(err).Str("identity_id", id.String()).Msg("failed to delete identity")
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"cv_builder/internal/domain"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+type languageRow struct {
+	ID          uuid.UUID      `db:"id"`
+	Code        string         `db:"code"`
+	Level       string         `db:"level"`
+	Certificate sql.NullString `db:"certificate"`
+}
+
+func (row languageRow) toDomain() *domain.Language {
+	return &domain.Language{
+		ID:          row.ID,
+		Code:        row.Code,
+		Level:       row.Level,
+		Certificate: row.Certificate.String,
+	}
+}
+
+func (r *PostgresCVRepository) AddLanguage(ctx context.Context, resumeId uuid.UUID, language *domain.Language) (uuid.UUID, error) {
+	query := `
+		INSERT INTO languages (
+			id, resume_id, code, level, certificate, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+
+	language.BeforeSave()
+
+	if err := language.Validate(); err != nil {
+		return uuid.Nil, err
+	}
+
+	id := uuid.New()
+	now := time.Now()
+
+	var returnedID uuid.UUID
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		id,
+		resumeId,
+		language.Code,
+		language.Level,
+		sql.NullString{String: language.Certificate, Valid: language.Certificate != ""},
+		now,
+		now,
+	).Scan(&returnedID)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to add language")
+		return uuid.Nil, err
+	}
+
+	language.ID = returnedID
+	return returnedID, nil
+}
+
+func (r *PostgresCVRepository) UpdateLanguage(ctx context.Context, resumeId, id uuid.UUID, language *domain.Language) error {
+	query := `
+		UPDATE languages
+		SET code = $1,
+			level = $2,
+			certificate = $3,
+			updated_at = $4
+		WHERE id = $5 AND resume_id = $6
+	`
+
+	language.BeforeSave()
+
+	if err := language.Validate(); err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(
+		ctx,
+		query,
+		language.Code,
+		language.Level,
+		sql.NullString{String: language.Certificate, Valid: language.Certificate != ""},
+		time.Now(),
+		id,
+		resumeId,
+	)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to update language")
+		return err
+	}
+
+	return requireRowsAffected(result)
+}
+
+func (r *PostgresCVRepository) DeleteLanguage(ctx context.Context, resumeId, id uuid.UUID) error {
+	query := `
+		DELETE FROM languages
+		WHERE id = $1 AND resume_id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, resumeId)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to delete language")
+		return err
+	}
+
+	return requireRowsAffected(result)
+}
+
+func (r *PostgresCVRepository) GetLanguage(ctx context.Context, resumeId, id uuid.UUID) (*domain.Language, error) {
+	query := `
+		SELECT id, code, level, certificate
+		FROM languages
+		WHERE id = $1 AND resume_id = $2
+	`
+
+	var row languageRow
+	err := r.db.GetContext(ctx, &row, query, id, resumeId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		log.Error().Err(err).Str("language_id", id.String()).Msg("failed to get language")
+		return nil, err
+	}
+
+	return row.toDomain(), nil
+}
+
+// GetLanguagesByResume lists the languages of a resume, mother tongues first
+// and then from the highest level down
+func (r *PostgresCVRepository) GetLanguagesByResume(ctx context.Context, resumeId uuid.UUID) ([]*domain.Language, error) {
+	query := `
+		SELECT id, code, level, certificate
+		FROM languages
+		WHERE resume_id = $1
+		ORDER BY CASE WHEN level = 'native' THEN 0 ELSE 1 END, level DESC, created_at
+	`
+
+	var rows []languageRow
+	err := r.db.SelectContext(ctx, &rows, query, resumeId)
+	if err != nil {
+		log.Error().Err(err).Str("resume_id", resumeId.String()).Msg("failed to get languages by resume")
+		return nil, err
+	}
+
+	languages := make([]*domain.Language, len(rows))
+	for i, row := range rows {
+		languages[i] = row.toDomain()
+	}
+	return languages, nil
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"cv_builder/pkg/mail"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+// PostgresMailQueue stores the mail outbox, see mail.Queue
+type PostgresMailQueue struct {
+	db *sqlx.DB
+}
+
+func NewPostgresMailQueue(db *sqlx.DB) *PostgresMailQueue {
+	return &PostgresMailQueue{
+		db: db,
+	}
+}
+
+func (r *PostgresMailQueue) Enqueue(ctx context.Context, message *mail.Message) error {
+	query := `
+		INSERT INTO mail_outbox (id, recipient, subject, text_body, html_body, next_attempt_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, uuid.New(), message.To, message.Subject, message.Text, message.HTML, time.Now())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to enqueue mail")
+		return err
+	}
+	return nil
+}
+
+// Claim leases due messages by pushing next_attempt_at past the lease, SKIP
+// LOCKED keeps concurrent workers from claiming the same rows
+func (r *PostgresMailQueue) Claim(ctx context.Context, limit, maxAttempts int, leaseUntil time.Time) ([]*mail.QueuedMessage, error) {
+	query := `
+		UPDATE mail_outbox
+		SET attempts = attempts + 1, next_attempt_at = $3
+		WHERE id IN (
+			SELECT id FROM mail_outbox
+			WHERE sent_at IS NULL AND attempts < $2 AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, recipient, subject, text_body, html_body, attempts
+	`
+
+	rows, err := r.db.QueryxContext(ctx, query, limit, maxAttempts, leaseUntil)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to claim mail")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []*mail.QueuedMessage
+	for rows.Next() {
+		var queued mail.QueuedMessage
+		err := rows.Scan(&queued.ID, &queued.Message.To, &queued.Message.Subject,
+			&queued.Message.Text, &queued.Message.HTML, &queued.Attempts)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, &queued)
+	}
+	return messages, rows.Err()
+}
+
+func (r *PostgresMailQueue) MarkSent(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE mail_outbox SET sent_at = $2, last_error = NULL WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, id, time.Now())
+	return err
+}
+
+func (r *PostgresMailQueue) MarkFailed(ctx context.Context, id uuid.UUID, reason string, retryAt time.Time) error {
+	query := `UPDATE mail_outbox SET last_error = $2, next_attempt_at = $3 WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, id, reason, retryAt)
+	return err
+}
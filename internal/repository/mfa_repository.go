@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"cv_builder/internal/domain"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+type PostgresMFARepository struct {
+	db *sqlx.DB
+}
+
+func NewPostgresMFARepository(db *sqlx.DB) *PostgresMFARepository {
+	return &PostgresMFARepository{
+		db: db,
+	}
+}
+
+func (r *PostgresMFARepository) SaveTOTP(ctx context.Context, enrollment *domain.TOTPEnrollment) error {
+	query := `
+		INSERT INTO user_totp (user_id, secret, confirmed_at, last_used_step, created_at)
+		VALUES ($1, $2, NULL, 0, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret = EXCLUDED.secret, last_used_step = 0, created_at = EXCLUDED.created_at
+		WHERE user_totp.confirmed_at IS NULL
+	`
+
+	enrollment.CreatedAt = time.Now()
+	enrollment.ConfirmedAt = nil
+	enrollment.LastUsedStep = 0
+	result, err := r.db.ExecContext(ctx, query, enrollment.UserID, enrollment.Secret, enrollment.CreatedAt)
+	if err != nil {
+		log.Error().Err(err).Str("user_id", enrollment.UserID.String()).Msg("failed to save totp enrollment")
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrConflict
+	}
+	return nil
+}
+
+func (r *PostgresMFARepository) GetTOTP(ctx context.Context, userId uuid.UUID) (*domain.TOTPEnrollment, error) {
+	query := `
+		SELECT user_id, secret, confirmed_at, last_used_step, created_at
+		FROM user_totp
+		WHERE user_id = $1
+	`
+
+	var enrollment domain.TOTPEnrollment
+	if err := r.db.GetContext(ctx, &enrollment, query, userId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		log.Error().Err(err).Str("user_id", userId.String()).Msg("failed to get totp enrollment")
+		return nil, err
+	}
+	return &enrollment, nil
+}
+
+// UseTOTPStep compares and sets the step in one statement, so two requests
+// with the same code cannot both succeed
+func (r *PostgresMFARepository) UseTOTPStep(ctx context.Context, userId uuid.UUID, step int64) error {
+	query := `
+		UPDATE user_totp
+		SET last_used_step = $2, confirmed_at = COALESCE(confirmed_at, $3)
+		WHERE user_id = $1 AND last_used_step < $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, userId, step, time.Now())
+	if err != nil {
+		log.Error().Err(err).Str("user_id", userId.String()).Msg("failed to use totp step")
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrConflict
+	}
+	return nil
+}
+
+func (r *PostgresMFARepository) DeleteTOTP(ctx context.Context, userId uuid.UUID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_recovery_codes WHERE user_id = $1`, userId); err != nil {
+		log.Error().Err(err).Str("user_id", userId.String()).Msg("failed to delete recovery codes")
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM user_totp WHERE user_id = $1`, userId)
+	if err != nil {
+		log.Error().Err(err).Str("user_id", userId.String()).Msg("failed to delete totp enrollment")
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return tx.Commit()
+}
+
+func (r *PostgresMFARepository) ReplaceRecoveryCodes(ctx context.Context, userId uuid.UUID, codeHashes []string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_recovery_codes WHERE user_id = $1`, userId); err != nil {
+		log.Error().Err(err).Str("user_id", userId.String()).Msg("failed to delete recovery codes")
+		return err
+	}
+
+	query := `
+		INSERT INTO user_recovery_codes (id, user_id, code_hash, created_at)
+		VALUES ($1, $2, $3, $4)
+	`
+	now := time.Now()
+	for _, codeHash := range codeHashes {
+		if _, err := tx.ExecContext(ctx, query, uuid.New(), userId, codeHash, now); err != nil {
+			log.Error().Err(err).Str("user_id", userId.String()).Msg("failed to create recovery code")
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *PostgresMFARepository) UseRecoveryCode(ctx context.Context, userId uuid.UUID, codeHash string) error {
+	query := `
+		UPDATE user_recovery_codes
+		SET used_at = $3
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, userId, codeHash, time.Now())
+	if err != nil {
+		log.Error().Err(err).Str("user_id", userId.String()).Msg("failed to use recovery code")
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresMFARepository) CountRecoveryCodes(ctx context.Context, userId uuid.UUID) (int, error) {
+	query := `SELECT COUNT(*) FROM user_recovery_codes WHERE user_id = $1 AND used_at IS NULL`
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, query, userId); err != nil {
+		log.Error().Err(err).Str("user_id", userId.String()).Msg("failed to count recovery codes")
+		return 0, err
+	}
+	return count, nil
+}
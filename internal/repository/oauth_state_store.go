@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"cv_builder/internal/domain"
+	"encoding/json"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+// RedisOAuthStateStore keeps the state of OAuth logins between the redirect
+// to the provider and the callback
+type RedisOAuthStateStore struct {
+	redis *redis.Client
+}
+
+func NewRedisOAuthStateStore(redisClient *redis.Client) *RedisOAuthStateStore {
+	return &RedisOAuthStateStore{
+		redis: redisClient,
+	}
+}
+
+func (s *RedisOAuthStateStore) SaveOAuthState(ctx context.Context, state string, data *domain.OAuthState, ttl time.Duration) error {
+	value, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	if err := s.redis.Set(ctx, oauthStateKey(state), value, ttl).Err(); err != nil {
+		log.Error().Err(err).Msg("failed to save oauth state")
+		return err
+	}
+	return nil
+}
+
+// TakeOAuthState returns and removes the state, so each callback can only be
+// used once
+func (s *RedisOAuthStateStore) TakeOAuthState(ctx context.Context, state string) (*domain.OAuthState, error) {
+	value, err := s.redis.GetDel(ctx, oauthStateKey(state)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrNotFound
+		}
+		log.Error().Err(err).Msg("failed to get oauth state")
+		return nil, err
+	}
+
+	var data domain.OAuthState
+	if err := json.Unmarshal(value, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func oauthStateKey(state string) string {
+	return "oauth:state:" + state
+}
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"cv_builder/internal/domain"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+type PostgresPasskeyRepository struct {
+	db *sqlx.DB
+}
+
+func NewPostgresPasskeyRepository(db *sqlx.DB) *PostgresPasskeyRepository {
+	return &PostgresPasskeyRepository{
+		db: db,
+	}
+}
+
+// passkeyRow is a passkey as stored, with its transports as an array
+type passkeyRow struct {
+	domain.Passkey
+	Transports pq.StringArray `db:"transports"`
+}
+
+func (row *passkeyRow) toPasskey() *domain.Passkey {
+	passkey := row.Passkey
+	passkey.Transports = []string(row.Transports)
+	return &passkey
+}
+
+const passkeyColumns = `
+	id, user_id, name, credential_id, public_key, attestation_type, aaguid, transports,
+	sign_count, user_verified, backup_eligible, backup_state, created_at, last_used_at
+`
+
+func (r *PostgresPasskeyRepository) CreatePasskey(ctx context.Context, passkey *domain.Passkey) error {
+	query := `
+		INSERT INTO user_passkeys (id, user_id, name, credential_id, public_key, attestation_type, aaguid, transports,
+			sign_count, user_verified, backup_eligible, backup_state, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`
+
+	passkey.ID = uuid.New()
+	passkey.CreatedAt = time.Now()
+	_, err := r.db.ExecContext(ctx, query, passkey.ID, passkey.UserID, passkey.Name, passkey.CredentialID,
+		passkey.PublicKey, passkey.AttestationType, passkey.AAGUID, pq.StringArray(passkey.Transports),
+		passkey.SignCount, passkey.UserVerified, passkey.BackupEligible, passkey.BackupState, passkey.CreatedAt)
+	if err != nil {
+		if isDubpicateKeyError(err) {
+			return ErrConflict
+		}
+		log.Error().Err(err).Str("user_id", passkey.UserID.String()).Msg("failed to create passkey")
+		return err
+	}
+	return nil
+}
+
+func (r *PostgresPasskeyRepository) GetPasskeysByUser(ctx context.Context, userId uuid.UUID) ([]*domain.Passkey, error) {
+	query := `SELECT ` + passkeyColumns + ` FROM user_passkeys WHERE user_id = $1 ORDER BY created_at`
+
+	var rows []passkeyRow
+	if err := r.db.SelectContext(ctx, &rows, query, userId); err != nil {
+		log.Error().Err(err).Str("user_id", userId.String()).Msg("failed to get passkeys")
+		return nil, err
+	}
+
+	passkeys := make([]*domain.Passkey, 0, len(rows))
+	for i := range rows {
+		passkeys = append(passkeys, rows[i].toPasskey())
+	}
+	return passkeys, nil
+}
+
+func (r *PostgresPasskeyRepository) GetPasskeyByCredentialID(ctx context.Context, credentialId []byte) (*domain.Passkey, error) {
+	query := `SELECT ` + passkeyColumns + ` FROM user_passkeys WHERE credential_id = $1`
+
+	var row passkeyRow
+	if err := r.db.GetContext(ctx, &row, query, credentialId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		log.Error().Err(err).Msg("failed to get passkey by credential id")
+		return nil, err
+	}
+	return row.toPasskey(), nil
+}
+
+func (r *PostgresPasskeyRepository) UpdatePasskeyUsage(ctx context.Context, passkey *domain.Passkey, previousSignCount int64) error {
+	query := `
+		UPDATE user_passkeys
+		SET sign_count = $3, user_verified = $4, backup_state = $5, last_used_at = $6
+		WHERE id = $1 AND sign_count = $2
+	`
+
+	now := time.Now()
+	result, err := r.db.ExecContext(ctx, query, passkey.ID, previousSignCount, passkey.SignCount,
+		passkey.UserVerified, passkey.BackupState, now)
+	if err != nil {
+		log.Error().Err(err).Str("passkey_id", passkey.ID.String()).Msg("failed to update passkey usage")
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrConflict
+	}
+
+	passkey.LastUsedAt = &now
+	return nil
+}
+
+func (r *PostgresPasskeyRepository) RenamePasskey(ctx context.Context, userId, id uuid.UUID, name string) error {
+	query := `UPDATE user_passkeys SET name = $3 WHERE id = $1 AND user_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, id, userId, name)
+	if err != nil {
+		log.Error().Err(err).Str("passkey_id", id.String()).Msg("failed to rename passkey")
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresPasskeyRepository) DeletePasskey(ctx context.Context, userId, id uuid.UUID) error {
+	query := `DELETE FROM user_passkeys WHERE id = $1 AND user_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, id, userId)
+	if err != nil {
+		log.Error().Err(err).Str("passkey_id", id.String()).Msg("failed to delete passkey")
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
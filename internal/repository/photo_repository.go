@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"cv_builder/internal/domain"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+// SavePhoto stores the photo metadata of a resume, replacing any previous photo
+func (r *PostgresCVRepository) SavePhoto(ctx context.Context, resumeId uuid.UUID, photo *domain.Photo) error {
+	query := `
+		INSERT INTO resume_photos (
+			resume_id, content_type, original_key, thumbnail_key, width, height, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (resume_id) DO UPDATE
+		SET content_type = EXCLUDED.content_type,
+			original_key = EXCLUDED.original_key,
+			thumbnail_key = EXCLUDED.thumbnail_key,
+			width = EXCLUDED.width,
+			height = EXCLUDED.height,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	now := time.Now()
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		resumeId,
+		photo.ContentType,
+		photo.OriginalKey,
+		photo.ThumbnailKey,
+		photo.Width,
+		photo.Height,
+		now,
+		now,
+	)
+	if err != nil {
+		log.Error().Err(err).Str("resume_id", resumeId.String()).Msg("failed to save photo")
+		return err
+	}
+
+	photo.UpdatedAt = now
+	return nil
+}
+
+func (r *PostgresCVRepository) GetPhoto(ctx context.Context, resumeId uuid.UUID) (*domain.Photo, error) {
+	query := `
+		SELECT content_type, original_key, thumbnail_key, width, height, updated_at
+		FROM resume_photos
+		WHERE resume_id = $1
+	`
+
+	var photo domain.Photo
+	err := r.db.GetContext(ctx, &photo, query, resumeId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		log.Error().Err(err).Str("resume_id", resumeId.String()).Msg("failed to get photo")
+		return nil, err
+	}
+
+	return &photo, nil
+}
+
+func (r *PostgresCVRepository) DeletePhoto(ctx context.Context, resumeId uuid.UUID) error {
+	query := `
+		DELETE FROM resume_photos
+		WHERE resume_id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query, resumeId)
+	if err != nil {
+		log.Error().Err(err).Str("resume_id", resumeId.String()).Msg("failed to delete photo")
+		return err
+	}
+
+	return requireRowsAffected(result)
+}
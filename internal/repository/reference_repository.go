@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"cv_builder/internal/domain"
+	"database/sql"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+type referenceRow struct {
+	ID                 uuid.UUID      `db:"id"`
+	Name               string         `db:"name"`
+	Relationship       string         `db:"relationship"`
+	Company            sql.NullString `db:"company"`
+	Email              sql.NullString `db:"email"`
+	Phone              sql.NullString `db:"phone"`
+	AvailableOnRequest bool           `db:"available_on_request"`
+}
+
+func (row referenceRow) toDomain() *domain.Reference {
+	return &domain.Reference{
+		ID:                 row.ID,
+		Name:               row.Name,
+		Relationship:       row.Relationship,
+		Company:            row.Company.String,
+		Email:              row.Email.String,
+		Phone:              row.Phone.String,
+		AvailableOnRequest: row.AvailableOnRequest,
+	}
+}
+
+func (r *PostgresCVRepository) AddReference(ctx context.Context, resumeId uuid.UUID, reference *domain.Reference) (uuid.UUID, error) {
+	query := `
+		INSERT INTO resume_references (
+			id, resume_id, name, relationship, company, email, phone,
+			available_on_request, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id
+	`
+
+	reference.BeforeSave()
+
+	if err := reference.Validate(); err != nil {
+		return uuid.Nil, err
+	}
+
+	id := uuid.New()
+	now := time.Now()
+
+	var returnedID uuid.UUID
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		id,
+		resumeId,
+		reference.Name,
+		reference.Relationship,
+		sql.NullString{String: reference.Company, Valid: reference.Company != ""},
+		sql.NullString{String: reference.Email, Valid: reference.Email != ""},
+		sql.NullString{String: reference.Phone, Valid: reference.Phone != ""},
+		reference.AvailableOnRequest,
+		now,
+		now,
+	).Scan(&returnedID)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to add reference")
+		return uuid.Nil, err
+	}
+
+	reference.ID = returnedID
+	return returnedID, nil
+}
+
+func (r *PostgresCVRepository) UpdateReference(ctx context.Context, resumeId, id uuid.UUID, reference *domain.Reference) error {
+	query := `
+		UPDATE resume_references
+		SET name = $1,
+			relationship = $2,
+			company = $3,
+			email = $4,
+			phone = $5,
+			available_on_request = $6,
+			updated_at = $7
+		WHERE id = $8 AND resume_id = $9
+	`
+
+	reference.BeforeSave()
+
+	if err := reference.Validate(); err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(
+		ctx,
+		query,
+		reference.Name,
+		reference.Relationship,
+		sql.NullString{String: reference.Company, Valid: reference.Company != ""},
+		sql.NullString{String: reference.Email, Valid: reference.Email != ""},
+		sql.NullString{String: reference.Phone, Valid: reference.Phone != ""},
+		reference.AvailableOnRequest,
+		time.Now(),
+		id,
+		resumeId,
+	)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to update reference")
+		return err
+	}
+
+	return requireRowsAffected(result)
+}
+
+func (r *PostgresCVRepository) DeleteReference(ctx context.Context, resumeId, id uuid.UUID) error {
+	query := `
+		DELETE FROM resume_references
+		WHERE id = $1 AND resume_id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, resumeId)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to delete reference")
+		return err
+	}
+
+	return requireRowsAffected(result)
+}
+
+func (r *PostgresCVRepository) GetReferencesByResume(ctx context.Context, resumeId uuid.UUID) ([]*domain.Reference, error) {
+	query := `
+		SELECT id, name, relationship, company, email, phone, available_on_request
+		FROM resume_references
+		WHERE resume_id = $1
+		ORDER BY created_at
+	`
+
+	var rows []referenceRow
+	err := r.db.SelectContext(ctx, &rows, query, resumeId)
+	if err != nil {
+		log.Error().Err(err).Str("resume_id", resumeId.String()).Msg("failed to get references by resume")
+		return nil, err
+	}
+
+	references := make([]*domain.Reference, len(rows))
+	for i, row := range rows {
+		references[i] = row.toDomain()
+	}
+	return references, nil
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"cv_builder/internal/domain"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+type PostgresTemplateRepository struct {
+	db *sqlx.DB
+}
+
+func NewPostgresTemplateRepository(db *sqlx.DB) *PostgresTemplateRepository {
+	return &PostgresTemplateRepository{
+		db: db,
+	}
+}
+
+// templateRow is a template as stored, with its content as JSON
+type templateRow struct {
+	domain.ResumeTemplate
+	Content []byte `db:"content"`
+}
+
+func (row *templateRow) toTemplate() (*domain.ResumeTemplate, error) {
+	template := row.ResumeTemplate
+	template.Content = &domain.Resume{}
+	if err := json.Unmarshal(row.Content, template.Content); err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *PostgresTemplateRepository) CreateTemplate(ctx context.Context, template *domain.ResumeTemplate) error {
+	query := `
+		INSERT INTO resume_templates (id, slug, name, description, content, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	content, err := json.Marshal(template.Content)
+	if err != nil {
+		return err
+	}
+
+	template.ID = uuid.New()
+	now := time.Now()
+	_, err = r.db.ExecContext(ctx, query, template.ID, template.Slug, template.Name, template.Description, content, now, now)
+	if err != nil {
+		if isDubpicateKeyError(err) {
+			return ErrConflict
+		}
+		log.Error().Err(err).Str("slug", template.Slug).Msg("failed to create template")
+		return err
+	}
+
+	template.CreatedAt = now
+	template.UpdatedAt = now
+	return nil
+}
+
+// UpdateTemplate replaces the template with the given slug, which may be
+// renamed through template.Slug
+func (r *PostgresTemplateRepository) UpdateTemplate(ctx context.Context, slug string, template *domain.ResumeTemplate) error {
+	query := `
+		UPDATE resume_templates
+		SET slug = $2, name = $3, description = $4, content = $5, updated_at = $6
+		WHERE slug = $1
+		RETURNING id, created_at
+	`
+
+	content, err := json.Marshal(template.Content)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	err = r.db.QueryRowContext(ctx, query, slug, template.Slug, template.Name, template.Description, content, now).
+		Scan(&template.ID, &template.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		if isDubpicateKeyError(err) {
+			return ErrConflict
+		}
+		log.Error().Err(err).Str("slug", slug).Msg("failed to update template")
+		return err
+	}
+
+	template.UpdatedAt = now
+	return nil
+}
+
+func (r *PostgresTemplateRepository) DeleteTemplate(ctx context.Context, slug string) error {
+	query := `
+		DELETE FROM resume_templates
+		WHERE slug = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query, slug)
+	if err != nil {
+		log.Error().Err(err).Str("slug", slug).Msg("failed to delete template")
+		return err
+	}
+
+	return requireRowsAffected(result)
+}
+
+func (r *PostgresTemplateRepository) GetTemplate(ctx context.Context, slug string) (*domain.ResumeTemplate, error) {
+	query := `
+		SELECT id, slug, name, description, content, created_at, updated_at
+		FROM resume_templates
+		WHERE slug = $1
+	`
+
+	var row templateRow
+	err := r.db.GetContext(ctx, &row, query, slug)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		log.Error().Err(err).Str("slug", slug).Msg("failed to get template")
+		return nil, err
+	}
+
+	template, err := row.toTemplate()
+	if err != nil {
+		log.Error().Err(err).Str("slug", slug).Msg("failed to decode template content")
+		return nil, err
+	}
+	return template, nil
+}
+
+func (r *PostgresTemplateRepository) GetTemplates(ctx context.Context) ([]*domain.ResumeTemplate, error) {
+	query := `
+		SELECT id, slug, name, description, content, created_at, updated_at
+		FROM resume_templates
+		ORDER BY name
+	`
+
+	var rows []templateRow
+	if err := r.db.SelectContext(ctx, &rows, query); err != nil {
+		log.Error().Err(err).Msg("failed to get templates")
+		return nil, err
+	}
+
+	templates := make([]*domain.ResumeTemplate, 0, len(rows))
+	for i := range rows {
+		template, err := rows[i].toTemplate()
+		if err != nil {
+			log.Error().Err(err).Str("slug", rows[i].Slug).Msg("failed to decode template content")
+			return nil, err
+		}
+		templates = append(templates, template)
+	}
+	return templates, nil
+}
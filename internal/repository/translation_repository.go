@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"cv_builder/internal/domain"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+func (r *PostgresCVRepository) SetDefaultLocale(ctx context.Context, resumeId uuid.UUID, locale string) error {
+	query := `
+		UPDATE resumes
+		SET default_locale = $2
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query, resumeId, locale)
+	if err != nil {
+		log.Error().Err(err).Str("resume_id", resumeId.String()).Msg("failed to set default locale")
+		return err
+	}
+
+	return requireRowsAffected(result)
+}
+
+// SaveTranslation creates or replaces the translation of a resume into one locale
+func (r *PostgresCVRepository) SaveTranslation(ctx context.Context, resumeId uuid.UUID, translation *domain.Translation) error {
+	query := `
+		INSERT INTO resume_translations (resume_id, locale, content, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (resume_id, locale) DO UPDATE
+		SET content = EXCLUDED.content,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	content, err := translation.ToJSON()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	_, err = r.db.ExecContext(ctx, query, resumeId, translation.Locale, content, now, now)
+	if err != nil {
+		log.Error().Err(err).Str("resume_id", resumeId.String()).Str("locale", translation.Locale).Msg("failed to save translation")
+		return err
+	}
+
+	return nil
+}
+
+func (r *PostgresCVRepository) GetTranslation(ctx context.Context, resumeId uuid.UUID, locale string) (*domain.Translation, error) {
+	query := `
+		SELECT content
+		FROM resume_translations
+		WHERE resume_id = $1 AND locale = $2
+	`
+
+	var content []byte
+	err := r.db.QueryRowContext(ctx, query, resumeId, locale).Scan(&content)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		log.Error().Err(err).Str("resume_id", resumeId.String()).Str("locale", locale).Msg("failed to get translation")
+		return nil, err
+	}
+
+	var translation domain.Translation
+	if err := translation.FromJSON(content); err != nil {
+		log.Error().Err(err).Str("resume_id", resumeId.String()).Str("locale", locale).Msg("failed to decode translation")
+		return nil, err
+	}
+	translation.Locale = locale
+
+	return &translation, nil
+}
+
+// GetTranslationLocales lists the locales a resume has been translated into
+func (r *PostgresCVRepository) GetTranslationLocales(ctx context.Context, resumeId uuid.UUID) ([]string, error) {
+	query := `
+		SELECT locale
+		FROM resume_translations
+		WHERE resume_id = $1
+		ORDER BY locale
+	`
+
+	locales := []string{}
+	err := r.db.SelectContext(ctx, &locales, query, resumeId)
+	if err != nil {
+		log.Error().Err(err).Str("resume_id", resumeId.String()).Msg("failed to get translation locales")
+		return nil, err
+	}
+
+	return locales, nil
+}
+
+func (r *PostgresCVRepository) DeleteTranslation(ctx context.Context, resumeId uuid.UUID, locale string) error {
+	query := `
+		DELETE FROM resume_translations
+		WHERE resume_id = $1 AND locale = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, resumeId, locale)
+	if err != nil {
+		log.Error().Err(err).Str("resume_id", resumeId.String()).Str("locale", locale).Msg("failed to delete translation")
+		return err
+	}
+
+	return requireRowsAffected(result)
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/rs/zerolog/log"
 	tgInitData "github.com/telegram-mini-apps/init-data-golang"
 	"time"
@@ -21,21 +22,6 @@ type PostgresRepository struct {
 	db *sqlx.DB
 }
 
-func (r *PostgresRepository) CreatePasswordReset(ctx context.Context, reset *domain.PasswordReset) error {
-	//TODO implement me
-	panic("implement me")
-}
-
-func (r *PostgresRepository) GetPasswordResetByToken(ctx context.Context, token string) (*domain.PasswordReset, error) {
-	//TODO implement me
-	panic("implement me")
-}
-
-func (r *PostgresRepository) DeleteExpiredPasswordReset(ctx context.Context) error {
-	//TODO implement me
-	panic("implement me")
-}
-
 func NewPostgresUserRepository(db *sqlx.DB) *PostgresRepository {
 	return &PostgresRepository{db: db}
 }
@@ -116,9 +102,12 @@ func (r *PostgresRepository) CreateTelegramUser(ctx context.Context, tgUser tgIn
 	return user, nil
 }
 
+// GetUserById loads any user. Telegram-only users have no email and password,
+// which are returned empty.
 func (r *PostgresRepository) GetUserById(ctx context.Context, id uuid.UUID) (*domain.User, error) {
 	query := `
-		SELECT id, email, password_hash, role, created_at, updated_at
+		SELECT id, COALESCE(email, '') AS email, COALESCE(password_hash, '') AS password_hash,
+		       role, email_verified_at, created_at, updated_at
 		FROM users
 		WHERE id = $1
 	`
@@ -157,6 +146,29 @@ func (r *PostgresRepository) GetUserByTelegramID(ctx context.Context, telegramID
 	return &user, nil
 }
 
+// GetTelegramUserById loads a user with the profile fields received from Telegram
+func (r *PostgresRepository) GetTelegramUserById(ctx context.Context, id uuid.UUID) (*domain.TelegramUser, error) {
+	query := `
+        SELECT id, email, telegram_id, first_name, last_name, username, 
+               photo_url, language_code, is_premium, role, created_at, updated_at
+        FROM users 
+        WHERE id = $1
+    `
+
+	var user domain.TelegramUser
+
+	err := r.db.GetContext(ctx, &user, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		log.Error().Err(err).Str("user_id", id.String()).Msg("failed to get telegram user by id")
+		return nil, err
+	}
+
+	return &user, nil
+}
+
 func (r *PostgresRepository) UpdateTelegramUser(ctx context.Context, user *domain.TelegramUser) error {
 	query := `
         UPDATE users 
@@ -192,7 +204,7 @@ func (r *PostgresRepository) UpdateTelegramUser(ctx context.Context, user *domai
 
 func (r *PostgresRepository) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
 	query := `
-		SELECT id, email, password_hash, role, created_at, updated_at
+		SELECT id, email, COALESCE(password_hash, '') AS password_hash, role, email_verified_at, created_at, updated_at
 		FROM users
 		WHERE email = $1
 	`
@@ -213,17 +225,23 @@ func (r *PostgresRepository) GetUserByEmail(ctx context.Context, email string) (
 func (r *PostgresRepository) UpdateUser(ctx context.Context, user *domain.User) error {
 	query := `
 		UPDATE users
-		SET email = $1, password_hash = $2, role = $3, updated_at = $4
+		SET email = NULLIF($1, ''), password_hash = NULLIF($2, ''), role = $3, updated_at = $4,
+			email_verified_at = CASE WHEN email = $1 THEN email_verified_at END
 		WHERE id = $5
+		RETURNING email_verified_at
 	`
 
 	user.UpdatedAt = time.Now()
 
-	result, err := r.db.ExecContext(
+	// A changed email address has to be verified again
+	err := r.db.QueryRowContext(
 		ctx,
 		query,
-		user.Email, user.PasswordHash, user.Role, user.UpdatedAt, user.ID)
+		user.Email, user.PasswordHash, user.Role, user.UpdatedAt, user.ID).Scan(&user.EmailVerifiedAt)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
 		if isDubpicateKeyError(err) {
 			log.Error().Err(err).Str("email", user.Email).Msg("failed to update user: same email found")
 			return ErrConflict
@@ -231,10 +249,27 @@ func (r *PostgresRepository) UpdateUser(ctx context.Context, user *domain.User)
 		log.Error().Err(err).Msg("failed to update user")
 		return err
 	}
-	rowsAffected, err := result.RowsAffected()
+	return nil
+}
+
+// MarkEmailVerified records that the user verified the given address. It
+// fails with ErrNotFound when the user changed their address since.
+func (r *PostgresRepository) MarkEmailVerified(ctx context.Context, id uuid.UUID, email string) error {
+	query := `
+		UPDATE users
+		SET email_verified_at = COALESCE(email_verified_at, $3), updated_at = $3
+		WHERE id = $1 AND email = $2
+	`
 
+	result, err := r.db.ExecContext(ctx, query, id, email, time.Now())
 	if err != nil {
-		log.Error().Err(err).Msg("failed to change rows")
+		log.Error().Err(err).Str("user_id", id.String()).Msg("failed to mark email verified")
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get rows affected")
 		return err
 	}
 	if rowsAffected == 0 {
@@ -263,8 +298,43 @@ func (r *PostgresRepository) DeleteUser(ctx context.Context, id uuid.UUID) error
 
 }
 
+func (r *PostgresRepository) CreatePasswordReset(ctx context.Context, reset *domain.PasswordReset) error {
+	query := `
+		INSERT INTO password_resets (id, user_id, token, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	if reset.ID == uuid.Nil {
+		reset.ID = uuid.New()
+	}
+	_, err := r.db.ExecContext(ctx, query, reset.ID, reset.UserID, reset.Token, reset.ExpiresAt, reset.CreatedAt)
+	if err != nil {
+		log.Error().Err(err).Str("user_id", reset.UserID.String()).Msg("failed to create pwd reset")
+		return err
+	}
+	return nil
+}
+
+func (r *PostgresRepository) GetPasswordResetByToken(ctx context.Context, token string) (*domain.PasswordReset, error) {
+	query := `
+		SELECT id, user_id, token, expires_at, created_at, used_at
+		FROM password_resets
+		WHERE token = $1
+	`
+
+	var reset domain.PasswordReset
+	if err := r.db.GetContext(ctx, &reset, query, token); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		log.Error().Err(err).Msg("failed to get pwd reset by token")
+		return nil, err
+	}
+	return &reset, nil
+}
+
 func (r *PostgresRepository) MarkPasswordResetUsed(ctx context.Context, id uuid.UUID) error {
-	query := `UPDATE password_resets SET user_at = $1 WHERE id = $2`
+	query := `UPDATE password_resets SET used_at = $1 WHERE id = $2`
 
 	now := time.Now()
 	result, err := r.db.ExecContext(ctx, query, now, id)
@@ -299,10 +369,197 @@ func (r *PostgresRepository) DeleteExpiredPasswordResets(ctx context.Context) er
 	return nil
 }
 
+// isDubpicateKeyError reports a unique constraint violation. The message
+// names the constraint, so the SQLSTATE code is checked instead.
 func isDubpicateKeyError(err error) bool {
-	return err != nil && err.Error() != "" &&
-		(err.Error() == "pq: duplicate key value violates unique constraint" ||
-			err.Error() == "ERROR: duplicate key value violates unique constraint (SQLSTATE 23505)")
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+func (r *PostgresRepository) LinkTelegram(ctx context.Context, userId uuid.UUID, tgUser tgInitData.User) error {
+	query := `
+		UPDATE users
+		SET telegram_id = $2, first_name = $3, last_name = $4, username = $5,
+			photo_url = $6, language_code = $7, is_premium = $8, updated_at = $9
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query, userId, tgUser.ID, stringPtr(tgUser.FirstName), stringPtr(tgUser.LastName),
+		stringPtr(tgUser.Username), stringPtr(tgUser.PhotoURL), stringPtr(tgUser.LanguageCode), tgUser.IsPremium, time.Now())
+	if err != nil {
+		if isDubpicateKeyError(err) {
+			return ErrConflict
+		}
+		log.Error().Err(err).Str("user_id", userId.String()).Msg("failed to link telegram")
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) UnlinkTelegram(ctx context.Context, userId uuid.UUID) error {
+	query := `
+		UPDATE users
+		SET telegram_id = NULL, username = NULL, photo_url = NULL, is_premium = FALSE, updated_at = $2
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query, userId, time.Now())
+	if err != nil {
+		log.Error().Err(err).Str("user_id", userId.String()).Msg("failed to unlink telegram")
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// MergeUsers moves resumes, sessions, passkeys and identities to the target
+// user. The target takes over the email login and Telegram account of the
+// source if it has none of its own, and its second factor if the target has
+// none, so the moved logins stay protected. The rest of the source is
+// deleted.
+func (r *PostgresRepository) MergeUsers(ctx context.Context, targetId, sourceId uuid.UUID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Locking both users keeps logins from changing them mid-merge
+	var locked []uuid.UUID
+	if err := tx.SelectContext(ctx, &locked, `SELECT id FROM users WHERE id IN ($1, $2) FOR UPDATE`, targetId, sourceId); err != nil {
+		log.Error().Err(err).Msg("failed to lock users for merge")
+		return err
+	}
+	if len(locked) != 2 {
+		return ErrNotFound
+	}
+
+	var source struct {
+		Email           *string    `db:"email"`
+		PasswordHash    *string    `db:"password_hash"`
+		EmailVerifiedAt *time.Time `db:"email_verified_at"`
+		TelegramID      *int64     `db:"telegram_id"`
+		FirstName       *string    `db:"first_name"`
+		LastName        *string    `db:"last_name"`
+		Username        *string    `db:"username"`
+		PhotoURL        *string    `db:"photo_url"`
+		LanguageCode    *string    `db:"language_code"`
+		IsPremium       *bool      `db:"is_premium"`
+	}
+	query := `
+		SELECT email, password_hash, email_verified_at, telegram_id, first_name, last_name,
+		       username, photo_url, language_code, is_premium
+		FROM users
+		WHERE id = $1
+	`
+	if err := tx.GetContext(ctx, &source, query, sourceId); err != nil {
+		log.Error().Err(err).Str("user_id", sourceId.String()).Msg("failed to get user to merge")
+		return err
+	}
+
+	for _, query := range []string{
+		`UPDATE resumes SET user_id = $1 WHERE user_id = $2`,
+		`UPDATE sessions SET user_id = $1 WHERE user_id = $2`,
+		`UPDATE user_passkeys SET user_id = $1 WHERE user_id = $2`,
+		`UPDATE user_identities SET user_id = $1 WHERE user_id = $2`,
+	} {
+		if _, err := tx.ExecContext(ctx, query, targetId, sourceId); err != nil {
+			log.Error().Err(err).Str("user_id", sourceId.String()).Msg("failed to move user data")
+			return err
+		}
+	}
+
+	if err := mergeSecondFactor(ctx, tx, targetId, sourceId); err != nil {
+		log.Error().Err(err).Str("user_id", sourceId.String()).Msg("failed to move second factor")
+		return err
+	}
+
+	// The source goes first, its email and Telegram ID are unique
+	if _, err := tx.ExecContext(ctx, `DELETE FROM users WHERE id = $1`, sourceId); err != nil {
+		log.Error().Err(err).Str("user_id", sourceId.String()).Msg("failed to delete merged user")
+		return err
+	}
+
+	now := time.Now()
+	if source.Email != nil {
+		query := `
+			UPDATE users
+			SET email = $2, password_hash = $3, email_verified_at = $4, updated_at = $5
+			WHERE id = $1 AND email IS NULL
+		`
+		if _, err := tx.ExecContext(ctx, query, targetId, source.Email, source.PasswordHash, source.EmailVerifiedAt, now); err != nil {
+			log.Error().Err(err).Str("user_id", targetId.String()).Msg("failed to move email login")
+			return err
+		}
+	}
+	if source.TelegramID != nil {
+		query := `
+			UPDATE users
+			SET telegram_id = $2, first_name = COALESCE(first_name, $3), last_name = COALESCE(last_name, $4),
+				username = $5, photo_url = $6, language_code = COALESCE(language_code, $7),
+				is_premium = COALESCE($8, FALSE), updated_at = $9
+			WHERE id = $1 AND telegram_id IS NULL
+		`
+		if _, err := tx.ExecContext(ctx, query, targetId, source.TelegramID, source.FirstName, source.LastName,
+			source.Username, source.PhotoURL, source.LanguageCode, source.IsPremium, now); err != nil {
+			log.Error().Err(err).Str("user_id", targetId.String()).Msg("failed to move telegram login")
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// mergeSecondFactor moves the confirmed authenticator app and recovery codes
+// of the source to the target, unless the target has its own. A pending
+// setup of the target is replaced.
+func mergeSecondFactor(ctx context.Context, tx *sqlx.Tx, targetId, sourceId uuid.UUID) error {
+	var sourceEnabled, targetEnabled bool
+	query := `
+		SELECT
+			EXISTS(SELECT 1 FROM user_totp WHERE user_id = $1 AND confirmed_at IS NOT NULL),
+			EXISTS(SELECT 1 FROM user_totp WHERE user_id = $2 AND confirmed_at IS NOT NULL)
+	`
+	if err := tx.QueryRowContext(ctx, query, sourceId, targetId).Scan(&sourceEnabled, &targetEnabled); err != nil {
+		return err
+	}
+	if !sourceEnabled || targetEnabled {
+		return nil
+	}
+
+	for _, query := range []string{
+		`DELETE FROM user_recovery_codes WHERE user_id = $1`,
+		`DELETE FROM user_totp WHERE user_id = $1`,
+	} {
+		if _, err := tx.ExecContext(ctx, query, targetId); err != nil {
+			return err
+		}
+	}
+
+	for _, query := range []string{
+		`UPDATE user_totp SET user_id = $1 WHERE user_id = $2`,
+		`UPDATE user_recovery_codes SET user_id = $1 WHERE user_id = $2`,
+	} {
+		if _, err := tx.ExecContext(ctx, query, targetId, sourceId); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (r *PostgresRepository) CreateSession(ctx context.Context, session *domain.Session) error {
@@ -326,7 +583,7 @@ func (r *PostgresRepository) CreateSession(ctx context.Context, session *domain.
 		query,
 		session.ID,
 		session.UserID,
-		session.RefreshToken,
+		session.RefreshTokenHash,
 		session.UserAgent,
 		session.ClientIP,
 		session.ExpiresAt,
@@ -340,7 +597,7 @@ func (r *PostgresRepository) CreateSession(ctx context.Context, session *domain.
 
 func (r *PostgresRepository) GetSessionById(ctx context.Context, id uuid.UUID) (*domain.Session, error) {
 	query := `
-		SELECT id, user_id, refresh_token, user_agent, client_ip, expires_at, created_at
+		SELECT id, user_id, refresh_token, user_agent, client_ip, expires_at, created_at, last_used_at
 		FROM sessions
 		WHERE id = $1
 	`
@@ -357,15 +614,15 @@ func (r *PostgresRepository) GetSessionById(ctx context.Context, id uuid.UUID) (
 	return &session, nil
 }
 
-func (r *PostgresRepository) GetSessionByToken(ctx context.Context, token string) (*domain.Session, error) {
+func (r *PostgresRepository) GetSessionByTokenHash(ctx context.Context, tokenHash string) (*domain.Session, error) {
 	query := `
-		SELECT id, user_id, refresh_token, user_agent, client_ip, expires_at, created_at
+		SELECT id, user_id, refresh_token, user_agent, client_ip, expires_at, created_at, last_used_at
 		FROM sessions
 		WHERE refresh_token = $1
 	`
 
 	var session domain.Session
-	err := r.db.GetContext(ctx, &session, query, token)
+	err := r.db.GetContext(ctx, &session, query, tokenHash)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrNotFound
@@ -376,6 +633,49 @@ func (r *PostgresRepository) GetSessionByToken(ctx context.Context, token string
 	return &session, nil
 }
 
+func (r *PostgresRepository) GetSessionsByUser(ctx context.Context, userId uuid.UUID) ([]*domain.Session, error) {
+	query := `
+		SELECT id, user_id, refresh_token, user_agent, client_ip, expires_at, created_at, last_used_at
+		FROM sessions
+		WHERE user_id = $1 AND expires_at > NOW()
+		ORDER BY COALESCE(last_used_at, created_at) DESC
+	`
+
+	sessions := []*domain.Session{}
+	if err := r.db.SelectContext(ctx, &sessions, query, userId); err != nil {
+		log.Error().Err(err).Str("user_id", userId.String()).Msg("failed to get user sessions")
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (r *PostgresRepository) RotateSession(ctx context.Context, session *domain.Session, previousHash string) error {
+	query := `
+		UPDATE sessions
+		SET refresh_token = $1, user_agent = $2, client_ip = $3, expires_at = $4, last_used_at = $5
+		WHERE id = $6 AND refresh_token = $7
+	`
+
+	now := time.Now()
+	result, err := r.db.ExecContext(ctx, query, session.RefreshTokenHash, session.UserAgent, session.ClientIP,
+		session.ExpiresAt, now, session.ID, previousHash)
+	if err != nil {
+		log.Error().Err(err).Str("session_id", session.ID.String()).Msg("failed to rotate session")
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get rows affected")
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	session.LastUsedAt = &now
+	return nil
+}
+
 func (r *PostgresRepository) DeleteSession(ctx context.Context, id uuid.UUID) error {
 	query := `DELETE FROM sessions
 			WHERE id = $1`
@@ -397,8 +697,28 @@ func (r *PostgresRepository) DeleteSession(ctx context.Context, id uuid.UUID) er
 	return nil
 }
 
+func (r *PostgresRepository) DeleteUserSession(ctx context.Context, userId, id uuid.UUID) error {
+	query := `DELETE FROM sessions WHERE id = $1 AND user_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, id, userId)
+	if err != nil {
+		log.Error().Err(err).Str("session_id", id.String()).Msg("failed to delete user session")
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get rows affected")
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
 func (r *PostgresRepository) DeleteUserSessions(ctx context.Context, userId uuid.UUID) error {
-	query := `DELETE FROM users WHERE user_id = $1`
+	query := `DELETE FROM sessions WHERE user_id = $1`
 
 	_, err := r.db.ExecContext(ctx, query, userId)
 
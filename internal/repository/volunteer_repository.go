@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"cv_builder/internal/domain"
+	"database/sql"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+type volunteerRow struct {
+	ID           uuid.UUID      `db:"id"`
+	Organization string         `db:"organization"`
+	Role         string         `db:"role"`
+	Location     sql.NullString `db:"location"`
+	StartDate    time.Time      `db:"start_date"`
+	EndDate      *time.Time     `db:"end_date"`
+	IsCurrent    bool           `db:"is_current"`
+	Description  sql.NullString `db:"description"`
+	URL          sql.NullString `db:"url"`
+}
+
+func (row volunteerRow) toDomain() *domain.Volunteer {
+	volunteer := &domain.Volunteer{
+		ID:           row.ID,
+		Organization: row.Organization,
+		Role:         row.Role,
+		Location:     row.Location.String,
+		StartDate:    row.StartDate.Format("2006-01-02"),
+		Description:  row.Description.String,
+		URL:          row.URL.String,
+	}
+	if row.IsCurrent {
+		volunteer.EndDate = "Present"
+	} else if row.EndDate != nil {
+		volunteer.EndDate = row.EndDate.Format("2006-01-02")
+	}
+	return volunteer
+}
+
+// volunteerDates converts the dates into column values; "Present" is stored
+// as a missing end date with is_current set
+func volunteerDates(volunteer *domain.Volunteer) (time.Time, *time.Time, bool, error) {
+	startDate, err := time.Parse("2006-01-02", volunteer.StartDate)
+	if err != nil {
+		return time.Time{}, nil, false, err
+	}
+	var endDate *time.Time
+	if volunteer.EndDate != "" && volunteer.EndDate != "Present" {
+		parsed, err := time.Parse("2006-01-02", volunteer.EndDate)
+		if err != nil {
+			return time.Time{}, nil, false, err
+		}
+		endDate = &parsed
+	}
+	return startDate, endDate, volunteer.EndDate == "Present", nil
+}
+
+func (r *PostgresCVRepository) AddVolunteer(ctx context.Context, resumeId uuid.UUID, volunteer *domain.Volunteer) (uuid.UUID, error) {
+	query := `
+		INSERT INTO volunteer (
+			id, resume_id, organization, role, location, start_date, end_date,
+			is_current, description, url, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id
+	`
+
+	volunteer.BeforeSave()
+
+	if err := volunteer.Validate(); err != nil {
+		return uuid.Nil, err
+	}
+
+	startDate, endDate, isCurrent, err := volunteerDates(volunteer)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	id := uuid.New()
+	now := time.Now()
+
+	var returnedID uuid.UUID
+	err = r.db.QueryRowContext(
+		ctx,
+		query,
+		id,
+		resumeId,
+		volunteer.Organization,
+		volunteer.Role,
+		sql.NullString{String: volunteer.Location, Valid: volunteer.Location != ""},
+		startDate,
+		endDate,
+		isCurrent,
+		sql.NullString{String: volunteer.Description, Valid: volunteer.Description != ""},
+		sql.NullString{String: volunteer.URL, Valid: volunteer.URL != ""},
+		now,
+		now,
+	).Scan(&returnedID)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to add volunteer work")
+		return uuid.Nil, err
+	}
+
+	volunteer.ID = returnedID
+	return returnedID, nil
+}
+
+func (r *PostgresCVRepository) UpdateVolunteer(ctx context.Context, resumeId, id uuid.UUID, volunteer *domain.Volunteer) error {
+	query := `
+		UPDATE volunteer
+		SET organization = $1,
+			role = $2,
+			location = $3,
+			start_date = $4,
+			end_date = $5,
+			is_current = $6,
+			description = $7,
+			url = $8,
+			updated_at = $9
+		WHERE id = $10 AND resume_id = $11
+	`
+
+	volunteer.BeforeSave()
+
+	if err := volunteer.Validate(); err != nil {
+		return err
+	}
+
+	startDate, endDate, isCurrent, err := volunteerDates(volunteer)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(
+		ctx,
+		query,
+		volunteer.Organization,
+		volunteer.Role,
+		sql.NullString{String: volunteer.Location, Valid: volunteer.Location != ""},
+		startDate,
+		endDate,
+		isCurrent,
+		sql.NullString{String: volunteer.Description, Valid: volunteer.Description != ""},
+		sql.NullString{String: volunteer.URL, Valid: volunteer.URL != ""},
+		time.Now(),
+		id,
+		resumeId,
+	)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to update volunteer work")
+		return err
+	}
+
+	return requireRowsAffected(result)
+}
+
+func (r *PostgresCVRepository) DeleteVolunteer(ctx context.Context, resumeId, id uuid.UUID) error {
+	query := `
+		DELETE FROM volunteer
+		WHERE id = $1 AND resume_id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, resumeId)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to delete volunteer work")
+		return err
+	}
+
+	return requireRowsAffected(result)
+}
+
+// GetVolunteerByResume lists volunteer work, ongoing and most recent first
+func (r *PostgresCVRepository) GetVolunteerByResume(ctx context.Context, resumeId uuid.UUID) ([]*domain.Volunteer, error) {
+	query := `
+		SELECT id, organization, role, location, start_date, end_date, is_current, description, url
+		FROM volunteer
+		WHERE resume_id = $1
+		ORDER BY is_current DESC, start_date DESC
+	`
+
+	var rows []volunteerRow
+	err := r.db.SelectContext(ctx, &rows, query, resumeId)
+	if err != nil {
+		log.Error().Err(err).Str("resume_id", resumeId.String()).Msg("failed to get volunteer work by resume")
+		return nil, err
+	}
+
+	volunteer := make([]*domain.Volunteer, len(rows))
+	for i, row := range rows {
+		volunteer[i] = row.toDomain()
+	}
+	return volunteer, nil
+}
@@ -1,23 +1,32 @@
 package routes
 
 import (
+	"cv_builder/config"
+	"cv_builder/internal/analysis"
 	"cv_builder/internal/handler"
 	"cv_builder/internal/repository"
 	"cv_builder/internal/service"
 	"cv_builder/pkg/auth"
+	"cv_builder/pkg/mail"
 	"cv_builder/pkg/security"
+	"cv_builder/pkg/storage"
 	"github.com/jmoiron/sqlx"
 	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
 	"net/http"
 	"time"
 )
 
-func SetupRoutes(db *sqlx.DB, redisClient *redis.Client, jwtConfig auth.JWTConfig) http.Handler {
+func SetupRoutes(cfg *config.Config, db *sqlx.DB, redisClient *redis.Client, jwtConfig auth.JWTConfig, store storage.BlobStore, mailer mail.Mailer) http.Handler {
 	corsMiddleware := security.CORSMiddleware(security.DefaultCORSConfig())
 	mux := http.NewServeMux()
 
 	userRepo := repository.NewPostgresUserRepository(db)
 	resumeRepo := repository.NewPostgresCVRepository(db)
+	templateRepo := repository.NewPostgresTemplateRepository(db)
+	mfaRepo := repository.NewPostgresMFARepository(db)
+	passkeyRepo := repository.NewPostgresPasskeyRepository(db)
+	identityRepo := repository.NewPostgresIdentityRepository(db)
 
 	jwtHandler := auth.NewJWT(jwtConfig)
 
@@ -25,17 +34,66 @@ func SetupRoutes(db *sqlx.DB, redisClient *redis.Client, jwtConfig auth.JWTConfi
 		AccessTokenExpiry:  jwtConfig.AccessTokenExpiry,
 		RefreshTokenExpiry: jwtConfig.RefreshTokenExpiry,
 		ResetTokenExpiry:   jwtConfig.ResetTokenExpiry,
+
+		VerificationTokenExpiry: jwtConfig.VerificationTokenExpiry,
+		AppURL:                  cfg.AppURL,
+		RequireVerifiedEmail:    cfg.RequireVerifiedEmail,
+		TelegramBotToken:        cfg.TelegramBotToken,
+	}
+
+	encryptor, err := security.NewEncryptor(cfg.EncryptionKey)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to set up encryption")
 	}
+	mfaAttempts := security.NewAttemptLimiter(redisClient, "mfa", 5, 15*time.Minute)
+	mfaService := service.NewMFAService(mfaRepo, userRepo, encryptor, mfaAttempts, "CV Builder")
+	// Revoked access tokens may pass on other instances for the cache TTL
+	tokenDenylist := security.NewTokenDenylist(redisClient, 10*time.Second)
 
-	authService := service.NewAuthService(userRepo, jwtHandler, authServiceConfig)
+	passkeyService, err := service.NewPasskeyService(passkeyRepo, userRepo, repository.NewRedisCeremonyStore(redisClient), service.PasskeyConfig{
+		RPID:          cfg.WebAuthnRPID,
+		RPDisplayName: "CV Builder",
+		RPOrigins:     cfg.WebAuthnOrigins,
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to set up passkeys")
+	}
+
+	var oauthProviders []*auth.OAuthProvider
+	for name, providerConfig := range cfg.OAuthProviders {
+		provider, err := auth.NewOAuthProvider(name, auth.OAuthConfig(providerConfig))
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to set up login provider")
+		}
+		oauthProviders = append(oauthProviders, provider)
+	}
+	oauthService := service.NewOAuthService(identityRepo, userRepo, passkeyRepo, repository.NewRedisOAuthStateStore(redisClient), oauthProviders)
+
+	authService := service.NewAuthService(userRepo, jwtHandler, mailer, mfaService, passkeyService, oauthService, tokenDenylist, authServiceConfig)
+	accountService := service.NewAccountService(userRepo, identityRepo, passkeyRepo, authService, mfaService)
+	resumeService := service.NewResumeService(resumeRepo)
+	templateService := service.NewTemplateService(templateRepo, resumeService)
 
 	authMiddleware := handler.NewAuthMiddleware(authService)
 	sessionLogger := handler.NewSessionLogger()
 
 	authHandler := handler.NewAuthHandler(authService, redisClient)
+	mfaHandler := handler.NewMFAHandler(mfaService)
+	passkeyHandler := handler.NewPasskeyHandler(passkeyService)
+	identityHandler := handler.NewIdentityHandler(oauthService)
+	accountHandler := handler.NewAccountHandler(accountService)
+	sessionHandler := handler.NewSessionHandler(authService)
 	userHandler := handler.NewUserHandler(userRepo, resumeRepo)
-	resumeHandler := handler.NewResumeHandler(resumeRepo)
+	resumeHandler := handler.NewResumeHandler(resumeRepo, templateService, store)
 	adminHandler := handler.NewAdminHandler(userRepo)
+	exportHandler := handler.NewExportHandler(resumeRepo, store)
+	importHandler := handler.NewImportHandler(resumeService)
+	customSectionHandler := handler.NewCustomSectionHandler(resumeRepo)
+	photoHandler := handler.NewPhotoHandler(resumeRepo, userRepo, store)
+	sectionHandler := handler.NewSectionHandler(resumeRepo)
+	translationHandler := handler.NewTranslationHandler(resumeRepo)
+	templateHandler := handler.NewTemplateHandler(templateService)
+	analysisHandler := handler.NewAnalysisHandler(resumeRepo, analysis.NewEngine(analysis.DefaultRules()...))
 
 	// Public routes
 	mux.HandleFunc("GET /api/v1/health", func(w http.ResponseWriter, r *http.Request) {
@@ -44,23 +102,72 @@ func SetupRoutes(db *sqlx.DB, redisClient *redis.Client, jwtConfig auth.JWTConfi
 			"time":   time.Now().Format(time.RFC3339),
 		})
 	})
+	mux.HandleFunc("GET /.well-known/jwks.json", authHandler.JWKSHandler)
 	mux.HandleFunc("POST /api/v1/register", authHandler.RegisterHandler)
 	mux.HandleFunc("POST /api/v1/login", authHandler.LoginHandler)
+	mux.HandleFunc("POST /api/v1/login/2fa", authHandler.LoginMFAHandler)
+	mux.HandleFunc("POST /api/v1/login/passkey/begin", authHandler.BeginPasskeyLoginHandler)
+	mux.HandleFunc("POST /api/v1/login/passkey/finish", authHandler.LoginPasskeyHandler)
 	mux.HandleFunc("POST /api/v1/refresh-token", authHandler.RefreshTokenHandler)
 	mux.HandleFunc("POST /api/v1/logout", authHandler.LogoutHandler)
 	mux.HandleFunc("POST /api/v1/request-password-reset", authHandler.RequestPasswordResetHandler)
 	mux.HandleFunc("POST /api/v1/reset-password", authHandler.ResetPasswordHandler)
+	mux.HandleFunc("POST /api/v1/verify-email", authHandler.VerifyEmailHandler)
 	mux.HandleFunc("POST /api/v1/auth/telegram", authHandler.LoginTelegram)
+	mux.HandleFunc("GET /api/v1/auth/oauth/providers", authHandler.OAuthProvidersHandler)
+	mux.HandleFunc("POST /api/v1/auth/oauth/{provider}/begin", authHandler.BeginOAuthLoginHandler)
+	mux.HandleFunc("POST /api/v1/auth/oauth/{provider}/callback", authHandler.OAuthCallbackHandler)
 
 	// User profile route
 	mux.Handle("GET /api/v1/user/profile", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(userHandler.GetProfileHandler))))
+	mux.Handle("POST /api/v1/resend-verification", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(authHandler.ResendVerificationHandler))))
+
+	// Signed in devices
+	mux.Handle("GET /api/v1/sessions", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(sessionHandler.GetSessionsHandler))))
+	mux.Handle("DELETE /api/v1/sessions", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(sessionHandler.RevokeAllSessionsHandler))))
+	mux.Handle("DELETE /api/v1/sessions/{id}", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(sessionHandler.RevokeSessionHandler))))
 
-	// Admin route
+	// Two-factor authentication routes
+	mux.Handle("GET /api/v1/2fa", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(mfaHandler.GetStatusHandler))))
+	mux.Handle("POST /api/v1/2fa/totp/setup", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(mfaHandler.SetupTOTPHandler))))
+	mux.Handle("POST /api/v1/2fa/totp/confirm", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(mfaHandler.ConfirmTOTPHandler))))
+	mux.Handle("POST /api/v1/2fa/totp/disable", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(mfaHandler.DisableTOTPHandler))))
+	mux.Handle("POST /api/v1/2fa/recovery-codes", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(mfaHandler.RegenerateRecoveryCodesHandler))))
+
+	// Passkeys
+	mux.Handle("GET /api/v1/passkeys", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(passkeyHandler.GetPasskeysHandler))))
+	mux.Handle("POST /api/v1/passkeys/register/begin", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(passkeyHandler.BeginRegistrationHandler))))
+	mux.Handle("POST /api/v1/passkeys/register/finish", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(passkeyHandler.FinishRegistrationHandler))))
+	mux.Handle("PATCH /api/v1/passkeys/{id}", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(passkeyHandler.RenamePasskeyHandler))))
+	mux.Handle("DELETE /api/v1/passkeys/{id}", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(passkeyHandler.DeletePasskeyHandler))))
+
+	// Accounts at identity providers
+	mux.Handle("GET /api/v1/identities", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(identityHandler.GetIdentitiesHandler))))
+	mux.Handle("DELETE /api/v1/identities/{id}", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(identityHandler.UnlinkIdentityHandler))))
+
+	// Linking logins and merging accounts
+	mux.Handle("POST /api/v1/account/telegram", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(accountHandler.LinkTelegramHandler))))
+	mux.Handle("DELETE /api/v1/account/telegram", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(accountHandler.UnlinkTelegramHandler))))
+	mux.Handle("POST /api/v1/account/email", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(accountHandler.LinkEmailHandler))))
+	mux.Handle("DELETE /api/v1/account/email", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(accountHandler.UnlinkEmailHandler))))
+	mux.Handle("POST /api/v1/account/merge", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(accountHandler.MergeAccountHandler))))
+
+	// Admin routes
 	mux.Handle("GET /api/v1/admin/users", sessionLogger.LogActivity(authMiddleware.AuthRequired(authMiddleware.RequireRole("admin")(http.HandlerFunc(adminHandler.GetUsersHandler)))))
+	mux.Handle("POST /api/v1/admin/templates", sessionLogger.LogActivity(authMiddleware.AuthRequired(authMiddleware.RequireRole("admin")(http.HandlerFunc(templateHandler.CreateTemplateHandler)))))
+	mux.Handle("PUT /api/v1/admin/templates/{slug}", sessionLogger.LogActivity(authMiddleware.AuthRequired(authMiddleware.RequireRole("admin")(http.HandlerFunc(templateHandler.UpdateTemplateHandler)))))
+	mux.Handle("DELETE /api/v1/admin/templates/{slug}", sessionLogger.LogActivity(authMiddleware.AuthRequired(authMiddleware.RequireRole("admin")(http.HandlerFunc(templateHandler.DeleteTemplateHandler)))))
+
+	// Resume template catalog
+	mux.Handle("GET /api/v1/templates", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(templateHandler.GetTemplatesHandler))))
+	mux.Handle("GET /api/v1/templates/{slug}", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(templateHandler.GetTemplateHandler))))
 
 	// Resume routes
 	mux.Handle("GET /api/v1/resumes", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(resumeHandler.GetResumeListHandler))))
 	mux.Handle("GET /api/v1/resumes/{id}", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(resumeHandler.GetResumeHandler))))
+	mux.Handle("POST /api/v1/resumes/import", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(importHandler.ImportResumeHandler))))
+	mux.Handle("POST /api/v1/resumes/import/preview", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(importHandler.PreviewImportHandler))))
+	mux.Handle("POST /api/v1/resumes/import/commit", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(importHandler.CommitImportHandler))))
 	mux.Handle("POST /api/v1/resumes", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(resumeHandler.CreateResumeHandler))))
 	mux.Handle("DELETE /api/v1/resumes/{id}", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(resumeHandler.DeleteResumeHandler))))
 	mux.Handle("GET /api/v1/resumes/{id}/personal-info", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(resumeHandler.GetPersonalInfoHandler))))
@@ -74,12 +181,55 @@ func SetupRoutes(db *sqlx.DB, redisClient *redis.Client, jwtConfig auth.JWTConfi
 	mux.Handle("GET /api/v1/resumes/{id}/skills", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(resumeHandler.GetSkillsHandler))))
 	mux.Handle("POST /api/v1/resumes/{id}/skills", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(resumeHandler.AddSkillHandler))))
 	mux.Handle("DELETE /api/v1/resumes/{id}/skills/{skillId}", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(resumeHandler.DeleteSkillHandler))))
+	mux.Handle("GET /api/v1/resumes/{id}/languages", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(resumeHandler.GetLanguagesHandler))))
+	mux.Handle("POST /api/v1/resumes/{id}/languages", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(resumeHandler.AddLanguageHandler))))
+	mux.Handle("DELETE /api/v1/resumes/{id}/languages/{languageId}", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(resumeHandler.DeleteLanguageHandler))))
 	mux.Handle("GET /api/v1/resumes/{id}/projects", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(resumeHandler.GetProjectsHandler))))
 	mux.Handle("POST /api/v1/resumes/{id}/projects", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(resumeHandler.AddProjectHandler))))
 	mux.Handle("DELETE /api/v1/resumes/{id}/projects/{projectId}", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(resumeHandler.DeleteProjectHandler))))
 	mux.Handle("GET /api/v1/resumes/{id}/certifications", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(resumeHandler.GetCertificationsHandler))))
 	mux.Handle("POST /api/v1/resumes/{id}/certifications", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(resumeHandler.AddCertificationHandler))))
 	mux.Handle("DELETE /api/v1/resumes/{id}/certifications/{certificationId}", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(resumeHandler.DeleteCertificationHandler))))
+	mux.Handle("GET /api/v1/resumes/{id}/volunteer", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(sectionHandler.GetVolunteerHandler))))
+	mux.Handle("POST /api/v1/resumes/{id}/volunteer", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(sectionHandler.AddVolunteerHandler))))
+	mux.Handle("PUT /api/v1/resumes/{id}/volunteer/{volunteerId}", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(sectionHandler.UpdateVolunteerHandler))))
+	mux.Handle("DELETE /api/v1/resumes/{id}/volunteer/{volunteerId}", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(sectionHandler.DeleteVolunteerHandler))))
+	mux.Handle("GET /api/v1/resumes/{id}/awards", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(sectionHandler.GetAwardsHandler))))
+	mux.Handle("POST /api/v1/resumes/{id}/awards", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(sectionHandler.AddAwardHandler))))
+	mux.Handle("PUT /api/v1/resumes/{id}/awards/{awardId}", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(sectionHandler.UpdateAwardHandler))))
+	mux.Handle("DELETE /api/v1/resumes/{id}/awards/{awardId}", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(sectionHandler.DeleteAwardHandler))))
+	mux.Handle("GET /api/v1/resumes/{id}/references", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(sectionHandler.GetReferencesHandler))))
+	mux.Handle("POST /api/v1/resumes/{id}/references", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(sectionHandler.AddReferenceHandler))))
+	mux.Handle("PUT /api/v1/resumes/{id}/references/{referenceId}", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(sectionHandler.UpdateReferenceHandler))))
+	mux.Handle("DELETE /api/v1/resumes/{id}/references/{referenceId}", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(sectionHandler.DeleteReferenceHandler))))
+
+	mux.Handle("PUT /api/v1/resumes/{id}/photo", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(photoHandler.UploadPhotoHandler))))
+	mux.Handle("GET /api/v1/resumes/{id}/photo", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(photoHandler.GetPhotoHandler))))
+	mux.Handle("DELETE /api/v1/resumes/{id}/photo", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(photoHandler.DeletePhotoHandler))))
+
+	mux.Handle("GET /api/v1/resumes/{id}/translations", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(translationHandler.GetTranslationsHandler))))
+	mux.Handle("GET /api/v1/resumes/{id}/translations/{locale}", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(translationHandler.GetTranslationHandler))))
+	mux.Handle("PUT /api/v1/resumes/{id}/translations/{locale}", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(translationHandler.SaveTranslationHandler))))
+	mux.Handle("DELETE /api/v1/resumes/{id}/translations/{locale}", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(translationHandler.DeleteTranslationHandler))))
+	mux.Handle("PUT /api/v1/resumes/{id}/default-locale", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(translationHandler.SetDefaultLocaleHandler))))
+
+	mux.Handle("GET /api/v1/resumes/{id}/custom-sections", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(customSectionHandler.GetCustomSectionsHandler))))
+	mux.Handle("POST /api/v1/resumes/{id}/custom-sections", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(customSectionHandler.AddCustomSectionHandler))))
+	mux.Handle("PUT /api/v1/resumes/{id}/custom-sections/order", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(customSectionHandler.ReorderCustomSectionsHandler))))
+	mux.Handle("GET /api/v1/resumes/{id}/custom-sections/{sectionId}", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(customSectionHandler.GetCustomSectionHandler))))
+	mux.Handle("PUT /api/v1/resumes/{id}/custom-sections/{sectionId}", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(customSectionHandler.UpdateCustomSectionHandler))))
+	mux.Handle("DELETE /api/v1/resumes/{id}/custom-sections/{sectionId}", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(customSectionHandler.DeleteCustomSectionHandler))))
+	mux.Handle("POST /api/v1/resumes/{id}/custom-sections/{sectionId}/entries", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(customSectionHandler.AddCustomSectionEntryHandler))))
+	mux.Handle("PUT /api/v1/resumes/{id}/custom-sections/{sectionId}/entries/order", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(customSectionHandler.ReorderCustomSectionEntriesHandler))))
+	mux.Handle("PUT /api/v1/resumes/{id}/custom-sections/{sectionId}/entries/{entryId}", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(customSectionHandler.UpdateCustomSectionEntryHandler))))
+	mux.Handle("DELETE /api/v1/resumes/{id}/custom-sections/{sectionId}/entries/{entryId}", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(customSectionHandler.DeleteCustomSectionEntryHandler))))
+
+	// Documents leave the service through exports, so unverified accounts
+	// cannot export when REQUIRE_VERIFIED_EMAIL is set
+	mux.Handle("GET /api/v1/resumes/{id}/export", sessionLogger.LogActivity(authMiddleware.AuthRequired(authMiddleware.VerifiedEmailRequired(http.HandlerFunc(exportHandler.ExportResumeHandler)))))
+	mux.Handle("GET /api/v1/resumes/{id}/score", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(analysisHandler.ScoreResumeHandler))))
+	mux.Handle("GET /api/v1/resumes/{id}/timeline", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(analysisHandler.TimelineHandler))))
+	mux.Handle("GET /api/v1/resumes/{id}/lint", sessionLogger.LogActivity(authMiddleware.AuthRequired(http.HandlerFunc(analysisHandler.LintResumeHandler))))
 
 	// Wrap the entire router with CORS middleware
 	handlerWithCORS := corsMiddleware(mux)
@@ -0,0 +1,286 @@
+package service
+
+import (
+	"context"
+	"cv_builder/internal/domain"
+	"cv_builder/internal/repository"
+	"cv_builder/pkg/security"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	ErrTelegramInUse         = errors.New("telegram account belongs to another user")
+	ErrTelegramAlreadyLinked = errors.New("another telegram account is linked")
+	ErrTelegramNotLinked     = errors.New("no telegram account is linked")
+	ErrEmailAlreadyLinked    = errors.New("account already has an email login")
+	ErrEmailNotLinked        = errors.New("account has no email login")
+	ErrMergeSameUser         = errors.New("cannot merge an account into itself")
+)
+
+// AccountService links and unlinks the ways a user signs in, and merges
+// accounts that turn out to belong to the same person
+type AccountService struct {
+	userRepo     domain.UserRepository
+	identityRepo domain.UserIdentityRepository
+	passkeyRepo  domain.PasskeyRepository
+	auth         *AuthService
+	mfa          *MFAService
+}
+
+func NewAccountService(userRepo domain.UserRepository, identityRepo domain.UserIdentityRepository, passkeyRepo domain.PasskeyRepository, authService *AuthService, mfa *MFAService) *AccountService {
+	return &AccountService{
+		userRepo:     userRepo,
+		identityRepo: identityRepo,
+		passkeyRepo:  passkeyRepo,
+		auth:         authService,
+		mfa:          mfa,
+	}
+}
+
+// loginMethods are the ways a user can sign in
+type loginMethods struct {
+	password   bool
+	telegram   bool
+	identities int
+	passkeys   int
+}
+
+func (m *loginMethods) count() int {
+	count := m.identities + m.passkeys
+	if m.password {
+		count++
+	}
+	if m.telegram {
+		count++
+	}
+	return count
+}
+
+func getLoginMethods(ctx context.Context, userRepo domain.UserRepository, identityRepo domain.UserIdentityRepository, passkeyRepo domain.PasskeyRepository, userId uuid.UUID) (*loginMethods, error) {
+	user, err := userRepo.GetUserById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	telegramUser, err := userRepo.GetTelegramUserById(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	identities, err := identityRepo.GetIdentitiesByUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	passkeys, err := passkeyRepo.GetPasskeysByUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &loginMethods{
+		password:   user.PasswordHash != "",
+		telegram:   telegramUser.TelegramID != nil,
+		identities: len(identities),
+		passkeys:   len(passkeys),
+	}, nil
+}
+
+// LinkTelegram adds the Telegram account of the Mini App init data. A
+// Telegram account that already has its own user has to be merged instead.
+func (s *AccountService) LinkTelegram(ctx context.Context, userId uuid.UUID, initData string) error {
+	data, err := s.auth.parseTelegramInitData(initData)
+	if err != nil {
+		return err
+	}
+
+	telegramUser, err := s.userRepo.GetTelegramUserById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	if telegramUser.TelegramID != nil {
+		if *telegramUser.TelegramID == data.User.ID {
+			return nil
+		}
+		return ErrTelegramAlreadyLinked
+	}
+
+	if err := s.userRepo.LinkTelegram(ctx, userId, data.User); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return ErrTelegramInUse
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *AccountService) UnlinkTelegram(ctx context.Context, userId uuid.UUID) error {
+	methods, err := getLoginMethods(ctx, s.userRepo, s.identityRepo, s.passkeyRepo, userId)
+	if err != nil {
+		return err
+	}
+	if !methods.telegram {
+		return ErrTelegramNotLinked
+	}
+	if methods.count() <= 1 {
+		return ErrLastLoginMethod
+	}
+
+	return s.userRepo.UnlinkTelegram(ctx, userId)
+}
+
+// LinkEmail adds an email and password login to an account that has none,
+// such as a Telegram account. A new address has to be verified.
+func (s *AccountService) LinkEmail(ctx context.Context, userId uuid.UUID, email, password string) error {
+	user, err := s.userRepo.GetUserById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	if user.PasswordHash != "" {
+		return ErrEmailAlreadyLinked
+	}
+
+	passwordHash, err := security.HashPassword(password, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to hash pwd")
+		return err
+	}
+
+	user.Email = email
+	user.PasswordHash = passwordHash
+	if err := s.userRepo.UpdateUser(ctx, user); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return ErrUserAlreadyExists
+		}
+		return err
+	}
+
+	if !user.IsEmailVerified() {
+		if err := s.auth.sendEmailVerification(ctx, user); err != nil {
+			log.Error().Err(err).Str("user_id", user.ID.String()).Msg("failed to send email verification")
+		}
+	}
+	return nil
+}
+
+// UnlinkEmail removes the email address and password of the account
+func (s *AccountService) UnlinkEmail(ctx context.Context, userId uuid.UUID) error {
+	methods, err := getLoginMethods(ctx, s.userRepo, s.identityRepo, s.passkeyRepo, userId)
+	if err != nil {
+		return err
+	}
+	if !methods.password {
+		return ErrEmailNotLinked
+	}
+	if methods.count() <= 1 {
+		return ErrLastLoginMethod
+	}
+
+	user, err := s.userRepo.GetUserById(ctx, userId)
+	if err != nil {
+		return err
+	}
+	user.Email = ""
+	user.PasswordHash = ""
+	return s.userRepo.UpdateUser(ctx, user)
+}
+
+// MergeWithPassword merges the account with the given email login into the
+// user. The password, and the second factor if the account has one, prove
+// that the user owns that account.
+func (s *AccountService) MergeWithPassword(ctx context.Context, userId uuid.UUID, email, password, code string) error {
+	source, err := s.userRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrInvalidCredentials
+		}
+		return err
+	}
+	if source.PasswordHash == "" {
+		return ErrInvalidCredentials
+	}
+
+	valid, err := security.VerifyPassword(password, source.PasswordHash)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to verify pwd")
+		return err
+	}
+	if !valid {
+		return ErrInvalidCredentials
+	}
+
+	if err := s.verifySecondFactor(ctx, source.ID, code); err != nil {
+		return err
+	}
+
+	return s.merge(ctx, userId, source.ID)
+}
+
+// MergeWithTelegram merges the account of the Telegram user into the user.
+// The init data, and the second factor if the account has one, prove that
+// the user owns that account.
+func (s *AccountService) MergeWithTelegram(ctx context.Context, userId uuid.UUID, initData, code string) error {
+	data, err := s.auth.parseTelegramInitData(initData)
+	if err != nil {
+		return err
+	}
+
+	source, err := s.userRepo.GetUserByTelegramID(ctx, data.User.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+
+	if err := s.verifySecondFactor(ctx, source.ID, code); err != nil {
+		return err
+	}
+
+	return s.merge(ctx, userId, source.ID)
+}
+
+// verifySecondFactor checks the code of an account that has two-factor
+// authentication enabled
+func (s *AccountService) verifySecondFactor(ctx context.Context, userId uuid.UUID, code string) error {
+	mfaEnabled, err := s.mfa.IsEnabled(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if !mfaEnabled {
+		return nil
+	}
+	return s.mfa.VerifyCode(ctx, userId, code)
+}
+
+func (s *AccountService) merge(ctx context.Context, targetId, sourceId uuid.UUID) error {
+	if targetId == sourceId {
+		return ErrMergeSameUser
+	}
+
+	if err := s.userRepo.MergeUsers(ctx, targetId, sourceId); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+
+	// The sessions moved to the target, tokens naming the deleted user have
+	// to be refreshed
+	if err := s.auth.RevokeUserTokens(ctx, sourceId); err != nil {
+		log.Error().Err(err).Str("user_id", sourceId.String()).Msg("failed to revoke tokens of merged user")
+	}
+
+	log.Info().Str("user_id", targetId.String()).Str("merged_user_id", sourceId.String()).Msg("accounts merged")
+	return nil
+}
@@ -2,14 +2,20 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
 	"cv_builder/internal/domain"
 	"cv_builder/internal/repository"
 	"cv_builder/pkg/auth"
+	"cv_builder/pkg/mail"
 	"cv_builder/pkg/security"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"github.com/go-webauthn/webauthn/protocol"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 	tgInitData "github.com/telegram-mini-apps/init-data-golang"
+	"net/url"
 	"time"
 )
 
@@ -20,8 +26,15 @@ var (
 	ErrInvalidToken         = errors.New("invalid token")
 	ErrExpiredToken         = errors.New("token expired")
 	ErrInvalidSession       = errors.New("invalid session")
+	ErrSessionNotFound      = errors.New("session not found")
+	ErrRefreshTokenReused   = errors.New("refresh token already used")
+	ErrTokenRevoked         = errors.New("token revoked")
 	ErrPasswordResetExpired = errors.New("password reset expired")
 	ErrPasswordResetUsed    = errors.New("password reset already used")
+	ErrEmailAlreadyVerified = errors.New("email already verified")
+	ErrEmailNotVerified     = errors.New("email not verified")
+
+	ErrTelegramNotConfigured = errors.New("telegram login is not configured")
 )
 
 type TokenPair struct {
@@ -29,22 +42,51 @@ type TokenPair struct {
 	RefreshToken string `json:"refresh_token"`
 	ExpiresIn    int64  `json:"expires_in"`
 }
+
+// MFAChallenge is returned by Login instead of a TokenPair when the account
+// has two-factor authentication enabled, see CompleteMFALogin
+type MFAChallenge struct {
+	MFARequired    bool   `json:"mfa_required"`
+	ChallengeToken string `json:"mfa_token"`
+	ExpiresIn      int64  `json:"expires_in"`
+}
+
 type AuthServiceConfig struct {
 	AccessTokenExpiry  time.Duration
 	RefreshTokenExpiry time.Duration
 	ResetTokenExpiry   time.Duration
+	// VerificationTokenExpiry is only shown in the verification email, the
+	// token expiry itself is set by auth.JWTConfig
+	VerificationTokenExpiry time.Duration
+	// AppURL is the frontend base URL that emailed links point to
+	AppURL string
+	// RequireVerifiedEmail restricts accounts that did not verify their email
+	// address, see RequireVerifiedEmail
+	RequireVerifiedEmail bool
+	// TelegramBotToken verifies the init data of Telegram logins
+	TelegramBotToken string
 }
 
 type AuthService struct {
 	userRepo domain.UserRepository
 	jwt      *auth.JWT
+	mailer   mail.Mailer
+	mfa      *MFAService
+	passkeys *PasskeyService
+	oauth    *OAuthService
+	denylist *security.TokenDenylist
 	config   AuthServiceConfig
 }
 
-func NewAuthService(userRepo domain.UserRepository, jwt *auth.JWT, config AuthServiceConfig) *AuthService {
+func NewAuthService(userRepo domain.UserRepository, jwt *auth.JWT, mailer mail.Mailer, mfa *MFAService, passkeys *PasskeyService, oauth *OAuthService, denylist *security.TokenDenylist, config AuthServiceConfig) *AuthService {
 	return &AuthService{
 		userRepo: userRepo,
 		jwt:      jwt,
+		mailer:   mailer,
+		mfa:      mfa,
+		passkeys: passkeys,
+		oauth:    oauth,
+		denylist: denylist,
 		config:   config,
 	}
 }
@@ -80,6 +122,11 @@ func (s *AuthService) Register(ctx context.Context, email, password, role string
 		return nil, err
 	}
 
+	// The account is usable right away, a failed delivery can be resent
+	if err := s.sendEmailVerification(ctx, user); err != nil {
+		log.Error().Err(err).Str("user_id", user.ID.String()).Msg("failed to send email verification")
+	}
+
 	return user, nil
 
 }
@@ -110,25 +157,45 @@ func (s *AuthService) Register(ctx context.Context, email, password, role string
 //	return user, nil
 //}
 
-func (s *AuthService) LoginWithTelegram(ctx context.Context, initData string, userAgent, clientIP string) (*TokenPair, error) {
+// telegramInitDataExpiry is how long init data from a Telegram Mini App is
+// accepted after Telegram signed it
+const telegramInitDataExpiry = 3 * time.Hour
 
-	err := tgInitData.Validate(initData, "OUR_TOKEN", time.Duration(3))
-	if err != nil {
-		return nil, err
+// parseTelegramInitData checks the signature of Mini App init data with the
+// bot token and returns its content
+func (s *AuthService) parseTelegramInitData(initData string) (*tgInitData.InitData, error) {
+	if s.config.TelegramBotToken == "" {
+		return nil, ErrTelegramNotConfigured
+	}
+
+	if err := tgInitData.Validate(initData, s.config.TelegramBotToken, telegramInitDataExpiry); err != nil {
+		log.Info().Err(err).Msg("telegram init data validation failed")
+		return nil, ErrInvalidCredentials
 	}
 
-	// Parse Tg init data
 	parsedData, err := tgInitData.Parse(initData)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to pars tg init data")
 		return nil, ErrInvalidCredentials
 	}
+	return &parsedData, nil
+}
+
+// LoginWithTelegram signs in with Mini App init data, creating an account on
+// the first login. With two-factor authentication enabled it returns a
+// challenge instead of tokens.
+func (s *AuthService) LoginWithTelegram(ctx context.Context, initData string, userAgent, clientIP string) (*TokenPair, *MFAChallenge, error) {
+
+	parsedData, err := s.parseTelegramInitData(initData)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	// Find existing user
 	user, err := s.userRepo.GetUserByTelegramID(ctx, parsedData.User.ID)
 	if err != nil && !errors.Is(err, repository.ErrNotFound) {
 		log.Error().Err(err).Msg("failed to get user by tg Id")
-		return nil, err
+		return nil, nil, err
 	}
 
 	if user == nil {
@@ -137,11 +204,11 @@ func (s *AuthService) LoginWithTelegram(ctx context.Context, initData string, us
 			if errors.Is(err, repository.ErrConflict) {
 				user, err = s.userRepo.GetUserByTelegramID(ctx, parsedData.User.ID)
 				if err != nil {
-					return nil, err
+					return nil, nil, err
 				}
 			} else {
 				log.Error().Err(err).Msg("failed to create tg user")
-				return nil, err
+				return nil, nil, err
 			}
 		}
 	} else {
@@ -158,85 +225,168 @@ func (s *AuthService) LoginWithTelegram(ctx context.Context, initData string, us
 		}
 	}
 
-	// Generate tokens
-	displayName := user.GetDisplayName()
-	  , err := s.jwt.GenerateAccessToken(user.ID.String(), displayName, user.Role)
+	// A linked account may have a password and a second factor, which a
+	// Telegram login must not skip
+	account, err := s.userRepo.GetUserById(ctx, user.ID)
 	if err != nil {
-		log.Error().Err(err).Msg("failed to generate access token")
-		return nil, err
+		return nil, nil, err
 	}
+	return s.startSession(ctx, account, userAgent, clientIP)
+}
+
+// Login checks the password. With two-factor authentication enabled it
+// returns a challenge instead of tokens.
+func (s *AuthService) Login(ctx context.Context, email, password, userAgent, clientIP string) (*TokenPair, *MFAChallenge, error) {
+	user, err := s.userRepo.GetUserByEmail(ctx, email)
 
-	refreshToken, err := s.jwt.GenerateRefreshToken(user.ID.String(), displayName, user.Role)
 	if err != nil {
-		log.Error().Err(err).Msg("failed to generate refresh token")
-		return nil, err
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, nil, ErrInvalidCredentials
+		}
+		return nil, nil, err
 	}
 
-	session := &domain.Session{
-		ID:           uuid.New(),
-		UserID:       user.ID,
-		RefreshToken: refreshToken,
-		UserAgent:    userAgent,
-		ClientIP:     clientIP,
-		ExpiresAt:    time.Now().Add(s.config.RefreshTokenExpiry),
-		CreatedAt:    time.Now(),
+	// Accounts created through an identity provider have no password
+	if user.PasswordHash == "" {
+		return nil, nil, ErrInvalidCredentials
 	}
 
-	if err := s.userRepo.CreateSession(ctx, session); err != nil {
-		log.Error().Err(err).Msg("failed to create session")
-		return nil, err
+	verifyPassword, err := security.VerifyPassword(password, user.PasswordHash)
+
+	if err != nil {
+		log.Error().Err(err).Msg("failed to verify pwd")
+		return nil, nil, err
 	}
 
-	return &TokenPair{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		ExpiresIn:    int64(s.config.AccessTokenExpiry.Seconds()),
-	}, nil
+	if !verifyPassword {
+		return nil, nil, ErrInvalidCredentials
+	}
 
+	return s.startSession(ctx, user, userAgent, clientIP)
 }
 
-func (s *AuthService) Login(ctx context.Context, email, password, userAgent, clientIP string) (*TokenPair, error) {
-	user, err := s.userRepo.GetUserByEmail(ctx, email)
+// startSession issues a token pair, or an MFA challenge when the user has
+// two-factor authentication enabled
+func (s *AuthService) startSession(ctx context.Context, user *domain.User, userAgent, clientIP string) (*TokenPair, *MFAChallenge, error) {
+	mfaEnabled, err := s.mfa.IsEnabled(ctx, user.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if mfaEnabled {
+		challengeToken, err := s.jwt.GenerateMFAChallengeToken(user.ID.String(), user.Email, user.Role)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to generate mfa challenge token")
+			return nil, nil, err
+		}
+		return nil, &MFAChallenge{
+			MFARequired:    true,
+			ChallengeToken: challengeToken,
+			ExpiresIn:      int64(s.jwt.MFAChallengeExpiry().Seconds()),
+		}, nil
+	}
+
+	tokens, err := s.createSession(ctx, user, userAgent, clientIP)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tokens, nil, nil
+}
 
+// CompleteMFALogin exchanges the challenge of Login and a second factor for
+// a token pair
+func (s *AuthService) CompleteMFALogin(ctx context.Context, challengeToken, code, userAgent, clientIP string) (*TokenPair, error) {
+	claims, err := s.jwt.ValidateMFAChallengeToken(challengeToken)
+	if err != nil {
+		if errors.Is(err, auth.ErrTokenExpired) {
+			return nil, ErrExpiredToken
+		}
+		return nil, ErrInvalidToken
+	}
+
+	userId, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		log.Error().Err(err).Msg("invalid user id in mfa challenge token")
+		return nil, ErrInvalidToken
+	}
+
+	user, err := s.userRepo.GetUserById(ctx, userId)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
-			return nil, ErrInvalidCredentials
+			return nil, ErrInvalidToken
 		}
 		return nil, err
 	}
 
-	verifyPassword, err := security.VerifyPassword(password, user.PasswordHash)
+	if err := s.mfa.VerifyCode(ctx, user.ID, code); err != nil {
+		return nil, err
+	}
+
+	return s.createSession(ctx, user, userAgent, clientIP)
+}
+
+// BeginPasskeyLogin starts a passwordless login, see PasskeyService.BeginLogin
+func (s *AuthService) BeginPasskeyLogin(ctx context.Context) (string, *protocol.CredentialAssertion, error) {
+	return s.passkeys.BeginLogin(ctx)
+}
 
+// LoginWithPasskey finishes a passkey login started with
+// PasskeyService.BeginLogin. Passkeys require user verification on the
+// authenticator, so no second factor is asked for.
+func (s *AuthService) LoginWithPasskey(ctx context.Context, ceremonyId string, response []byte, userAgent, clientIP string) (*TokenPair, error) {
+	user, err := s.passkeys.FinishLogin(ctx, ceremonyId, response)
 	if err != nil {
-		log.Error().Err(err).Msg("failed to verify pwd")
 		return nil, err
 	}
 
-	if !verifyPassword {
-		return nil, ErrInvalidCredentials
+	return s.createSession(ctx, user, userAgent, clientIP)
+}
+
+// OAuthProviders returns the names of the providers users can sign in with
+func (s *AuthService) OAuthProviders() []string {
+	return s.oauth.Providers()
+}
+
+// BeginOAuthLogin returns the provider URL to sign in at, see
+// OAuthService.BeginLogin
+func (s *AuthService) BeginOAuthLogin(ctx context.Context, provider string) (string, error) {
+	return s.oauth.BeginLogin(ctx, provider)
+}
+
+// LoginWithOAuth finishes a login at an identity provider. Users with
+// two-factor authentication get a challenge, as with Login.
+func (s *AuthService) LoginWithOAuth(ctx context.Context, provider, state, code, userAgent, clientIP string) (*TokenPair, *MFAChallenge, error) {
+	user, err := s.oauth.FinishLogin(ctx, provider, state, code)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	accessToken, err := s.jwt.GenerateAccessToken(user.ID.String(), user.Email, user.Role)
+	return s.startSession(ctx, user, userAgent, clientIP)
+}
+
+// createSession issues a token pair and stores the refresh token. The tokens
+// carry the session ID, so a request knows which session it belongs to.
+func (s *AuthService) createSession(ctx context.Context, user *domain.User, userAgent, clientIP string) (*TokenPair, error) {
+	session := &domain.Session{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		UserAgent: userAgent,
+		ClientIP:  clientIP,
+		ExpiresAt: time.Now().Add(s.config.RefreshTokenExpiry),
+		CreatedAt: time.Now(),
+	}
+
+	accessToken, err := s.jwt.GenerateAccessToken(user.ID.String(), user.Email, user.Role, session.ID.String())
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to generate access token")
 		return nil, err
 	}
 
-	refreshToken, err := s.jwt.GenerateRefreshToken(user.ID.String(), user.Email, user.Role)
+	refreshToken, err := s.jwt.GenerateRefreshToken(user.ID.String(), user.Email, user.Role, session.ID.String())
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to generate refresh token")
 		return nil, err
 	}
-
-	session := &domain.Session{
-		ID:           uuid.New(),
-		UserID:       user.ID,
-		RefreshToken: refreshToken,
-		UserAgent:    userAgent,
-		ClientIP:     clientIP,
-		ExpiresAt:    time.Now().Add(s.config.RefreshTokenExpiry),
-		CreatedAt:    time.Now(),
-	}
+	session.RefreshTokenHash = hashRefreshToken(refreshToken)
 
 	if err := s.userRepo.CreateSession(ctx, session); err != nil {
 		log.Error().Err(err).Msg("Failed to create session")
@@ -251,7 +401,7 @@ func (s *AuthService) Login(ctx context.Context, email, password, userAgent, cli
 }
 
 func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
-	session, err := s.userRepo.GetSessionByToken(ctx, refreshToken)
+	session, err := s.userRepo.GetSessionByTokenHash(ctx, hashRefreshToken(refreshToken))
 
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
@@ -260,28 +410,83 @@ func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
 		return err
 	}
 
-	return s.userRepo.DeleteSession(ctx, session.ID)
+	if err := s.userRepo.DeleteSession(ctx, session.ID); err != nil {
+		return err
+	}
+	s.revokeSessionTokens(ctx, session.ID)
+	return nil
 }
 
 func (s *AuthService) LogoutAll(ctx context.Context, userId uuid.UUID) error {
-	return s.userRepo.DeleteUserSessions(ctx, userId)
+	if err := s.userRepo.DeleteUserSessions(ctx, userId); err != nil {
+		return err
+	}
+	return s.RevokeUserTokens(ctx, userId)
 }
 
-func (s *AuthService) RequestPasswordReset(ctx context.Context, email string) (string, error) {
+// RevokeUserTokens makes the access tokens the user holds invalid, e.g.
+// after a role change. Sessions stay, the next refresh issues tokens with the
+// current state of the user.
+func (s *AuthService) RevokeUserTokens(ctx context.Context, userId uuid.UUID) error {
+	return s.denylist.RevokeBefore(ctx, userId.String(), time.Now(), s.config.AccessTokenExpiry)
+}
+
+// revokeSessionTokens makes the access tokens of a session invalid once the
+// session is gone. The session is already revoked, so a failure is logged
+// rather than returned.
+func (s *AuthService) revokeSessionTokens(ctx context.Context, sessionId uuid.UUID) {
+	if err := s.denylist.Revoke(ctx, sessionId.String(), s.config.AccessTokenExpiry); err != nil {
+		log.Error().Err(err).Str("session_id", sessionId.String()).Msg("failed to revoke session access tokens")
+	}
+}
 
+// CheckAccessToken returns ErrTokenRevoked for an access token whose session
+// ended or that was revoked with the other tokens of its user
+func (s *AuthService) CheckAccessToken(ctx context.Context, claims *auth.JWTClaims) error {
+	var issuedAt time.Time
+	if claims.IssuedAt != nil {
+		issuedAt = claims.IssuedAt.Time
+	}
+
+	err := s.denylist.Check(ctx, []string{claims.ID, claims.SessionID}, claims.UserID, issuedAt)
+	if errors.Is(err, security.ErrTokenRevoked) {
+		return ErrTokenRevoked
+	}
+	return err
+}
+
+// GetSessions returns the devices the user is signed in on
+func (s *AuthService) GetSessions(ctx context.Context, userId uuid.UUID) ([]*domain.Session, error) {
+	return s.userRepo.GetSessionsByUser(ctx, userId)
+}
+
+// RevokeSession signs the user out on one device
+func (s *AuthService) RevokeSession(ctx context.Context, userId, sessionId uuid.UUID) error {
+	if err := s.userRepo.DeleteUserSession(ctx, userId, sessionId); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+	s.revokeSessionTokens(ctx, sessionId)
+	return nil
+}
+
+// RequestPasswordReset emails a reset link to the user with the address
+func (s *AuthService) RequestPasswordReset(ctx context.Context, email string) error {
 	user, err := s.userRepo.GetUserByEmail(ctx, email)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
-			return "", ErrUserNotFound
+			return ErrUserNotFound
 		}
-		return "", err
+		return err
 	}
 
 	// make a reset token
 	resetToken, err := s.jwt.GenerateResetToken(user.ID.String(), user.Email)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to gen reset token")
-		return "", nil
+		return err
 	}
 
 	//save updated version
@@ -295,10 +500,22 @@ func (s *AuthService) RequestPasswordReset(ctx context.Context, email string) (s
 
 	if err := s.userRepo.CreatePasswordReset(ctx, reset); err != nil {
 		log.Error().Err(err).Msg("failed to create pwd reset")
+		return err
+	}
+
+	message, err := mail.Render(mail.TemplatePasswordReset, user.Email, mail.PasswordResetData{
+		ResetURL:  s.appLink("/reset-password", resetToken),
+		ExpiresIn: s.config.ResetTokenExpiry,
+	})
+	if err != nil {
+		return err
 	}
-	return resetToken, nil
+	return s.mailer.Send(ctx, message)
 }
 
+// RefreshToken exchanges the refresh token for a new token pair. Every
+// refresh token works once, presenting one again means it was stolen, so the
+// whole session is revoked.
 func (s *AuthService) RefreshToken(ctx context.Context, refreshToken, userAgent, clientIp string) (*TokenPair, error) {
 	claims, err := s.jwt.ValidateRefreshToken(refreshToken)
 
@@ -309,24 +526,26 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken, userAgent,
 		return nil, ErrInvalidToken
 	}
 
-	userId, err := uuid.Parse(claims.UserID)
-	if err != nil {
+	if _, err := uuid.Parse(claims.UserID); err != nil {
 		log.Error().Err(err).Msg("invalid user id in token")
 		return nil, ErrInvalidToken
 	}
 
-	user, err := s.userRepo.GetUserById(ctx, userId)
+	tokenHash := hashRefreshToken(refreshToken)
+	session, err := s.userRepo.GetSessionByTokenHash(ctx, tokenHash)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
-			return nil, ErrUserNotFound
+			return nil, s.checkRefreshTokenReuse(ctx, claims, clientIp)
 		}
 		return nil, err
 	}
 
-	session, err := s.userRepo.GetSessionByToken(ctx, refreshToken)
+	// The session decides the user, it moves with the account when two
+	// accounts are merged
+	user, err := s.userRepo.GetUserById(ctx, session.UserID)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
-			return nil, ErrInvalidSession
+			return nil, ErrUserNotFound
 		}
 		return nil, err
 	}
@@ -336,34 +555,31 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken, userAgent,
 		return nil, ErrExpiredToken
 	}
 
-	newAccessToken, err := s.jwt.GenerateAccessToken(user.ID.String(), user.Email, user.Role)
+	// The session keeps its ID, only the refresh token changes
+	newAccessToken, err := s.jwt.GenerateAccessToken(user.ID.String(), user.Email, user.Role, session.ID.String())
 	if err != nil {
 		log.Error().Err(err).Msg("failed to gen access token")
 		return nil, err
 	}
 
-	newRefreshToken, err := s.jwt.GenerateRefreshToken(user.ID.String(), user.Email, user.Role)
+	newRefreshToken, err := s.jwt.GenerateRefreshToken(user.ID.String(), user.Email, user.Role, session.ID.String())
 	if err != nil {
 		log.Error().Err(err).Msg("failed to gen refresh token")
 		return nil, err
 	}
 
-	if err := s.userRepo.DeleteSession(ctx, session.ID); err != nil {
-		log.Error().Err(err).Msg("failed to delete old session")
-	}
-
-	newSession := &domain.Session{
-		ID:           uuid.New(),
-		UserID:       user.ID,
-		RefreshToken: newRefreshToken,
-		UserAgent:    userAgent,
-		ClientIP:     clientIp,
-		ExpiresAt:    time.Now().Add(s.config.ResetTokenExpiry),
-		CreatedAt:    time.Now(),
-	}
+	session.RefreshTokenHash = hashRefreshToken(newRefreshToken)
+	session.UserAgent = userAgent
+	session.ClientIP = clientIp
+	session.ExpiresAt = time.Now().Add(s.config.RefreshTokenExpiry)
 
-	if err := s.userRepo.CreateSession(ctx, newSession); err != nil {
-		log.Error().Err(err).Msg("failed to create new session")
+	if err := s.userRepo.RotateSession(ctx, session, tokenHash); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			// Another request rotated the same token first
+			return nil, s.checkRefreshTokenReuse(ctx, claims, clientIp)
+		}
+		log.Error().Err(err).Msg("failed to rotate session")
+		return nil, err
 	}
 
 	return &TokenPair{
@@ -374,6 +590,46 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken, userAgent,
 
 }
 
+// checkRefreshTokenReuse handles a validly signed refresh token that is not
+// the current token of any session. If its session still exists the token
+// was rotated before, and the session is revoked since either the user or an
+// attacker holds a copy of it.
+func (s *AuthService) checkRefreshTokenReuse(ctx context.Context, claims *auth.JWTClaims, clientIP string) error {
+	sessionId, err := uuid.Parse(claims.SessionID)
+	if err != nil {
+		return ErrInvalidSession
+	}
+
+	session, err := s.userRepo.GetSessionById(ctx, sessionId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrInvalidSession
+		}
+		return err
+	}
+
+	if err := s.userRepo.DeleteSession(ctx, session.ID); err != nil && !errors.Is(err, repository.ErrNotFound) {
+		log.Error().Err(err).Str("session_id", session.ID.String()).Msg("failed to revoke session after refresh token reuse")
+		return err
+	}
+	s.revokeSessionTokens(ctx, session.ID)
+
+	log.Warn().
+		Str("event", "refresh_token_reuse").
+		Str("user_id", session.UserID.String()).
+		Str("session_id", session.ID.String()).
+		Str("ip", clientIP).
+		Msg("refresh token reused, session revoked")
+	return ErrRefreshTokenReused
+}
+
+// hashRefreshToken is what sessions store instead of the refresh token, so
+// the table is no use to someone who reads it
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
 func (s *AuthService) ResetPassword(ctx context.Context, resetToken, newPassword string) error {
 	claims, err := s.jwt.ValidateResetToken(resetToken)
 	if err != nil {
@@ -409,7 +665,7 @@ func (s *AuthService) ResetPassword(ctx context.Context, resetToken, newPassword
 		return ErrPasswordResetExpired
 	}
 
-	if !reset.UsedAt.IsZero() {
+	if reset.UsedAt != nil {
 		return ErrPasswordResetUsed
 	}
 
@@ -436,8 +692,106 @@ func (s *AuthService) ResetPassword(ctx context.Context, resetToken, newPassword
 		log.Error().Err(err).Msg("Failed to delete user sessions")
 	}
 
+	if err := s.RevokeUserTokens(ctx, user.ID); err != nil {
+		log.Error().Err(err).Msg("Failed to revoke user tokens")
+	}
+
+	return nil
+
+}
+
+// ResendEmailVerification sends a new verification link to the address of
+// the user
+func (s *AuthService) ResendEmailVerification(ctx context.Context, userId uuid.UUID) error {
+	user, err := s.userRepo.GetUserById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+
+	if user.IsEmailVerified() {
+		return ErrEmailAlreadyVerified
+	}
+
+	return s.sendEmailVerification(ctx, user)
+}
+
+// VerifyEmail marks the address in a verification token as verified. Tokens
+// for an address the user has since changed are rejected.
+func (s *AuthService) VerifyEmail(ctx context.Context, token string) error {
+	claims, err := s.jwt.ValidateEmailVerificationToken(token)
+	if err != nil {
+		if errors.Is(err, auth.ErrTokenExpired) {
+			return ErrExpiredToken
+		}
+		return ErrInvalidToken
+	}
+
+	userId, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		log.Error().Err(err).Msg("invalid user id in verification token")
+		return ErrInvalidToken
+	}
+
+	if err := s.userRepo.MarkEmailVerified(ctx, userId, claims.Email); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrInvalidToken
+		}
+		return err
+	}
+
 	return nil
+}
+
+// RequireVerifiedEmail returns ErrEmailNotVerified when unverified accounts
+// are restricted and the user has not verified their address yet
+func (s *AuthService) RequireVerifiedEmail(ctx context.Context, userId uuid.UUID) error {
+	if !s.config.RequireVerifiedEmail {
+		return nil
+	}
+
+	user, err := s.userRepo.GetUserById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+
+	if !user.IsEmailVerified() {
+		return ErrEmailNotVerified
+	}
+	return nil
+}
+
+// sendEmailVerification emails a verification link for the address of the
+// user
+func (s *AuthService) sendEmailVerification(ctx context.Context, user *domain.User) error {
+	token, err := s.jwt.GenerateEmailVerificationToken(user.ID.String(), user.Email)
+	if err != nil {
+		return err
+	}
+
+	message, err := mail.Render(mail.TemplateEmailVerification, user.Email, mail.EmailVerificationData{
+		VerifyURL: s.appLink("/verify-email", token),
+		ExpiresIn: s.config.VerificationTokenExpiry,
+	})
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(ctx, message)
+}
+
+// appLink returns a frontend URL that carries a token
+func (s *AuthService) appLink(path, token string) string {
+	return fmt.Sprintf("%s%s?token=%s", s.config.AppURL, path, url.QueryEscape(token))
+}
 
+// JWKS returns the public keys other services verify access tokens with
+func (s *AuthService) JWKS() auth.JWKS {
+	return s.jwt.JWKS()
 }
 
 func (s *AuthService) ValidateAccessToken(accessToken string) (*auth.JWTClaims, error) {
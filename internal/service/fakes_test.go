@@ -0,0 +1,351 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"cv_builder/internal/domain"
+	"cv_builder/internal/repository"
+	"encoding/json"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"sync"
+	"time"
+)
+
+// memUserRepo keeps users in memory. Methods the tests do not need panic
+// through the nil embedded interface.
+type memUserRepo struct {
+	domain.UserRepository
+
+	mu    sync.Mutex
+	users map[uuid.UUID]*domain.User
+}
+
+func newMemUserRepo() *memUserRepo {
+	return &memUserRepo{users: map[uuid.UUID]*domain.User{}}
+}
+
+func (r *memUserRepo) add(user *domain.User) *domain.User {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if user.ID == uuid.Nil {
+		user.ID = uuid.New()
+	}
+	copied := *user
+	r.users[user.ID] = &copied
+	return user
+}
+
+func (r *memUserRepo) GetUserById(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (r *memUserRepo) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, user := range r.users {
+		if user.Email != "" && user.Email == email {
+			copied := *user
+			return &copied, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+// memIdentityRepo keeps identities in memory and creates their users in
+// the user repository
+type memIdentityRepo struct {
+	users *memUserRepo
+
+	mu         sync.Mutex
+	identities []*domain.UserIdentity
+}
+
+func newMemIdentityRepo(users *memUserRepo) *memIdentityRepo {
+	return &memIdentityRepo{users: users}
+}
+
+func (r *memIdentityRepo) GetIdentity(ctx context.Context, provider, subject string) (*domain.UserIdentity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, identity := range r.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			copied := *identity
+			return &copied, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *memIdentityRepo) GetIdentitiesByUser(ctx context.Context, userId uuid.UUID) ([]*domain.UserIdentity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	identities := []*domain.UserIdentity{}
+	for _, identity := range r.identities {
+		if identity.UserID == userId {
+			copied := *identity
+			identities = append(identities, &copied)
+		}
+	}
+	return identities, nil
+}
+
+func (r *memIdentityRepo) CreateIdentity(ctx context.Context, identity *domain.UserIdentity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.identities {
+		if existing.Provider == identity.Provider && existing.Subject == identity.Subject {
+			return repository.ErrConflict
+		}
+	}
+	identity.ID = uuid.New()
+	identity.CreatedAt = time.Now()
+	copied := *identity
+	r.identities = append(r.identities, &copied)
+	return nil
+}
+
+func (r *memIdentityRepo) CreateUserWithIdentity(ctx context.Context, user *domain.User, identity *domain.UserIdentity) error {
+	if user.Email != "" {
+		if _, err := r.users.GetUserByEmail(ctx, user.Email); err == nil {
+			return repository.ErrConflict
+		}
+	}
+
+	identity.UserID = user.ID
+	if err := r.CreateIdentity(ctx, identity); err != nil {
+		return err
+	}
+	r.users.add(user)
+	return nil
+}
+
+func (r *memIdentityRepo) UpdateIdentityLogin(ctx context.Context, identity *domain.UserIdentity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.identities {
+		if existing.ID == identity.ID {
+			now := time.Now()
+			existing.Email = identity.Email
+			existing.Username = identity.Username
+			existing.LastLoginAt = &now
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+func (r *memIdentityRepo) DeleteIdentity(ctx context.Context, userId, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, identity := range r.identities {
+		if identity.ID == id && identity.UserID == userId {
+			r.identities = append(r.identities[:i], r.identities[i+1:]...)
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+// memPasskeyRepo keeps passkeys in memory
+type memPasskeyRepo struct {
+	mu       sync.Mutex
+	passkeys []*domain.Passkey
+}
+
+func (r *memPasskeyRepo) CreatePasskey(ctx context.Context, passkey *domain.Passkey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.passkeys {
+		if bytes.Equal(existing.CredentialID, passkey.CredentialID) {
+			return repository.ErrConflict
+		}
+	}
+	passkey.ID = uuid.New()
+	passkey.CreatedAt = time.Now()
+	copied := *passkey
+	r.passkeys = append(r.passkeys, &copied)
+	return nil
+}
+
+func (r *memPasskeyRepo) GetPasskeysByUser(ctx context.Context, userId uuid.UUID) ([]*domain.Passkey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	passkeys := []*domain.Passkey{}
+	for _, passkey := range r.passkeys {
+		if passkey.UserID == userId {
+			copied := *passkey
+			passkeys = append(passkeys, &copied)
+		}
+	}
+	return passkeys, nil
+}
+
+func (r *memPasskeyRepo) GetPasskeyByCredentialID(ctx context.Context, credentialId []byte) (*domain.Passkey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, passkey := range r.passkeys {
+		if bytes.Equal(passkey.CredentialID, credentialId) {
+			copied := *passkey
+			return &copied, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *memPasskeyRepo) UpdatePasskeyUsage(ctx context.Context, passkey *domain.Passkey, previousSignCount int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.passkeys {
+		if existing.ID == passkey.ID {
+			if existing.SignCount != previousSignCount {
+				return repository.ErrConflict
+			}
+			now := time.Now()
+			existing.SignCount = passkey.SignCount
+			existing.UserVerified = passkey.UserVerified
+			existing.BackupState = passkey.BackupState
+			existing.LastUsedAt = &now
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+func (r *memPasskeyRepo) RenamePasskey(ctx context.Context, userId, id uuid.UUID, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, passkey := range r.passkeys {
+		if passkey.ID == id && passkey.UserID == userId {
+			passkey.Name = name
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+func (r *memPasskeyRepo) DeletePasskey(ctx context.Context, userId, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, passkey := range r.passkeys {
+		if passkey.ID == id && passkey.UserID == userId {
+			r.passkeys = append(r.passkeys[:i], r.passkeys[i+1:]...)
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+// memEntry is a stored value with the deadline Redis would expire it at
+type memEntry struct {
+	data    []byte
+	expires time.Time
+}
+
+// memStore stands in for the Redis ceremony and OAuth state stores. Values
+// go through JSON like they do in Redis.
+type memStore struct {
+	mu      sync.Mutex
+	entries map[string]memEntry
+}
+
+func newMemStore() *memStore {
+	return &memStore{entries: map[string]memEntry{}}
+}
+
+func (s *memStore) save(key string, value any, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = memEntry{data: data, expires: time.Now().Add(ttl)}
+	return nil
+}
+
+func (s *memStore) take(key string, value any) error {
+	s.mu.Lock()
+	entry, ok := s.entries[key]
+	delete(s.entries, key)
+	s.mu.Unlock()
+
+	if !ok || !time.Now().Before(entry.expires) {
+		return repository.ErrNotFound
+	}
+	return json.Unmarshal(entry.data, value)
+}
+
+// update rewrites a stored value in place, keeping its deadline
+func (s *memStore) update(key string, value any, change func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.entries[key]
+	if err := json.Unmarshal(entry.data, value); err != nil {
+		panic(err)
+	}
+	change()
+	data, err := json.Marshal(value)
+	if err != nil {
+		panic(err)
+	}
+	entry.data = data
+	s.entries[key] = entry
+}
+
+// expire lets the stored value run out as if its TTL passed
+func (s *memStore) expire(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.entries[key]
+	entry.expires = time.Now().Add(-time.Second)
+	s.entries[key] = entry
+}
+
+func (s *memStore) SaveCeremony(ctx context.Context, id string, session *webauthn.SessionData, ttl time.Duration) error {
+	return s.save(id, session, ttl)
+}
+
+func (s *memStore) TakeCeremony(ctx context.Context, id string) (*webauthn.SessionData, error) {
+	var session webauthn.SessionData
+	if err := s.take(id, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (s *memStore) SaveOAuthState(ctx context.Context, state string, data *domain.OAuthState, ttl time.Duration) error {
+	return s.save(state, data, ttl)
+}
+
+func (s *memStore) TakeOAuthState(ctx context.Context, state string) (*domain.OAuthState, error) {
+	var data domain.OAuthState
+	if err := s.take(state, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
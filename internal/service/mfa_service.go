@@ -0,0 +1,319 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"cv_builder/internal/domain"
+	"cv_builder/internal/repository"
+	"cv_builder/pkg/auth"
+	"cv_builder/pkg/security"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+)
+
+var (
+	ErrMFAAlreadyEnabled  = errors.New("two-factor authentication already enabled")
+	ErrMFANotEnabled      = errors.New("two-factor authentication not enabled")
+	ErrMFASetupRequired   = errors.New("two-factor setup not started")
+	ErrInvalidMFACode     = errors.New("invalid two-factor code")
+	ErrTooManyMFAAttempts = errors.New("too many two-factor attempts")
+	// ErrMFARequiresPassword is returned for Telegram-only accounts, the
+	// second factor guards password logins
+	ErrMFARequiresPassword = errors.New("two-factor authentication requires a password login")
+)
+
+// recoveryCodeCount is the size of a set of recovery codes
+const recoveryCodeCount = 10
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TOTPSetup is what the user needs to add the account to an authenticator
+// app
+type TOTPSetup struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+type MFAStatus struct {
+	Enabled bool `json:"enabled"`
+	// Pending is set between setup and confirmation
+	Pending                bool `json:"pending"`
+	RecoveryCodesRemaining int  `json:"recovery_codes_remaining"`
+}
+
+// MFAService manages TOTP two-factor authentication and recovery codes
+type MFAService struct {
+	mfaRepo   domain.MFARepository
+	userRepo  domain.UserRepository
+	encryptor *security.Encryptor
+	attempts  *security.AttemptLimiter
+	issuer    string
+}
+
+func NewMFAService(mfaRepo domain.MFARepository, userRepo domain.UserRepository, encryptor *security.Encryptor, attempts *security.AttemptLimiter, issuer string) *MFAService {
+	return &MFAService{
+		mfaRepo:   mfaRepo,
+		userRepo:  userRepo,
+		encryptor: encryptor,
+		attempts:  attempts,
+		issuer:    issuer,
+	}
+}
+
+// IsEnabled reports whether logins of the user need a second factor
+func (s *MFAService) IsEnabled(ctx context.Context, userId uuid.UUID) (bool, error) {
+	enrollment, err := s.mfaRepo.GetTOTP(ctx, userId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return enrollment.IsConfirmed(), nil
+}
+
+func (s *MFAService) Status(ctx context.Context, userId uuid.UUID) (*MFAStatus, error) {
+	enrollment, err := s.mfaRepo.GetTOTP(ctx, userId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return &MFAStatus{}, nil
+		}
+		return nil, err
+	}
+
+	remaining, err := s.mfaRepo.CountRecoveryCodes(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MFAStatus{
+		Enabled:                enrollment.IsConfirmed(),
+		Pending:                !enrollment.IsConfirmed(),
+		RecoveryCodesRemaining: remaining,
+	}, nil
+}
+
+// SetupTOTP starts an enrollment with a new secret. It has no effect on
+// logins until confirmed, and starting over replaces a pending secret.
+func (s *MFAService) SetupTOTP(ctx context.Context, userId uuid.UUID) (*TOTPSetup, error) {
+	user, err := s.userRepo.GetUserById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	if user.Email == "" || user.PasswordHash == "" {
+		return nil, ErrMFARequiresPassword
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := s.encryptor.Encrypt([]byte(secret))
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.mfaRepo.SaveTOTP(ctx, &domain.TOTPEnrollment{UserID: userId, Secret: encrypted})
+	if err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return nil, ErrMFAAlreadyEnabled
+		}
+		return nil, err
+	}
+
+	return &TOTPSetup{
+		Secret: secret,
+		URI:    auth.TOTPURI(s.issuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmTOTP enables two-factor authentication with a first code from the
+// app and returns the recovery codes, which are only shown this once
+func (s *MFAService) ConfirmTOTP(ctx context.Context, userId uuid.UUID, code string) ([]string, error) {
+	enrollment, err := s.mfaRepo.GetTOTP(ctx, userId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrMFASetupRequired
+		}
+		return nil, err
+	}
+	if enrollment.IsConfirmed() {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	if err := s.checkCode(ctx, enrollment, code, false); err != nil {
+		return nil, err
+	}
+
+	return s.newRecoveryCodes(ctx, userId)
+}
+
+// DisableTOTP turns two-factor authentication off, which takes a current
+// code or a recovery code
+func (s *MFAService) DisableTOTP(ctx context.Context, userId uuid.UUID, code string) error {
+	if err := s.VerifyCode(ctx, userId, code); err != nil {
+		return err
+	}
+
+	if err := s.mfaRepo.DeleteTOTP(ctx, userId); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrMFANotEnabled
+		}
+		return err
+	}
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes, which takes a current
+// code from the app
+func (s *MFAService) RegenerateRecoveryCodes(ctx context.Context, userId uuid.UUID, code string) ([]string, error) {
+	enrollment, err := s.confirmedEnrollment(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCode(ctx, enrollment, code, false); err != nil {
+		return nil, err
+	}
+
+	return s.newRecoveryCodes(ctx, userId)
+}
+
+// VerifyCode checks a second factor of the user, either a code from the app
+// or an unused recovery code. Failures are counted per user.
+func (s *MFAService) VerifyCode(ctx context.Context, userId uuid.UUID, code string) error {
+	enrollment, err := s.confirmedEnrollment(ctx, userId)
+	if err != nil {
+		return err
+	}
+	return s.checkCode(ctx, enrollment, code, true)
+}
+
+func (s *MFAService) confirmedEnrollment(ctx context.Context, userId uuid.UUID) (*domain.TOTPEnrollment, error) {
+	enrollment, err := s.mfaRepo.GetTOTP(ctx, userId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrMFANotEnabled
+		}
+		return nil, err
+	}
+	if !enrollment.IsConfirmed() {
+		return nil, ErrMFANotEnabled
+	}
+	return enrollment, nil
+}
+
+// checkCode verifies the code under the attempt limit. A TOTP code is used
+// up by recording its time step.
+func (s *MFAService) checkCode(ctx context.Context, enrollment *domain.TOTPEnrollment, code string, allowRecovery bool) error {
+	key := enrollment.UserID.String()
+	if err := s.attempts.Check(ctx, key); err != nil {
+		if errors.Is(err, security.ErrTooManyAttempts) {
+			return ErrTooManyMFAAttempts
+		}
+		return err
+	}
+
+	code = strings.TrimSpace(code)
+	var err error
+	if isTOTPCode(code) {
+		err = s.useTOTPCode(ctx, enrollment, code)
+	} else if allowRecovery {
+		err = s.useRecoveryCode(ctx, enrollment.UserID, code)
+	} else {
+		err = ErrInvalidMFACode
+	}
+
+	if err != nil {
+		if errors.Is(err, ErrInvalidMFACode) {
+			s.attempts.Fail(ctx, key)
+		}
+		return err
+	}
+
+	s.attempts.Reset(ctx, key)
+	return nil
+}
+
+func (s *MFAService) useTOTPCode(ctx context.Context, enrollment *domain.TOTPEnrollment, code string) error {
+	secret, err := s.encryptor.Decrypt(enrollment.Secret)
+	if err != nil {
+		return err
+	}
+
+	step, ok := auth.ValidateTOTP(string(secret), code, time.Now())
+	if !ok || step <= enrollment.LastUsedStep {
+		return ErrInvalidMFACode
+	}
+
+	if err := s.mfaRepo.UseTOTPStep(ctx, enrollment.UserID, step); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return ErrInvalidMFACode
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *MFAService) useRecoveryCode(ctx context.Context, userId uuid.UUID, code string) error {
+	if err := s.mfaRepo.UseRecoveryCode(ctx, userId, hashRecoveryCode(code)); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrInvalidMFACode
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *MFAService) newRecoveryCodes(ctx context.Context, userId uuid.UUID) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		random := make([]byte, 5)
+		if _, err := rand.Read(random); err != nil {
+			return nil, err
+		}
+		code := strings.ToLower(recoveryCodeEncoding.EncodeToString(random))
+		codes[i] = code[:4] + "-" + code[4:]
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+
+	if err := s.mfaRepo.ReplaceRecoveryCodes(ctx, userId, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// hashRecoveryCode ignores case, spaces and dashes, so codes can be typed
+// the way they are read out
+func hashRecoveryCode(code string) string {
+	normalized := strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(code))
+
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+func isTOTPCode(code string) bool {
+	if len(code) != auth.TOTPDigits {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
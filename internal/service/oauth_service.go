@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"cv_builder/internal/domain"
+	"cv_builder/internal/repository"
+	"cv_builder/pkg/auth"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/oauth2"
+	"slices"
+	"time"
+)
+
+var (
+	ErrOAuthProviderNotFound = errors.New("oauth provider not found")
+	ErrOAuthStateNotFound    = errors.New("oauth state not found or expired")
+	ErrOAuthFailed           = errors.New("oauth login failed")
+	ErrIdentityEmailInUse    = errors.New("email belongs to an account the identity is not linked to")
+	ErrIdentityNotFound      = errors.New("identity not found")
+	ErrLastLoginMethod       = errors.New("cannot remove the last way to sign in")
+)
+
+// oauthStateTTL is how long the user has to sign in at the provider
+const oauthStateTTL = 10 * time.Minute
+
+// OAuthStateStore keeps OAuth logins between the redirect and the callback
+type OAuthStateStore interface {
+	SaveOAuthState(ctx context.Context, state string, data *domain.OAuthState, ttl time.Duration) error
+	// TakeOAuthState returns and removes the state, repository.ErrNotFound
+	// if it does not exist or expired
+	TakeOAuthState(ctx context.Context, state string) (*domain.OAuthState, error)
+}
+
+// OAuthService signs users in through external identity providers, creating
+// an account on the first login
+type OAuthService struct {
+	identityRepo domain.UserIdentityRepository
+	userRepo     domain.UserRepository
+	passkeyRepo  domain.PasskeyRepository
+	states       OAuthStateStore
+	providers    map[string]*auth.OAuthProvider
+}
+
+func NewOAuthService(identityRepo domain.UserIdentityRepository, userRepo domain.UserRepository, passkeyRepo domain.PasskeyRepository, states OAuthStateStore, providers []*auth.OAuthProvider) *OAuthService {
+	byName := make(map[string]*auth.OAuthProvider, len(providers))
+	for _, provider := range providers {
+		byName[provider.Name()] = provider
+	}
+
+	return &OAuthService{
+		identityRepo: identityRepo,
+		userRepo:     userRepo,
+		passkeyRepo:  passkeyRepo,
+		states:       states,
+		providers:    byName,
+	}
+}
+
+// Providers returns the names of the configured providers
+func (s *OAuthService) Providers() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// BeginLogin returns the provider URL to send the user to. The provider
+// redirects back to the frontend with the code and state for FinishLogin.
+func (s *OAuthService) BeginLogin(ctx context.Context, providerName string) (string, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return "", ErrOAuthProviderNotFound
+	}
+
+	state := rand.Text()
+	data := &domain.OAuthState{
+		Provider:     providerName,
+		Nonce:        rand.Text(),
+		CodeVerifier: oauth2.GenerateVerifier(),
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, state, data.Nonce, data.CodeVerifier)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.states.SaveOAuthState(ctx, state, data, oauthStateTTL); err != nil {
+		return "", err
+	}
+	return authURL, nil
+}
+
+// FinishLogin redeems the code and returns the user the identity belongs to
+func (s *OAuthService) FinishLogin(ctx context.Context, providerName, state, code string) (*domain.User, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, ErrOAuthProviderNotFound
+	}
+
+	data, err := s.states.TakeOAuthState(ctx, state)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrOAuthStateNotFound
+		}
+		return nil, err
+	}
+	if data.Provider != providerName {
+		return nil, ErrOAuthStateNotFound
+	}
+
+	identity, err := provider.Exchange(ctx, code, data.Nonce, data.CodeVerifier)
+	if err != nil {
+		if errors.Is(err, auth.ErrOAuthExchange) || errors.Is(err, auth.ErrOAuthIdentity) {
+			log.Warn().Err(err).Str("provider", providerName).Msg("oauth login rejected")
+			return nil, fmt.Errorf("%w: %w", ErrOAuthFailed, err)
+		}
+		return nil, err
+	}
+
+	return s.resolveUser(ctx, providerName, identity, true)
+}
+
+// resolveUser finds the user of an identity. An unknown identity is linked
+// to the account with the same email address when both the provider and the
+// account verified it, and gets a new account otherwise.
+func (s *OAuthService) resolveUser(ctx context.Context, providerName string, identity *auth.OAuthIdentity, retry bool) (*domain.User, error) {
+	existing, err := s.identityRepo.GetIdentity(ctx, providerName, identity.Subject)
+	if err == nil {
+		existing.Email = identity.Email
+		existing.Username = identity.Username
+		if err := s.identityRepo.UpdateIdentityLogin(ctx, existing); err != nil {
+			return nil, err
+		}
+		return s.userRepo.GetUserById(ctx, existing.UserID)
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+
+	link := &domain.UserIdentity{
+		Provider: providerName,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+		Username: identity.Username,
+	}
+
+	// An unverified address could belong to anyone, so it is not used
+	var email string
+	if identity.EmailVerified {
+		email = identity.Email
+	}
+
+	if email != "" {
+		user, err := s.userRepo.GetUserByEmail(ctx, email)
+		if err == nil {
+			if user.EmailVerifiedAt == nil {
+				return nil, ErrIdentityEmailInUse
+			}
+
+			link.UserID = user.ID
+			if err := s.identityRepo.CreateIdentity(ctx, link); err != nil {
+				if errors.Is(err, repository.ErrConflict) && retry {
+					return s.resolveUser(ctx, providerName, identity, false)
+				}
+				return nil, err
+			}
+			log.Info().Str("user_id", user.ID.String()).Str("provider", providerName).Msg("identity linked by verified email")
+			return user, nil
+		}
+		if !errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+	}
+
+	user := &domain.User{
+		ID:    uuid.New(),
+		Email: email,
+		Role:  "user",
+	}
+	if email != "" {
+		verifiedAt := time.Now()
+		user.EmailVerifiedAt = &verifiedAt
+	}
+
+	if err := s.identityRepo.CreateUserWithIdentity(ctx, user, link); err != nil {
+		// A concurrent login created the identity or the account first
+		if errors.Is(err, repository.ErrConflict) && retry {
+			return s.resolveUser(ctx, providerName, identity, false)
+		}
+		return nil, err
+	}
+	log.Info().Str("user_id", user.ID.String()).Str("provider", providerName).Msg("user created from identity")
+	return user, nil
+}
+
+func (s *OAuthService) GetIdentities(ctx context.Context, userId uuid.UUID) ([]*domain.UserIdentity, error) {
+	return s.identityRepo.GetIdentitiesByUser(ctx, userId)
+}
+
+// UnlinkIdentity removes an identity, unless the user could not sign in
+// anymore without it
+func (s *OAuthService) UnlinkIdentity(ctx context.Context, userId, id uuid.UUID) error {
+	identities, err := s.identityRepo.GetIdentitiesByUser(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(identities, func(identity *domain.UserIdentity) bool { return identity.ID == id }) {
+		return ErrIdentityNotFound
+	}
+
+	methods, err := getLoginMethods(ctx, s.userRepo, s.identityRepo, s.passkeyRepo, userId)
+	if err != nil {
+		return err
+	}
+	if methods.count() <= 1 {
+		return ErrLastLoginMethod
+	}
+
+	if err := s.identityRepo.DeleteIdentity(ctx, userId, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrIdentityNotFound
+		}
+		return err
+	}
+	return nil
+}
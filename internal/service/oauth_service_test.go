@@ -0,0 +1,516 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"cv_builder/internal/domain"
+	"cv_builder/pkg/auth"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"golang.org/x/oauth2"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	stubClientID    = "cv-builder"
+	stubRedirectURL = "http://localhost:3000/auth/oauth/stub/callback"
+	stubKeyID       = "stub-key"
+)
+
+// stubUser is the account that signs in at the stub provider
+type stubUser struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Login         string
+}
+
+type stubEmail struct {
+	Email    string `json:"email"`
+	Primary  bool   `json:"primary"`
+	Verified bool   `json:"verified"`
+}
+
+// stubGrant is an authorization code waiting to be redeemed
+type stubGrant struct {
+	challenge string
+	nonce     string
+	user      stubUser
+}
+
+// stubIdP is a local identity provider. It serves OpenID Connect discovery,
+// its JWKS, a token endpoint enforcing PKCE, and GitHub style user and
+// email endpoints.
+type stubIdP struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu     sync.Mutex
+	grants map[string]stubGrant
+	tokens map[string]stubUser
+	emails []stubEmail
+}
+
+func newStubIdP(t *testing.T) *stubIdP {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idp := &stubIdP{
+		key:    key,
+		grants: map[string]stubGrant{},
+		tokens: map[string]stubUser{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", idp.discovery)
+	mux.HandleFunc("GET /jwks", idp.jwks)
+	mux.HandleFunc("POST /token", idp.token)
+	mux.HandleFunc("GET /user", idp.user)
+	mux.HandleFunc("GET /user/emails", idp.userEmails)
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+	return idp
+}
+
+// oidcConfig configures the stub as an OpenID Connect provider
+func (idp *stubIdP) oidcConfig() auth.OAuthConfig {
+	return auth.OAuthConfig{
+		ClientID:     stubClientID,
+		ClientSecret: "secret",
+		Issuer:       idp.server.URL,
+		RedirectURL:  stubRedirectURL,
+	}
+}
+
+// githubConfig configures the stub as a plain OAuth2 provider like GitHub
+func (idp *stubIdP) githubConfig() auth.OAuthConfig {
+	return auth.OAuthConfig{
+		ClientID:     stubClientID,
+		ClientSecret: "secret",
+		AuthURL:      idp.server.URL + "/authorize",
+		TokenURL:     idp.server.URL + "/token",
+		UserInfoURL:  idp.server.URL + "/user",
+		EmailsURL:    idp.server.URL + "/user/emails",
+		RedirectURL:  stubRedirectURL,
+	}
+}
+
+// authorize plays the user signing in at the provider and returns the state
+// and code the provider redirects back with
+func (idp *stubIdP) authorize(t *testing.T, authURL string, user stubUser) (string, string) {
+	t.Helper()
+
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := parsed.Query()
+	if query.Get("client_id") != stubClientID || query.Get("redirect_uri") != stubRedirectURL {
+		t.Fatalf("unexpected client in auth url %s", authURL)
+	}
+	if query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		t.Fatalf("auth url %s has no S256 code challenge", authURL)
+	}
+	if query.Get("state") == "" {
+		t.Fatalf("auth url %s has no state", authURL)
+	}
+
+	code := rand.Text()
+	idp.mu.Lock()
+	idp.grants[code] = stubGrant{
+		challenge: query.Get("code_challenge"),
+		nonce:     query.Get("nonce"),
+		user:      user,
+	}
+	idp.mu.Unlock()
+	return query.Get("state"), code
+}
+
+func (idp *stubIdP) discovery(w http.ResponseWriter, r *http.Request) {
+	writeStubJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                idp.server.URL,
+		"authorization_endpoint":                idp.server.URL + "/authorize",
+		"token_endpoint":                        idp.server.URL + "/token",
+		"jwks_uri":                              idp.server.URL + "/jwks",
+		"userinfo_endpoint":                     idp.server.URL + "/user",
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (idp *stubIdP) jwks(w http.ResponseWriter, r *http.Request) {
+	writeStubJSON(w, http.StatusOK, map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": stubKeyID,
+			"alg": "RS256",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(idp.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(idp.key.E)).Bytes()),
+		}},
+	})
+}
+
+// token redeems a code once, and only with the verifier of its challenge
+func (idp *stubIdP) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeStubJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	idp.mu.Lock()
+	grant, ok := idp.grants[r.PostForm.Get("code")]
+	delete(idp.grants, r.PostForm.Get("code"))
+	idp.mu.Unlock()
+
+	verifier := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(verifier[:]) != grant.challenge {
+		writeStubJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	accessToken := rand.Text()
+	idp.mu.Lock()
+	idp.tokens[accessToken] = grant.user
+	idp.mu.Unlock()
+
+	now := time.Now()
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            idp.server.URL,
+		"sub":            grant.user.Subject,
+		"aud":            stubClientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+		"nonce":          grant.nonce,
+		"email":          grant.user.Email,
+		"email_verified": grant.user.EmailVerified,
+	})
+	idToken.Header["kid"] = stubKeyID
+	signed, err := idToken.SignedString(idp.key)
+	if err != nil {
+		writeStubJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeStubJSON(w, http.StatusOK, map[string]any{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     signed,
+	})
+}
+
+func (idp *stubIdP) bearerUser(r *http.Request) (stubUser, bool) {
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+
+	user, ok := idp.tokens[strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")]
+	return user, ok
+}
+
+// user answers like the GitHub user API, with a numeric id and no private
+// email address
+func (idp *stubIdP) user(w http.ResponseWriter, r *http.Request) {
+	user, ok := idp.bearerUser(r)
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	writeStubJSON(w, http.StatusOK, map[string]any{
+		"id":    json.Number(user.Subject),
+		"login": user.Login,
+		"email": nil,
+	})
+}
+
+func (idp *stubIdP) userEmails(w http.ResponseWriter, r *http.Request) {
+	if _, ok := idp.bearerUser(r); !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+	writeStubJSON(w, http.StatusOK, idp.emails)
+}
+
+func writeStubJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+type oauthTest struct {
+	service    *OAuthService
+	users      *memUserRepo
+	identities *memIdentityRepo
+	states     *memStore
+}
+
+func newOAuthTest(t *testing.T, configs map[string]auth.OAuthConfig) *oauthTest {
+	t.Helper()
+
+	var providers []*auth.OAuthProvider
+	for name, config := range configs {
+		provider, err := auth.NewOAuthProvider(name, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		providers = append(providers, provider)
+	}
+
+	users := newMemUserRepo()
+	identities := newMemIdentityRepo(users)
+	states := newMemStore()
+	return &oauthTest{
+		service:    NewOAuthService(identities, users, &memPasskeyRepo{}, states, providers),
+		users:      users,
+		identities: identities,
+		states:     states,
+	}
+}
+
+// login runs a whole login of the user at the named provider
+func (o *oauthTest) login(t *testing.T, idp *stubIdP, provider string, user stubUser) (*domain.User, error) {
+	t.Helper()
+
+	authURL, err := o.service.BeginLogin(context.Background(), provider)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, code := idp.authorize(t, authURL, user)
+	return o.service.FinishLogin(context.Background(), provider, state, code)
+}
+
+func TestOAuthLoginCreatesUserOnce(t *testing.T) {
+	idp := newStubIdP(t)
+	o := newOAuthTest(t, map[string]auth.OAuthConfig{"stub": idp.oidcConfig()})
+	ctx := context.Background()
+	alice := stubUser{Subject: "alice", Email: "alice@example.com", EmailVerified: true}
+
+	authURL, err := o.service.BeginLogin(ctx, "stub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, code := idp.authorize(t, authURL, alice)
+	if !strings.Contains(authURL, "nonce=") {
+		t.Fatalf("auth url %s has no nonce", authURL)
+	}
+
+	user, err := o.service.FinishLogin(ctx, "stub", state, code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Email != alice.Email || user.EmailVerifiedAt == nil {
+		t.Errorf("user email = %q verified %v, want verified %q", user.Email, user.EmailVerifiedAt, alice.Email)
+	}
+
+	// The state is used up by the first callback
+	if _, err := o.service.FinishLogin(ctx, "stub", state, code); !errors.Is(err, ErrOAuthStateNotFound) {
+		t.Errorf("second callback error = %v, want %v", err, ErrOAuthStateNotFound)
+	}
+
+	again, err := o.login(t, idp, "stub", alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != user.ID {
+		t.Errorf("second login user = %s, want %s", again.ID, user.ID)
+	}
+}
+
+func TestOAuthLoginChecksCallback(t *testing.T) {
+	idp := newStubIdP(t)
+	alice := stubUser{Subject: "alice", Email: "alice@example.com", EmailVerified: true}
+
+	tests := []struct {
+		name     string
+		provider string
+		change   func(data *domain.OAuthState)
+		wantErr  error
+	}{
+		{
+			name:     "wrong code verifier",
+			provider: "stub",
+			change:   func(data *domain.OAuthState) { data.CodeVerifier = oauth2.GenerateVerifier() },
+			wantErr:  auth.ErrOAuthExchange,
+		},
+		{
+			name:     "wrong nonce",
+			provider: "stub",
+			change:   func(data *domain.OAuthState) { data.Nonce = rand.Text() },
+			wantErr:  auth.ErrOAuthIdentity,
+		},
+		{
+			name:     "state of another provider",
+			provider: "other",
+			change:   func(data *domain.OAuthState) {},
+			wantErr:  ErrOAuthStateNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := newOAuthTest(t, map[string]auth.OAuthConfig{
+				"stub":  idp.oidcConfig(),
+				"other": idp.oidcConfig(),
+			})
+			ctx := context.Background()
+
+			authURL, err := o.service.BeginLogin(ctx, "stub")
+			if err != nil {
+				t.Fatal(err)
+			}
+			state, code := idp.authorize(t, authURL, alice)
+
+			var data domain.OAuthState
+			o.states.update(state, &data, func() { tt.change(&data) })
+
+			if _, err := o.service.FinishLogin(ctx, tt.provider, state, code); !errors.Is(err, tt.wantErr) {
+				t.Errorf("FinishLogin error = %v, want %v", err, tt.wantErr)
+			}
+			if identities, _ := o.identities.GetIdentitiesByUser(ctx, uuid.Nil); len(o.users.users) != 0 || len(identities) != 0 {
+				t.Errorf("rejected login created a user")
+			}
+		})
+	}
+}
+
+func TestOAuthLoginLinksByVerifiedEmail(t *testing.T) {
+	idp := newStubIdP(t)
+	verifiedAt := time.Now()
+
+	tests := []struct {
+		name          string
+		account       *domain.User
+		user          stubUser
+		wantErr       error
+		wantLinked    bool
+		wantUserEmail string
+	}{
+		{
+			name:       "verified account",
+			account:    &domain.User{Email: "bob@example.com", EmailVerifiedAt: &verifiedAt},
+			user:       stubUser{Subject: "bob", Email: "bob@example.com", EmailVerified: true},
+			wantLinked: true,
+		},
+		{
+			name:    "unverified account",
+			account: &domain.User{Email: "bob@example.com"},
+			user:    stubUser{Subject: "bob", Email: "bob@example.com", EmailVerified: true},
+			wantErr: ErrIdentityEmailInUse,
+		},
+		{
+			name:          "address not verified by the provider",
+			account:       &domain.User{Email: "bob@example.com", EmailVerifiedAt: &verifiedAt},
+			user:          stubUser{Subject: "bob", Email: "bob@example.com"},
+			wantUserEmail: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := newOAuthTest(t, map[string]auth.OAuthConfig{"stub": idp.oidcConfig()})
+			account := o.users.add(tt.account)
+
+			user, err := o.login(t, idp, "stub", tt.user)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("FinishLogin error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if linked := user.ID == account.ID; linked != tt.wantLinked {
+				t.Errorf("linked to the account = %v, want %v", linked, tt.wantLinked)
+			}
+			if !tt.wantLinked && user.Email != tt.wantUserEmail {
+				t.Errorf("new user email = %q, want %q", user.Email, tt.wantUserEmail)
+			}
+
+			identities, err := o.identities.GetIdentitiesByUser(context.Background(), user.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(identities) != 1 || identities[0].Subject != tt.user.Subject {
+				t.Errorf("identities of the user = %+v, want subject %q", identities, tt.user.Subject)
+			}
+		})
+	}
+}
+
+func TestOAuthLoginReadsGitHubEmails(t *testing.T) {
+	tests := []struct {
+		name         string
+		emails       []stubEmail
+		wantEmail    string
+		wantVerified bool
+	}{
+		{
+			name: "primary verified",
+			emails: []stubEmail{
+				{Email: "old@example.com", Verified: true},
+				{Email: "octo@example.com", Primary: true, Verified: true},
+			},
+			wantEmail:    "octo@example.com",
+			wantVerified: true,
+		},
+		{
+			name: "primary not verified",
+			emails: []stubEmail{
+				{Email: "octo@example.com", Primary: true},
+				{Email: "old@example.com", Verified: true},
+			},
+		},
+		{
+			name: "no addresses",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idp := newStubIdP(t)
+			idp.emails = tt.emails
+			o := newOAuthTest(t, map[string]auth.OAuthConfig{"github": idp.githubConfig()})
+
+			authURL, err := o.service.BeginLogin(context.Background(), "github")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(authURL, "nonce=") {
+				t.Errorf("plain OAuth2 auth url %s has a nonce", authURL)
+			}
+			state, code := idp.authorize(t, authURL, stubUser{Subject: "583231", Login: "octocat"})
+
+			user, err := o.service.FinishLogin(context.Background(), "github", state, code)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if user.Email != tt.wantEmail || (user.EmailVerifiedAt != nil) != tt.wantVerified {
+				t.Errorf("user email = %q verified %v, want %q verified %v", user.Email, user.EmailVerifiedAt != nil, tt.wantEmail, tt.wantVerified)
+			}
+
+			identity, err := o.identities.GetIdentity(context.Background(), "github", "583231")
+			if err != nil {
+				t.Fatalf("identity by numeric id: %v", err)
+			}
+			if identity.Username != "octocat" || identity.UserID != user.ID {
+				t.Errorf("identity = %+v, want username octocat of user %s", identity, user.ID)
+			}
+		})
+	}
+}
@@ -0,0 +1,375 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"cv_builder/internal/domain"
+	"cv_builder/internal/repository"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+var (
+	ErrCeremonyNotFound = errors.New("webauthn ceremony not found or expired")
+	ErrPasskeyNotFound  = errors.New("passkey not found")
+	ErrPasskeyExists    = errors.New("passkey already registered")
+	ErrPasskeyInvalid   = errors.New("passkey verification failed")
+	ErrPasskeyCloned    = errors.New("passkey signature counter went backwards")
+)
+
+// ceremonyTTL is how long a started ceremony can be finished
+const ceremonyTTL = 5 * time.Minute
+
+// CeremonyStore keeps WebAuthn ceremony state between begin and finish
+type CeremonyStore interface {
+	SaveCeremony(ctx context.Context, id string, session *webauthn.SessionData, ttl time.Duration) error
+	// TakeCeremony returns and removes the ceremony, repository.ErrNotFound
+	// if it does not exist or expired
+	TakeCeremony(ctx context.Context, id string) (*webauthn.SessionData, error)
+}
+
+type PasskeyConfig struct {
+	// RPID is the domain passkeys are bound to, e.g. "cv.example.com"
+	RPID          string
+	RPDisplayName string
+	// RPOrigins are the frontend origins allowed to run ceremonies
+	RPOrigins []string
+}
+
+// PasskeyService runs the WebAuthn registration and login ceremonies. The
+// finish methods take the credential JSON the browser produced, so they can
+// be driven by a software authenticator as well.
+type PasskeyService struct {
+	passkeyRepo domain.PasskeyRepository
+	userRepo    domain.UserRepository
+	ceremonies  CeremonyStore
+	webauthn    *webauthn.WebAuthn
+}
+
+func NewPasskeyService(passkeyRepo domain.PasskeyRepository, userRepo domain.UserRepository, ceremonies CeremonyStore, config PasskeyConfig) (*PasskeyService, error) {
+	w, err := webauthn.New(&webauthn.Config{
+		RPID:          config.RPID,
+		RPDisplayName: config.RPDisplayName,
+		RPOrigins:     config.RPOrigins,
+		// Passkeys replace the password, so the authenticator has to verify
+		// the user with a PIN or biometrics
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			ResidentKey:      protocol.ResidentKeyRequirementRequired,
+			UserVerification: protocol.VerificationRequired,
+		},
+		AttestationPreference: protocol.PreferNoAttestation,
+		Timeouts: webauthn.TimeoutsConfig{
+			Login:        webauthn.TimeoutConfig{Enforce: true, Timeout: ceremonyTTL},
+			Registration: webauthn.TimeoutConfig{Enforce: true, Timeout: ceremonyTTL},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid webauthn config: %w", err)
+	}
+
+	return &PasskeyService{
+		passkeyRepo: passkeyRepo,
+		userRepo:    userRepo,
+		ceremonies:  ceremonies,
+		webauthn:    w,
+	}, nil
+}
+
+// passkeyUser adapts a user and their passkeys to webauthn.User. The user
+// handle is the user ID, which is random and reveals nothing about the user.
+type passkeyUser struct {
+	user     *domain.User
+	passkeys []*domain.Passkey
+}
+
+func (u *passkeyUser) WebAuthnID() []byte {
+	return u.user.ID[:]
+}
+
+func (u *passkeyUser) WebAuthnName() string {
+	if u.user.Email != "" {
+		return u.user.Email
+	}
+	return u.user.ID.String()
+}
+
+func (u *passkeyUser) WebAuthnDisplayName() string {
+	return u.WebAuthnName()
+}
+
+func (u *passkeyUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, 0, len(u.passkeys))
+	for _, passkey := range u.passkeys {
+		credentials = append(credentials, toCredential(passkey))
+	}
+	return credentials
+}
+
+func toCredential(passkey *domain.Passkey) webauthn.Credential {
+	transports := make([]protocol.AuthenticatorTransport, 0, len(passkey.Transports))
+	for _, transport := range passkey.Transports {
+		transports = append(transports, protocol.AuthenticatorTransport(transport))
+	}
+
+	return webauthn.Credential{
+		ID:              passkey.CredentialID,
+		PublicKey:       passkey.PublicKey,
+		AttestationType: passkey.AttestationType,
+		Transport:       transports,
+		Flags: webauthn.CredentialFlags{
+			UserPresent:    true,
+			UserVerified:   passkey.UserVerified,
+			BackupEligible: passkey.BackupEligible,
+			BackupState:    passkey.BackupState,
+		},
+		Authenticator: webauthn.Authenticator{
+			AAGUID:    passkey.AAGUID,
+			SignCount: uint32(passkey.SignCount),
+		},
+	}
+}
+
+func (s *PasskeyService) loadUser(ctx context.Context, userId uuid.UUID) (*passkeyUser, error) {
+	user, err := s.userRepo.GetUserById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	passkeys, err := s.passkeyRepo.GetPasskeysByUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return &passkeyUser{user: user, passkeys: passkeys}, nil
+}
+
+func (s *PasskeyService) GetPasskeys(ctx context.Context, userId uuid.UUID) ([]*domain.Passkey, error) {
+	return s.passkeyRepo.GetPasskeysByUser(ctx, userId)
+}
+
+// BeginRegistration starts adding a passkey to the account. The options are
+// passed to navigator.credentials.create() in the browser.
+func (s *PasskeyService) BeginRegistration(ctx context.Context, userId uuid.UUID) (string, *protocol.CredentialCreation, error) {
+	user, err := s.loadUser(ctx, userId)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Authenticators refuse to register a second passkey for the account
+	exclusions := webauthn.Credentials(user.WebAuthnCredentials()).CredentialDescriptors()
+	options, session, err := s.webauthn.BeginRegistration(user, webauthn.WithExclusions(exclusions))
+	if err != nil {
+		return "", nil, err
+	}
+
+	ceremonyId, err := s.saveCeremony(ctx, session)
+	if err != nil {
+		return "", nil, err
+	}
+	return ceremonyId, options, nil
+}
+
+// FinishRegistration verifies the new credential and stores it as a passkey
+func (s *PasskeyService) FinishRegistration(ctx context.Context, userId uuid.UUID, ceremonyId, name string, response []byte) (*domain.Passkey, error) {
+	passkey := &domain.Passkey{UserID: userId, Name: name}
+	passkey.BeforeSave()
+	if err := passkey.Validate(); err != nil {
+		return nil, err
+	}
+
+	session, err := s.takeCeremony(ctx, ceremonyId)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.loadUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(response)
+	if err != nil {
+		return nil, passkeyError(err)
+	}
+
+	credential, err := s.webauthn.CreateCredential(user, *session, parsed)
+	if err != nil {
+		return nil, passkeyError(err)
+	}
+
+	passkey.CredentialID = credential.ID
+	passkey.PublicKey = credential.PublicKey
+	passkey.AttestationType = credential.AttestationType
+	passkey.AAGUID = credential.Authenticator.AAGUID
+	passkey.SignCount = int64(credential.Authenticator.SignCount)
+	passkey.UserVerified = credential.Flags.UserVerified
+	passkey.BackupEligible = credential.Flags.BackupEligible
+	passkey.BackupState = credential.Flags.BackupState
+	for _, transport := range credential.Transport {
+		passkey.Transports = append(passkey.Transports, string(transport))
+	}
+
+	if err := s.passkeyRepo.CreatePasskey(ctx, passkey); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return nil, ErrPasskeyExists
+		}
+		return nil, err
+	}
+	return passkey, nil
+}
+
+// BeginLogin starts a passwordless login. No account is named, the browser
+// offers the passkeys it has for the site.
+func (s *PasskeyService) BeginLogin(ctx context.Context) (string, *protocol.CredentialAssertion, error) {
+	options, session, err := s.webauthn.BeginDiscoverableLogin(webauthn.WithUserVerification(protocol.VerificationRequired))
+	if err != nil {
+		return "", nil, err
+	}
+
+	ceremonyId, err := s.saveCeremony(ctx, session)
+	if err != nil {
+		return "", nil, err
+	}
+	return ceremonyId, options, nil
+}
+
+// FinishLogin verifies the assertion and returns the user it belongs to. A
+// signature counter that did not increase means the credential was copied,
+// and the login is refused.
+func (s *PasskeyService) FinishLogin(ctx context.Context, ceremonyId string, response []byte) (*domain.User, error) {
+	session, err := s.takeCeremony(ctx, ceremonyId)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(response)
+	if err != nil {
+		return nil, passkeyError(err)
+	}
+
+	var (
+		owner   *passkeyUser
+		passkey *domain.Passkey
+	)
+	findUser := func(rawId, userHandle []byte) (webauthn.User, error) {
+		found, err := s.passkeyRepo.GetPasskeyByCredentialID(ctx, rawId)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(found.UserID[:], userHandle) {
+			return nil, ErrPasskeyInvalid
+		}
+
+		user, err := s.loadUser(ctx, found.UserID)
+		if err != nil {
+			return nil, err
+		}
+		passkey, owner = found, user
+		return owner, nil
+	}
+
+	credential, err := s.webauthn.ValidateDiscoverableLogin(findUser, *session, parsed)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) || errors.Is(err, ErrUserNotFound) {
+			return nil, ErrPasskeyNotFound
+		}
+		return nil, passkeyError(err)
+	}
+
+	if credential.Authenticator.CloneWarning {
+		log.Warn().
+			Str("user_id", passkey.UserID.String()).
+			Str("passkey_id", passkey.ID.String()).
+			Msg("passkey signature counter did not increase, possible cloned authenticator")
+		return nil, ErrPasskeyCloned
+	}
+
+	previousSignCount := passkey.SignCount
+	passkey.SignCount = int64(credential.Authenticator.SignCount)
+	passkey.UserVerified = credential.Flags.UserVerified
+	passkey.BackupState = credential.Flags.BackupState
+	if err := s.passkeyRepo.UpdatePasskeyUsage(ctx, passkey, previousSignCount); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			// Another login with the same counter value won the race
+			return nil, ErrPasskeyCloned
+		}
+		return nil, err
+	}
+
+	return owner.user, nil
+}
+
+func (s *PasskeyService) RenamePasskey(ctx context.Context, userId, id uuid.UUID, name string) error {
+	passkey := &domain.Passkey{Name: name}
+	passkey.BeforeSave()
+	if err := passkey.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.passkeyRepo.RenamePasskey(ctx, userId, id, passkey.Name); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrPasskeyNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *PasskeyService) DeletePasskey(ctx context.Context, userId, id uuid.UUID) error {
+	if err := s.passkeyRepo.DeletePasskey(ctx, userId, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrPasskeyNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *PasskeyService) saveCeremony(ctx context.Context, session *webauthn.SessionData) (string, error) {
+	random := make([]byte, 24)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	ceremonyId := base64.RawURLEncoding.EncodeToString(random)
+
+	if err := s.ceremonies.SaveCeremony(ctx, ceremonyId, session, ceremonyTTL); err != nil {
+		return "", err
+	}
+	return ceremonyId, nil
+}
+
+func (s *PasskeyService) takeCeremony(ctx context.Context, ceremonyId string) (*webauthn.SessionData, error) {
+	session, err := s.ceremonies.TakeCeremony(ctx, ceremonyId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrCeremonyNotFound
+		}
+		return nil, err
+	}
+
+	// The library does not check the timeout of discoverable logins, the
+	// store TTL alone would leave it to Redis
+	if !session.Expires.IsZero() && !time.Now().Before(session.Expires) {
+		return nil, ErrCeremonyNotFound
+	}
+	return session, nil
+}
+
+// passkeyError wraps protocol errors, whose details are only logged
+func passkeyError(err error) error {
+	var protocolErr *protocol.Error
+	if errors.As(err, &protocolErr) {
+		log.Info().Str("type", protocolErr.Type).Str("details", protocolErr.Details).Str("info", protocolErr.DevInfo).Msg("webauthn verification failed")
+		return fmt.Errorf("%w: %s", ErrPasskeyInvalid, protocolErr.Details)
+	}
+	return err
+}
@@ -0,0 +1,382 @@
+package service
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"cv_builder/internal/domain"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"testing"
+	"time"
+)
+
+const (
+	testRPID   = "cv.example.com"
+	testOrigin = "https://cv.example.com"
+)
+
+// Authenticator data flags, see the WebAuthn spec
+const (
+	flagUserPresent  = 0x01
+	flagUserVerified = 0x04
+	flagAttested     = 0x40
+)
+
+// softAuthenticator is a software authenticator holding one ES256 passkey.
+// signCount is the counter it reports with the next signature.
+type softAuthenticator struct {
+	key          *ecdsa.PrivateKey
+	credentialId []byte
+	userHandle   []byte
+	signCount    uint32
+}
+
+func newSoftAuthenticator(t *testing.T) *softAuthenticator {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	credentialId := make([]byte, 16)
+	if _, err := rand.Read(credentialId); err != nil {
+		t.Fatal(err)
+	}
+	return &softAuthenticator{key: key, credentialId: credentialId}
+}
+
+func (a *softAuthenticator) clientData(t *testing.T, ceremony string, challenge protocol.URLEncodedBase64) []byte {
+	t.Helper()
+
+	data, err := json.Marshal(map[string]string{
+		"type":      ceremony,
+		"challenge": base64.RawURLEncoding.EncodeToString(challenge),
+		"origin":    testOrigin,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// authenticatorData is the RP ID hash, the flags and the sign counter,
+// followed by the attested credential when registering
+func (a *softAuthenticator) authenticatorData(t *testing.T, flags byte) []byte {
+	t.Helper()
+
+	rpIdHash := sha256.Sum256([]byte(testRPID))
+	data := append(rpIdHash[:], flags)
+	data = binary.BigEndian.AppendUint32(data, a.signCount)
+	if flags&flagAttested == 0 {
+		return data
+	}
+
+	publicKey, err := webauthncbor.Marshal(webauthncose.EC2PublicKeyData{
+		PublicKeyData: webauthncose.PublicKeyData{
+			KeyType:   int64(webauthncose.EllipticKey),
+			Algorithm: int64(webauthncose.AlgES256),
+		},
+		Curve:  1, // P-256
+		XCoord: a.key.X.FillBytes(make([]byte, 32)),
+		YCoord: a.key.Y.FillBytes(make([]byte, 32)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data = append(data, make([]byte, 16)...) // zero AAGUID
+	data = binary.BigEndian.AppendUint16(data, uint16(len(a.credentialId)))
+	data = append(data, a.credentialId...)
+	return append(data, publicKey...)
+}
+
+// create answers navigator.credentials.create() with a "none" attestation
+func (a *softAuthenticator) create(t *testing.T, options *protocol.CredentialCreation) []byte {
+	t.Helper()
+
+	a.userHandle = options.Response.User.ID.(protocol.URLEncodedBase64)
+	attestation, err := webauthncbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": a.authenticatorData(t, flagUserPresent|flagUserVerified|flagAttested),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return a.credentialJSON(t, map[string]any{
+		"clientDataJSON":    a.clientData(t, "webauthn.create", options.Response.Challenge),
+		"attestationObject": attestation,
+		"transports":        []string{"internal"},
+	})
+}
+
+// get answers navigator.credentials.get() with a signed assertion
+func (a *softAuthenticator) get(t *testing.T, options *protocol.CredentialAssertion) []byte {
+	t.Helper()
+
+	clientData := a.clientData(t, "webauthn.get", options.Response.Challenge)
+	authData := a.authenticatorData(t, flagUserPresent|flagUserVerified)
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(authData, clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return a.credentialJSON(t, map[string]any{
+		"clientDataJSON":    clientData,
+		"authenticatorData": authData,
+		"signature":         signature,
+		"userHandle":        a.userHandle,
+	})
+}
+
+// credentialJSON encodes the credential like the browser, binary fields as
+// base64url
+func (a *softAuthenticator) credentialJSON(t *testing.T, response map[string]any) []byte {
+	t.Helper()
+
+	encoded := map[string]any{}
+	for name, value := range response {
+		if data, ok := value.([]byte); ok {
+			value = base64.RawURLEncoding.EncodeToString(data)
+		}
+		encoded[name] = value
+	}
+
+	data, err := json.Marshal(map[string]any{
+		"id":       base64.RawURLEncoding.EncodeToString(a.credentialId),
+		"rawId":    base64.RawURLEncoding.EncodeToString(a.credentialId),
+		"type":     "public-key",
+		"response": encoded,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+type passkeyTest struct {
+	service    *PasskeyService
+	users      *memUserRepo
+	passkeys   *memPasskeyRepo
+	ceremonies *memStore
+	user       *domain.User
+}
+
+func newPasskeyTest(t *testing.T) *passkeyTest {
+	t.Helper()
+
+	users := newMemUserRepo()
+	passkeys := &memPasskeyRepo{}
+	ceremonies := newMemStore()
+	service, err := NewPasskeyService(passkeys, users, ceremonies, PasskeyConfig{
+		RPID:          testRPID,
+		RPDisplayName: "CV Builder",
+		RPOrigins:     []string{testOrigin},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &passkeyTest{
+		service:    service,
+		users:      users,
+		passkeys:   passkeys,
+		ceremonies: ceremonies,
+		user:       users.add(&domain.User{Email: "carol@example.com"}),
+	}
+}
+
+// register adds the authenticator's passkey to the test user
+func (p *passkeyTest) register(t *testing.T, authenticator *softAuthenticator) *domain.Passkey {
+	t.Helper()
+
+	ctx := context.Background()
+	ceremonyId, options, err := p.service.BeginRegistration(ctx, p.user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	passkey, err := p.service.FinishRegistration(ctx, p.user.ID, ceremonyId, "Laptop", authenticator.create(t, options))
+	if err != nil {
+		t.Fatalf("FinishRegistration: %v", err)
+	}
+	return passkey
+}
+
+// login signs in with the authenticator
+func (p *passkeyTest) login(t *testing.T, authenticator *softAuthenticator) (*domain.User, error) {
+	t.Helper()
+
+	ceremonyId, options, err := p.service.BeginLogin(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p.service.FinishLogin(context.Background(), ceremonyId, authenticator.get(t, options))
+}
+
+func TestPasskeyRegisterAndLogin(t *testing.T) {
+	p := newPasskeyTest(t)
+	authenticator := newSoftAuthenticator(t)
+
+	passkey := p.register(t, authenticator)
+	if passkey.Name != "Laptop" || !passkey.UserVerified || passkey.AttestationType != "none" {
+		t.Errorf("registered passkey = %+v", passkey)
+	}
+
+	for count := uint32(1); count <= 2; count++ {
+		authenticator.signCount = count
+		user, err := p.login(t, authenticator)
+		if err != nil {
+			t.Fatalf("login with counter %d: %v", count, err)
+		}
+		if user.ID != p.user.ID {
+			t.Errorf("logged in as %s, want %s", user.ID, p.user.ID)
+		}
+	}
+
+	stored, err := p.passkeys.GetPasskeyByCredentialID(context.Background(), authenticator.credentialId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.SignCount != 2 || stored.LastUsedAt == nil {
+		t.Errorf("stored passkey sign count %d, last used %v, want 2 and set", stored.SignCount, stored.LastUsedAt)
+	}
+}
+
+func TestPasskeyRegisterTwice(t *testing.T) {
+	p := newPasskeyTest(t)
+	authenticator := newSoftAuthenticator(t)
+	p.register(t, authenticator)
+
+	ctx := context.Background()
+	ceremonyId, options, err := p.service.BeginRegistration(ctx, p.user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(options.Response.CredentialExcludeList) != 1 {
+		t.Errorf("exclude list = %v, want the registered passkey", options.Response.CredentialExcludeList)
+	}
+	if _, err := p.service.FinishRegistration(ctx, p.user.ID, ceremonyId, "", authenticator.create(t, options)); !errors.Is(err, ErrPasskeyExists) {
+		t.Errorf("second registration error = %v, want %v", err, ErrPasskeyExists)
+	}
+}
+
+func TestPasskeyLoginRejectsCounterRegression(t *testing.T) {
+	tests := []struct {
+		name  string
+		count uint32
+	}{
+		{name: "same counter", count: 5},
+		{name: "lower counter", count: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newPasskeyTest(t)
+			authenticator := newSoftAuthenticator(t)
+			p.register(t, authenticator)
+
+			authenticator.signCount = 5
+			if _, err := p.login(t, authenticator); err != nil {
+				t.Fatal(err)
+			}
+
+			authenticator.signCount = tt.count
+			if _, err := p.login(t, authenticator); !errors.Is(err, ErrPasskeyCloned) {
+				t.Errorf("login error = %v, want %v", err, ErrPasskeyCloned)
+			}
+
+			stored, err := p.passkeys.GetPasskeyByCredentialID(context.Background(), authenticator.credentialId)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored.SignCount != 5 {
+				t.Errorf("stored sign count = %d, want 5", stored.SignCount)
+			}
+		})
+	}
+}
+
+func TestPasskeyLoginRejectsCeremony(t *testing.T) {
+	tests := []struct {
+		name    string
+		spoil   func(p *passkeyTest, ceremonyId string)
+		reuse   bool
+		wantErr error
+	}{
+		{
+			name:    "reused",
+			reuse:   true,
+			wantErr: ErrCeremonyNotFound,
+		},
+		{
+			name:    "expired in the store",
+			spoil:   func(p *passkeyTest, ceremonyId string) { p.ceremonies.expire(ceremonyId) },
+			wantErr: ErrCeremonyNotFound,
+		},
+		{
+			name: "past its timeout",
+			spoil: func(p *passkeyTest, ceremonyId string) {
+				var session webauthn.SessionData
+				p.ceremonies.update(ceremonyId, &session, func() { session.Expires = time.Now().Add(-time.Second) })
+			},
+			wantErr: ErrCeremonyNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newPasskeyTest(t)
+			authenticator := newSoftAuthenticator(t)
+			p.register(t, authenticator)
+			ctx := context.Background()
+
+			ceremonyId, options, err := p.service.BeginLogin(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			authenticator.signCount = 1
+			response := authenticator.get(t, options)
+
+			if tt.reuse {
+				if _, err := p.service.FinishLogin(ctx, ceremonyId, response); err != nil {
+					t.Fatal(err)
+				}
+				// A replay with a higher counter must not get past the used ceremony
+				authenticator.signCount = 2
+				response = authenticator.get(t, options)
+			}
+			if tt.spoil != nil {
+				tt.spoil(p, ceremonyId)
+			}
+
+			if _, err := p.service.FinishLogin(ctx, ceremonyId, response); !errors.Is(err, tt.wantErr) {
+				t.Errorf("FinishLogin error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPasskeyLoginRejectsForeignUserHandle(t *testing.T) {
+	p := newPasskeyTest(t)
+	authenticator := newSoftAuthenticator(t)
+	p.register(t, authenticator)
+
+	other := p.users.add(&domain.User{Email: "mallory@example.com"})
+	authenticator.userHandle = other.ID[:]
+	authenticator.signCount = 1
+	if _, err := p.login(t, authenticator); !errors.Is(err, ErrPasskeyInvalid) {
+		t.Errorf("login error = %v, want %v", err, ErrPasskeyInvalid)
+	}
+}
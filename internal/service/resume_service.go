@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"cv_builder/internal/domain"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type ResumeService struct {
+	resumeRepo domain.ResumeRepository
+}
+
+func NewResumeService(resumeRepo domain.ResumeRepository) *ResumeService {
+	return &ResumeService{
+		resumeRepo: resumeRepo,
+	}
+}
+
+// CreateResume creates a resume for the user together with all of its
+// sections. Every section is validated before anything is written, and a
+// partially saved resume is removed again if one of the inserts fails.
+func (s *ResumeService) CreateResume(ctx context.Context, userId uuid.UUID, content *domain.Resume) (*domain.Resume, error) {
+	if err := validateResumeContent(content); err != nil {
+		return nil, err
+	}
+
+	resume, err := s.resumeRepo.CreateCV(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.saveSections(ctx, resume.ID, content); err != nil {
+		if deleteErr := s.resumeRepo.DeleteCV(ctx, resume.ID); deleteErr != nil {
+			log.Error().Err(deleteErr).Str("resume_id", resume.ID.String()).Msg("failed to clean up partially created resume")
+		}
+		return nil, err
+	}
+
+	return s.resumeRepo.GetCompleteResume(ctx, resume.ID)
+}
+
+func (s *ResumeService) saveSections(ctx context.Context, resumeId uuid.UUID, content *domain.Resume) error {
+	if content.PersonalInfo != nil {
+		if err := s.resumeRepo.SavePersonalInfo(ctx, resumeId, content.PersonalInfo); err != nil {
+			return err
+		}
+	}
+	for _, education := range content.Education {
+		if _, err := s.resumeRepo.AddEducation(ctx, resumeId, education); err != nil {
+			return err
+		}
+	}
+	for _, experience := range content.Experience {
+		if _, err := s.resumeRepo.AddExperience(ctx, resumeId, experience); err != nil {
+			return err
+		}
+	}
+	for _, skill := range content.Skills {
+		if _, err := s.resumeRepo.AddSkill(ctx, resumeId, skill); err != nil {
+			return err
+		}
+	}
+	for _, language := range content.Languages {
+		if _, err := s.resumeRepo.AddLanguage(ctx, resumeId, language); err != nil {
+			return err
+		}
+	}
+	for _, project := range content.Projects {
+		if _, err := s.resumeRepo.AddProject(ctx, resumeId, project); err != nil {
+			return err
+		}
+	}
+	for _, certification := range content.Certifications {
+		if _, err := s.resumeRepo.AddCertification(ctx, resumeId, certification); err != nil {
+			return err
+		}
+	}
+	for _, volunteer := range content.Volunteer {
+		if _, err := s.resumeRepo.AddVolunteer(ctx, resumeId, volunteer); err != nil {
+			return err
+		}
+	}
+	for _, award := range content.Awards {
+		if _, err := s.resumeRepo.AddAward(ctx, resumeId, award); err != nil {
+			return err
+		}
+	}
+	for _, reference := range content.References {
+		if _, err := s.resumeRepo.AddReference(ctx, resumeId, reference); err != nil {
+			return err
+		}
+	}
+	for _, section := range content.CustomSections {
+		if _, err := s.resumeRepo.AddCustomSection(ctx, resumeId, section); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateResumeContent validates every section of a resume, prefixing the
+// field of a validation error with the section and entry it belongs to
+func validateResumeContent(content *domain.Resume) error {
+	if content.PersonalInfo != nil {
+		content.PersonalInfo.BeforeSave()
+		if err := content.PersonalInfo.Validate(); err != nil {
+			return prefixValidationError("personal_info", err)
+		}
+	}
+	for i, education := range content.Education {
+		if education == nil {
+			return domain.NewValidationError(fmt.Sprintf("education[%d]", i), "Entry is empty", domain.ErrInvalidField)
+		}
+		education.BeforeSave()
+		if err := education.Validate(); err != nil {
+			return prefixValidationError(fmt.Sprintf("education[%d]", i), err)
+		}
+	}
+	for i, experience := range content.Experience {
+		if experience == nil {
+			return domain.NewValidationError(fmt.Sprintf("experience[%d]", i), "Entry is empty", domain.ErrInvalidField)
+		}
+		experience.BeforeSave()
+		if err := experience.Validate(); err != nil {
+			return prefixValidationError(fmt.Sprintf("experience[%d]", i), err)
+		}
+	}
+	for i, skill := range content.Skills {
+		if skill == nil {
+			return domain.NewValidationError(fmt.Sprintf("skills[%d]", i), "Entry is empty", domain.ErrInvalidField)
+		}
+		skill.BeforeSave()
+		if err := skill.Validate(); err != nil {
+			return prefixValidationError(fmt.Sprintf("skills[%d]", i), err)
+		}
+	}
+	for i, language := range content.Languages {
+		if language == nil {
+			return domain.NewValidationError(fmt.Sprintf("languages[%d]", i), "Entry is empty", domain.ErrInvalidField)
+		}
+		language.BeforeSave()
+		if err := language.Validate(); err != nil {
+			return prefixValidationError(fmt.Sprintf("languages[%d]", i), err)
+		}
+	}
+	for i, project := range content.Projects {
+		if project == nil {
+			return domain.NewValidationError(fmt.Sprintf("projects[%d]", i), "Entry is empty", domain.ErrInvalidField)
+		}
+		project.BeforeSave()
+		if err := project.Validate(); err != nil {
+			return prefixValidationError(fmt.Sprintf("projects[%d]", i), err)
+		}
+	}
+	for i, certification := range content.Certifications {
+		if certification == nil {
+			return domain.NewValidationError(fmt.Sprintf("certifications[%d]", i), "Entry is empty", domain.ErrInvalidField)
+		}
+		certification.BeforeSave()
+		if err := certification.Validate(); err != nil {
+			return prefixValidationError(fmt.Sprintf("certifications[%d]", i), err)
+		}
+	}
+	for i, volunteer := range content.Volunteer {
+		if volunteer == nil {
+			return domain.NewValidationError(fmt.Sprintf("volunteer[%d]", i), "Entry is empty", domain.ErrInvalidField)
+		}
+		volunteer.BeforeSave()
+		if err := volunteer.Validate(); err != nil {
+			return prefixValidationError(fmt.Sprintf("volunteer[%d]", i), err)
+		}
+	}
+	for i, award := range content.Awards {
+		if award == nil {
+			return domain.NewValidationError(fmt.Sprintf("awards[%d]", i), "Entry is empty", domain.ErrInvalidField)
+		}
+		award.BeforeSave()
+		if err := award.Validate(); err != nil {
+			return prefixValidationError(fmt.Sprintf("awards[%d]", i), err)
+		}
+	}
+	for i, reference := range content.References {
+		if reference == nil {
+			return domain.NewValidationError(fmt.Sprintf("references[%d]", i), "Entry is empty", domain.ErrInvalidField)
+		}
+		reference.BeforeSave()
+		if err := reference.Validate(); err != nil {
+			return prefixValidationError(fmt.Sprintf("references[%d]", i), err)
+		}
+	}
+	for i, section := range content.CustomSections {
+		if section == nil {
+			return domain.NewValidationError(fmt.Sprintf("custom_sections[%d]", i), "Entry is empty", domain.ErrInvalidField)
+		}
+		section.BeforeSave()
+		if err := section.Validate(); err != nil {
+			return prefixValidationError(fmt.Sprintf("custom_sections[%d]", i), err)
+		}
+	}
+	return nil
+}
+
+func prefixValidationError(prefix string, err error) error {
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		return domain.NewValidationError(prefix+"."+validationErr.Field, validationErr.Message, validationErr.Err)
+	}
+	return domain.NewValidationError(prefix, err.Error(), err)
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"cv_builder/internal/domain"
+	"github.com/google/uuid"
+)
+
+type TemplateService struct {
+	templateRepo  domain.TemplateRepository
+	resumeService *ResumeService
+}
+
+func NewTemplateService(templateRepo domain.TemplateRepository, resumeService *ResumeService) *TemplateService {
+	return &TemplateService{
+		templateRepo:  templateRepo,
+		resumeService: resumeService,
+	}
+}
+
+func (s *TemplateService) GetTemplates(ctx context.Context) ([]*domain.ResumeTemplate, error) {
+	return s.templateRepo.GetTemplates(ctx)
+}
+
+func (s *TemplateService) GetTemplate(ctx context.Context, slug string) (*domain.ResumeTemplate, error) {
+	return s.templateRepo.GetTemplate(ctx, slug)
+}
+
+// CreateTemplate adds a template to the catalog. Its content is validated
+// like a resume, so every template can be instantiated.
+func (s *TemplateService) CreateTemplate(ctx context.Context, template *domain.ResumeTemplate) error {
+	if err := validateTemplate(template); err != nil {
+		return err
+	}
+	return s.templateRepo.CreateTemplate(ctx, template)
+}
+
+func (s *TemplateService) UpdateTemplate(ctx context.Context, slug string, template *domain.ResumeTemplate) error {
+	if err := validateTemplate(template); err != nil {
+		return err
+	}
+	return s.templateRepo.UpdateTemplate(ctx, slug, template)
+}
+
+func (s *TemplateService) DeleteTemplate(ctx context.Context, slug string) error {
+	return s.templateRepo.DeleteTemplate(ctx, slug)
+}
+
+// CreateResumeFromTemplate creates a resume for the user with a copy of the
+// content of the template
+func (s *TemplateService) CreateResumeFromTemplate(ctx context.Context, userId uuid.UUID, slug string) (*domain.Resume, error) {
+	template, err := s.templateRepo.GetTemplate(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	return s.resumeService.CreateResume(ctx, userId, template.Content)
+}
+
+func validateTemplate(template *domain.ResumeTemplate) error {
+	template.BeforeSave()
+	if err := template.Validate(); err != nil {
+		return err
+	}
+	if err := validateResumeContent(template.Content); err != nil {
+		return prefixValidationError("content", err)
+	}
+	return nil
+}
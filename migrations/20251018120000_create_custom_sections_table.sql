@@ -0,0 +1,43 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Custom sections hold content the fixed sections do not cover, such as
+-- publications, talks or patents
+CREATE TABLE custom_sections (
+                                 id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                                 resume_id UUID NOT NULL,
+                                 title TEXT NOT NULL,
+                                 position INT NOT NULL DEFAULT 0,
+                                 created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                                 updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                                 CONSTRAINT fk_custom_sections_resume FOREIGN KEY (resume_id)
+                                     REFERENCES resumes(id) ON DELETE CASCADE
+);
+
+CREATE TABLE custom_section_entries (
+                                        id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                                        section_id UUID NOT NULL,
+                                        title TEXT NOT NULL,
+                                        subtitle TEXT,
+                                        start_date DATE,
+                                        end_date DATE,
+                                        is_current BOOLEAN NOT NULL DEFAULT FALSE,
+                                        url TEXT,
+                                        bullets TEXT[] NOT NULL DEFAULT '{}',
+                                        position INT NOT NULL DEFAULT 0,
+                                        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                                        updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                                        CONSTRAINT fk_custom_section_entries_section FOREIGN KEY (section_id)
+                                            REFERENCES custom_sections(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_custom_sections_resume_id ON custom_sections(resume_id, position);
+CREATE INDEX idx_custom_section_entries_section_id ON custom_section_entries(section_id, position);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX IF EXISTS idx_custom_section_entries_section_id;
+DROP INDEX IF EXISTS idx_custom_sections_resume_id;
+
+DROP TABLE IF EXISTS custom_section_entries;
+DROP TABLE IF EXISTS custom_sections;
@@ -0,0 +1,23 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Spoken languages with a CEFR level (A1-C2) or "native"
+CREATE TABLE languages (
+                           id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                           resume_id UUID NOT NULL,
+                           code TEXT NOT NULL,
+                           level TEXT NOT NULL CHECK (level IN ('A1', 'A2', 'B1', 'B2', 'C1', 'C2', 'native')),
+                           certificate TEXT,
+                           created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                           updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                           CONSTRAINT fk_languages_resume FOREIGN KEY (resume_id)
+                               REFERENCES resumes(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_languages_resume_id ON languages(resume_id);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX IF EXISTS idx_languages_resume_id;
+
+DROP TABLE IF EXISTS languages;
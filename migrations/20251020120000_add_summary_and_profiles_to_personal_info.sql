@@ -0,0 +1,40 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+ALTER TABLE personal_info ADD COLUMN summary TEXT;
+
+-- A resume has a single personal info row, which SavePersonalInfo upserts on
+-- resume_id. Keep the most recently updated row of any duplicates before
+-- enforcing it.
+DELETE FROM personal_info a
+    USING personal_info b
+WHERE a.resume_id = b.resume_id
+  AND (a.updated_at < b.updated_at OR (a.updated_at = b.updated_at AND a.id < b.id));
+
+DROP INDEX IF EXISTS idx_personal_info_resume_id;
+CREATE UNIQUE INDEX idx_personal_info_resume_id ON personal_info(resume_id);
+
+-- Links to online profiles such as LinkedIn or GitHub
+CREATE TABLE personal_info_profiles (
+                                        id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                                        personal_info_id UUID NOT NULL,
+                                        network TEXT NOT NULL,
+                                        username TEXT,
+                                        url TEXT NOT NULL,
+                                        position INT NOT NULL DEFAULT 0,
+                                        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                                        CONSTRAINT fk_personal_info_profiles_personal_info FOREIGN KEY (personal_info_id)
+                                            REFERENCES personal_info(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_personal_info_profiles_personal_info_id ON personal_info_profiles(personal_info_id, position);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX IF EXISTS idx_personal_info_profiles_personal_info_id;
+DROP TABLE IF EXISTS personal_info_profiles;
+
+DROP INDEX IF EXISTS idx_personal_info_resume_id;
+CREATE INDEX idx_personal_info_resume_id ON personal_info(resume_id);
+
+ALTER TABLE personal_info DROP COLUMN IF EXISTS summary;
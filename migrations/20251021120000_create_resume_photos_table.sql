@@ -0,0 +1,20 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Profile photo of a resume; the images themselves are kept in blob storage
+CREATE TABLE resume_photos (
+                               resume_id UUID PRIMARY KEY,
+                               content_type TEXT NOT NULL CHECK (content_type IN ('image/jpeg', 'image/png')),
+                               original_key TEXT NOT NULL,
+                               thumbnail_key TEXT NOT NULL,
+                               width INTEGER NOT NULL,
+                               height INTEGER NOT NULL,
+                               created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                               updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                               CONSTRAINT fk_resume_photos_resume FOREIGN KEY (resume_id)
+                                   REFERENCES resumes(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS resume_photos;
@@ -0,0 +1,66 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+CREATE TABLE volunteer (
+                           id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                           resume_id UUID NOT NULL,
+                           organization TEXT NOT NULL,
+                           role TEXT NOT NULL,
+                           location TEXT,
+                           start_date DATE NOT NULL,
+                           end_date DATE,
+                           is_current BOOLEAN NOT NULL DEFAULT FALSE,
+                           description TEXT,
+                           url TEXT,
+                           created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                           updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                           CONSTRAINT fk_volunteer_resume FOREIGN KEY (resume_id)
+                               REFERENCES resumes(id) ON DELETE CASCADE
+);
+
+CREATE TABLE awards (
+                        id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                        resume_id UUID NOT NULL,
+                        title TEXT NOT NULL,
+                        awarder TEXT,
+                        award_date DATE NOT NULL,
+                        description TEXT,
+                        url TEXT,
+                        created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                        updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                        CONSTRAINT fk_awards_resume FOREIGN KEY (resume_id)
+                            REFERENCES resumes(id) ON DELETE CASCADE
+);
+
+-- References hold personal data of third parties and are left out of
+-- public share links unless the owner opts in
+CREATE TABLE resume_references (
+                                    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                                    resume_id UUID NOT NULL,
+                                    name TEXT NOT NULL,
+                                    relationship TEXT NOT NULL,
+                                    company TEXT,
+                                    email TEXT,
+                                    phone TEXT,
+                                    available_on_request BOOLEAN NOT NULL DEFAULT FALSE,
+                                    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                                    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                                    CONSTRAINT fk_resume_references_resume FOREIGN KEY (resume_id)
+                                        REFERENCES resumes(id) ON DELETE CASCADE,
+                                    CONSTRAINT check_resume_references_contact
+                                        CHECK (available_on_request OR email IS NOT NULL OR phone IS NOT NULL)
+);
+
+CREATE INDEX idx_volunteer_resume_id ON volunteer(resume_id);
+CREATE INDEX idx_awards_resume_id ON awards(resume_id);
+CREATE INDEX idx_resume_references_resume_id ON resume_references(resume_id);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX IF EXISTS idx_resume_references_resume_id;
+DROP INDEX IF EXISTS idx_awards_resume_id;
+DROP INDEX IF EXISTS idx_volunteer_resume_id;
+
+DROP TABLE IF EXISTS resume_references;
+DROP TABLE IF EXISTS awards;
+DROP TABLE IF EXISTS volunteer;
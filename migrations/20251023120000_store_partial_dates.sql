@@ -0,0 +1,59 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Dates may be known only to the year or the month, so they are stored as
+-- "YYYY", "YYYY-MM" or "YYYY-MM-DD" text. These sort like dates. An ongoing
+-- period ends with "Present" rather than NULL, which now means no end date.
+ALTER TABLE education
+    ALTER COLUMN start_date TYPE TEXT USING to_char(start_date, 'YYYY-MM-DD'),
+    ALTER COLUMN end_date TYPE TEXT USING to_char(end_date, 'YYYY-MM-DD');
+UPDATE education SET end_date = 'Present' WHERE end_date IS NULL;
+
+ALTER TABLE experience
+    ALTER COLUMN start_date TYPE TEXT USING to_char(start_date, 'YYYY-MM-DD'),
+    ALTER COLUMN end_date TYPE TEXT USING to_char(end_date, 'YYYY-MM-DD');
+UPDATE experience SET end_date = 'Present' WHERE end_date IS NULL;
+
+ALTER TABLE projects
+    ALTER COLUMN start_date TYPE TEXT USING to_char(start_date, 'YYYY-MM-DD'),
+    ALTER COLUMN end_date TYPE TEXT USING to_char(end_date, 'YYYY-MM-DD');
+UPDATE projects SET end_date = 'Present' WHERE end_date IS NULL AND start_date IS NOT NULL;
+
+-- Certifications without an expiry date keep NULL
+ALTER TABLE certifications
+    ALTER COLUMN issue_date TYPE TEXT USING to_char(issue_date, 'YYYY-MM-DD'),
+    ALTER COLUMN expiry_date TYPE TEXT USING to_char(expiry_date, 'YYYY-MM-DD');
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+
+-- Partial dates fall back to the first day of their year or month and
+-- "Present" to NULL
+-- +goose StatementBegin
+CREATE FUNCTION partial_date_to_date(value TEXT) RETURNS DATE AS $$
+SELECT CASE
+           WHEN value IS NULL OR value = 'Present' THEN NULL
+           WHEN length(value) = 4 THEN to_date(value, 'YYYY')
+           WHEN length(value) = 7 THEN to_date(value, 'YYYY-MM')
+           ELSE to_date(value, 'YYYY-MM-DD')
+           END
+$$ LANGUAGE SQL IMMUTABLE;
+-- +goose StatementEnd
+
+ALTER TABLE certifications
+    ALTER COLUMN issue_date TYPE DATE USING partial_date_to_date(issue_date),
+    ALTER COLUMN expiry_date TYPE DATE USING partial_date_to_date(expiry_date);
+
+ALTER TABLE projects
+    ALTER COLUMN start_date TYPE DATE USING partial_date_to_date(start_date),
+    ALTER COLUMN end_date TYPE DATE USING partial_date_to_date(end_date);
+
+ALTER TABLE experience
+    ALTER COLUMN start_date TYPE DATE USING partial_date_to_date(start_date),
+    ALTER COLUMN end_date TYPE DATE USING partial_date_to_date(end_date);
+
+ALTER TABLE education
+    ALTER COLUMN start_date TYPE DATE USING partial_date_to_date(start_date),
+    ALTER COLUMN end_date TYPE DATE USING partial_date_to_date(end_date);
+
+DROP FUNCTION partial_date_to_date(TEXT);
@@ -0,0 +1,23 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- The resume itself holds the text of its default locale
+ALTER TABLE resumes ADD COLUMN default_locale TEXT NOT NULL DEFAULT 'en';
+
+-- Translated free text of a resume, keyed by entry ID, see domain.Translation
+CREATE TABLE resume_translations (
+                                     resume_id UUID NOT NULL,
+                                     locale TEXT NOT NULL,
+                                     content JSONB NOT NULL,
+                                     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                                     updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                                     PRIMARY KEY (resume_id, locale),
+                                     CONSTRAINT fk_resume_translations_resume FOREIGN KEY (resume_id)
+                                         REFERENCES resumes(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS resume_translations;
+
+ALTER TABLE resumes DROP COLUMN IF EXISTS default_locale;
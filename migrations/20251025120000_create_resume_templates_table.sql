@@ -0,0 +1,281 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Catalog of starter resumes with placeholder content, managed by admins
+CREATE TABLE resume_templates (
+                                  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                                  slug TEXT NOT NULL UNIQUE,
+                                  name TEXT NOT NULL,
+                                  description TEXT NOT NULL DEFAULT '',
+                                  content JSONB NOT NULL,
+                                  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                                  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO resume_templates (slug, name, description, content) VALUES
+('software-engineer', 'Software Engineer',
+ 'Experience-first layout with skills, projects and GitHub links for developers.',
+ '{
+  "personal_info": {
+    "first_name": "First",
+    "last_name": "Last",
+    "email": "name@example.com",
+    "job_title": "Software Engineer",
+    "summary": "Software engineer with N years of experience building [kind of systems] in [main languages]. Known for [strength], looking to [goal].",
+    "profiles": [
+      {
+        "network": "github",
+        "url": "https://github.com/username"
+      },
+      {
+        "network": "linkedin",
+        "url": "https://www.linkedin.com/in/username"
+      }
+    ]
+  },
+  "experience": [
+    {
+      "employer": "Company Name",
+      "title": "Software Engineer",
+      "location": "City, Country",
+      "start_date": "2021-01",
+      "end_date": "Present",
+      "description": "Team and product you worked on, in one sentence.",
+      "achievements": [
+        "Built [feature] with [technology], used by [number] customers",
+        "Reduced [latency, cost, build time] by [percentage] by [what you did]",
+        "Mentored [number] engineers and introduced [practice]"
+      ]
+    }
+  ],
+  "education": [
+    {
+      "institution": "University Name",
+      "location": "City, Country",
+      "degree": "Bachelor of Science",
+      "field": "Computer Science",
+      "start_date": "2016",
+      "end_date": "2020"
+    }
+  ],
+  "skills": [
+    {
+      "name": "Go",
+      "category": "language"
+    },
+    {
+      "name": "TypeScript",
+      "category": "language"
+    },
+    {
+      "name": "PostgreSQL",
+      "category": "database"
+    },
+    {
+      "name": "Docker",
+      "category": "tool"
+    }
+  ],
+  "projects": [
+    {
+      "name": "Project Name",
+      "description": "What the project does and which problem it solves.",
+      "technologies": [
+        "Go",
+        "React"
+      ],
+      "repo_url": "https://github.com/username/project"
+    }
+  ]
+}'),
+('designer', 'Designer',
+ 'Portfolio-oriented resume with case studies for product, UX and visual designers.',
+ '{
+  "personal_info": {
+    "first_name": "First",
+    "last_name": "Last",
+    "email": "name@example.com",
+    "job_title": "Product Designer",
+    "summary": "Product designer focused on [area, e.g. mobile apps or design systems]. I turn research into [outcome] and work closely with engineering.",
+    "profiles": [
+      {
+        "network": "website",
+        "url": "https://www.example.com"
+      },
+      {
+        "network": "other",
+        "url": "https://dribbble.com/username"
+      }
+    ]
+  },
+  "experience": [
+    {
+      "employer": "Company Name",
+      "title": "Product Designer",
+      "location": "City, Country",
+      "start_date": "2021-01",
+      "end_date": "Present",
+      "description": "Product area you designed for and who you worked with.",
+      "achievements": [
+        "Redesigned [flow], raising [conversion, retention] by [percentage]",
+        "Built and maintained a design system of [number] components",
+        "Ran [number] usability studies that shaped [decision]"
+      ]
+    }
+  ],
+  "education": [
+    {
+      "institution": "School of Design",
+      "location": "City, Country",
+      "degree": "Bachelor of Arts",
+      "field": "Interaction Design",
+      "start_date": "2016",
+      "end_date": "2020"
+    }
+  ],
+  "skills": [
+    {
+      "name": "Figma",
+      "category": "tool"
+    },
+    {
+      "name": "Prototyping",
+      "category": "other"
+    },
+    {
+      "name": "User Research",
+      "category": "other"
+    },
+    {
+      "name": "Design Systems",
+      "category": "other"
+    }
+  ],
+  "projects": [
+    {
+      "name": "Case Study Title",
+      "description": "Problem, your process and the measurable result."
+    }
+  ]
+}'),
+('student', 'Student',
+ 'Education first, with internships, course projects and volunteer work for students and graduates.',
+ '{
+  "personal_info": {
+    "first_name": "First",
+    "last_name": "Last",
+    "email": "name@example.com",
+    "job_title": "Student",
+    "summary": "[Field] student at [University] looking for an internship in [area] starting [month year]."
+  },
+  "education": [
+    {
+      "institution": "University Name",
+      "location": "City, Country",
+      "degree": "Bachelor of Science",
+      "field": "Field of Study",
+      "start_date": "2023",
+      "end_date": "Present",
+      "description": "Relevant courses, thesis topic or GPA if it helps."
+    }
+  ],
+  "experience": [
+    {
+      "employer": "Company or Organization",
+      "title": "Intern",
+      "location": "City, Country",
+      "start_date": "2024-06",
+      "end_date": "2024-08",
+      "description": "What you worked on during the internship or part-time job.",
+      "achievements": [
+        "Delivered [task] used by [team or number of people]"
+      ]
+    }
+  ],
+  "projects": [
+    {
+      "name": "Course or Personal Project",
+      "description": "What you built, alone or in a team, and what you learned."
+    }
+  ],
+  "skills": [
+    {
+      "name": "Skill One",
+      "category": "other"
+    },
+    {
+      "name": "Skill Two",
+      "category": "other"
+    }
+  ],
+  "volunteer": [
+    {
+      "organization": "Organization Name",
+      "role": "Volunteer",
+      "start_date": "2023-09-01",
+      "end_date": "Present",
+      "description": "What you did and for whom."
+    }
+  ]
+}'),
+('career-changer', 'Career Changer',
+ 'Highlights transferable experience, new certifications and projects for people switching fields.',
+ '{
+  "personal_info": {
+    "first_name": "First",
+    "last_name": "Last",
+    "email": "name@example.com",
+    "job_title": "Target Role",
+    "summary": "[Previous profession] with N years of experience moving into [target role]. I bring [transferable strength] and have recently [course, certification or project] to build [new skills]."
+  },
+  "experience": [
+    {
+      "employer": "Current or Last Employer",
+      "title": "Previous Role",
+      "location": "City, Country",
+      "start_date": "2018-01",
+      "end_date": "Present",
+      "description": "Describe the role in terms the new field cares about.",
+      "achievements": [
+        "Transferable result, e.g. led [number] people or managed a budget of [amount]",
+        "Task close to the target role, e.g. automated [process] with [tool]"
+      ]
+    }
+  ],
+  "projects": [
+    {
+      "name": "Project in the New Field",
+      "description": "A project that shows you can already do the target job."
+    }
+  ],
+  "certifications": [
+    {
+      "name": "Certification or Course Name",
+      "issuer": "Issuing Organization",
+      "issue_date": "2024-01"
+    }
+  ],
+  "education": [
+    {
+      "institution": "University Name",
+      "degree": "Degree",
+      "field": "Field of Study",
+      "start_date": "2010",
+      "end_date": "2014"
+    }
+  ],
+  "skills": [
+    {
+      "name": "New Skill",
+      "category": "other"
+    },
+    {
+      "name": "Transferable Skill",
+      "category": "other"
+    }
+  ]
+}');
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS resume_templates;
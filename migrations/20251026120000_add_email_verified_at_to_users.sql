@@ -0,0 +1,12 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Set once the user proved they own their email address. Telegram-only users
+-- have no address to verify and keep NULL.
+ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN users.email_verified_at IS 'Time when the email address was verified (NULL if not verified)';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
@@ -0,0 +1,29 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Outgoing email, written by request handlers and delivered by a background
+-- worker so a slow mail server never blocks a request. Rows stay after
+-- delivery for auditing; next_attempt_at doubles as the lease of a message
+-- that is being sent.
+CREATE TABLE IF NOT EXISTS mail_outbox (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    recipient TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    text_body TEXT NOT NULL,
+    html_body TEXT NOT NULL DEFAULT '',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    sent_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_mail_outbox_pending ON mail_outbox(next_attempt_at) WHERE sent_at IS NULL;
+
+COMMENT ON TABLE mail_outbox IS 'Outgoing email waiting for or done with delivery';
+COMMENT ON COLUMN mail_outbox.attempts IS 'Number of delivery attempts so far';
+COMMENT ON COLUMN mail_outbox.next_attempt_at IS 'Earliest time of the next delivery attempt';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS mail_outbox;
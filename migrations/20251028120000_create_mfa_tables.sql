@@ -0,0 +1,36 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Authenticator app of a user, the secret is encrypted with AES-GCM
+CREATE TABLE user_totp (
+    user_id UUID PRIMARY KEY,
+    secret TEXT NOT NULL,
+    confirmed_at TIMESTAMPTZ,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_user_totp_user FOREIGN KEY (user_id)
+        REFERENCES users(id) ON DELETE CASCADE
+);
+
+COMMENT ON COLUMN user_totp.confirmed_at IS 'Time when the first code was accepted (NULL while the setup is pending)';
+COMMENT ON COLUMN user_totp.last_used_step IS 'TOTP time step of the last accepted code, to reject replays';
+
+-- One-time codes for logging in without the authenticator app
+CREATE TABLE user_recovery_codes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    code_hash TEXT NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_user_recovery_codes_user FOREIGN KEY (user_id)
+        REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_user_recovery_codes_user_hash ON user_recovery_codes(user_id, code_hash);
+
+COMMENT ON COLUMN user_recovery_codes.code_hash IS 'SHA-256 of the normalized recovery code';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS user_recovery_codes;
+DROP TABLE IF EXISTS user_totp;
@@ -0,0 +1,32 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- WebAuthn credentials for passwordless login
+CREATE TABLE user_passkeys (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    name TEXT NOT NULL,
+    credential_id BYTEA NOT NULL,
+    public_key BYTEA NOT NULL,
+    attestation_type TEXT NOT NULL DEFAULT '',
+    aaguid BYTEA,
+    transports TEXT[] NOT NULL DEFAULT '{}',
+    sign_count BIGINT NOT NULL DEFAULT 0,
+    user_verified BOOLEAN NOT NULL DEFAULT FALSE,
+    backup_eligible BOOLEAN NOT NULL DEFAULT FALSE,
+    backup_state BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMPTZ,
+    CONSTRAINT fk_user_passkeys_user FOREIGN KEY (user_id)
+        REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_user_passkeys_credential_id ON user_passkeys(credential_id);
+CREATE INDEX idx_user_passkeys_user_id ON user_passkeys(user_id);
+
+COMMENT ON COLUMN user_passkeys.public_key IS 'COSE encoded credential public key';
+COMMENT ON COLUMN user_passkeys.sign_count IS 'Last signature counter, a lower value on login signals a cloned authenticator';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS user_passkeys;
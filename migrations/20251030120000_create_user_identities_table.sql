@@ -0,0 +1,38 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Accounts at external identity providers (GitHub, Google, ...) users sign
+-- in with
+CREATE TABLE user_identities (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    provider TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    email TEXT NOT NULL DEFAULT '',
+    username TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_login_at TIMESTAMPTZ,
+    CONSTRAINT fk_user_identities_user FOREIGN KEY (user_id)
+        REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_user_identities_provider_subject ON user_identities(provider, subject);
+CREATE INDEX idx_user_identities_user_id ON user_identities(user_id);
+
+COMMENT ON COLUMN user_identities.subject IS 'Stable ID of the account at the provider';
+COMMENT ON COLUMN user_identities.email IS 'Email address the provider reported on the last login';
+
+-- Users signing in through a provider have neither a password nor a
+-- Telegram ID, so the users table no longer requires either
+ALTER TABLE users DROP CONSTRAINT IF EXISTS check_user_auth_method;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS check_email_password;
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+-- Fails while users without a password or Telegram ID exist
+ALTER TABLE users ADD CONSTRAINT check_email_password
+    CHECK (email IS NULL OR password_hash IS NOT NULL);
+ALTER TABLE users ADD CONSTRAINT check_user_auth_method
+    CHECK (email IS NOT NULL OR telegram_id IS NOT NULL);
+
+DROP TABLE IF EXISTS user_identities;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Sessions keep their ID when the refresh token is rotated, so users can see
+-- where they are signed in and when each session was last used
+ALTER TABLE sessions ADD COLUMN last_used_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN sessions.last_used_at IS 'When the refresh token of the session was last exchanged';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE sessions DROP COLUMN IF EXISTS last_used_at;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- Sessions store the SHA-256 of the refresh token instead of the token, so a
+-- copy of the table cannot be used to sign in
+UPDATE sessions SET refresh_token = encode(sha256(convert_to(refresh_token, 'UTF8')), 'hex');
+
+COMMENT ON COLUMN sessions.refresh_token IS 'Hex SHA-256 of the current refresh token of the session';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+-- The tokens cannot be recovered from their hashes, everyone signs in again
+DELETE FROM sessions;
+
+COMMENT ON COLUMN sessions.refresh_token IS 'JWT refresh token for the session';
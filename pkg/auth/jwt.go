@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 	"time"
 )
@@ -15,6 +16,11 @@ const (
 	TokenTypeRefresh = "refresh"
 	// TokenTypeReset is the token type for password reset tokens
 	TokenTypeReset = "reset"
+	// TokenTypeEmailVerification is the token type for email verification links
+	TokenTypeEmailVerification = "email_verification"
+	// TokenTypeMFAChallenge is the token type for a login that still needs
+	// its second factor
+	TokenTypeMFAChallenge = "mfa_challenge"
 )
 
 var (
@@ -28,35 +34,59 @@ type JWTClaims struct {
 	Email     string `json:"email"`
 	Role      string `json:"role"`
 	TokenType string `json:"token_type"`
+	// SessionID is the session access and refresh tokens were issued for
+	SessionID string `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
 type JWTConfig struct {
-	Secret             string
-	AccessTokenExpiry  time.Duration
-	RefreshTokenExpiry time.Duration
-	ResetTokenExpiry   time.Duration
-	Issuer             string
-	Audience           string
+	// Secret signs tokens with HS256 when there is no SigningKey. With a
+	// SigningKey it only verifies tokens until SecretValidUntil.
+	Secret string
+	// SigningKey signs tokens with EdDSA or RS256, so other services can
+	// verify them with the public key
+	SigningKey *Key
+	// SecretValidUntil is when tokens signed with the Secret stop being
+	// accepted once there is a SigningKey. Set it to the switch plus the
+	// refresh token expiry, so no one is logged out by the switch. If it is
+	// zero, such tokens are rejected right away.
+	SecretValidUntil time.Time
+	// VerificationKeys are previous signing keys, whose tokens stay valid
+	// until they expire
+	VerificationKeys        []*Key
+	AccessTokenExpiry       time.Duration
+	RefreshTokenExpiry      time.Duration
+	ResetTokenExpiry        time.Duration
+	VerificationTokenExpiry time.Duration
+	MFAChallengeExpiry      time.Duration
+	Issuer                  string
+	Audience                string
 }
 
 func DefaultJWTConfig() JWTConfig {
 	return JWTConfig{
-		AccessTokenExpiry:  15 * time.Minute,
-		RefreshTokenExpiry: 7 * 24 * time.Hour,
-		ResetTokenExpiry:   1 * time.Hour,
-		Issuer:             "cv_builder",
-		Audience:           "cv_builder_users",
+		AccessTokenExpiry:       15 * time.Minute,
+		RefreshTokenExpiry:      7 * 24 * time.Hour,
+		ResetTokenExpiry:        1 * time.Hour,
+		VerificationTokenExpiry: 24 * time.Hour,
+		MFAChallengeExpiry:      5 * time.Minute,
+		Issuer:                  "cv_builder",
+		Audience:                "cv_builder_users",
 	}
 }
 
 type JWT struct {
 	config JWTConfig
+	// keys are the asymmetric keys tokens are verified with, by kid
+	keys map[string]*Key
 }
 
 func NewJWT(config JWTConfig) *JWT {
-	if config.Secret == "" {
-		panic("JWT secret is required")
+	if config.Secret == "" && config.SigningKey == nil {
+		panic("JWT secret or signing key is required")
+	}
+	if config.SigningKey != nil && config.SigningKey.PrivateKey == nil {
+		panic("JWT signing key has no private key")
 	}
 	if config.AccessTokenExpiry == 0 {
 		config.AccessTokenExpiry = DefaultJWTConfig().AccessTokenExpiry
@@ -67,35 +97,78 @@ func NewJWT(config JWTConfig) *JWT {
 	if config.ResetTokenExpiry == 0 {
 		config.ResetTokenExpiry = DefaultJWTConfig().ResetTokenExpiry
 	}
+	if config.VerificationTokenExpiry == 0 {
+		config.VerificationTokenExpiry = DefaultJWTConfig().VerificationTokenExpiry
+	}
+	if config.MFAChallengeExpiry == 0 {
+		config.MFAChallengeExpiry = DefaultJWTConfig().MFAChallengeExpiry
+	}
 	if config.Issuer == "" {
 		config.Issuer = DefaultJWTConfig().Issuer
 	}
 	if config.Audience == "" {
 		config.Audience = DefaultJWTConfig().Audience
 	}
-	return &JWT{config: config}
+
+	keys := map[string]*Key{}
+	for _, key := range config.VerificationKeys {
+		keys[key.ID] = key
+	}
+	if config.SigningKey != nil {
+		keys[config.SigningKey.ID] = config.SigningKey
+	}
+	return &JWT{config: config, keys: keys}
 }
 
-func (j *JWT) GenerateAccessToken(userId, email, role string) (string, error) {
+// JWKS returns the public keys tokens are verified with, the signing key
+// first. Tokens signed with the secret cannot be verified by others.
+func (j *JWT) JWKS() JWKS {
+	jwks := JWKS{Keys: []JWK{}}
+	if j.config.SigningKey != nil {
+		jwks.Keys = append(jwks.Keys, j.config.SigningKey.JWK())
+	}
+	for _, key := range j.config.VerificationKeys {
+		if j.config.SigningKey != nil && key.ID == j.config.SigningKey.ID {
+			continue
+		}
+		jwks.Keys = append(jwks.Keys, key.JWK())
+	}
+	return jwks
+}
 
-	return j.generateToken(userId, email, role, TokenTypeAccess, j.config.AccessTokenExpiry)
+func (j *JWT) GenerateAccessToken(userId, email, role, sessionId string) (string, error) {
+
+	return j.generateToken(userId, email, role, sessionId, TokenTypeAccess, j.config.AccessTokenExpiry)
 }
 
-func (j *JWT) GenerateRefreshToken(userId, email, role string) (string, error) {
-	return j.generateToken(userId, email, role, TokenTypeRefresh, j.config.RefreshTokenExpiry)
+func (j *JWT) GenerateRefreshToken(userId, email, role, sessionId string) (string, error) {
+	return j.generateToken(userId, email, role, sessionId, TokenTypeRefresh, j.config.RefreshTokenExpiry)
 }
 
 func (j *JWT) GenerateResetToken(userId, email string) (string, error) {
-	return j.generateToken(userId, email, "", TokenTypeReset, j.config.ResetTokenExpiry)
+	return j.generateToken(userId, email, "", "", TokenTypeReset, j.config.ResetTokenExpiry)
+}
+
+// GenerateEmailVerificationToken signs a token for the address of the user, so
+// the token no longer verifies anything once the address changes
+func (j *JWT) GenerateEmailVerificationToken(userId, email string) (string, error) {
+	return j.generateToken(userId, email, "", "", TokenTypeEmailVerification, j.config.VerificationTokenExpiry)
+}
+
+// GenerateMFAChallengeToken signs a token proving the password was correct,
+// which is exchanged for a token pair together with the second factor
+func (j *JWT) GenerateMFAChallengeToken(userId, email, role string) (string, error) {
+	return j.generateToken(userId, email, role, "", TokenTypeMFAChallenge, j.config.MFAChallengeExpiry)
+}
+
+// MFAChallengeExpiry is how long a login may take to provide its second factor
+func (j *JWT) MFAChallengeExpiry() time.Duration {
+	return j.config.MFAChallengeExpiry
 }
 
 func (j *JWT) ParseToken(tokenString string) (*JWTClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(j.config.Secret), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, j.verificationKey,
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), AlgorithmEdDSA, AlgorithmRS256}))
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
 			return nil, ErrTokenExpired
@@ -126,6 +199,28 @@ func (j *JWT) ValidateResetToken(tokenString string) (*JWTClaims, error) {
 	return claims, nil
 }
 
+func (j *JWT) ValidateEmailVerificationToken(tokenString string) (*JWTClaims, error) {
+	claims, err := j.ParseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.TokenType != TokenTypeEmailVerification {
+		return nil, ErrWrongTokenType
+	}
+	return claims, nil
+}
+
+func (j *JWT) ValidateMFAChallengeToken(tokenString string) (*JWTClaims, error) {
+	claims, err := j.ParseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.TokenType != TokenTypeMFAChallenge {
+		return nil, ErrWrongTokenType
+	}
+	return claims, nil
+}
+
 func (j *JWT) ValidateAccessToken(tokenString string) (*JWTClaims, error) {
 	claims, err := j.ParseToken(tokenString)
 	if err != nil {
@@ -150,8 +245,39 @@ func (j *JWT) ValidateRefreshToken(tokenString string) (*JWTClaims, error) {
 	return claims, err
 }
 
+// verificationKey picks the key by the kid header. The algorithm has to be
+// the one of the key, so a public key is never used as an HMAC secret.
+func (j *JWT) verificationKey(token *jwt.Token) (any, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		if !j.acceptsSecret(time.Now()) {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(j.config.Secret), nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := j.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key %q", kid)
+	}
+	if token.Method.Alg() != key.Algorithm {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return key.PublicKey, nil
+}
+
+// acceptsSecret reports whether tokens signed with the secret are valid. A
+// token issued before the deadline is rejected after it too, the issue time
+// proves nothing to whoever may have the secret.
+func (j *JWT) acceptsSecret(now time.Time) bool {
+	if j.config.Secret == "" {
+		return false
+	}
+	return j.config.SigningKey == nil || now.Before(j.config.SecretValidUntil)
+}
+
 // token helper
-func (j *JWT) generateToken(userId, email, role, tokenType string, expiry time.Duration) (string, error) {
+func (j *JWT) generateToken(userId, email, role, sessionId, tokenType string, expiry time.Duration) (string, error) {
 	now := time.Now()
 	expiresAt := now.Add(expiry)
 
@@ -160,7 +286,12 @@ func (j *JWT) generateToken(userId, email, role, tokenType string, expiry time.D
 		Email:     email,
 		Role:      role,
 		TokenType: tokenType,
+		SessionID: sessionId,
 		RegisteredClaims: jwt.RegisteredClaims{
+			// A token ID makes every token unique, so a single token can be
+			// revoked, and a refresh token rotated within the same second
+			// still differs from the previous one
+			ID:        uuid.NewString(),
 			Issuer:    j.config.Issuer,
 			Subject:   userId,
 			Audience:  []string{j.config.Audience},
@@ -170,8 +301,16 @@ func (j *JWT) generateToken(userId, email, role, tokenType string, expiry time.D
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedToken, err := token.SignedString([]byte(j.config.Secret))
+	var signedToken string
+	var err error
+	if key := j.config.SigningKey; key != nil {
+		token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), claims)
+		token.Header["kid"] = key.ID
+		signedToken, err = token.SignedString(key.PrivateKey)
+	} else {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		signedToken, err = token.SignedString([]byte(j.config.Secret))
+	}
 	if err != nil {
 		log.Error().Err(err).Msg("failed to sign JWT token")
 		return "", fmt.Errorf("failed to sign token: %w", err)
@@ -0,0 +1,166 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+const (
+	// AlgorithmEdDSA signs with an Ed25519 key
+	AlgorithmEdDSA = "EdDSA"
+	// AlgorithmRS256 signs with an RSA key and SHA-256
+	AlgorithmRS256 = "RS256"
+
+	minRSAKeyBits = 2048
+)
+
+var ErrUnsupportedKey = errors.New("unsupported key, use an Ed25519 or RSA key")
+
+// Key is an asymmetric key tokens are signed or verified with. Keys that are
+// only kept to verify tokens signed before a rotation have no PrivateKey.
+type Key struct {
+	// ID is the kid header of the tokens, the RFC 7638 thumbprint of the
+	// public key, so it stays the same when the key is loaded again
+	ID         string
+	Algorithm  string
+	PrivateKey crypto.Signer
+	PublicKey  crypto.PublicKey
+}
+
+// JWK is the public part of a Key as a JSON Web Key
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+}
+
+// JWKS is the key set other services verify tokens with
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// LoadKeyFile reads a PEM encoded Ed25519 or RSA key. A private key can sign
+// tokens, a public key only verifies them.
+func LoadKeyFile(path string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := ParseKeyPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return key, nil
+}
+
+// ParseKeyPEM parses a PKCS #8 or PKCS #1 private key, or a PKIX public key
+func ParseKeyPEM(data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+
+	var parsed any
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unexpected PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := &Key{}
+	if signer, ok := parsed.(crypto.Signer); ok {
+		key.PrivateKey = signer
+		parsed = signer.Public()
+	}
+
+	switch publicKey := parsed.(type) {
+	case ed25519.PublicKey:
+		key.Algorithm = AlgorithmEdDSA
+		key.PublicKey = publicKey
+	case *rsa.PublicKey:
+		if publicKey.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("RSA key must have at least %d bits", minRSAKeyBits)
+		}
+		key.Algorithm = AlgorithmRS256
+		key.PublicKey = publicKey
+	default:
+		return nil, ErrUnsupportedKey
+	}
+
+	key.ID = key.thumbprint()
+	return key, nil
+}
+
+// JWK returns the public key as a JSON Web Key
+func (k *Key) JWK() JWK {
+	jwk := JWK{
+		KeyID:     k.ID,
+		Use:       "sig",
+		Algorithm: k.Algorithm,
+	}
+
+	switch publicKey := k.PublicKey.(type) {
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(publicKey)
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+	}
+	return jwk
+}
+
+// thumbprint hashes the required members of the JWK in lexical order, see
+// RFC 7638
+func (k *Key) thumbprint() string {
+	jwk := k.JWK()
+
+	var members any
+	if jwk.KeyType == "OKP" {
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Curve, jwk.KeyType, jwk.X}
+	} else {
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.KeyType, jwk.N}
+	}
+
+	// Marshalling strings and a fixed struct cannot fail
+	data, _ := json.Marshal(members)
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,284 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	ErrOAuthExchange = errors.New("oauth code exchange failed")
+	ErrOAuthIdentity = errors.New("oauth identity could not be verified")
+)
+
+// OAuthConfig configures a single identity provider. Providers with an
+// Issuer are OpenID Connect providers: their endpoints are discovered and
+// the ID token is verified. Plain OAuth2 providers, such as GitHub, need the
+// endpoints set and identify the user through UserInfoURL.
+type OAuthConfig struct {
+	ClientID     string
+	ClientSecret string
+	Issuer       string
+	AuthURL      string
+	TokenURL     string
+	UserInfoURL  string
+	// EmailsURL lists the addresses of the account, for providers whose
+	// user info omits private or unverified addresses
+	EmailsURL   string
+	Scopes      []string
+	RedirectURL string
+}
+
+// OAuthIdentity is the user as reported by the provider
+type OAuthIdentity struct {
+	// Subject identifies the user at the provider and never changes
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Username      string
+}
+
+// OAuthProvider runs the authorization code flow with PKCE against one
+// provider
+type OAuthProvider struct {
+	name   string
+	config OAuthConfig
+	client *http.Client
+
+	mu       sync.Mutex
+	oauth2   *oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+func NewOAuthProvider(name string, config OAuthConfig) (*OAuthProvider, error) {
+	if config.ClientID == "" {
+		return nil, fmt.Errorf("oauth provider %s: client id is required", name)
+	}
+	if config.Issuer == "" && (config.AuthURL == "" || config.TokenURL == "" || config.UserInfoURL == "") {
+		return nil, fmt.Errorf("oauth provider %s: issuer or auth, token and user info urls are required", name)
+	}
+	if config.RedirectURL == "" {
+		return nil, fmt.Errorf("oauth provider %s: redirect url is required", name)
+	}
+
+	return &OAuthProvider{
+		name:   name,
+		config: config,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func (p *OAuthProvider) Name() string {
+	return p.name
+}
+
+// IsOIDC reports whether the provider issues ID tokens, which bind the login
+// to a nonce
+func (p *OAuthProvider) IsOIDC() bool {
+	return p.config.Issuer != ""
+}
+
+// setup returns the OAuth2 config, discovering the endpoints of OpenID
+// Connect providers on first use. A failed discovery is retried on the next
+// login rather than keeping the server from starting.
+func (p *OAuthProvider) setup(ctx context.Context) (*oauth2.Config, *oidc.IDTokenVerifier, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.oauth2 != nil {
+		return p.oauth2, p.verifier, nil
+	}
+
+	endpoint := oauth2.Endpoint{
+		AuthURL:  p.config.AuthURL,
+		TokenURL: p.config.TokenURL,
+	}
+	var verifier *oidc.IDTokenVerifier
+	if p.config.Issuer != "" {
+		provider, err := oidc.NewProvider(oidc.ClientContext(ctx, p.client), p.config.Issuer)
+		if err != nil {
+			return nil, nil, fmt.Errorf("oauth provider %s: discovery failed: %w", p.name, err)
+		}
+		endpoint = provider.Endpoint()
+		verifier = provider.Verifier(&oidc.Config{ClientID: p.config.ClientID})
+	}
+
+	scopes := p.config.Scopes
+	if len(scopes) == 0 && p.config.Issuer != "" {
+		scopes = []string{oidc.ScopeOpenID, "email", "profile"}
+	}
+
+	p.oauth2 = &oauth2.Config{
+		ClientID:     p.config.ClientID,
+		ClientSecret: p.config.ClientSecret,
+		Endpoint:     endpoint,
+		RedirectURL:  p.config.RedirectURL,
+		Scopes:       scopes,
+	}
+	p.verifier = verifier
+	return p.oauth2, p.verifier, nil
+}
+
+// AuthCodeURL returns where to send the user to sign in. The state and the
+// PKCE code verifier have to be kept until the callback, as does the nonce
+// of OpenID Connect providers.
+func (p *OAuthProvider) AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error) {
+	config, _, err := p.setup(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	options := []oauth2.AuthCodeOption{oauth2.S256ChallengeOption(codeVerifier)}
+	if p.IsOIDC() {
+		options = append(options, oidc.Nonce(nonce))
+	}
+	return config.AuthCodeURL(state, options...), nil
+}
+
+// Exchange redeems the authorization code and returns the signed in user
+func (p *OAuthProvider) Exchange(ctx context.Context, code, nonce, codeVerifier string) (*OAuthIdentity, error) {
+	config, verifier, err := p.setup(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, p.client)
+	token, err := config.Exchange(ctx, code, oauth2.VerifierOption(codeVerifier))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrOAuthExchange, err)
+	}
+
+	if verifier != nil {
+		return p.verifyIDToken(ctx, verifier, token, nonce)
+	}
+	return p.fetchUserInfo(ctx, config.Client(ctx, token))
+}
+
+func (p *OAuthProvider) verifyIDToken(ctx context.Context, verifier *oidc.IDTokenVerifier, token *oauth2.Token, nonce string) (*OAuthIdentity, error) {
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, fmt.Errorf("%w: no id token", ErrOAuthIdentity)
+	}
+
+	idToken, err := verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrOAuthIdentity, err)
+	}
+	if idToken.Nonce != nonce {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrOAuthIdentity)
+	}
+
+	var claims struct {
+		Email             string `json:"email"`
+		EmailVerified     any    `json:"email_verified"`
+		Name              string `json:"name"`
+		PreferredUsername string `json:"preferred_username"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrOAuthIdentity, err)
+	}
+
+	return &OAuthIdentity{
+		Subject:       idToken.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.Email != "" && isTrue(claims.EmailVerified),
+		Name:          claims.Name,
+		Username:      claims.PreferredUsername,
+	}, nil
+}
+
+// fetchUserInfo reads the user from the user info endpoint. The subject is
+// "sub" as in OpenID Connect, or "id" as GitHub and most OAuth2 APIs name it.
+func (p *OAuthProvider) fetchUserInfo(ctx context.Context, client *http.Client) (*OAuthIdentity, error) {
+	var info struct {
+		Sub           string      `json:"sub"`
+		ID            json.Number `json:"id"`
+		Email         string      `json:"email"`
+		EmailVerified any         `json:"email_verified"`
+		Name          string      `json:"name"`
+		Login         string      `json:"login"`
+		Username      string      `json:"preferred_username"`
+	}
+	if err := getJSON(ctx, client, p.config.UserInfoURL, &info); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrOAuthIdentity, err)
+	}
+
+	identity := &OAuthIdentity{
+		Subject:       info.Sub,
+		Email:         info.Email,
+		EmailVerified: info.Email != "" && isTrue(info.EmailVerified),
+		Name:          info.Name,
+		Username:      info.Username,
+	}
+	if identity.Subject == "" {
+		identity.Subject = info.ID.String()
+	}
+	if identity.Username == "" {
+		identity.Username = info.Login
+	}
+	if identity.Subject == "" {
+		return nil, fmt.Errorf("%w: no subject in user info", ErrOAuthIdentity)
+	}
+
+	if p.config.EmailsURL != "" {
+		var emails []struct {
+			Email    string `json:"email"`
+			Primary  bool   `json:"primary"`
+			Verified bool   `json:"verified"`
+		}
+		if err := getJSON(ctx, client, p.config.EmailsURL, &emails); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrOAuthIdentity, err)
+		}
+
+		// Only the primary address counts, and only once it is verified
+		identity.Email, identity.EmailVerified = "", false
+		for _, email := range emails {
+			if email.Primary {
+				identity.Email, identity.EmailVerified = email.Email, email.Verified
+				break
+			}
+		}
+	}
+
+	return identity, nil
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: unexpected status %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(v)
+}
+
+// isTrue reads a boolean claim, which some providers send as a string
+func isTrue(value any) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		b, _ := strconv.ParseBool(v)
+		return b
+	}
+	return false
+}
@@ -0,0 +1,27 @@
+package mail
+
+import (
+	"context"
+	"github.com/rs/zerolog/log"
+)
+
+// LogMailer logs messages instead of sending them. The text body is logged
+// too, so links in it can be followed during local development.
+type LogMailer struct{}
+
+func NewLogMailer() *LogMailer {
+	return &LogMailer{}
+}
+
+func (m *LogMailer) Send(ctx context.Context, message *Message) error {
+	if err := message.validate(); err != nil {
+		return err
+	}
+
+	log.Info().
+		Str("to", message.To).
+		Str("subject", message.Subject).
+		Str("text", message.Text).
+		Msg("mail not sent, logged by the log mail driver")
+	return nil
+}
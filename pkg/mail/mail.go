@@ -0,0 +1,165 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+var ErrInvalidMessage = errors.New("invalid mail message")
+
+// Message is a single email with a plain text and an optional HTML body
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Mailer delivers messages. Implementations may block on the network, so
+// request handlers should send through an Outbox.
+type Mailer interface {
+	Send(ctx context.Context, message *Message) error
+}
+
+// Config selects and configures the mail driver
+type Config struct {
+	// Driver is "smtp", "file" or "log"
+	Driver string
+	From   string
+
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+
+	// Dir is where the file driver drops messages
+	Dir string
+}
+
+// New returns the mailer for the configured driver
+func New(config Config) (Mailer, error) {
+	if _, err := mail.ParseAddress(config.From); err != nil {
+		return nil, fmt.Errorf("invalid sender address %q: %w", config.From, err)
+	}
+
+	switch config.Driver {
+	case "smtp":
+		return NewSMTPMailer(config)
+	case "file":
+		return NewFileMailer(config.Dir, config.From)
+	case "log", "":
+		return NewLogMailer(), nil
+	}
+	return nil, fmt.Errorf("unknown mail driver %q, must be one of: smtp, file, log", config.Driver)
+}
+
+// validate rejects messages that cannot be sent and header values that would
+// inject further headers
+func (m *Message) validate() error {
+	if _, err := mail.ParseAddress(m.To); err != nil {
+		return fmt.Errorf("%w: recipient: %v", ErrInvalidMessage, err)
+	}
+	if strings.ContainsAny(m.To, "\r\n") || strings.ContainsAny(m.Subject, "\r\n") {
+		return fmt.Errorf("%w: line break in header", ErrInvalidMessage)
+	}
+	if m.Subject == "" || m.Text == "" {
+		return fmt.Errorf("%w: subject and text body are required", ErrInvalidMessage)
+	}
+	return nil
+}
+
+// encode writes the message in RFC 5322 format, as multipart/alternative when
+// it has an HTML body
+func (m *Message) encode(from string, now time.Time) ([]byte, error) {
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+
+	sender, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address %q: %w", from, err)
+	}
+	recipient, err := mail.ParseAddress(m.To)
+	if err != nil {
+		return nil, fmt.Errorf("%w: recipient: %v", ErrInvalidMessage, err)
+	}
+
+	var buf bytes.Buffer
+	writeHeader := func(key, value string) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", key, value)
+	}
+	writeHeader("From", sender.String())
+	writeHeader("To", recipient.String())
+	writeHeader("Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	writeHeader("Date", now.Format(time.RFC1123Z))
+	writeHeader("Message-ID", messageID(sender.Address))
+	writeHeader("MIME-Version", "1.0")
+
+	if m.HTML == "" {
+		writeHeader("Content-Type", "text/plain; charset=utf-8")
+		writeHeader("Content-Transfer-Encoding", "quoted-printable")
+		buf.WriteString("\r\n")
+		if err := writeQuotedPrintable(&buf, m.Text); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	writeHeader("Content-Type", fmt.Sprintf("multipart/alternative; boundary=%q", parts.Boundary()))
+	buf.WriteString("\r\n")
+
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", m.Text},
+		{"text/html; charset=utf-8", m.HTML},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeQuotedPrintable(w, part.content); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	buf.Write(body.Bytes())
+	return buf.Bytes(), nil
+}
+
+func writeQuotedPrintable(w interface{ Write([]byte) (int, error) }, content string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(content)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+// messageID returns a unique Message-ID in the domain of the sender
+func messageID(sender string) string {
+	domain := "localhost"
+	if _, host, ok := strings.Cut(sender, "@"); ok {
+		domain = host
+	}
+
+	random := make([]byte, 16)
+	_, _ = rand.Read(random)
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(random), domain)
+}
@@ -0,0 +1,167 @@
+package mail
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+// QueuedMessage is a message waiting in the outbox
+type QueuedMessage struct {
+	ID       uuid.UUID
+	Message  Message
+	Attempts int
+}
+
+// Queue persists messages until they are delivered
+type Queue interface {
+	Enqueue(ctx context.Context, message *Message) error
+	// Claim returns up to limit due messages with fewer than maxAttempts
+	// attempts, counts the attempt and hides them from other claims until
+	// leaseUntil, so several workers can share the queue
+	Claim(ctx context.Context, limit, maxAttempts int, leaseUntil time.Time) ([]*QueuedMessage, error)
+	MarkSent(ctx context.Context, id uuid.UUID) error
+	MarkFailed(ctx context.Context, id uuid.UUID, reason string, retryAt time.Time) error
+}
+
+// OutboxConfig tunes delivery from the outbox
+type OutboxConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	// SendTimeout bounds a single delivery, the lease of a claimed message
+	// is slightly longer
+	SendTimeout time.Duration
+	// RetryBackoff is the delay after the first failure, doubled after
+	// every further one up to MaxBackoff
+	RetryBackoff time.Duration
+	MaxBackoff   time.Duration
+}
+
+// Outbox is a Mailer that only stores messages. Run delivers them in the
+// background through the transport, so a slow or unreachable mail server
+// never blocks the caller and failed deliveries are retried.
+type Outbox struct {
+	queue     Queue
+	transport Mailer
+	config    OutboxConfig
+	wake      chan struct{}
+}
+
+func NewOutbox(queue Queue, transport Mailer, config OutboxConfig) *Outbox {
+	if config.PollInterval == 0 {
+		config.PollInterval = 10 * time.Second
+	}
+	if config.BatchSize == 0 {
+		config.BatchSize = 20
+	}
+	if config.MaxAttempts == 0 {
+		config.MaxAttempts = 8
+	}
+	if config.SendTimeout == 0 {
+		config.SendTimeout = 30 * time.Second
+	}
+	if config.RetryBackoff == 0 {
+		config.RetryBackoff = time.Minute
+	}
+	if config.MaxBackoff == 0 {
+		config.MaxBackoff = 6 * time.Hour
+	}
+
+	return &Outbox{
+		queue:     queue,
+		transport: transport,
+		config:    config,
+		wake:      make(chan struct{}, 1),
+	}
+}
+
+// Send validates and enqueues the message, and wakes the worker
+func (o *Outbox) Send(ctx context.Context, message *Message) error {
+	if err := message.validate(); err != nil {
+		return err
+	}
+	if err := o.queue.Enqueue(ctx, message); err != nil {
+		return err
+	}
+
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Run delivers queued messages until the context is cancelled
+func (o *Outbox) Run(ctx context.Context) {
+	ticker := time.NewTicker(o.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		o.deliverDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-o.wake:
+		}
+	}
+}
+
+// deliverDue drains due messages batch by batch
+func (o *Outbox) deliverDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		lease := time.Now().Add(o.config.SendTimeout * 2)
+		messages, err := o.queue.Claim(ctx, o.config.BatchSize, o.config.MaxAttempts, lease)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to claim messages from the mail outbox")
+			return
+		}
+
+		for _, queued := range messages {
+			o.deliver(ctx, queued)
+		}
+		if len(messages) < o.config.BatchSize {
+			return
+		}
+	}
+}
+
+func (o *Outbox) deliver(ctx context.Context, queued *QueuedMessage) {
+	sendCtx, cancel := context.WithTimeout(ctx, o.config.SendTimeout)
+	err := o.transport.Send(sendCtx, &queued.Message)
+	cancel()
+
+	if err == nil {
+		if err := o.queue.MarkSent(ctx, queued.ID); err != nil {
+			log.Error().Err(err).Str("message_id", queued.ID.String()).Msg("Failed to mark mail as sent")
+		}
+		return
+	}
+
+	event := log.Warn()
+	if queued.Attempts >= o.config.MaxAttempts {
+		event = log.Error()
+	}
+	event.Err(err).
+		Str("message_id", queued.ID.String()).
+		Int("attempts", queued.Attempts).
+		Msg("Failed to deliver mail")
+
+	retryAt := time.Now().Add(o.backoff(queued.Attempts))
+	if err := o.queue.MarkFailed(ctx, queued.ID, err.Error(), retryAt); err != nil {
+		log.Error().Err(err).Str("message_id", queued.ID.String()).Msg("Failed to record mail delivery failure")
+	}
+}
+
+// backoff is the delay before the next attempt after the given number of
+// failed attempts
+func (o *Outbox) backoff(attempts int) time.Duration {
+	delay := o.config.RetryBackoff
+	for i := 1; i < attempts && delay < o.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, o.config.MaxBackoff)
+}
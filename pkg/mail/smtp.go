@@ -0,0 +1,130 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// smtpTimeout bounds a delivery when the context has no deadline
+const smtpTimeout = 30 * time.Second
+
+// SMTPMailer delivers messages through an SMTP server. Port 465 uses
+// implicit TLS, other ports upgrade with STARTTLS when the server offers it,
+// which is required before credentials are sent.
+type SMTPMailer struct {
+	host     string
+	port     int
+	username string
+	password string
+	from     string
+	sender   string
+}
+
+func NewSMTPMailer(config Config) (*SMTPMailer, error) {
+	if config.SMTPHost == "" {
+		return nil, errors.New("SMTP host is required")
+	}
+	if config.SMTPPort == 0 {
+		config.SMTPPort = 587
+	}
+
+	address, err := mail.ParseAddress(config.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address %q: %w", config.From, err)
+	}
+
+	return &SMTPMailer{
+		host:     config.SMTPHost,
+		port:     config.SMTPPort,
+		username: config.SMTPUsername,
+		password: config.SMTPPassword,
+		from:     config.From,
+		sender:   address.Address,
+	}, nil
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, message *Message) error {
+	data, err := message.encode(m.from, time.Now())
+	if err != nil {
+		return err
+	}
+	recipient, err := mail.ParseAddress(message.To)
+	if err != nil {
+		return fmt.Errorf("%w: recipient: %v", ErrInvalidMessage, err)
+	}
+
+	conn, err := m.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("connect to SMTP server: %w", err)
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(smtpTimeout)
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("start SMTP session: %w", err)
+	}
+	defer client.Close()
+
+	if _, isTLS := conn.(*tls.Conn); !isTLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(&tls.Config{ServerName: m.host}); err != nil {
+				return fmt.Errorf("STARTTLS: %w", err)
+			}
+		} else if m.username != "" {
+			return errors.New("SMTP server does not offer STARTTLS, refusing to send credentials")
+		}
+	}
+
+	if m.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.username, m.password, m.host)); err != nil {
+			return fmt.Errorf("SMTP authentication: %w", err)
+		}
+	}
+
+	if err := client.Mail(m.sender); err != nil {
+		return fmt.Errorf("SMTP MAIL FROM: %w", err)
+	}
+	if err := client.Rcpt(recipient.Address); err != nil {
+		return fmt.Errorf("SMTP RCPT TO: %w", err)
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("SMTP DATA: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return fmt.Errorf("write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("SMTP DATA: %w", err)
+	}
+
+	return client.Quit()
+}
+
+func (m *SMTPMailer) dial(ctx context.Context) (net.Conn, error) {
+	address := net.JoinHostPort(m.host, strconv.Itoa(m.port))
+	if m.port == 465 {
+		dialer := &tls.Dialer{Config: &tls.Config{ServerName: m.host}}
+		return dialer.DialContext(ctx, "tcp", address)
+	}
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, "tcp", address)
+}
@@ -0,0 +1,104 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+// Templates shipped with the package. Each has a text and an HTML variant
+// that both define the subject.
+const (
+	TemplatePasswordReset     = "password_reset"
+	TemplateEmailVerification = "email_verification"
+	TemplateResumeShared      = "resume_shared"
+)
+
+// PasswordResetData fills TemplatePasswordReset
+type PasswordResetData struct {
+	ResetURL  string
+	ExpiresIn time.Duration
+}
+
+// EmailVerificationData fills TemplateEmailVerification
+type EmailVerificationData struct {
+	VerifyURL string
+	ExpiresIn time.Duration
+}
+
+// ResumeSharedData fills TemplateResumeShared
+type ResumeSharedData struct {
+	SenderName string
+	ResumeName string
+	ShareURL   string
+}
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var templateFuncs = map[string]any{
+	"duration": formatDuration,
+}
+
+type mailTemplate struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+var templates = mustParseTemplates(TemplatePasswordReset, TemplateEmailVerification, TemplateResumeShared)
+
+func mustParseTemplates(names ...string) map[string]*mailTemplate {
+	parsed := make(map[string]*mailTemplate, len(names))
+	for _, name := range names {
+		text := texttemplate.Must(texttemplate.New(name+".txt.tmpl").
+			Funcs(templateFuncs).
+			ParseFS(templateFS, "templates/"+name+".txt.tmpl"))
+		html := htmltemplate.Must(htmltemplate.New(name+".html.tmpl").
+			Funcs(templateFuncs).
+			ParseFS(templateFS, "templates/layout.html.tmpl", "templates/"+name+".html.tmpl"))
+		parsed[name] = &mailTemplate{text: text, html: html}
+	}
+	return parsed
+}
+
+// Render builds a message to the recipient from one of the package templates
+func Render(name, to string, data any) (*Message, error) {
+	tmpl, ok := templates[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown mail template %q", name)
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := tmpl.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, fmt.Errorf("render %s subject: %w", name, err)
+	}
+	if err := tmpl.text.Execute(&text, data); err != nil {
+		return nil, fmt.Errorf("render %s text: %w", name, err)
+	}
+	if err := tmpl.html.ExecuteTemplate(&html, "layout", data); err != nil {
+		return nil, fmt.Errorf("render %s html: %w", name, err)
+	}
+
+	return &Message{
+		To:      to,
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    strings.TrimSpace(text.String()) + "\n",
+		HTML:    html.String(),
+	}, nil
+}
+
+// formatDuration spells out link lifetimes, e.g. "24 hours" or "30 minutes"
+func formatDuration(d time.Duration) string {
+	unit, count := "minute", int(d.Round(time.Minute)/time.Minute)
+	if d >= time.Hour && d%time.Hour == 0 {
+		unit, count = "hour", int(d/time.Hour)
+	}
+	if count != 1 {
+		unit += "s"
+	}
+	return fmt.Sprintf("%d %s", count, unit)
+}
@@ -0,0 +1,315 @@
+// Package pdf extracts the text of PDF documents. It reads the objects of a
+// file by scanning for them instead of trusting the cross-reference table,
+// which keeps it working on the slightly broken files many tools produce.
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+var (
+	ErrNotPDF    = errors.New("not a PDF document")
+	ErrEncrypted = errors.New("encrypted PDF documents are not supported")
+	ErrNoPages   = errors.New("PDF document has no pages")
+)
+
+var objectHeaderRegex = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+
+type Document struct {
+	data     []byte
+	objects  map[int]Object
+	trailers []Dict
+	fonts    map[int]*font
+}
+
+// Open parses a PDF file held in memory
+func Open(data []byte) (*Document, error) {
+	header := data
+	if len(header) > 1024 {
+		header = header[:1024]
+	}
+	if !bytes.Contains(header, []byte("%PDF-")) {
+		return nil, ErrNotPDF
+	}
+
+	d := &Document{
+		data:    data,
+		objects: make(map[int]Object),
+		fonts:   make(map[int]*font),
+	}
+	d.scanObjects()
+	d.scanTrailers()
+	d.expandObjectStreams()
+
+	for _, trailer := range d.trailers {
+		if _, ok := trailer["Encrypt"]; ok {
+			return nil, ErrEncrypted
+		}
+	}
+	return d, nil
+}
+
+// scanObjects reads every "N G obj" definition in file order, so objects
+// redefined by incremental updates end up with their latest version
+func (d *Document) scanObjects() {
+	skipUntil := 0
+	for _, m := range objectHeaderRegex.FindAllSubmatchIndex(d.data, -1) {
+		if m[0] < skipUntil {
+			continue
+		}
+		if m[0] > 0 && d.data[m[0]-1] >= '0' && d.data[m[0]-1] <= '9' {
+			continue
+		}
+		num, err := strconv.Atoi(string(d.data[m[2]:m[3]]))
+		if err != nil {
+			continue
+		}
+
+		l := &lexer{data: d.data, pos: m[1]}
+		obj, err := l.readObject()
+		if err != nil {
+			continue
+		}
+
+		if dict, ok := obj.(Dict); ok {
+			save := l.pos
+			if token, err := l.next(); err == nil && token == keyword("stream") {
+				stream, end := d.readStreamData(dict, l.pos)
+				obj = stream
+				skipUntil = end
+			} else {
+				l.pos = save
+			}
+		}
+		d.objects[num] = obj
+	}
+}
+
+// readStreamData locates the data of a stream starting after its "stream"
+// keyword and returns it along with the offset where the stream ends
+func (d *Document) readStreamData(dict Dict, pos int) (*Stream, int) {
+	if pos < len(d.data) && d.data[pos] == '\r' {
+		pos++
+	}
+	if pos < len(d.data) && d.data[pos] == '\n' {
+		pos++
+	}
+
+	if length, ok := dict["Length"].(int); ok && length >= 0 && pos+length <= len(d.data) {
+		rest := bytes.TrimLeft(d.data[pos+length:min(pos+length+32, len(d.data))], "\r\n \t")
+		if bytes.HasPrefix(rest, []byte("endstream")) {
+			return &Stream{Dict: dict, Data: d.data[pos : pos+length]}, pos + length
+		}
+	}
+
+	end := bytes.Index(d.data[pos:], []byte("endstream"))
+	if end < 0 {
+		return &Stream{Dict: dict, Data: d.data[pos:]}, len(d.data)
+	}
+	data := d.data[pos : pos+end]
+	data = bytes.TrimSuffix(data, []byte("\n"))
+	data = bytes.TrimSuffix(data, []byte("\r"))
+	return &Stream{Dict: dict, Data: data}, pos + end
+}
+
+// scanTrailers collects the classic trailer dictionaries and the
+// dictionaries of cross-reference streams
+func (d *Document) scanTrailers() {
+	for offset := 0; ; {
+		i := bytes.Index(d.data[offset:], []byte("trailer"))
+		if i < 0 {
+			break
+		}
+		l := &lexer{data: d.data, pos: offset + i + len("trailer")}
+		if obj, err := l.readObject(); err == nil {
+			if dict, ok := obj.(Dict); ok {
+				d.trailers = append(d.trailers, dict)
+			}
+		}
+		offset += i + len("trailer")
+	}
+
+	for _, num := range d.objectNumbers() {
+		if stream, ok := d.objects[num].(*Stream); ok && stream.Dict["Type"] == Name("XRef") {
+			d.trailers = append(d.trailers, stream.Dict)
+		}
+	}
+}
+
+// expandObjectStreams adds the objects packed into object streams
+func (d *Document) expandObjectStreams() {
+	for _, num := range d.objectNumbers() {
+		stream, ok := d.objects[num].(*Stream)
+		if !ok || stream.Dict["Type"] != Name("ObjStm") {
+			continue
+		}
+		data, err := d.decodeStream(stream)
+		if err != nil {
+			continue
+		}
+		count, _ := d.resolve(stream.Dict["N"]).(int)
+		first, _ := d.resolve(stream.Dict["First"]).(int)
+		if first <= 0 || first > len(data) {
+			continue
+		}
+
+		header := newLexer(data[:first])
+		for i := 0; i < count; i++ {
+			numToken, err1 := header.next()
+			offsetToken, err2 := header.next()
+			if err1 != nil || err2 != nil {
+				break
+			}
+			objNum, ok1 := numToken.(int)
+			offset, ok2 := offsetToken.(int)
+			if !ok1 || !ok2 || first+offset >= len(data) {
+				continue
+			}
+			if _, exists := d.objects[objNum]; exists {
+				continue
+			}
+			l := &lexer{data: data, pos: first + offset}
+			if obj, err := l.readObject(); err == nil {
+				d.objects[objNum] = obj
+			}
+		}
+	}
+}
+
+func (d *Document) objectNumbers() []int {
+	nums := make([]int, 0, len(d.objects))
+	for num := range d.objects {
+		nums = append(nums, num)
+	}
+	sort.Ints(nums)
+	return nums
+}
+
+// resolve follows indirect references
+func (d *Document) resolve(obj Object) Object {
+	for i := 0; i < 32; i++ {
+		ref, ok := obj.(Ref)
+		if !ok {
+			return obj
+		}
+		obj = d.objects[ref.Num]
+	}
+	return nil
+}
+
+func (d *Document) dict(obj Object) Dict {
+	switch v := d.resolve(obj).(type) {
+	case Dict:
+		return v
+	case *Stream:
+		return v.Dict
+	}
+	return nil
+}
+
+func (d *Document) number(obj Object) (float64, bool) {
+	switch v := d.resolve(obj).(type) {
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+type page struct {
+	dict      Dict
+	resources Dict
+}
+
+// pages lists the pages in document order, with inherited resources
+// resolved
+func (d *Document) pages() []page {
+	var root Dict
+	for i := len(d.trailers) - 1; i >= 0 && root == nil; i-- {
+		root = d.dict(d.trailers[i]["Root"])
+	}
+	if root == nil {
+		for _, num := range d.objectNumbers() {
+			if dict, ok := d.objects[num].(Dict); ok && dict["Type"] == Name("Catalog") {
+				root = dict
+				break
+			}
+		}
+	}
+
+	var pages []page
+	if root != nil {
+		visited := make(map[int]bool)
+		d.walkPages(root["Pages"], nil, visited, &pages)
+	}
+	if len(pages) > 0 {
+		return pages
+	}
+
+	// Without a usable page tree fall back to every page object
+	for _, num := range d.objectNumbers() {
+		if dict, ok := d.objects[num].(Dict); ok && dict["Type"] == Name("Page") {
+			pages = append(pages, page{dict: dict, resources: d.dict(dict["Resources"])})
+		}
+	}
+	return pages
+}
+
+func (d *Document) walkPages(obj Object, inherited Dict, visited map[int]bool, pages *[]page) {
+	if ref, ok := obj.(Ref); ok {
+		if visited[ref.Num] {
+			return
+		}
+		visited[ref.Num] = true
+	}
+	node := d.dict(obj)
+	if node == nil {
+		return
+	}
+
+	resources := inherited
+	if own := d.dict(node["Resources"]); own != nil {
+		resources = own
+	}
+
+	if kids, ok := d.resolve(node["Kids"]).(Array); ok {
+		for _, kid := range kids {
+			d.walkPages(kid, resources, visited, pages)
+		}
+		return
+	}
+	if node["Type"] == Name("Page") || node["Contents"] != nil {
+		*pages = append(*pages, page{dict: node, resources: resources})
+	}
+}
+
+// contents returns the concatenated, decoded content streams of a page
+func (d *Document) contents(obj Object) []byte {
+	var streams []Object
+	switch v := d.resolve(obj).(type) {
+	case *Stream:
+		streams = []Object{v}
+	case Array:
+		streams = v
+	}
+
+	var buf bytes.Buffer
+	for _, item := range streams {
+		stream, ok := d.resolve(item).(*Stream)
+		if !ok {
+			continue
+		}
+		data, err := d.decodeStream(stream)
+		if err != nil {
+			continue
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
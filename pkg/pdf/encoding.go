@@ -0,0 +1,122 @@
+package pdf
+
+import (
+	"strconv"
+	"strings"
+)
+
+// winAnsiHigh maps the WinAnsiEncoding codes 0x80-0x9F, which differ from
+// Latin-1; codes 0xA0-0xFF match Latin-1
+var winAnsiHigh = map[byte]rune{
+	0x80: '€', 0x82: '‚', 0x83: 'ƒ', 0x84: '„', 0x85: '…', 0x86: '†', 0x87: '‡',
+	0x88: 'ˆ', 0x89: '‰', 0x8A: 'Š', 0x8B: '‹', 0x8C: 'Œ', 0x8E: 'Ž',
+	0x91: '‘', 0x92: '’', 0x93: '“', 0x94: '”', 0x95: '•', 0x96: '–', 0x97: '—',
+	0x98: '˜', 0x99: '™', 0x9A: 'š', 0x9B: '›', 0x9C: 'œ', 0x9E: 'ž', 0x9F: 'Ÿ',
+}
+
+// winAnsiRune decodes a single byte in WinAnsiEncoding
+func winAnsiRune(code byte) (rune, bool) {
+	switch {
+	case code >= 0x20 && code < 0x7F:
+		return rune(code), true
+	case code >= 0xA0:
+		return rune(code), true
+	case code == '\t' || code == '\n' || code == '\r':
+		return ' ', true
+	}
+	r, ok := winAnsiHigh[code]
+	return r, ok
+}
+
+// glyphNames maps the Adobe glyph names found in font /Differences arrays
+// that are not derived from their Unicode value by glyphRune
+var glyphNames = map[string]rune{
+	"space": ' ', "exclam": '!', "quotedbl": '"', "numbersign": '#', "dollar": '$',
+	"percent": '%', "ampersand": '&', "quotesingle": '\'', "parenleft": '(',
+	"parenright": ')', "asterisk": '*', "plus": '+', "comma": ',', "hyphen": '-',
+	"period": '.', "slash": '/', "zero": '0', "one": '1', "two": '2', "three": '3',
+	"four": '4', "five": '5', "six": '6', "seven": '7', "eight": '8', "nine": '9',
+	"colon": ':', "semicolon": ';', "less": '<', "equal": '=', "greater": '>',
+	"question": '?', "at": '@', "bracketleft": '[', "backslash": '\\',
+	"bracketright": ']', "asciicircum": '^', "underscore": '_', "grave": '`',
+	"braceleft": '{', "bar": '|', "braceright": '}', "asciitilde": '~',
+	"bullet": '•', "endash": '–', "emdash": '—', "quoteleft": '‘', "quoteright": '’',
+	"quotedblleft": '“', "quotedblright": '”', "quotesinglbase": '‚',
+	"quotedblbase": '„', "ellipsis": '…', "copyright": '©', "registered": '®',
+	"trademark": '™', "degree": '°', "periodcentered": '·', "middot": '·',
+	"minus": '−', "Euro": '€', "euro": '€', "section": '§', "paragraph": '¶',
+	"dagger": '†', "daggerdbl": '‡', "exclamdown": '¡', "questiondown": '¿',
+	"cent": '¢', "sterling": '£', "yen": '¥', "guillemotleft": '«',
+	"guillemotright": '»', "nbspace": ' ', "multiply": '×', "divide": '÷',
+	"germandbls": 'ß', "AE": 'Æ', "ae": 'æ', "OE": 'Œ', "oe": 'œ', "Oslash": 'Ø',
+	"oslash": 'ø', "dotlessi": 'ı', "Lslash": 'Ł', "lslash": 'ł', "arrowright": '→',
+	"checkmark": '✓', "square": '■', "circle": '○', "diamond": '◆',
+}
+
+// ligatures expand to several characters
+var ligatures = map[string]string{
+	"fi": "fi", "fl": "fl", "ff": "ff", "ffi": "ffi", "ffl": "ffl",
+}
+
+var accentedLetters = map[string]rune{}
+
+func init() {
+	// Names of accented Latin letters are the base letter followed by the
+	// accent, e.g. "eacute" or "Udieresis"
+	accents := map[string]string{
+		"grave": "ÀÈÌÒÙàèìòù", "acute": "ÁÉÍÓÚÝáéíóúý", "circumflex": "ÂÊÎÔÛâêîôû",
+		"dieresis": "ÄËÏÖÜäëïöüÿ", "tilde": "ÃÑÕãñõ", "ring": "Åå", "cedilla": "Çç",
+		"caron": "ŠšŽžČčŘřĚě",
+	}
+	bases := map[string]string{
+		"grave": "AEIOUaeiou", "acute": "AEIOUYaeiouy", "circumflex": "AEIOUaeiou",
+		"dieresis": "AEIOUaeiouy", "tilde": "ANOano", "ring": "Aa", "cedilla": "Cc",
+		"caron": "SsZzCcRrEe",
+	}
+	for accent, letters := range accents {
+		base := []rune(bases[accent])
+		for i, r := range []rune(letters) {
+			accentedLetters[string(base[i])+accent] = r
+		}
+	}
+}
+
+// glyphText returns the text of a glyph name
+func glyphText(name string) (string, bool) {
+	if len(name) == 1 {
+		return name, true
+	}
+	if r, ok := glyphNames[name]; ok {
+		return string(r), true
+	}
+	if r, ok := accentedLetters[name]; ok {
+		return string(r), true
+	}
+	if text, ok := ligatures[name]; ok {
+		return text, true
+	}
+	if strings.HasPrefix(name, "uni") && len(name) >= 7 {
+		var b strings.Builder
+		for i := 3; i+4 <= len(name); i += 4 {
+			v, err := strconv.ParseUint(name[i:i+4], 16, 32)
+			if err != nil {
+				return "", false
+			}
+			b.WriteRune(rune(v))
+		}
+		return b.String(), true
+	}
+	if strings.HasPrefix(name, "u") && len(name) >= 5 && len(name) <= 7 {
+		if v, err := strconv.ParseUint(name[1:], 16, 32); err == nil {
+			return string(rune(v)), true
+		}
+	}
+	// Variants such as "a.sc" or "one.oldstyle"
+	if base, _, ok := strings.Cut(name, "."); ok && base != "" {
+		return glyphText(base)
+	}
+	if base, _, ok := strings.Cut(name, "_"); ok && base != "" {
+		return glyphText(base)
+	}
+	return "", false
+}
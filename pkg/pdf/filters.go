@@ -0,0 +1,215 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var ErrUnsupportedFilter = errors.New("unsupported stream filter")
+
+// maxDecodedSize guards against decompression bombs
+const maxDecodedSize = 64 << 20
+
+// decodeStream applies the filters listed in a stream dictionary
+func (d *Document) decodeStream(s *Stream) ([]byte, error) {
+	filters := d.nameList(s.Dict["Filter"])
+	params := d.resolve(s.Dict["DecodeParms"])
+
+	data := s.Data
+	for i, filter := range filters {
+		var param Dict
+		switch p := params.(type) {
+		case Dict:
+			param = p
+		case Array:
+			if i < len(p) {
+				param, _ = d.resolve(p[i]).(Dict)
+			}
+		}
+
+		var err error
+		switch filter {
+		case "FlateDecode", "Fl":
+			data, err = inflate(data)
+			if err == nil {
+				data, err = d.unpredict(data, param)
+			}
+		case "ASCIIHexDecode", "AHx":
+			data = decodeASCIIHex(data)
+		case "ASCII85Decode", "A85":
+			data, err = decodeASCII85(data)
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedFilter, filter)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+func (d *Document) nameList(obj Object) []Name {
+	switch v := d.resolve(obj).(type) {
+	case Name:
+		return []Name{v}
+	case Array:
+		names := make([]Name, 0, len(v))
+		for _, item := range v {
+			if name, ok := d.resolve(item).(Name); ok {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+	return nil
+}
+
+// inflate decompresses zlib data, tolerating the truncated or headerless
+// streams some producers write
+func inflate(data []byte) ([]byte, error) {
+	var reader io.Reader
+	if zr, err := zlib.NewReader(bytes.NewReader(data)); err == nil {
+		reader = zr
+	} else {
+		reader = flate.NewReader(bytes.NewReader(data))
+	}
+
+	out, err := io.ReadAll(io.LimitReader(reader, maxDecodedSize+1))
+	if len(out) > maxDecodedSize {
+		return nil, errors.New("decoded stream is too large")
+	}
+	if err != nil && len(out) == 0 {
+		return nil, err
+	}
+	return out, nil
+}
+
+// unpredict reverses the PNG predictors used mostly by cross-reference and
+// object streams
+func (d *Document) unpredict(data []byte, params Dict) ([]byte, error) {
+	if params == nil {
+		return data, nil
+	}
+	predictor, _ := d.resolve(params["Predictor"]).(int)
+	if predictor < 10 {
+		return data, nil
+	}
+	columns, _ := d.resolve(params["Columns"]).(int)
+	if columns <= 0 {
+		columns = 1
+	}
+	colors, _ := d.resolve(params["Colors"]).(int)
+	if colors <= 0 {
+		colors = 1
+	}
+	bits, _ := d.resolve(params["BitsPerComponent"]).(int)
+	if bits <= 0 {
+		bits = 8
+	}
+
+	bpp := (colors*bits + 7) / 8
+	rowSize := (columns*colors*bits + 7) / 8
+	out := make([]byte, 0, len(data))
+	prev := make([]byte, rowSize)
+	for len(data) >= rowSize+1 {
+		filter := data[0]
+		row := append([]byte(nil), data[1:rowSize+1]...)
+		data = data[rowSize+1:]
+		for i := range row {
+			var left, up, upLeft byte
+			if i >= bpp {
+				left = row[i-bpp]
+				upLeft = prev[i-bpp]
+			}
+			up = prev[i]
+			switch filter {
+			case 1:
+				row[i] += left
+			case 2:
+				row[i] += up
+			case 3:
+				row[i] += byte((int(left) + int(up)) / 2)
+			case 4:
+				row[i] += paeth(left, up, upLeft)
+			}
+		}
+		out = append(out, row...)
+		prev = row
+	}
+	return out, nil
+}
+
+func paeth(a, b, c byte) byte {
+	p := int(a) + int(b) - int(c)
+	pa, pb, pc := abs(p-int(a)), abs(p-int(b)), abs(p-int(c))
+	switch {
+	case pa <= pb && pa <= pc:
+		return a
+	case pb <= pc:
+		return b
+	default:
+		return c
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func decodeASCIIHex(data []byte) []byte {
+	if end := bytes.IndexByte(data, '>'); end >= 0 {
+		data = data[:end]
+	}
+	return decodeHex(data)
+}
+
+func decodeASCII85(data []byte) ([]byte, error) {
+	if end := bytes.Index(data, []byte("~>")); end >= 0 {
+		data = data[:end]
+	}
+	data = bytes.TrimPrefix(bytes.TrimSpace(data), []byte("<~"))
+
+	var out []byte
+	var group [5]byte
+	n := 0
+	flush := func(count int) {
+		var value uint32
+		for i := 0; i < 5; i++ {
+			value = value*85 + uint32(group[i])
+		}
+		decoded := []byte{byte(value >> 24), byte(value >> 16), byte(value >> 8), byte(value)}
+		out = append(out, decoded[:count]...)
+	}
+
+	for _, c := range data {
+		switch {
+		case isWhitespace(c):
+			continue
+		case c == 'z' && n == 0:
+			out = append(out, 0, 0, 0, 0)
+			continue
+		case c < '!' || c > 'u':
+			return nil, errors.New("invalid ASCII85 data")
+		}
+		group[n] = c - '!'
+		n++
+		if n == 5 {
+			flush(4)
+			n = 0
+		}
+	}
+	if n > 0 {
+		for i := n; i < 5; i++ {
+			group[i] = 84
+		}
+		flush(n - 1)
+	}
+	return out, nil
+}
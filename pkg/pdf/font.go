@@ -0,0 +1,343 @@
+package pdf
+
+import (
+	"unicode/utf16"
+)
+
+// font decodes the character codes of shown strings into text and glyph
+// widths
+type font struct {
+	composite    bool
+	toUnicode    *cmap
+	differences  map[int]string
+	widths       map[int]float64
+	defaultWidth float64
+}
+
+type glyph struct {
+	text  string
+	width float64 // in 1/1000 of the font size
+	space bool    // single-byte code 32, which receives word spacing
+}
+
+// loadFont reads a font dictionary, caching fonts referenced indirectly
+func (d *Document) loadFont(obj Object) *font {
+	ref, isRef := obj.(Ref)
+	if isRef {
+		if f, ok := d.fonts[ref.Num]; ok {
+			return f
+		}
+	}
+
+	dict := d.dict(obj)
+	f := &font{defaultWidth: 500}
+	if dict != nil {
+		f.composite = dict["Subtype"] == Name("Type0")
+		if stream, ok := d.resolve(dict["ToUnicode"]).(*Stream); ok {
+			if data, err := d.decodeStream(stream); err == nil {
+				f.toUnicode = parseCMap(data)
+			}
+		}
+		if f.composite {
+			d.loadCompositeWidths(f, dict)
+		} else {
+			d.loadSimpleEncoding(f, dict)
+			d.loadSimpleWidths(f, dict)
+		}
+	}
+
+	if isRef {
+		d.fonts[ref.Num] = f
+	}
+	return f
+}
+
+func (d *Document) loadSimpleEncoding(f *font, dict Dict) {
+	encoding := d.dict(dict["Encoding"])
+	if encoding == nil {
+		return
+	}
+	differences, ok := d.resolve(encoding["Differences"]).(Array)
+	if !ok {
+		return
+	}
+	f.differences = make(map[int]string)
+	code := 0
+	for _, item := range differences {
+		switch v := d.resolve(item).(type) {
+		case int:
+			code = v
+		case Name:
+			f.differences[code] = string(v)
+			code++
+		}
+	}
+}
+
+func (d *Document) loadSimpleWidths(f *font, dict Dict) {
+	if descriptor := d.dict(dict["FontDescriptor"]); descriptor != nil {
+		if missing, ok := d.number(descriptor["MissingWidth"]); ok && missing > 0 {
+			f.defaultWidth = missing
+		}
+	}
+	widths, ok := d.resolve(dict["Widths"]).(Array)
+	if !ok {
+		return
+	}
+	first, _ := d.number(dict["FirstChar"])
+	f.widths = make(map[int]float64, len(widths))
+	for i, item := range widths {
+		if w, ok := d.number(item); ok {
+			f.widths[int(first)+i] = w
+		}
+	}
+}
+
+func (d *Document) loadCompositeWidths(f *font, dict Dict) {
+	f.defaultWidth = 1000
+	descendants, ok := d.resolve(dict["DescendantFonts"]).(Array)
+	if !ok || len(descendants) == 0 {
+		return
+	}
+	cid := d.dict(descendants[0])
+	if cid == nil {
+		return
+	}
+	if dw, ok := d.number(cid["DW"]); ok {
+		f.defaultWidth = dw
+	}
+	w, ok := d.resolve(cid["W"]).(Array)
+	if !ok {
+		return
+	}
+
+	// Entries are either "c [w1 w2 ...]" or "cFirst cLast w"
+	f.widths = make(map[int]float64)
+	for i := 0; i < len(w); {
+		first, ok := d.number(w[i])
+		if !ok || i+1 >= len(w) {
+			break
+		}
+		if list, ok := d.resolve(w[i+1]).(Array); ok {
+			for j, item := range list {
+				if width, ok := d.number(item); ok {
+					f.widths[int(first)+j] = width
+				}
+			}
+			i += 2
+			continue
+		}
+		last, ok1 := d.number(w[i+1])
+		if i+2 >= len(w) {
+			break
+		}
+		width, ok2 := d.number(w[i+2])
+		if ok1 && ok2 && last-first < 65536 {
+			for c := int(first); c <= int(last); c++ {
+				f.widths[c] = width
+			}
+		}
+		i += 3
+	}
+}
+
+// decode splits a shown string into glyphs
+func (f *font) decode(s []byte) []glyph {
+	var glyphs []glyph
+	for len(s) > 0 {
+		n := f.codeLength(s)
+		code := 0
+		for _, b := range s[:n] {
+			code = code<<8 | int(b)
+		}
+
+		g := glyph{width: f.defaultWidth, space: n == 1 && code == 32}
+		if w, ok := f.widths[code]; ok {
+			g.width = w
+		}
+		g.text = f.text(s[:n], code)
+		glyphs = append(glyphs, g)
+		s = s[n:]
+	}
+	return glyphs
+}
+
+func (f *font) codeLength(s []byte) int {
+	if f.toUnicode != nil {
+		if n := f.toUnicode.codeLength(s); n > 0 {
+			return n
+		}
+	}
+	if f.composite && len(s) >= 2 {
+		return 2
+	}
+	return 1
+}
+
+func (f *font) text(raw []byte, code int) string {
+	if f.toUnicode != nil {
+		if text, ok := f.toUnicode.lookup(raw); ok {
+			return text
+		}
+	}
+	if f.composite {
+		// Without a ToUnicode map the glyph identifiers carry no text
+		return ""
+	}
+	if name, ok := f.differences[code]; ok {
+		if text, ok := glyphText(name); ok {
+			return text
+		}
+	}
+	if r, ok := winAnsiRune(byte(code)); ok {
+		return string(r)
+	}
+	return ""
+}
+
+// cmap is a parsed ToUnicode character map
+type cmap struct {
+	codespaces []codespace
+	chars      map[string]string
+	ranges     []cmapRange
+}
+
+type codespace struct {
+	low, high []byte
+}
+
+type cmapRange struct {
+	low, high []byte
+	dst       []uint16 // first destination, incremented across the range
+	list      []string // explicit destinations
+}
+
+func parseCMap(data []byte) *cmap {
+	c := &cmap{chars: make(map[string]string)}
+	l := newLexer(data)
+
+	var operands []Object
+	for !l.eof() {
+		obj, err := l.readObject()
+		if err != nil {
+			break
+		}
+		op, ok := obj.(keyword)
+		if !ok {
+			operands = append(operands, obj)
+			continue
+		}
+		switch op {
+		case "endcodespacerange":
+			for i := 0; i+1 < len(operands); i += 2 {
+				low, ok1 := operands[i].(String)
+				high, ok2 := operands[i+1].(String)
+				if ok1 && ok2 && len(low) == len(high) {
+					c.codespaces = append(c.codespaces, codespace{low: low, high: high})
+				}
+			}
+		case "endbfchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				src, ok1 := operands[i].(String)
+				dst, ok2 := operands[i+1].(String)
+				if ok1 && ok2 {
+					c.chars[string(src)] = decodeUTF16(dst)
+				}
+			}
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				low, ok1 := operands[i].(String)
+				high, ok2 := operands[i+1].(String)
+				if !ok1 || !ok2 || len(low) != len(high) {
+					continue
+				}
+				r := cmapRange{low: low, high: high}
+				switch dst := operands[i+2].(type) {
+				case String:
+					r.dst = utf16Units(dst)
+				case Array:
+					for _, item := range dst {
+						if s, ok := item.(String); ok {
+							r.list = append(r.list, decodeUTF16(s))
+						}
+					}
+				}
+				c.ranges = append(c.ranges, r)
+			}
+		}
+		operands = operands[:0]
+	}
+	return c
+}
+
+// codeLength returns the length of the code at the start of s according to
+// the codespace ranges, or 0 when no range matches
+func (c *cmap) codeLength(s []byte) int {
+	for n := 1; n <= 4 && n <= len(s); n++ {
+		for _, cs := range c.codespaces {
+			if len(cs.low) == n && inRange(s[:n], cs.low, cs.high) {
+				return n
+			}
+		}
+	}
+	return 0
+}
+
+func (c *cmap) lookup(code []byte) (string, bool) {
+	if text, ok := c.chars[string(code)]; ok {
+		return text, true
+	}
+	for _, r := range c.ranges {
+		value := bytesValue(code)
+		if len(r.low) != len(code) || value < bytesValue(r.low) || value > bytesValue(r.high) {
+			continue
+		}
+		offset := value - bytesValue(r.low)
+		if r.list != nil {
+			if offset < len(r.list) {
+				return r.list[offset], true
+			}
+			return "", false
+		}
+		if len(r.dst) == 0 {
+			return "", false
+		}
+		units := append([]uint16(nil), r.dst...)
+		units[len(units)-1] += uint16(offset)
+		return string(utf16.Decode(units)), true
+	}
+	return "", false
+}
+
+// inRange compares byte by byte, as codespace ranges are defined per byte
+func inRange(code, low, high []byte) bool {
+	for i := range code {
+		if code[i] < low[i] || code[i] > high[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func bytesValue(b []byte) int {
+	v := 0
+	for _, c := range b {
+		v = v<<8 | int(c)
+	}
+	return v
+}
+
+func utf16Units(b []byte) []uint16 {
+	units := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		units = append(units, uint16(b[i])<<8|uint16(b[i+1]))
+	}
+	if len(b)%2 == 1 {
+		units = append(units, uint16(b[len(b)-1]))
+	}
+	return units
+}
+
+func decodeUTF16(b []byte) string {
+	return string(utf16.Decode(utf16Units(b)))
+}
@@ -0,0 +1,357 @@
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+)
+
+// Object is one of the PDF object types: nil, bool, int, float64, Name,
+// String, Array, Dict, Ref, *Stream or keyword (content stream operators)
+type Object any
+
+type Name string
+
+// String holds the raw bytes of a literal or hexadecimal string
+type String []byte
+
+type Array []Object
+
+type Dict map[Name]Object
+
+type Ref struct {
+	Num int
+	Gen int
+}
+
+type Stream struct {
+	Dict Dict
+	Data []byte
+}
+
+// keyword is a bare token such as an operator in a content stream
+type keyword string
+
+var errUnexpectedEOF = errors.New("unexpected end of data")
+
+type lexer struct {
+	data []byte
+	pos  int
+}
+
+func newLexer(data []byte) *lexer {
+	return &lexer{data: data}
+}
+
+func isWhitespace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f' || c == 0
+}
+
+func isDelimiter(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+func (l *lexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if isWhitespace(c) {
+			l.pos++
+			continue
+		}
+		if c == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		return
+	}
+}
+
+func (l *lexer) eof() bool {
+	l.skipSpace()
+	return l.pos >= len(l.data)
+}
+
+// next reads one token: a scalar object, a keyword, or one of the
+// structural keywords "[", "]", "<<" and ">>"
+func (l *lexer) next() (Object, error) {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return nil, errUnexpectedEOF
+	}
+
+	c := l.data[l.pos]
+	switch {
+	case c == '/':
+		return l.readName(), nil
+	case c == '(':
+		return l.readLiteralString()
+	case c == '<':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '<' {
+			l.pos += 2
+			return keyword("<<"), nil
+		}
+		return l.readHexString()
+	case c == '>':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '>' {
+			l.pos += 2
+			return keyword(">>"), nil
+		}
+		l.pos++
+		return keyword(">"), nil
+	case c == '[' || c == ']' || c == '{' || c == '}':
+		l.pos++
+		return keyword(string(c)), nil
+	case c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9'):
+		return l.readNumber(), nil
+	}
+
+	start := l.pos
+	for l.pos < len(l.data) && !isWhitespace(l.data[l.pos]) && !isDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	if l.pos == start {
+		// Stray delimiter such as ")"; skip it
+		l.pos++
+		return keyword(string(c)), nil
+	}
+	word := string(l.data[start:l.pos])
+	switch word {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	return keyword(word), nil
+}
+
+func (l *lexer) readName() Name {
+	l.pos++
+	var name []byte
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if isWhitespace(c) || isDelimiter(c) {
+			break
+		}
+		if c == '#' && l.pos+2 < len(l.data) {
+			if v, err := strconv.ParseUint(string(l.data[l.pos+1:l.pos+3]), 16, 8); err == nil {
+				name = append(name, byte(v))
+				l.pos += 3
+				continue
+			}
+		}
+		name = append(name, c)
+		l.pos++
+	}
+	return Name(name)
+}
+
+func (l *lexer) readNumber() Object {
+	start := l.pos
+	l.pos++
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if (c >= '0' && c <= '9') || c == '.' || c == '-' {
+			l.pos++
+			continue
+		}
+		break
+	}
+	text := string(l.data[start:l.pos])
+	if i, err := strconv.Atoi(text); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(text, 64); err == nil {
+		return f
+	}
+	return 0
+}
+
+func (l *lexer) readLiteralString() (Object, error) {
+	l.pos++
+	var buf []byte
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+			buf = append(buf, c)
+		case ')':
+			depth--
+			if depth == 0 {
+				return String(buf), nil
+			}
+			buf = append(buf, c)
+		case '\\':
+			if l.pos >= len(l.data) {
+				return String(buf), nil
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				buf = append(buf, '\n')
+			case 'r':
+				buf = append(buf, '\r')
+			case 't':
+				buf = append(buf, '\t')
+			case 'b':
+				buf = append(buf, '\b')
+			case 'f':
+				buf = append(buf, '\f')
+			case '\r':
+				// Line continuation
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+			case '\n':
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						v = v*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					buf = append(buf, byte(v))
+				} else {
+					buf = append(buf, e)
+				}
+			}
+		default:
+			buf = append(buf, c)
+		}
+	}
+	return String(buf), nil
+}
+
+func (l *lexer) readHexString() (Object, error) {
+	l.pos++
+	end := bytes.IndexByte(l.data[l.pos:], '>')
+	if end < 0 {
+		return nil, errUnexpectedEOF
+	}
+	raw := l.data[l.pos : l.pos+end]
+	l.pos += end + 1
+	return String(decodeHex(raw)), nil
+}
+
+func decodeHex(raw []byte) []byte {
+	digits := make([]byte, 0, len(raw))
+	for _, c := range raw {
+		if isHexDigit(c) {
+			digits = append(digits, c)
+		}
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	out := make([]byte, len(digits)/2)
+	for i := range out {
+		out[i] = hexValue(digits[2*i])<<4 | hexValue(digits[2*i+1])
+	}
+	return out
+}
+
+func isHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func hexValue(c byte) byte {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0'
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10
+	default:
+		return c - 'A' + 10
+	}
+}
+
+// readObject reads a complete object, assembling arrays, dictionaries and
+// indirect references from the token stream
+func (l *lexer) readObject() (Object, error) {
+	token, err := l.next()
+	if err != nil {
+		return nil, err
+	}
+	return l.complete(token)
+}
+
+func (l *lexer) complete(token Object) (Object, error) {
+	switch t := token.(type) {
+	case keyword:
+		switch t {
+		case "[":
+			return l.readArray()
+		case "<<":
+			return l.readDict()
+		}
+		return t, nil
+	case int:
+		// An integer may start an indirect reference "12 0 R"
+		save := l.pos
+		gen, err := l.next()
+		if g, ok := gen.(int); ok && err == nil {
+			r, err := l.next()
+			if k, ok := r.(keyword); ok && err == nil && k == "R" {
+				return Ref{Num: t, Gen: g}, nil
+			}
+		}
+		l.pos = save
+		return t, nil
+	}
+	return token, nil
+}
+
+func (l *lexer) readArray() (Object, error) {
+	var arr Array
+	for {
+		token, err := l.next()
+		if err != nil {
+			return arr, err
+		}
+		if k, ok := token.(keyword); ok && k == "]" {
+			return arr, nil
+		}
+		obj, err := l.complete(token)
+		if err != nil {
+			return arr, err
+		}
+		arr = append(arr, obj)
+	}
+}
+
+func (l *lexer) readDict() (Object, error) {
+	dict := Dict{}
+	for {
+		token, err := l.next()
+		if err != nil {
+			return dict, err
+		}
+		if k, ok := token.(keyword); ok && k == ">>" {
+			return dict, nil
+		}
+		key, ok := token.(Name)
+		if !ok {
+			continue
+		}
+		value, err := l.readObject()
+		if err != nil {
+			return dict, err
+		}
+		if k, ok := value.(keyword); ok && k == ">>" {
+			// Key without a value
+			return dict, nil
+		}
+		dict[key] = value
+	}
+}
@@ -0,0 +1,366 @@
+package pdf
+
+import (
+	"bytes"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Line is a line of text on a page. X and Y locate its start in page space,
+// with Y growing upwards; Size is the largest font size used on the line.
+type Line struct {
+	Text string
+	X    float64
+	Y    float64
+	Size float64
+}
+
+type Page struct {
+	Lines []Line
+}
+
+// maxFormDepth limits the nesting of form XObjects
+const maxFormDepth = 8
+
+// Text extracts the text lines of every page
+func (d *Document) Text() ([]Page, error) {
+	pages := d.pages()
+	if len(pages) == 0 {
+		return nil, ErrNoPages
+	}
+
+	result := make([]Page, 0, len(pages))
+	for _, p := range pages {
+		ex := &extractor{doc: d}
+		state := graphicsState{ctm: identity, scale: 1}
+		ex.run(d.contents(p.dict["Contents"]), p.resources, state, 0)
+		result = append(result, Page{Lines: assembleLines(ex.runs)})
+	}
+	return result, nil
+}
+
+type matrix [6]float64
+
+var identity = matrix{1, 0, 0, 1, 0, 0}
+
+// multiply returns m × n
+func (m matrix) multiply(n matrix) matrix {
+	return matrix{
+		m[0]*n[0] + m[1]*n[2],
+		m[0]*n[1] + m[1]*n[3],
+		m[2]*n[0] + m[3]*n[2],
+		m[2]*n[1] + m[3]*n[3],
+		m[4]*n[0] + m[5]*n[2] + n[4],
+		m[4]*n[1] + m[5]*n[3] + n[5],
+	}
+}
+
+func translate(tx, ty float64) matrix {
+	return matrix{1, 0, 0, 1, tx, ty}
+}
+
+type graphicsState struct {
+	ctm       matrix
+	font      *font
+	size      float64
+	charSpace float64
+	wordSpace float64
+	scale     float64
+	leading   float64
+	rise      float64
+}
+
+// textRun is a piece of text shown by a single operator
+type textRun struct {
+	text  string
+	x, y  float64
+	size  float64
+	width float64
+}
+
+type extractor struct {
+	doc  *Document
+	runs []textRun
+}
+
+func (ex *extractor) run(content []byte, resources Dict, state graphicsState, depth int) {
+	d := ex.doc
+	fonts := d.dict(resources["Font"])
+	xobjects := d.dict(resources["XObject"])
+
+	var stack []graphicsState
+	tm, tlm := identity, identity
+	var operands []Object
+
+	l := newLexer(content)
+	for !l.eof() {
+		obj, err := l.readObject()
+		if err != nil {
+			break
+		}
+		op, ok := obj.(keyword)
+		if !ok {
+			operands = append(operands, obj)
+			continue
+		}
+
+		switch op {
+		case "q":
+			stack = append(stack, state)
+		case "Q":
+			if len(stack) > 0 {
+				state = stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+			}
+		case "cm":
+			if m, ok := matrixOperand(operands); ok {
+				state.ctm = m.multiply(state.ctm)
+			}
+		case "BT":
+			tm, tlm = identity, identity
+		case "Tf":
+			if len(operands) >= 2 {
+				if name, ok := operands[0].(Name); ok && fonts != nil {
+					state.font = d.loadFont(fonts[name])
+				}
+				state.size, _ = numberOperand(operands[1])
+			}
+		case "Tc":
+			state.charSpace = lastNumber(operands)
+		case "Tw":
+			state.wordSpace = lastNumber(operands)
+		case "Tz":
+			state.scale = lastNumber(operands) / 100
+		case "TL":
+			state.leading = lastNumber(operands)
+		case "Ts":
+			state.rise = lastNumber(operands)
+		case "Td", "TD":
+			if len(operands) >= 2 {
+				tx, _ := numberOperand(operands[0])
+				ty, _ := numberOperand(operands[1])
+				if op == "TD" {
+					state.leading = -ty
+				}
+				tlm = translate(tx, ty).multiply(tlm)
+				tm = tlm
+			}
+		case "Tm":
+			if m, ok := matrixOperand(operands); ok {
+				tm, tlm = m, m
+			}
+		case "T*":
+			tlm = translate(0, -state.leading).multiply(tlm)
+			tm = tlm
+		case "Tj":
+			if len(operands) >= 1 {
+				if s, ok := operands[0].(String); ok {
+					ex.show(&tm, state, []Object{s})
+				}
+			}
+		case "'", "\"":
+			if op == "\"" && len(operands) >= 3 {
+				state.wordSpace, _ = numberOperand(operands[0])
+				state.charSpace, _ = numberOperand(operands[1])
+			}
+			tlm = translate(0, -state.leading).multiply(tlm)
+			tm = tlm
+			if len(operands) >= 1 {
+				if s, ok := operands[len(operands)-1].(String); ok {
+					ex.show(&tm, state, []Object{s})
+				}
+			}
+		case "TJ":
+			if len(operands) >= 1 {
+				if arr, ok := operands[0].(Array); ok {
+					ex.show(&tm, state, arr)
+				}
+			}
+		case "Do":
+			if len(operands) >= 1 && depth < maxFormDepth && xobjects != nil {
+				if name, ok := operands[0].(Name); ok {
+					ex.runForm(xobjects[name], resources, state, depth)
+				}
+			}
+		case "ID":
+			skipInlineImage(l)
+		}
+		operands = operands[:0]
+	}
+}
+
+func (ex *extractor) runForm(obj Object, resources Dict, state graphicsState, depth int) {
+	d := ex.doc
+	form, ok := d.resolve(obj).(*Stream)
+	if !ok || form.Dict["Subtype"] != Name("Form") {
+		return
+	}
+	data, err := d.decodeStream(form)
+	if err != nil {
+		return
+	}
+	if own := d.dict(form.Dict["Resources"]); own != nil {
+		resources = own
+	}
+	if arr, ok := d.resolve(form.Dict["Matrix"]).(Array); ok {
+		if m, ok := matrixOperand(arr); ok {
+			state.ctm = m.multiply(state.ctm)
+		}
+	}
+	ex.run(data, resources, state, depth+1)
+}
+
+// show renders the strings and spacing adjustments of a Tj or TJ operand,
+// advancing the text matrix
+func (ex *extractor) show(tm *matrix, state graphicsState, items []Object) {
+	if state.font == nil {
+		state.font = &font{defaultWidth: 500}
+	}
+
+	start := matrix{state.size * state.scale, 0, 0, state.size, 0, state.rise}.multiply(*tm).multiply(state.ctm)
+	device := tm.multiply(state.ctm)
+	xScale := math.Hypot(device[0], device[1])
+	yScale := math.Hypot(device[2], device[3])
+
+	var text strings.Builder
+	var advance float64
+	for _, item := range items {
+		switch v := item.(type) {
+		case String:
+			for _, g := range state.font.decode(v) {
+				text.WriteString(g.text)
+				tx := g.width/1000*state.size + state.charSpace
+				if g.space {
+					tx += state.wordSpace
+				}
+				advance += tx * state.scale
+			}
+		case int, float64:
+			adjust, _ := numberOperand(v)
+			advance -= adjust / 1000 * state.size * state.scale
+			// Large negative adjustments separate words in justified text
+			if adjust < -200 && text.Len() > 0 && !strings.HasSuffix(text.String(), " ") {
+				text.WriteByte(' ')
+			}
+		}
+	}
+
+	*tm = translate(advance, 0).multiply(*tm)
+
+	if strings.TrimSpace(text.String()) == "" {
+		return
+	}
+	ex.runs = append(ex.runs, textRun{
+		text:  text.String(),
+		x:     start[4],
+		y:     start[5],
+		size:  state.size * yScale,
+		width: advance * xScale,
+	})
+}
+
+func skipInlineImage(l *lexer) {
+	if l.pos < len(l.data) && isWhitespace(l.data[l.pos]) {
+		l.pos++
+	}
+	for i := l.pos; i+2 <= len(l.data); i++ {
+		if l.data[i] == 'E' && l.data[i+1] == 'I' && (i == 0 || isWhitespace(l.data[i-1])) &&
+			(i+2 == len(l.data) || isWhitespace(l.data[i+2])) {
+			l.pos = i + 2
+			return
+		}
+	}
+	l.pos = len(l.data)
+}
+
+func numberOperand(obj Object) (float64, bool) {
+	switch v := obj.(type) {
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+func lastNumber(operands []Object) float64 {
+	if len(operands) == 0 {
+		return 0
+	}
+	v, _ := numberOperand(operands[len(operands)-1])
+	return v
+}
+
+func matrixOperand(operands []Object) (matrix, bool) {
+	if len(operands) < 6 {
+		return matrix{}, false
+	}
+	var m matrix
+	for i := 0; i < 6; i++ {
+		v, ok := numberOperand(operands[len(operands)-6+i])
+		if !ok {
+			return matrix{}, false
+		}
+		m[i] = v
+	}
+	return m, true
+}
+
+// assembleLines groups the text runs of a page into lines, top to bottom,
+// inserting spaces where runs are separated by a visible gap
+func assembleLines(runs []textRun) []Line {
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].y > runs[j].y })
+
+	var lines []Line
+	var current []textRun
+	flush := func() {
+		if len(current) > 0 {
+			lines = append(lines, joinRuns(current))
+		}
+		current = nil
+	}
+	for _, r := range runs {
+		if len(current) > 0 && math.Abs(current[0].y-r.y) > lineTolerance(current[0], r) {
+			flush()
+		}
+		current = append(current, r)
+	}
+	flush()
+	return lines
+}
+
+func lineTolerance(a, b textRun) float64 {
+	return math.Max(math.Min(a.size, b.size)*0.4, 1)
+}
+
+func joinRuns(runs []textRun) Line {
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].x < runs[j].x })
+
+	var buf bytes.Buffer
+	line := Line{X: runs[0].x, Y: runs[0].y}
+	var prev *textRun
+	for i := range runs {
+		r := &runs[i]
+		if prev != nil {
+			// Some producers fake bold text by drawing it twice
+			if r.text == prev.text && math.Abs(r.x-prev.x) < 1 {
+				continue
+			}
+			gap := r.x - (prev.x + prev.width)
+			if gap > math.Max(r.size, prev.size)*0.15 && !endsWithSpace(buf.Bytes()) && !strings.HasPrefix(r.text, " ") {
+				buf.WriteByte(' ')
+			}
+		}
+		buf.WriteString(r.text)
+		line.Size = math.Max(line.Size, r.size)
+		prev = r
+	}
+	line.Text = strings.Join(strings.FieldsFunc(buf.String(), unicode.IsSpace), " ")
+	return line
+}
+
+func endsWithSpace(b []byte) bool {
+	return len(b) > 0 && b[len(b)-1] == ' '
+}
@@ -0,0 +1,73 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+var ErrTooManyAttempts = errors.New("too many failed attempts")
+
+// AttemptLimiter counts failed attempts per key, e.g. per user, and locks the
+// key out for the rest of the window once the limit is reached. Unlike
+// RateLimiter it does not depend on the client IP, so guessing a code from
+// many addresses is limited too.
+type AttemptLimiter struct {
+	redis  *redis.Client
+	prefix string
+	limit  int
+	window time.Duration
+}
+
+func NewAttemptLimiter(redisClient *redis.Client, prefix string, limit int, window time.Duration) *AttemptLimiter {
+	if redisClient == nil {
+		panic("Redis client is required for attempt limiting")
+	}
+	return &AttemptLimiter{
+		redis:  redisClient,
+		prefix: prefix,
+		limit:  limit,
+		window: window,
+	}
+}
+
+// Check returns ErrTooManyAttempts while the key is locked out
+func (l *AttemptLimiter) Check(ctx context.Context, key string) error {
+	count, err := l.redis.Get(ctx, l.key(key)).Int()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			// Fail closed, an unlimited guess rate defeats the second factor
+			log.Error().Err(err).Msg("Failed to read attempt counter")
+			return err
+		}
+		return nil
+	}
+
+	if count >= l.limit {
+		return ErrTooManyAttempts
+	}
+	return nil
+}
+
+// Fail records a failed attempt, the window starts with the first failure
+func (l *AttemptLimiter) Fail(ctx context.Context, key string) {
+	pipe := l.redis.TxPipeline()
+	pipe.Incr(ctx, l.key(key))
+	pipe.ExpireNX(ctx, l.key(key), l.window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Error().Err(err).Msg("Failed to record failed attempt")
+	}
+}
+
+// Reset clears the failures of the key after a successful attempt
+func (l *AttemptLimiter) Reset(ctx context.Context, key string) {
+	if err := l.redis.Del(ctx, l.key(key)).Err(); err != nil {
+		log.Error().Err(err).Msg("Failed to reset attempt counter")
+	}
+}
+
+func (l *AttemptLimiter) key(key string) string {
+	return "attempts:" + l.prefix + ":" + key
+}
@@ -0,0 +1,66 @@
+package security
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+)
+
+var ErrDecryption = errors.New("decryption failed")
+
+// Encryptor seals small secrets at rest with AES-256-GCM, the same scheme as
+// the session cookie
+type Encryptor struct {
+	aead cipher.AEAD
+}
+
+func NewEncryptor(key []byte) (*Encryptor, error) {
+	// AES-256 requires a 32-byte key
+	if len(key) != 32 {
+		return nil, errors.New("encryption key should be 32 bytes")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Encryptor{aead: aead}, nil
+}
+
+// Encrypt returns the base64 encoded nonce and ciphertext
+func (e *Encryptor) Encrypt(plaintext []byte) (string, error) {
+	nonce := make([]byte, e.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	ciphertext := e.aead.Seal(nonce, nonce, plaintext, nil)
+	return base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+func (e *Encryptor) Decrypt(encrypted string) ([]byte, error) {
+	ciphertext, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return nil, ErrDecryption
+	}
+
+	nonceSize := e.aead.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return nil, ErrDecryption
+	}
+	nonce, ciphertext := ciphertext[:nonceSize], ciphertext[nonceSize:]
+
+	plaintext, err := e.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, ErrDecryption
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,167 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var ErrTokenRevoked = errors.New("token revoked")
+
+// maxCachedEntries bounds the in-process cache, expired entries are swept
+// once it is reached
+const maxCachedEntries = 10000
+
+// TokenDenylist revokes tokens before they expire. IDs on the list, token IDs
+// or session IDs, are revoked, and so are all tokens of a subject issued
+// before its cutoff. Lookups are cached in-process for cacheTTL, so a
+// revocation made by another instance takes up to cacheTTL to apply there.
+type TokenDenylist struct {
+	redis    *redis.Client
+	cacheTTL time.Duration
+
+	mu    sync.Mutex
+	cache map[string]denylistEntry
+}
+
+type denylistEntry struct {
+	// value is what Redis holds for the key, empty if nothing
+	value   string
+	expires time.Time
+}
+
+func NewTokenDenylist(redisClient *redis.Client, cacheTTL time.Duration) *TokenDenylist {
+	if redisClient == nil {
+		panic("Redis client is required for the token denylist")
+	}
+	return &TokenDenylist{
+		redis:    redisClient,
+		cacheTTL: cacheTTL,
+		cache:    map[string]denylistEntry{},
+	}
+}
+
+// Revoke puts the ID on the list. ttl is how long tokens carrying it stay
+// valid, after that they expire on their own.
+func (d *TokenDenylist) Revoke(ctx context.Context, id string, ttl time.Duration) error {
+	if err := d.redis.Set(ctx, d.idKey(id), "1", ttl).Err(); err != nil {
+		log.Error().Err(err).Msg("Failed to revoke token")
+		return err
+	}
+	d.store(d.idKey(id), "1")
+	return nil
+}
+
+// RevokeBefore revokes the tokens of the subject issued up to now. Token
+// issue times have second precision, so tokens issued later in the same
+// second are revoked as well.
+func (d *TokenDenylist) RevokeBefore(ctx context.Context, subject string, now time.Time, ttl time.Duration) error {
+	cutoff := strconv.FormatInt(now.Truncate(time.Second).Add(time.Second).Unix(), 10)
+	if err := d.redis.Set(ctx, d.subjectKey(subject), cutoff, ttl).Err(); err != nil {
+		log.Error().Err(err).Msg("Failed to revoke subject tokens")
+		return err
+	}
+	d.store(d.subjectKey(subject), cutoff)
+	return nil
+}
+
+// Check returns ErrTokenRevoked if one of the IDs is on the list, or the
+// token was issued before the cutoff of its subject
+func (d *TokenDenylist) Check(ctx context.Context, ids []string, subject string, issuedAt time.Time) error {
+	keys := make([]string, 0, len(ids)+1)
+	for _, id := range ids {
+		if id != "" {
+			keys = append(keys, d.idKey(id))
+		}
+	}
+	subjectKey := d.subjectKey(subject)
+	keys = append(keys, subjectKey)
+
+	values, err := d.lookup(ctx, keys)
+	if err != nil {
+		return err
+	}
+
+	for key, value := range values {
+		if value == "" {
+			continue
+		}
+		if key != subjectKey {
+			return ErrTokenRevoked
+		}
+
+		cutoff, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			log.Error().Err(err).Str("subject", subject).Msg("Invalid token revocation cutoff")
+			return ErrTokenRevoked
+		}
+		if issuedAt.Unix() < cutoff {
+			return ErrTokenRevoked
+		}
+	}
+	return nil
+}
+
+// lookup returns the values of the keys, reading those not cached from Redis
+// in one round trip
+func (d *TokenDenylist) lookup(ctx context.Context, keys []string) (map[string]string, error) {
+	values := make(map[string]string, len(keys))
+	var missing []string
+
+	now := time.Now()
+	d.mu.Lock()
+	for _, key := range keys {
+		if entry, ok := d.cache[key]; ok && now.Before(entry.expires) {
+			values[key] = entry.value
+		} else {
+			missing = append(missing, key)
+		}
+	}
+	d.mu.Unlock()
+
+	if len(missing) == 0 {
+		return values, nil
+	}
+
+	results, err := d.redis.MGet(ctx, missing...).Result()
+	if err != nil {
+		// Fail closed, a revoked token must not pass while Redis is down
+		log.Error().Err(err).Msg("Failed to read token denylist")
+		return nil, err
+	}
+
+	for i, key := range missing {
+		value, _ := results[i].(string)
+		values[key] = value
+		d.store(key, value)
+	}
+	return values, nil
+}
+
+func (d *TokenDenylist) store(key, value string) {
+	now := time.Now()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if len(d.cache) >= maxCachedEntries {
+		for cachedKey, entry := range d.cache {
+			if !now.Before(entry.expires) {
+				delete(d.cache, cachedKey)
+			}
+		}
+	}
+	d.cache[key] = denylistEntry{value: value, expires: now.Add(d.cacheTTL)}
+}
+
+func (d *TokenDenylist) idKey(id string) string {
+	return "denylist:id:" + id
+}
+
+func (d *TokenDenylist) subjectKey(subject string) string {
+	return "denylist:subject:" + subject
+}
@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+)
+
+var (
+	ErrBlobNotFound = errors.New("blob not found")
+	ErrInvalidKey   = errors.New("invalid blob key")
+)
+
+// BlobStore keeps binary objects such as uploaded photos under slash
+// separated keys, e.g. "resumes/<id>/photo.jpg"
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, contentType string) error
+	// Get returns ErrBlobNotFound when nothing is stored under the key
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete succeeds when nothing is stored under the key
+	Delete(ctx context.Context, key string) error
+}
+
+// validateKey rejects keys that are absolute, not clean or that would escape
+// the store through ".." segments
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, `\`) {
+		return ErrInvalidKey
+	}
+	if path.Clean(key) != key {
+		return ErrInvalidKey
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == ".." || segment == "." {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}